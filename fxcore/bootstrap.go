@@ -48,6 +48,7 @@ func (b *Bootstrapper) BootstrapApp(options ...fx.Option) *fx.App {
 		fx.WithLogger(fxlog.NewFxEventLogger),
 		fx.Options(b.options...),
 		fx.Options(options...),
+		fx.Invoke(RegisterShutdownCoordinator),
 	)
 }
 
@@ -63,6 +64,7 @@ func (b *Bootstrapper) BootstrapTestApp(tb testing.TB, options ...fx.Option) *fx
 		fx.NopLogger,
 		fx.Options(b.options...),
 		fx.Options(options...),
+		fx.Invoke(RegisterShutdownCoordinator),
 	)
 }
 
@@ -15,9 +15,10 @@ import (
 	"github.com/ankorstore/yokai/fxlog"
 	"github.com/ankorstore/yokai/fxmetrics"
 	"github.com/ankorstore/yokai/fxtrace"
-	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
 	"github.com/ankorstore/yokai/httpserver/handler"
 	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
 	"github.com/ankorstore/yokai/log"
@@ -66,6 +67,7 @@ var FxCoreModule = fx.Module(
 	fx.Provide(
 		NewFxModuleInfoRegistry,
 		NewTaskRegistry,
+		NewFxShutdownCoordinator,
 		NewFxCore,
 		fx.Annotate(
 			NewFxCoreModuleInfo,
@@ -88,16 +90,21 @@ type FxCoreDashboardTheme struct {
 //nolint:containedctx
 type FxCoreParam struct {
 	fx.In
-	Context         context.Context
-	LifeCycle       fx.Lifecycle
-	Generator       uuid.UuidGenerator
-	TracerProvider  oteltrace.TracerProvider
-	Checker         *healthcheck.Checker
-	Config          *config.Config
-	Logger          *log.Logger
-	InfoRegistry    *FxModuleInfoRegistry
-	TaskRegistry    *TaskRegistry
-	MetricsRegistry *prometheus.Registry
+	Context             context.Context
+	LifeCycle           fx.Lifecycle
+	Generator           correlation.CorrelationIdGenerator
+	Validator           correlation.CorrelationIdValidator
+	TracerProvider      oteltrace.TracerProvider
+	Checker             *healthcheck.Checker
+	Config              *config.Config
+	Logger              *log.Logger
+	InfoRegistry        *FxModuleInfoRegistry
+	TaskRegistry        *TaskRegistry
+	MetricsRegistry     *prometheus.Registry
+	ShutdownCoordinator *ShutdownCoordinator
+	Authenticators      []auth.Authenticator   `group:"httpserver-authenticators"`
+	HttpServer          *echo.Echo             `optional:"true"`
+	RoutesInfo          *httpserver.RoutesInfo `optional:"true"`
 }
 
 // NewFxCore returns a new [Core].
@@ -105,6 +112,14 @@ func NewFxCore(p FxCoreParam) (*Core, error) {
 	var coreServer *echo.Echo
 	var err error
 
+	// healthcheck metrics
+	if p.Config.GetBool("modules.healthcheck.metrics.collect.enabled") {
+		err = registerHealthCheckMetrics(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register healthcheck metrics: %w", err)
+		}
+	}
+
 	if p.Config.GetBool("modules.core.server.expose") {
 		appDebug := p.Config.AppDebug()
 
@@ -125,11 +140,22 @@ func NewFxCore(p FxCoreParam) (*Core, error) {
 					p.Config.GetBool("modules.core.server.errors.stack") || appDebug,
 				).Handle(),
 			),
+			httpserver.WithReadTimeout(p.Config.GetDuration("modules.core.server.timeouts.read")),
+			httpserver.WithReadHeaderTimeout(p.Config.GetDuration("modules.core.server.timeouts.read_header")),
+			httpserver.WithWriteTimeout(p.Config.GetDuration("modules.core.server.timeouts.write")),
+			httpserver.WithIdleTimeout(p.Config.GetDuration("modules.core.server.timeouts.idle")),
+			httpserver.WithMaxHeaderBytes(p.Config.GetInt("modules.core.server.max_header_bytes")),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create core http server: %w", err)
 		}
 
+		// shutdown
+		shutdownParticipant := httpserver.NewShutdownParticipant(ModuleName).Bind(coreServer)
+		coreServer.Use(shutdownParticipant.Middleware())
+
+		p.ShutdownCoordinator.Register(shutdownParticipant)
+
 		// middlewares
 		coreServer = withMiddlewares(coreServer, p)
 
@@ -153,7 +179,7 @@ func NewFxCore(p FxCoreParam) (*Core, error) {
 				return nil
 			},
 			OnStop: func(ctx context.Context) error {
-				return coreServer.Shutdown(ctx)
+				return shutdownParticipant.Shutdown(ctx)
 			},
 		})
 	}
@@ -169,6 +195,7 @@ func withMiddlewares(coreServer *echo.Echo, p FxCoreParam) *echo.Echo {
 	coreServer.Use(httpservermiddleware.RequestIdMiddlewareWithConfig(
 		httpservermiddleware.RequestIdMiddlewareConfig{
 			Generator: p.Generator,
+			Validator: p.Validator,
 		},
 	))
 
@@ -224,6 +251,11 @@ func withMiddlewares(coreServer *echo.Echo, p FxCoreParam) *echo.Echo {
 		coreServer.Use(httpservermiddleware.RequestMetricsMiddlewareWithConfig(metricsMiddlewareConfig))
 	}
 
+	// body limit middleware
+	if bodyLimit := p.Config.GetString("modules.core.server.body_limit"); bodyLimit != "" {
+		coreServer.Use(middleware.BodyLimit(bodyLimit))
+	}
+
 	// recovery middleware
 	coreServer.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		DisableErrorHandler: true,
@@ -233,6 +265,70 @@ func withMiddlewares(coreServer *echo.Echo, p FxCoreParam) *echo.Echo {
 	return coreServer
 }
 
+func registerHealthCheckMetrics(p FxCoreParam) error {
+	var buckets []float64
+	if bucketsConfig := p.Config.GetString("modules.healthcheck.metrics.buckets"); bucketsConfig != "" {
+		for _, s := range Split(bucketsConfig) {
+			f, err := strconv.ParseFloat(s, 64)
+			if err == nil {
+				buckets = append(buckets, f)
+			}
+		}
+	}
+
+	namespace := Sanitize(p.Config.GetString("modules.healthcheck.metrics.collect.namespace"))
+	subsystem := Sanitize(p.Config.GetString("modules.healthcheck.metrics.collect.subsystem"))
+
+	var checkerMetrics *fxhealthcheck.CheckerMetrics
+	if len(buckets) > 0 {
+		checkerMetrics = fxhealthcheck.NewCheckerMetricsWithBuckets(namespace, subsystem, buckets)
+	} else {
+		checkerMetrics = fxhealthcheck.NewCheckerMetrics(namespace, subsystem)
+	}
+
+	err := checkerMetrics.Register(p.MetricsRegistry)
+	if err != nil {
+		return err
+	}
+
+	p.Checker.RegisterObserver(checkerMetrics)
+
+	return nil
+}
+
+func createHealthCheckHandlerOptions(p FxCoreParam) ([]handler.HealthCheckHandlerOption, error) {
+	options := []handler.HealthCheckHandlerOption{
+		handler.WithHealthCheckService(p.Config.AppName(), p.Config.AppVersion(), p.Config.AppDescription()),
+	}
+
+	verbosity := handler.FetchHealthCheckVerbosity(p.Config.GetString("modules.core.server.healthcheck.verbosity.level"))
+	if verbosity == handler.HealthCheckFullVerbosity {
+		return append(options, handler.WithHealthCheckVerbosity(verbosity)), nil
+	}
+
+	// full verbosity for the callers from the configured networks, or authenticated
+	var resolvers []handler.HealthCheckVerbosityResolver
+
+	if networks := p.Config.GetStringSlice("modules.core.server.healthcheck.verbosity.networks"); len(networks) > 0 {
+		resolver, err := handler.NewNetworksHealthCheckVerbosityResolver(networks...)
+		if err != nil {
+			return nil, err
+		}
+
+		resolvers = append(resolvers, resolver)
+	}
+
+	if p.Config.GetBool("modules.core.server.healthcheck.verbosity.authenticated") {
+		resolvers = append(resolvers, handler.NewAuthenticatedHealthCheckVerbosityResolver(p.Authenticators...))
+	}
+
+	if len(resolvers) > 0 {
+		return append(options, handler.WithHealthCheckVerbosityResolver(handler.NewAnyHealthCheckVerbosityResolver(resolvers...))), nil
+	}
+
+	return append(options, handler.WithHealthCheckVerbosity(verbosity)), nil
+}
+
 //nolint:cyclop,gocognit,gocyclo,maintidx
 func withHandlers(coreServer *echo.Echo, p FxCoreParam) (*echo.Echo, error) {
 	appDebug := p.Config.AppDebug()
@@ -282,6 +378,14 @@ func withHandlers(coreServer *echo.Echo, p FxCoreParam) (*echo.Echo, error) {
 	buildPath := p.Config.GetString("modules.core.server.debug.build.path")
 	modulesPath := p.Config.GetString("modules.core.server.debug.modules.path")
 
+	if routesPath == "" {
+		routesPath = DefaultDebugRoutesPath
+	}
+
+	// http server routes, exposed with the debug routes if the http server is provided (by the fxhttpserver module)
+	httpRoutesExpose := (routesExpose || appDebug) && p.HttpServer != nil && p.RoutesInfo != nil
+	httpRoutesPath := routesPath + "/http"
+
 	// tasks
 	if tasksExpose {
 		if tasksPath == "" {
@@ -335,13 +439,19 @@ func withHandlers(coreServer *echo.Echo, p FxCoreParam) (*echo.Echo, error) {
 		coreServer.Logger.Debug("registered metrics handler")
 	}
 
+	// healthcheck handlers options
+	healthCheckHandlerOptions, err := createHealthCheckHandlerOptions(p)
+	if err != nil {
+		return nil, err
+	}
+
 	// healthcheck startup
 	if startupExpose {
 		if startupPath == "" {
 			startupPath = DefaultHealthCheckStartupPath
 		}
 
-		coreServer.GET(startupPath, handler.HealthCheckHandler(p.Checker, healthcheck.Startup))
+		coreServer.GET(startupPath, handler.HealthCheckHandler(p.Checker, healthcheck.Startup, healthCheckHandlerOptions...))
 
 		coreServer.Logger.Debug("registered healthcheck startup handler")
 	}
@@ -352,7 +462,7 @@ func withHandlers(coreServer *echo.Echo, p FxCoreParam) (*echo.Echo, error) {
 			livenessPath = DefaultHealthCheckLivenessPath
 		}
 
-		coreServer.GET(livenessPath, handler.HealthCheckHandler(p.Checker, healthcheck.Liveness))
+		coreServer.GET(livenessPath, handler.HealthCheckHandler(p.Checker, healthcheck.Liveness, healthCheckHandlerOptions...))
 
 		coreServer.Logger.Debug("registered healthcheck liveness handler")
 	}
@@ -363,7 +473,7 @@ func withHandlers(coreServer *echo.Echo, p FxCoreParam) (*echo.Echo, error) {
 			readinessPath = DefaultHealthCheckReadinessPath
 		}
 
-		coreServer.GET(readinessPath, handler.HealthCheckHandler(p.Checker, healthcheck.Readiness))
+		coreServer.GET(readinessPath, handler.HealthCheckHandler(p.Checker, healthcheck.Readiness, healthCheckHandlerOptions...))
 
 		coreServer.Logger.Debug("registered healthcheck readiness handler")
 	}
@@ -405,15 +515,18 @@ func withHandlers(coreServer *echo.Echo, p FxCoreParam) (*echo.Echo, error) {
 
 	// debug routes
 	if routesExpose || appDebug {
-		if routesPath == "" {
-			routesPath = DefaultDebugRoutesPath
-		}
-
 		coreServer.GET(routesPath, handler.DebugRoutesHandler(coreServer))
 
 		coreServer.Logger.Debug("registered debug routes handler")
 	}
 
+	// debug http server routes
+	if httpRoutesExpose {
+		coreServer.GET(httpRoutesPath, handler.DebugRoutesWithInfoHandler(p.HttpServer, p.RoutesInfo))
+
+		coreServer.Logger.Debug("registered debug http server routes handler")
+	}
+
 	// debug stats
 	if statsExpose || appDebug {
 		if statsPath == "" {
@@ -540,6 +653,8 @@ func withHandlers(coreServer *echo.Echo, p FxCoreParam) (*echo.Echo, error) {
 				"pprofPath":                    pprofPath,
 				"routesExpose":                 routesExpose || appDebug,
 				"routesPath":                   routesPath,
+				"httpRoutesExpose":             httpRoutesExpose,
+				"httpRoutesPath":               httpRoutesPath,
 				"statsExpose":                  statsExpose || appDebug,
 				"statsPath":                    statsPath,
 				"buildExpose":                  buildExpose || appDebug,
@@ -2,19 +2,25 @@ package fxcore_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/fxcore"
 	"github.com/ankorstore/yokai/fxcore/testdata/probes"
 	"github.com/ankorstore/yokai/fxcore/testdata/tasks"
 	"github.com/ankorstore/yokai/fxhealthcheck"
 	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/httpserver/handler"
 	"github.com/ankorstore/yokai/log/logtest"
 	"github.com/ankorstore/yokai/trace/tracetest"
+	"github.com/labstack/echo/v4"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +39,20 @@ func TestModuleWithServerDisabled(t *testing.T) {
 	assert.Nil(t, core.HttpServer())
 }
 
+func TestModuleWithServerTimeouts(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var core *fxcore.Core
+
+	fxcore.NewBootstrapper().RunTestApp(t, fx.Populate(&core))
+
+	assert.Equal(t, 10*time.Second, core.HttpServer().Server.ReadTimeout)
+	assert.Equal(t, 5*time.Second, core.HttpServer().Server.ReadHeaderTimeout)
+	assert.Equal(t, 15*time.Second, core.HttpServer().Server.WriteTimeout)
+	assert.Equal(t, 60*time.Second, core.HttpServer().Server.IdleTimeout)
+	assert.Equal(t, 4096, core.HttpServer().Server.MaxHeaderBytes)
+}
+
 func TestModuleWithMetricsDisabled(t *testing.T) {
 	t.Setenv("APP_CONFIG_PATH", "testdata/config")
 	t.Setenv("METRICS_ENABLED", "false")
@@ -329,8 +349,8 @@ func TestModuleWithHealthcheckEnabled(t *testing.T) {
 	core.HttpServer().ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
-	assert.Equal(t,
-		`{"success":true,"probes":{"successProbe":{"success":true,"message":"success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":true,"status":"healthy","probes":\{"successProbe":\{"success":true,"message":"success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
 	)
 
@@ -360,8 +380,8 @@ func TestModuleWithHealthcheckEnabled(t *testing.T) {
 	core.HttpServer().ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Equal(t,
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"failure"},"successProbe":{"success":true,"message":"success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"failure","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
 	)
 
@@ -399,14 +419,24 @@ func TestModuleWithHealthcheckEnabled(t *testing.T) {
 	rec = httptest.NewRecorder()
 	core.HttpServer().ServeHTTP(rec, req)
 
-	assert.Equal(t, http.StatusOK, rec.Code)
-	assert.Equal(t,
-		`{"success":true,"probes":{"successProbe":{"success":true,"message":"success"}}}`,
+	// the test app is already stopped, so the shutdown probe flipped the readiness
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Regexp(t,
+		`^\{"success":false,"status":"unhealthy","probes":\{"shutdown":\{"success":false,"message":"applicationisshuttingdown","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
 	)
 
-	logtest.AssertHasNotLogRecord(t, logBuffer, map[string]interface{}{
-		"level":   "info",
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":        "error",
+		"service":      "core-app",
+		"module":       "core",
+		"shutdown":     "success: false, message: application is shutting down",
+		"successProbe": "success: true, message: success",
+		"message":      "healthcheck failure",
+	})
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "error",
 		"service": "core-app",
 		"module":  "core",
 		"method":  "GET",
@@ -420,10 +450,150 @@ func TestModuleWithHealthcheckEnabled(t *testing.T) {
 		"GET /readyz",
 		semconv.HTTPMethod(http.MethodGet),
 		semconv.HTTPRoute("/readyz"),
-		semconv.HTTPStatusCode(http.StatusOK),
+		semconv.HTTPStatusCode(http.StatusInternalServerError),
+	)
+}
+
+func TestModuleWithHealthcheckVerbosity(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("STARTUP_ENABLED", "true")
+	t.Setenv("HEALTHCHECK_VERBOSITY", "minimal")
+
+	var core *fxcore.Core
+
+	fxcore.NewBootstrapper().RunTestApp(
+		t,
+		fxhealthcheck.AsCheckerProbe(probes.NewSuccessProbe),
+		fx.Populate(&core),
+	)
+
+	// [GET] /healthz from outside the configured networks
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	core.HttpServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(
+		t,
+		`{"success":true,"status":"healthy"}`,
+		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
+	)
+
+	// [GET] /healthz from outside the configured networks, as application/health+json
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set(echo.HeaderAccept, handler.MIMEApplicationHealthJSON)
+	rec = httptest.NewRecorder()
+	core.HttpServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, handler.MIMEApplicationHealthJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(
+		t,
+		`{"status":"pass"}`,
+		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
+	)
+
+	// [GET] /healthz from the configured networks, as application/health+json
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set(echo.HeaderAccept, handler.MIMEApplicationHealthJSON)
+	rec = httptest.NewRecorder()
+	core.HttpServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Regexp(
+		t,
+		`^\{"status":"pass","version":"0.1.0","serviceId":"core-app","description":"coreappdescription","checks":\{"successProbe:responseTime":\[\{"componentId":"successProbe","status":"pass",`,
+		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
 	)
 }
 
+func TestModuleWithHealthcheckAuthenticatedVerbosity(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("STARTUP_ENABLED", "true")
+	t.Setenv("HEALTHCHECK_VERBOSITY", "minimal")
+	t.Setenv("HEALTHCHECK_VERBOSITY_AUTHENTICATED", "true")
+
+	var core *fxcore.Core
+
+	fxcore.NewBootstrapper().RunTestApp(
+		t,
+		fxhealthcheck.AsCheckerProbe(probes.NewSuccessProbe),
+		fx.Provide(
+			fx.Annotate(
+				func() auth.Authenticator {
+					return auth.NewBasicAuthenticator(map[string]auth.BasicUser{
+						"admin": {Password: "secret"},
+					})
+				},
+				fx.ResultTags(`group:"httpserver-authenticators"`),
+			),
+		),
+		fx.Populate(&core),
+	)
+
+	// [GET] /healthz anonymous
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	core.HttpServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(
+		t,
+		`{"success":true,"status":"healthy"}`,
+		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
+	)
+
+	// [GET] /healthz authenticated
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.SetBasicAuth("admin", "secret")
+	rec = httptest.NewRecorder()
+	core.HttpServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Regexp(
+		t,
+		`^\{"success":true,"status":"healthy","probes":\{"successProbe":`,
+		strings.ReplaceAll(strings.ReplaceAll(rec.Body.String(), " ", ""), "\n", ""),
+	)
+}
+
+func TestModuleWithHealthcheckMetrics(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("HEALTHCHECK_METRICS_ENABLED", "true")
+
+	var core *fxcore.Core
+	var checker *healthcheck.Checker
+	var metricsRegistry *prometheus.Registry
+
+	fxcore.NewBootstrapper().RunTestApp(
+		t,
+		fxhealthcheck.AsCheckerProbe(probes.NewSuccessProbe),
+		fxhealthcheck.AsCheckerProbe(probes.NewFailureProbe, healthcheck.Readiness),
+		fx.Populate(&core, &checker, &metricsRegistry),
+	)
+
+	result := checker.Check(context.Background(), healthcheck.Readiness)
+	assert.False(t, result.Success)
+
+	expectedStatus := `
+		# HELP foo_bar_healthcheck_probe_status Status of the last health check probe execution (1 for success, 0 for failure)
+		# TYPE foo_bar_healthcheck_probe_status gauge
+		foo_bar_healthcheck_probe_status{probe="failureProbe"} 0
+		foo_bar_healthcheck_probe_status{probe="shutdown"} 0
+		foo_bar_healthcheck_probe_status{probe="successProbe"} 1
+	`
+
+	err := testutil.GatherAndCompare(
+		metricsRegistry,
+		strings.NewReader(expectedStatus),
+		"foo_bar_healthcheck_probe_status",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(metricsRegistry, "foo_bar_healthcheck_probe_duration_seconds"))
+}
+
 func TestModuleWithDebugConfigDisabled(t *testing.T) {
 	t.Setenv("APP_CONFIG_PATH", "testdata/config")
 	t.Setenv("CONFIG_ENABLED", "false")
@@ -670,6 +840,43 @@ func TestModuleWithDebugRoutesEnabled(t *testing.T) {
 	)
 }
 
+func TestModuleWithDebugHttpRoutes(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("ROUTES_ENABLED", "true")
+
+	httpServer := echo.New()
+	httpServer.GET("/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}).Name = "list-users"
+
+	routesInfo := httpserver.NewRoutesInfo()
+	routesInfo.Add(httpserver.RouteInfo{
+		Method: http.MethodGet,
+		Path:   "/users",
+		Tags:   []string{"users"},
+	})
+
+	var core *fxcore.Core
+
+	fxcore.NewBootstrapper().RunTestApp(t, fx.Supply(httpServer, routesInfo), fx.Populate(&core))
+
+	// [GET] /debug/routes/http
+	req := httptest.NewRequest(http.MethodGet, "/debug/routes/http", nil)
+	rec := httptest.NewRecorder()
+	core.HttpServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"method":"GET","path":"/users","name":"list-users","tags":["users"]}]`, rec.Body.String())
+
+	// [GET] /
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	rec = httptest.NewRecorder()
+	core.HttpServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `data-url="/debug/routes/http"`)
+}
+
 func TestModuleWithDebugStatsDisabled(t *testing.T) {
 	t.Setenv("APP_CONFIG_PATH", "testdata/config")
 	t.Setenv("STATS_ENABLED", "false")
@@ -0,0 +1,257 @@
+package fxcore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/log"
+	"github.com/rs/zerolog"
+	"go.uber.org/fx"
+)
+
+const (
+	DefaultShutdownTimeout = 5 * time.Second
+	ShutdownProbeName      = "shutdown"
+)
+
+// FxShutdownParticipant is the interface to implement by modules to take part in the core graceful shutdown.
+type FxShutdownParticipant interface {
+	Name() string
+	Shutdown(ctx context.Context) error
+}
+
+// FxShutdownInFlightParticipant is the interface to implement by a [FxShutdownParticipant] to report its in-flight requests.
+type FxShutdownInFlightParticipant interface {
+	InFlight() int64
+}
+
+// FxShutdownDrainParticipant is the interface to implement by a [FxShutdownParticipant] to be notified when the drain starts.
+type FxShutdownDrainParticipant interface {
+	Drain()
+}
+
+// ShutdownCoordinator coordinates the graceful shutdown of the application servers: it flips the readiness to failing,
+// waits for a drain delay, then stops the [FxShutdownParticipant] in order, with a bounded timeout for each.
+type ShutdownCoordinator struct {
+	logger       *log.Logger
+	drainDelay   time.Duration
+	timeout      time.Duration
+	order        []string
+	mutex        sync.RWMutex
+	participants []FxShutdownParticipant
+	draining     atomic.Bool
+	once         sync.Once
+	err          error
+}
+
+// FxShutdownCoordinatorParam allows injection of the required dependencies in [NewFxShutdownCoordinator].
+type FxShutdownCoordinatorParam struct {
+	fx.In
+	Config       *config.Config
+	Logger       *log.Logger
+	Checker      *healthcheck.Checker
+	Participants []any `group:"core-shutdown-participants"`
+}
+
+// NewFxShutdownCoordinator returns a new [ShutdownCoordinator], and registers its readiness [ShutdownProbe] in the
+// [healthcheck.Checker].
+func NewFxShutdownCoordinator(p FxShutdownCoordinatorParam) *ShutdownCoordinator {
+	timeout := p.Config.GetDuration("modules.core.shutdown.timeout")
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+
+	coordinator := &ShutdownCoordinator{
+		logger:     log.FromZerolog(p.Logger.ToZerolog().With().Str("module", ModuleName).Logger()),
+		drainDelay: p.Config.GetDuration("modules.core.shutdown.drain.delay"),
+		timeout:    timeout,
+		order:      p.Config.GetStringSlice("modules.core.shutdown.order"),
+	}
+
+	for _, participant := range p.Participants {
+		if castParticipant, ok := participant.(FxShutdownParticipant); ok {
+			coordinator.Register(castParticipant)
+		}
+	}
+
+	p.Checker.RegisterProbeWithOptions(
+		NewShutdownProbe(coordinator),
+		healthcheck.Readiness,
+		// never served from the background execution cache, to fail as soon as the drain starts
+		healthcheck.WithProbeCached(false),
+	)
+
+	return coordinator
+}
+
+// RegisterShutdownCoordinator registers the [ShutdownCoordinator] in the application lifecycle.
+// It must be invoked after all servers are created, for its stop hook to be executed before theirs.
+func RegisterShutdownCoordinator(lc fx.Lifecycle, coordinator *ShutdownCoordinator) {
+	lc.Append(fx.Hook{
+		OnStop: coordinator.Shutdown,
+	})
+}
+
+// Register registers a [FxShutdownParticipant] in the [ShutdownCoordinator].
+func (c *ShutdownCoordinator) Register(participant FxShutdownParticipant) *ShutdownCoordinator {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.participants = append(c.participants, participant)
+
+	return c
+}
+
+// Participants returns the registered [FxShutdownParticipant], in their stop order.
+// The configured order is applied first, then the remaining participants are ordered by name, the core being stopped last.
+func (c *ShutdownCoordinator) Participants() []FxShutdownParticipant {
+	c.mutex.RLock()
+	participants := make([]FxShutdownParticipant, len(c.participants))
+	copy(participants, c.participants)
+	c.mutex.RUnlock()
+
+	sort.SliceStable(participants, func(i, j int) bool {
+		iPosition, jPosition := c.position(participants[i].Name()), c.position(participants[j].Name())
+		if iPosition != jPosition {
+			return iPosition < jPosition
+		}
+
+		return participants[i].Name() < participants[j].Name()
+	})
+
+	return participants
+}
+
+// Draining returns true if the [ShutdownCoordinator] shutdown has started.
+func (c *ShutdownCoordinator) Draining() bool {
+	return c.draining.Load()
+}
+
+// Shutdown performs the graceful shutdown.
+// Only the first call performs the shutdown, next calls return its result.
+func (c *ShutdownCoordinator) Shutdown(ctx context.Context) error {
+	c.once.Do(func() {
+		c.err = c.shutdown(ctx)
+	})
+
+	return c.err
+}
+
+func (c *ShutdownCoordinator) shutdown(ctx context.Context) error {
+	participants := c.Participants()
+
+	c.draining.Store(true)
+
+	for _, participant := range participants {
+		if drainParticipant, ok := participant.(FxShutdownDrainParticipant); ok {
+			drainParticipant.Drain()
+		}
+	}
+
+	c.logger.
+		Info().
+		Dur("drain_delay", c.drainDelay).
+		Dict("in_flight", c.inFlight(participants)).
+		Msg("shutdown drain started")
+
+	if c.drainDelay > 0 {
+		timer := time.NewTimer(c.drainDelay)
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	c.logger.
+		Info().
+		Dict("in_flight", c.inFlight(participants)).
+		Msg("shutdown drain ended")
+
+	var errs []error
+
+	for _, participant := range participants {
+		evt := c.logger.Info().Str("participant", participant.Name())
+		if inFlightParticipant, ok := participant.(FxShutdownInFlightParticipant); ok {
+			evt.Int64("in_flight", inFlightParticipant.InFlight())
+		}
+
+		evt.Msg("shutdown participant stopping")
+
+		participantCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		err := participant.Shutdown(participantCtx)
+		cancel()
+
+		if err != nil {
+			c.logger.Warn().Err(err).Str("participant", participant.Name()).Msg("shutdown participant forced to stop")
+
+			errs = append(errs, fmt.Errorf("failed to gracefully stop %s: %w", participant.Name(), err))
+		} else {
+			c.logger.Info().Str("participant", participant.Name()).Msg("shutdown participant stopped")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *ShutdownCoordinator) position(name string) int {
+	if name == ModuleName {
+		return math.MaxInt
+	}
+
+	for position, orderedName := range c.order {
+		if strings.EqualFold(orderedName, name) {
+			return position
+		}
+	}
+
+	return len(c.order)
+}
+
+func (c *ShutdownCoordinator) inFlight(participants []FxShutdownParticipant) *zerolog.Event {
+	dict := zerolog.Dict()
+
+	for _, participant := range participants {
+		if inFlightParticipant, ok := participant.(FxShutdownInFlightParticipant); ok {
+			dict.Int64(participant.Name(), inFlightParticipant.InFlight())
+		}
+	}
+
+	return dict
+}
+
+// ShutdownProbe is a [healthcheck.CheckerProbe] failing while the [ShutdownCoordinator] is draining.
+type ShutdownProbe struct {
+	coordinator *ShutdownCoordinator
+}
+
+// NewShutdownProbe returns a new [ShutdownProbe].
+func NewShutdownProbe(coordinator *ShutdownCoordinator) *ShutdownProbe {
+	return &ShutdownProbe{
+		coordinator: coordinator,
+	}
+}
+
+// Name returns the name of the [ShutdownProbe].
+func (p *ShutdownProbe) Name() string {
+	return ShutdownProbeName
+}
+
+// Check returns a failed [healthcheck.CheckerProbeResult] if the [ShutdownCoordinator] is draining.
+func (p *ShutdownProbe) Check(context.Context) *healthcheck.CheckerProbeResult {
+	if p.coordinator.Draining() {
+		return healthcheck.NewCheckerProbeResult(false, "application is shutting down")
+	}
+
+	return healthcheck.NewCheckerProbeResult(true, "application is running")
+}
@@ -0,0 +1,146 @@
+package fxcore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/fxcore"
+	"github.com/ankorstore/yokai/fxcore/testdata/shutdown"
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+func TestShutdownCoordinator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("SHUTDOWN_DRAIN_DELAY", "50ms")
+
+	recorder := shutdown.NewRecorder()
+
+	fooParticipant := shutdown.NewTestParticipant("foo", recorder, false)
+	barParticipant := shutdown.NewTestParticipant("bar", recorder, false)
+	bazParticipant := shutdown.NewTestParticipant("baz", recorder, true)
+
+	var checker *healthcheck.Checker
+	var coordinator *fxcore.ShutdownCoordinator
+	var logBuffer logtest.TestLogBuffer
+
+	app := fxcore.NewBootstrapper().BootstrapTestApp(
+		t,
+		fx.Supply(
+			fx.Annotate(fooParticipant, fx.As(new(any)), fx.ResultTags(`group:"core-shutdown-participants"`)),
+			fx.Annotate(barParticipant, fx.As(new(any)), fx.ResultTags(`group:"core-shutdown-participants"`)),
+			fx.Annotate(bazParticipant, fx.As(new(any)), fx.ResultTags(`group:"core-shutdown-participants"`)),
+		),
+		fx.Populate(&checker, &coordinator, &logBuffer),
+	).RequireStart()
+
+	// stop order: configured order, then by name, core last
+	var names []string
+	for _, participant := range coordinator.Participants() {
+		names = append(names, participant.Name())
+	}
+
+	assert.Equal(t, []string{"bar", "foo", "baz", fxcore.ModuleName}, names)
+
+	// readiness before shutdown
+	result := checker.Check(context.Background(), healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.Equal(t, "application is running", result.ProbesResults[fxcore.ShutdownProbeName].Message)
+	assert.False(t, coordinator.Draining())
+
+	// shutdown
+	err := app.Stop(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to gracefully stop baz: context deadline exceeded")
+
+	assert.Equal(t, []string{"bar", "foo", "baz"}, recorder.Names())
+	assert.True(t, fooParticipant.Drained())
+	assert.True(t, barParticipant.Drained())
+	assert.True(t, bazParticipant.Drained())
+
+	// next shutdown calls return the first shutdown result
+	assert.Equal(t, err.Error(), coordinator.Shutdown(context.Background()).Error())
+	assert.Equal(t, []string{"bar", "foo", "baz"}, recorder.Names())
+
+	// readiness after shutdown
+	assert.True(t, coordinator.Draining())
+
+	result = checker.Check(context.Background(), healthcheck.Readiness)
+	assert.False(t, result.Success)
+	assert.Equal(t, "application is shutting down", result.ProbesResults[fxcore.ShutdownProbeName].Message)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "info",
+		"module":  "core",
+		"message": "shutdown drain started",
+	})
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":       "info",
+		"module":      "core",
+		"participant": "foo",
+		"in_flight":   1,
+		"message":     "shutdown participant stopping",
+	})
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":       "info",
+		"module":      "core",
+		"participant": "foo",
+		"message":     "shutdown participant stopped",
+	})
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":       "warn",
+		"module":      "core",
+		"participant": "baz",
+		"message":     "shutdown participant forced to stop",
+	})
+}
+
+func TestShutdownCoordinatorWithHealthCheckBackgroundExecution(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("SHUTDOWN_DRAIN_DELAY", "10ms")
+	t.Setenv("HEALTHCHECK_BACKGROUND_ENABLED", "true")
+
+	var checker *healthcheck.Checker
+	var coordinator *fxcore.ShutdownCoordinator
+
+	app := fxcore.NewBootstrapper().BootstrapTestApp(
+		t,
+		fx.Populate(&checker, &coordinator),
+	).RequireStart()
+
+	// let the first background execution complete
+	time.Sleep(50 * time.Millisecond)
+
+	result := checker.Check(context.Background(), healthcheck.Readiness)
+	assert.True(t, result.Success)
+
+	err := app.Stop(context.Background())
+	assert.NoError(t, err)
+
+	// the shutdown probe result is never served from the background execution cache
+	result = checker.Check(context.Background(), healthcheck.Readiness)
+	assert.False(t, result.Success)
+	assert.Equal(t, "application is shutting down", result.ProbesResults[fxcore.ShutdownProbeName].Message)
+}
+
+func TestShutdownCoordinatorWithoutDrainDelay(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var checker *healthcheck.Checker
+	var coordinator *fxcore.ShutdownCoordinator
+
+	fxcore.NewBootstrapper().RunTestApp(t, fx.Populate(&checker, &coordinator))
+
+	assert.True(t, coordinator.Draining())
+
+	// the readiness is flipped, without waiting for a drain delay
+	result := checker.Check(context.Background(), healthcheck.Readiness)
+	assert.False(t, result.Success)
+	assert.Equal(t, "application is shutting down", result.ProbesResults[fxcore.ShutdownProbeName].Message)
+}
@@ -0,0 +1,73 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type Recorder struct {
+	mutex sync.Mutex
+	names []string
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Record(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.names = append(r.names, name)
+}
+
+func (r *Recorder) Names() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.names
+}
+
+type TestParticipant struct {
+	name     string
+	recorder *Recorder
+	blocking bool
+	drained  atomic.Bool
+}
+
+func NewTestParticipant(name string, recorder *Recorder, blocking bool) *TestParticipant {
+	return &TestParticipant{
+		name:     name,
+		recorder: recorder,
+		blocking: blocking,
+	}
+}
+
+func (p *TestParticipant) Name() string {
+	return p.name
+}
+
+func (p *TestParticipant) InFlight() int64 {
+	return 1
+}
+
+func (p *TestParticipant) Drain() {
+	p.drained.Store(true)
+}
+
+func (p *TestParticipant) Drained() bool {
+	return p.drained.Load()
+}
+
+func (p *TestParticipant) Shutdown(ctx context.Context) error {
+	p.recorder.Record(p.name)
+
+	if p.blocking {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	return nil
+}
@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/ankorstore/yokai/config"
-	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/log"
 	"github.com/ankorstore/yokai/trace"
 	"github.com/go-co-op/gocron/v2"
@@ -45,7 +45,7 @@ var FxCronModule = fx.Module(
 type FxCronParam struct {
 	fx.In
 	LifeCycle       fx.Lifecycle
-	Generator       uuid.UuidGenerator
+	Generator       correlation.CorrelationIdGenerator
 	TracerProvider  oteltrace.TracerProvider
 	Factory         CronSchedulerFactory
 	Config          *config.Config
@@ -0,0 +1,35 @@
+package ksuid
+
+import (
+	ksuidtest "github.com/ankorstore/yokai/generate/generatetest/ksuid"
+	"github.com/ankorstore/yokai/generate/ksuid"
+	"go.uber.org/fx"
+)
+
+// FxTestKsuidGeneratorFactoryParam is used to retrieve the provided generate-test-ksuid-value from Fx.
+type FxTestKsuidGeneratorFactoryParam struct {
+	fx.In
+	Value string `name:"generate-test-ksuid-value"`
+}
+
+// TestKsuidGeneratorFactory is a [ksuid.KsuidGeneratorFactory] implementation.
+type TestKsuidGeneratorFactory struct {
+	value string
+}
+
+// NewFxTestKsuidGeneratorFactory returns a new [TestKsuidGeneratorFactory], implementing [ksuid.KsuidGeneratorFactory].
+func NewFxTestKsuidGeneratorFactory(p FxTestKsuidGeneratorFactoryParam) ksuid.KsuidGeneratorFactory {
+	return &TestKsuidGeneratorFactory{
+		value: p.Value,
+	}
+}
+
+// Create returns a new [ksuid.KsuidGenerator].
+func (f *TestKsuidGeneratorFactory) Create() ksuid.KsuidGenerator {
+	generator, err := ksuidtest.NewTestKsuidGenerator(f.value)
+	if err != nil {
+		return nil
+	}
+
+	return generator
+}
@@ -0,0 +1,64 @@
+package ksuid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/fxgenerate"
+	fxgeneratetestksuid "github.com/ankorstore/yokai/fxgenerate/fxgeneratetest/ksuid"
+	testksuid "github.com/ankorstore/yokai/fxgenerate/testdata/ksuid"
+	"github.com/ankorstore/yokai/generate/ksuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTestKsuidGeneratorSuccess(t *testing.T) {
+	t.Parallel()
+
+	var generator ksuid.KsuidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Provide(
+			fx.Annotate(
+				func() string {
+					return testksuid.TestKSUID
+				},
+				fx.ResultTags(`name:"generate-test-ksuid-value"`),
+			),
+		),
+		fx.Decorate(fxgeneratetestksuid.NewFxTestKsuidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, testksuid.TestKSUID, value.String())
+}
+
+func TestTestKsuidGeneratorError(t *testing.T) {
+	t.Parallel()
+
+	var generator ksuid.KsuidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Provide(
+			fx.Annotate(
+				func() string {
+					return "invalid"
+				},
+				fx.ResultTags(`name:"generate-test-ksuid-value"`),
+			),
+		),
+		fx.Decorate(fxgeneratetestksuid.NewFxTestKsuidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	assert.Nil(t, generator)
+}
@@ -0,0 +1,30 @@
+package nanoid
+
+import (
+	nanoidtest "github.com/ankorstore/yokai/generate/generatetest/nanoid"
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"go.uber.org/fx"
+)
+
+// FxTestNanoidGeneratorFactoryParam is used to retrieve the provided generate-test-nanoid-value from Fx.
+type FxTestNanoidGeneratorFactoryParam struct {
+	fx.In
+	Value string `name:"generate-test-nanoid-value"`
+}
+
+// TestNanoidGeneratorFactory is a [nanoid.NanoidGeneratorFactory] implementation.
+type TestNanoidGeneratorFactory struct {
+	value string
+}
+
+// NewFxTestNanoidGeneratorFactory returns a new [TestNanoidGeneratorFactory], implementing [nanoid.NanoidGeneratorFactory].
+func NewFxTestNanoidGeneratorFactory(p FxTestNanoidGeneratorFactoryParam) nanoid.NanoidGeneratorFactory {
+	return &TestNanoidGeneratorFactory{
+		value: p.Value,
+	}
+}
+
+// Create returns a new [nanoid.NanoidGenerator], ignoring the provided options.
+func (f *TestNanoidGeneratorFactory) Create(...nanoid.NanoidGeneratorOption) (nanoid.NanoidGenerator, error) {
+	return nanoidtest.NewTestNanoidGenerator(f.value), nil
+}
@@ -0,0 +1,41 @@
+package nanoid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	fxgeneratetestnanoid "github.com/ankorstore/yokai/fxgenerate/fxgeneratetest/nanoid"
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTestNanoidGenerator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "../../testdata/config")
+
+	var generator nanoid.NanoidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Provide(
+			fx.Annotate(
+				func() string {
+					return "some test value"
+				},
+				fx.ResultTags(`name:"generate-test-nanoid-value"`),
+			),
+		),
+		fx.Decorate(fxgeneratetestnanoid.NewFxTestNanoidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "some test value", value)
+}
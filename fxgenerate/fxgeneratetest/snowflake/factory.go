@@ -0,0 +1,30 @@
+package snowflake
+
+import (
+	snowflaketest "github.com/ankorstore/yokai/generate/generatetest/snowflake"
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"go.uber.org/fx"
+)
+
+// FxTestSnowflakeGeneratorFactoryParam is used to retrieve the provided generate-test-snowflake-value from Fx.
+type FxTestSnowflakeGeneratorFactoryParam struct {
+	fx.In
+	Value int64 `name:"generate-test-snowflake-value"`
+}
+
+// TestSnowflakeGeneratorFactory is a [snowflake.SnowflakeGeneratorFactory] implementation.
+type TestSnowflakeGeneratorFactory struct {
+	value int64
+}
+
+// NewFxTestSnowflakeGeneratorFactory returns a new [TestSnowflakeGeneratorFactory], implementing [snowflake.SnowflakeGeneratorFactory].
+func NewFxTestSnowflakeGeneratorFactory(p FxTestSnowflakeGeneratorFactoryParam) snowflake.SnowflakeGeneratorFactory {
+	return &TestSnowflakeGeneratorFactory{
+		value: p.Value,
+	}
+}
+
+// Create returns a new [snowflake.SnowflakeGenerator], ignoring the provided options.
+func (f *TestSnowflakeGeneratorFactory) Create(...snowflake.SnowflakeGeneratorOption) (snowflake.SnowflakeGenerator, error) {
+	return snowflaketest.NewTestSnowflakeGenerator(f.value), nil
+}
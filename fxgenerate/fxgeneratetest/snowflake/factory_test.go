@@ -0,0 +1,41 @@
+package snowflake_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	fxgeneratetestsnowflake "github.com/ankorstore/yokai/fxgenerate/fxgeneratetest/snowflake"
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTestSnowflakeGenerator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "../../testdata/config")
+
+	var generator snowflake.SnowflakeGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Provide(
+			fx.Annotate(
+				func() int64 {
+					return 123
+				},
+				fx.ResultTags(`name:"generate-test-snowflake-value"`),
+			),
+		),
+		fx.Decorate(fxgeneratetestsnowflake.NewFxTestSnowflakeGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(123), value)
+}
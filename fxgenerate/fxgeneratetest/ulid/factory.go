@@ -0,0 +1,35 @@
+package ulid
+
+import (
+	ulidtest "github.com/ankorstore/yokai/generate/generatetest/ulid"
+	"github.com/ankorstore/yokai/generate/ulid"
+	"go.uber.org/fx"
+)
+
+// FxTestUlidGeneratorFactoryParam is used to retrieve the provided generate-test-ulid-value from Fx.
+type FxTestUlidGeneratorFactoryParam struct {
+	fx.In
+	Value string `name:"generate-test-ulid-value"`
+}
+
+// TestUlidGeneratorFactory is a [ulid.UlidGeneratorFactory] implementation.
+type TestUlidGeneratorFactory struct {
+	value string
+}
+
+// NewFxTestUlidGeneratorFactory returns a new [TestUlidGeneratorFactory], implementing [ulid.UlidGeneratorFactory].
+func NewFxTestUlidGeneratorFactory(p FxTestUlidGeneratorFactoryParam) ulid.UlidGeneratorFactory {
+	return &TestUlidGeneratorFactory{
+		value: p.Value,
+	}
+}
+
+// Create returns a new [ulid.UlidGenerator].
+func (f *TestUlidGeneratorFactory) Create() ulid.UlidGenerator {
+	generator, err := ulidtest.NewTestUlidGenerator(f.value)
+	if err != nil {
+		return nil
+	}
+
+	return generator
+}
@@ -0,0 +1,64 @@
+package ulid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/fxgenerate"
+	fxgeneratetestulid "github.com/ankorstore/yokai/fxgenerate/fxgeneratetest/ulid"
+	testulid "github.com/ankorstore/yokai/fxgenerate/testdata/ulid"
+	"github.com/ankorstore/yokai/generate/ulid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestTestUlidGeneratorSuccess(t *testing.T) {
+	t.Parallel()
+
+	var generator ulid.UlidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Provide(
+			fx.Annotate(
+				func() string {
+					return testulid.TestULID
+				},
+				fx.ResultTags(`name:"generate-test-ulid-value"`),
+			),
+		),
+		fx.Decorate(fxgeneratetestulid.NewFxTestUlidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, testulid.TestULID, value.String())
+}
+
+func TestTestUlidGeneratorError(t *testing.T) {
+	t.Parallel()
+
+	var generator ulid.UlidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Provide(
+			fx.Annotate(
+				func() string {
+					return "invalid"
+				},
+				fx.ResultTags(`name:"generate-test-ulid-value"`),
+			),
+		),
+		fx.Decorate(fxgeneratetestulid.NewFxTestUlidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	assert.Nil(t, generator)
+}
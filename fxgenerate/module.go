@@ -1,6 +1,18 @@
 package fxgenerate
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/generate/correlation"
+	"github.com/ankorstore/yokai/generate/ksuid"
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"github.com/ankorstore/yokai/generate/sqids"
+	"github.com/ankorstore/yokai/generate/ulid"
 	"github.com/ankorstore/yokai/generate/uuid"
 	"github.com/ankorstore/yokai/generate/uuidv6"
 	"github.com/ankorstore/yokai/generate/uuidv7"
@@ -28,9 +40,36 @@ var FxGenerateModule = fx.Module(
 			uuidv7.NewDefaultUuidV7GeneratorFactory,
 			fx.As(new(uuidv7.UuidV7GeneratorFactory)),
 		),
+		fx.Annotate(
+			ulid.NewDefaultUlidGeneratorFactory,
+			fx.As(new(ulid.UlidGeneratorFactory)),
+		),
+		fx.Annotate(
+			ksuid.NewDefaultKsuidGeneratorFactory,
+			fx.As(new(ksuid.KsuidGeneratorFactory)),
+		),
+		fx.Annotate(
+			snowflake.NewDefaultSnowflakeGeneratorFactory,
+			fx.As(new(snowflake.SnowflakeGeneratorFactory)),
+		),
+		fx.Annotate(
+			nanoid.NewDefaultNanoidGeneratorFactory,
+			fx.As(new(nanoid.NanoidGeneratorFactory)),
+		),
+		fx.Annotate(
+			sqids.NewDefaultSqidsEncoderFactory,
+			fx.As(new(sqids.SqidsEncoderFactory)),
+		),
 		NewFxUuidGenerator,
 		NewFxUuidV6Generator,
 		NewFxUuidV7Generator,
+		NewFxUlidGenerator,
+		NewFxKsuidGenerator,
+		NewFxSnowflakeGenerator,
+		NewFxNanoidGenerator,
+		NewFxSqidsEncoder,
+		NewFxCorrelationIdGenerator,
+		NewFxCorrelationIdValidator,
 	),
 )
 
@@ -66,3 +105,214 @@ type FxUuidV7GeneratorParam struct {
 func NewFxUuidV7Generator(p FxUuidV7GeneratorParam) uuidv7.UuidV7Generator {
 	return p.Factory.Create()
 }
+
+// FxUlidGeneratorParam allows injection of the required dependencies in [NewFxUlidGenerator].
+type FxUlidGeneratorParam struct {
+	fx.In
+	Factory ulid.UlidGeneratorFactory
+}
+
+// NewFxUlidGenerator returns a [ulid.UlidGenerator].
+func NewFxUlidGenerator(p FxUlidGeneratorParam) ulid.UlidGenerator {
+	return p.Factory.Create()
+}
+
+// FxKsuidGeneratorParam allows injection of the required dependencies in [NewFxKsuidGenerator].
+type FxKsuidGeneratorParam struct {
+	fx.In
+	Factory ksuid.KsuidGeneratorFactory
+}
+
+// NewFxKsuidGenerator returns a [ksuid.KsuidGenerator].
+func NewFxKsuidGenerator(p FxKsuidGeneratorParam) ksuid.KsuidGenerator {
+	return p.Factory.Create()
+}
+
+// FxSnowflakeGeneratorParam allows injection of the required dependencies in [NewFxSnowflakeGenerator].
+type FxSnowflakeGeneratorParam struct {
+	fx.In
+	Factory snowflake.SnowflakeGeneratorFactory
+	Config  *config.Config `optional:"true"`
+}
+
+// NewFxSnowflakeGenerator returns a [snowflake.SnowflakeGenerator], configured by the modules.generate.snowflake config
+// (with the default options if no config is provided).
+func NewFxSnowflakeGenerator(p FxSnowflakeGeneratorParam) (snowflake.SnowflakeGenerator, error) {
+	options, err := snowflakeGeneratorOptions(p.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Factory.Create(options...)
+}
+
+// FxNanoidGeneratorParam allows injection of the required dependencies in [NewFxNanoidGenerator].
+type FxNanoidGeneratorParam struct {
+	fx.In
+	Factory nanoid.NanoidGeneratorFactory
+	Config  *config.Config `optional:"true"`
+}
+
+// NewFxNanoidGenerator returns a [nanoid.NanoidGenerator], configured by the modules.generate.nanoid config
+// (with the default options if no config is provided).
+func NewFxNanoidGenerator(p FxNanoidGeneratorParam) (nanoid.NanoidGenerator, error) {
+	return p.Factory.Create(nanoidGeneratorOptions(p.Config)...)
+}
+
+// FxSqidsEncoderParam allows injection of the required dependencies in [NewFxSqidsEncoder].
+type FxSqidsEncoderParam struct {
+	fx.In
+	Factory sqids.SqidsEncoderFactory
+	Config  *config.Config `optional:"true"`
+}
+
+// NewFxSqidsEncoder returns a [sqids.SqidsEncoder], configured by the modules.generate.sqids config
+// (with the default options if no config is provided).
+func NewFxSqidsEncoder(p FxSqidsEncoderParam) (sqids.SqidsEncoder, error) {
+	var options []sqids.SqidsEncoderOption
+
+	if p.Config == nil {
+		return p.Factory.Create(options...)
+	}
+
+	if p.Config.IsSet("modules.generate.sqids.alphabet") {
+		options = append(options, sqids.WithAlphabet(p.Config.GetString("modules.generate.sqids.alphabet")))
+	}
+
+	if p.Config.IsSet("modules.generate.sqids.min_length") {
+		options = append(options, sqids.WithMinLength(p.Config.GetInt("modules.generate.sqids.min_length")))
+	}
+
+	if p.Config.IsSet("modules.generate.sqids.blocklist") {
+		options = append(options, sqids.WithBlocklist(sqids.DefaultBlocklist(p.Config.GetStringSlice("modules.generate.sqids.blocklist")...)))
+	}
+
+	return p.Factory.Create(options...)
+}
+
+// FxCorrelationIdGeneratorParam allows injection of the required dependencies in [NewFxCorrelationIdGenerator].
+type FxCorrelationIdGeneratorParam struct {
+	fx.In
+	UuidFactory   uuid.UuidGeneratorFactory
+	UuidV6Factory uuidv6.UuidV6GeneratorFactory
+	UuidV7Factory uuidv7.UuidV7GeneratorFactory
+	UlidFactory   ulid.UlidGeneratorFactory
+	KsuidFactory  ksuid.KsuidGeneratorFactory
+	Snowflake     snowflake.SnowflakeGenerator
+	Nanoid        nanoid.NanoidGenerator
+	Config        *config.Config `optional:"true"`
+}
+
+// NewFxCorrelationIdGenerator returns a [correlation.CorrelationIdGenerator], backed by the generator selected
+// in the modules.generate.correlation.generator config (UUID V4 by default).
+// The snowflake and nanoid generators are the ones provided by this module.
+//
+//nolint:cyclop
+func NewFxCorrelationIdGenerator(p FxCorrelationIdGeneratorParam) (correlation.CorrelationIdGenerator, error) {
+	var generatorName string
+	if p.Config != nil {
+		generatorName = p.Config.GetString("modules.generate.correlation.generator")
+	}
+
+	kind := correlation.FetchGeneratorKind(generatorName)
+	if generatorName != "" && kind.String() != strings.ToLower(generatorName) {
+		return nil, fmt.Errorf("invalid correlation id generator %s", generatorName)
+	}
+
+	switch kind {
+	case correlation.UuidV6GeneratorKind:
+		return correlation.NewDefaultCorrelationIdGenerator(
+			correlation.Stringify(p.UuidV6Factory.Create().Generate),
+		), nil
+	case correlation.UuidV7GeneratorKind:
+		return correlation.NewDefaultCorrelationIdGenerator(
+			correlation.Stringify(p.UuidV7Factory.Create().Generate),
+		), nil
+	case correlation.UlidGeneratorKind:
+		return correlation.NewDefaultCorrelationIdGenerator(
+			correlation.Stringify(p.UlidFactory.Create().Generate),
+		), nil
+	case correlation.KsuidGeneratorKind:
+		return correlation.NewDefaultCorrelationIdGenerator(
+			correlation.Stringify(p.KsuidFactory.Create().Generate),
+		), nil
+	case correlation.SnowflakeGeneratorKind:
+		return correlation.NewDefaultCorrelationIdGenerator(func() (string, error) {
+			id, err := p.Snowflake.Generate()
+			if err != nil {
+				return "", err
+			}
+
+			return strconv.FormatInt(id, 10), nil
+		}), nil
+	case correlation.NanoidGeneratorKind:
+		return correlation.NewDefaultCorrelationIdGenerator(p.Nanoid.Generate), nil
+	default:
+		return p.UuidFactory.Create(), nil
+	}
+}
+
+// FxCorrelationIdValidatorParam allows injection of the required dependencies in [NewFxCorrelationIdValidator].
+type FxCorrelationIdValidatorParam struct {
+	fx.In
+	Config *config.Config `optional:"true"`
+}
+
+// NewFxCorrelationIdValidator returns a [correlation.CorrelationIdValidator], configured by the
+// modules.generate.correlation.validation config (without length and format constraints if no config is provided).
+func NewFxCorrelationIdValidator(p FxCorrelationIdValidatorParam) (correlation.CorrelationIdValidator, error) {
+	if p.Config == nil {
+		return correlation.NewDefaultCorrelationIdValidator(0, "")
+	}
+
+	validator, err := correlation.NewDefaultCorrelationIdValidator(
+		p.Config.GetInt("modules.generate.correlation.validation.max_length"),
+		p.Config.GetString("modules.generate.correlation.validation.format"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return validator, nil
+}
+
+func snowflakeGeneratorOptions(cfg *config.Config) ([]snowflake.SnowflakeGeneratorOption, error) {
+	var options []snowflake.SnowflakeGeneratorOption
+
+	if cfg == nil {
+		return options, nil
+	}
+
+	if cfg.IsSet("modules.generate.snowflake.node_id") {
+		options = append(options, snowflake.WithNodeId(cfg.GetInt64("modules.generate.snowflake.node_id")))
+	}
+
+	if cfg.IsSet("modules.generate.snowflake.epoch") {
+		epoch, err := time.Parse(time.RFC3339, cfg.GetString("modules.generate.snowflake.epoch"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid snowflake epoch: %w", err)
+		}
+
+		options = append(options, snowflake.WithEpoch(epoch))
+	}
+
+	return options, nil
+}
+
+func nanoidGeneratorOptions(cfg *config.Config) []nanoid.NanoidGeneratorOption {
+	var options []nanoid.NanoidGeneratorOption
+
+	if cfg == nil {
+		return options
+	}
+
+	if cfg.IsSet("modules.generate.nanoid.alphabet") {
+		options = append(options, nanoid.WithAlphabet(cfg.GetString("modules.generate.nanoid.alphabet")))
+	}
+
+	if cfg.IsSet("modules.generate.nanoid.size") {
+		options = append(options, nanoid.WithSize(cfg.GetInt("modules.generate.nanoid.size")))
+	}
+
+	return options
+}
@@ -1,16 +1,34 @@
 package fxgenerate_test
 
 import (
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ankorstore/yokai/fxconfig"
 	"github.com/ankorstore/yokai/fxgenerate"
+	testksuid "github.com/ankorstore/yokai/fxgenerate/testdata/ksuid"
+	testnanoid "github.com/ankorstore/yokai/fxgenerate/testdata/nanoid"
+	testsnowflake "github.com/ankorstore/yokai/fxgenerate/testdata/snowflake"
+	testsqids "github.com/ankorstore/yokai/fxgenerate/testdata/sqids"
+	testulid "github.com/ankorstore/yokai/fxgenerate/testdata/ulid"
 	testuuid "github.com/ankorstore/yokai/fxgenerate/testdata/uuid"
 	testuuidv6 "github.com/ankorstore/yokai/fxgenerate/testdata/uuidv6"
 	testuuidv7 "github.com/ankorstore/yokai/fxgenerate/testdata/uuidv7"
+	"github.com/ankorstore/yokai/generate/correlation"
+	snowflaketest "github.com/ankorstore/yokai/generate/generatetest/snowflake"
+	"github.com/ankorstore/yokai/generate/ksuid"
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"github.com/ankorstore/yokai/generate/sqids"
+	"github.com/ankorstore/yokai/generate/ulid"
 	"github.com/ankorstore/yokai/generate/uuid"
 	"github.com/ankorstore/yokai/generate/uuidv6"
 	"github.com/ankorstore/yokai/generate/uuidv7"
 	googleuuid "github.com/google/uuid"
+	oklogulid "github.com/oklog/ulid/v2"
+	segmentioksuid "github.com/segmentio/ksuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxtest"
@@ -162,3 +180,596 @@ func TestModuleUuidV7GeneratorDecoration(t *testing.T) {
 
 	assert.Equal(t, testuuidv7.TestUUIDV7, value.String())
 }
+
+func TestModuleUlidGenerator(t *testing.T) {
+	t.Parallel()
+
+	var generator ulid.UlidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, value1, value2)
+
+	parsedValue1, err := oklogulid.Parse(value1.String())
+	assert.NoError(t, err)
+
+	parsedValue2, err := oklogulid.Parse(value2.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, value1.String(), parsedValue1.String())
+	assert.Equal(t, value2.String(), parsedValue2.String())
+}
+
+func TestModuleKsuidGenerator(t *testing.T) {
+	t.Parallel()
+
+	var generator ksuid.KsuidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, value1, value2)
+
+	parsedValue1, err := segmentioksuid.Parse(value1.String())
+	assert.NoError(t, err)
+
+	parsedValue2, err := segmentioksuid.Parse(value2.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, value1.String(), parsedValue1.String())
+	assert.Equal(t, value2.String(), parsedValue2.String())
+}
+
+func TestModuleSnowflakeGenerator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var generator snowflake.SnowflakeGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Less(t, value1, value2)
+
+	// configured node id
+	assert.Equal(t, int64(12), (value1>>snowflake.SequenceBits)&snowflake.MaxNodeId)
+	assert.Equal(t, int64(12), (value2>>snowflake.SequenceBits)&snowflake.MaxNodeId)
+
+	// configured epoch
+	expectedTimestamp := time.Since(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)).Milliseconds()
+	timestamp := value1 >> (snowflake.NodeIdBits + snowflake.SequenceBits)
+	assert.InDelta(t, expectedTimestamp, timestamp, float64(time.Minute.Milliseconds()))
+}
+
+func TestModuleSnowflakeGeneratorWithInvalidEpoch(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("MODULES_GENERATE_SNOWFLAKE_EPOCH", "invalid")
+
+	var generator snowflake.SnowflakeGenerator
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	)
+
+	err := app.Err()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid snowflake epoch")
+}
+
+func TestModuleSnowflakeGeneratorWithoutConfig(t *testing.T) {
+	t.Parallel()
+
+	var generator snowflake.SnowflakeGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	// default node id
+	assert.Equal(t, snowflake.DefaultSnowflakeGeneratorOptions().NodeId, (value>>snowflake.SequenceBits)&snowflake.MaxNodeId)
+}
+
+func TestModuleNanoidGenerator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var generator nanoid.NanoidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, value1, value2)
+
+	// configured alphabet and size
+	for _, value := range []string{value1, value2} {
+		assert.Len(t, value, 10)
+		assert.Empty(t, strings.Trim(value, "0123456789abcdef"))
+	}
+}
+
+func TestModuleNanoidGeneratorWithoutConfig(t *testing.T) {
+	t.Parallel()
+
+	var generator nanoid.NanoidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	// default size
+	assert.Len(t, value, nanoid.DefaultNanoidGeneratorOptions().Size)
+}
+
+func TestModuleNanoidGeneratorWithInvalidSize(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("MODULES_GENERATE_NANOID_SIZE", "0")
+
+	var generator nanoid.NanoidGenerator
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	)
+
+	err := app.Err()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid nanoid size, must be positive")
+}
+
+func TestModuleUlidGeneratorDecoration(t *testing.T) {
+	t.Parallel()
+
+	var generator ulid.UlidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Decorate(testulid.NewTestStaticUlidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, testulid.TestULID, value.String())
+}
+
+func TestModuleKsuidGeneratorDecoration(t *testing.T) {
+	t.Parallel()
+
+	var generator ksuid.KsuidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Decorate(testksuid.NewTestStaticKsuidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, testksuid.TestKSUID, value.String())
+}
+
+func TestModuleSnowflakeGeneratorDecoration(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var generator snowflake.SnowflakeGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Decorate(testsnowflake.NewTestStaticSnowflakeGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, testsnowflake.TestSnowflake, value)
+}
+
+func TestModuleNanoidGeneratorDecoration(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var generator nanoid.NanoidGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Decorate(testnanoid.NewTestStaticNanoidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	value, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "static", value)
+}
+
+func TestModuleSqidsEncoder(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var encoder sqids.SqidsEncoder
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&encoder),
+	).RequireStart().RequireStop()
+
+	id, err := encoder.Encode(42)
+	assert.NoError(t, err)
+
+	// configured alphabet and min length
+	assert.Len(t, id, 8)
+	assert.Empty(t, strings.Trim(id, "abcdefghijklmnopqrstuvwxyz0123456789"))
+
+	numbers, err := encoder.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{42}, numbers)
+}
+
+func TestModuleSqidsEncoderWithoutConfig(t *testing.T) {
+	t.Parallel()
+
+	var encoder sqids.SqidsEncoder
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&encoder),
+	).RequireStart().RequireStop()
+
+	id, err := encoder.Encode(42)
+	assert.NoError(t, err)
+
+	decoded, err := encoder.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{42}, decoded)
+}
+
+func TestModuleSqidsEncoderWithInvalidMinLength(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("MODULES_GENERATE_SQIDS_MIN_LENGTH", "-1")
+
+	var encoder sqids.SqidsEncoder
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&encoder),
+	)
+
+	err := app.Err()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid sqids min length -1, must be between 0 and 255")
+}
+
+func TestModuleSqidsEncoderDecoration(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var encoder sqids.SqidsEncoder
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Decorate(testsqids.NewTestStaticSqidsEncoderFactory),
+		fx.Populate(&encoder),
+	).RequireStart().RequireStop()
+
+	id, err := encoder.Encode(42)
+	assert.NoError(t, err)
+
+	assert.Empty(t, strings.Trim(id, "0123456789"))
+}
+
+func TestModuleCorrelationIdGenerator(t *testing.T) {
+	tests := []struct {
+		generator string
+		assertion func(tb testing.TB, value string)
+	}{
+		{
+			generator: "",
+			assertion: func(tb testing.TB, value string) {
+				tb.Helper()
+
+				parsedValue, err := googleuuid.Parse(value)
+				assert.NoError(tb, err)
+				assert.Equal(tb, googleuuid.Version(4), parsedValue.Version())
+			},
+		},
+		{
+			generator: "uuidv6",
+			assertion: func(tb testing.TB, value string) {
+				tb.Helper()
+
+				parsedValue, err := googleuuid.Parse(value)
+				assert.NoError(tb, err)
+				assert.Equal(tb, googleuuid.Version(6), parsedValue.Version())
+			},
+		},
+		{
+			generator: "uuidv7",
+			assertion: func(tb testing.TB, value string) {
+				tb.Helper()
+
+				parsedValue, err := googleuuid.Parse(value)
+				assert.NoError(tb, err)
+				assert.Equal(tb, googleuuid.Version(7), parsedValue.Version())
+			},
+		},
+		{
+			generator: "ulid",
+			assertion: func(tb testing.TB, value string) {
+				tb.Helper()
+
+				_, err := oklogulid.ParseStrict(value)
+				assert.NoError(tb, err)
+			},
+		},
+		{
+			generator: "ksuid",
+			assertion: func(tb testing.TB, value string) {
+				tb.Helper()
+
+				_, err := segmentioksuid.Parse(value)
+				assert.NoError(tb, err)
+			},
+		},
+		{
+			generator: "snowflake",
+			assertion: func(tb testing.TB, value string) {
+				tb.Helper()
+
+				id, err := strconv.ParseInt(value, 10, 64)
+				assert.NoError(tb, err)
+				assert.Equal(tb, int64(12), (id>>snowflake.SequenceBits)&snowflake.MaxNodeId)
+			},
+		},
+		{
+			generator: "nanoid",
+			assertion: func(tb testing.TB, value string) {
+				tb.Helper()
+
+				assert.Len(tb, value, 10)
+				assert.Empty(tb, strings.Trim(value, "0123456789abcdef"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.generator, func(t *testing.T) {
+			t.Setenv("APP_CONFIG_PATH", "testdata/config")
+			t.Setenv("CORRELATION_GENERATOR", tt.generator)
+
+			var generator correlation.CorrelationIdGenerator
+
+			fxtest.New(
+				t,
+				fx.NopLogger,
+				fxconfig.FxConfigModule,
+				fxgenerate.FxGenerateModule,
+				fx.Populate(&generator),
+			).RequireStart().RequireStop()
+
+			value1 := generator.Generate()
+			value2 := generator.Generate()
+
+			assert.NotEqual(t, value1, value2)
+
+			tt.assertion(t, value1)
+			tt.assertion(t, value2)
+		})
+	}
+}
+
+func TestModuleCorrelationIdGeneratorWithInvalidSnowflakeEpoch(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("CORRELATION_GENERATOR", "snowflake")
+	t.Setenv("MODULES_GENERATE_SNOWFLAKE_EPOCH", "invalid")
+
+	var generator correlation.CorrelationIdGenerator
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	)
+
+	err := app.Err()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid snowflake epoch")
+}
+
+func TestModuleCorrelationIdGeneratorWithSnowflakeGenerator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("CORRELATION_GENERATOR", "snowflake")
+
+	var generator correlation.CorrelationIdGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Decorate(func(snowflake.SnowflakeGenerator) snowflake.SnowflakeGenerator {
+			return snowflaketest.NewTestSnowflakeGenerator(testsnowflake.TestSnowflake)
+		}),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	// the module snowflake generator is reused
+	assert.Equal(t, strconv.FormatInt(testsnowflake.TestSnowflake, 10), generator.Generate())
+}
+
+func TestModuleCorrelationIdGeneratorWithInvalidGenerator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("CORRELATION_GENERATOR", "invalid")
+
+	var generator correlation.CorrelationIdGenerator
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator),
+	)
+
+	err := app.Err()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid correlation id generator invalid")
+}
+
+func TestModuleCorrelationIdGeneratorWithoutConfig(t *testing.T) {
+	t.Parallel()
+
+	var generator correlation.CorrelationIdGenerator
+	var validator correlation.CorrelationIdValidator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&generator, &validator),
+	).RequireStart().RequireStop()
+
+	value := generator.Generate()
+
+	parsedValue, err := googleuuid.Parse(value)
+	assert.NoError(t, err)
+	assert.Equal(t, googleuuid.Version(4), parsedValue.Version())
+
+	assert.NoError(t, validator.Validate(value))
+}
+
+func TestModuleCorrelationIdGeneratorDecoration(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var generator correlation.CorrelationIdGenerator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Decorate(testuuid.NewTestStaticUuidGeneratorFactory),
+		fx.Populate(&generator),
+	).RequireStart().RequireStop()
+
+	assert.Equal(t, "static", generator.Generate())
+}
+
+func TestModuleCorrelationIdValidator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var validator correlation.CorrelationIdValidator
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&validator),
+	).RequireStart().RequireStop()
+
+	assert.NoError(t, validator.Validate("0191c0a4-6b1e-7c3e-9e4a-1d5f2b3c4d5e"))
+
+	err := validator.Validate(strings.Repeat("a", 65))
+	assert.Error(t, err)
+	assert.Equal(t, "correlation id length 65 exceeds max length 64", err.Error())
+
+	err = validator.Validate("foo bar")
+	assert.Error(t, err)
+	assert.Equal(t, "correlation id does not match format ^[a-zA-Z0-9-]+$", err.Error())
+}
+
+func TestModuleCorrelationIdValidatorWithInvalidFormat(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("MODULES_GENERATE_CORRELATION_VALIDATION_FORMAT", "[")
+
+	var validator correlation.CorrelationIdValidator
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxgenerate.FxGenerateModule,
+		fx.Populate(&validator),
+	)
+
+	err := app.Err()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid correlation id format")
+}
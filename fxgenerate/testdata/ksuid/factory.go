@@ -0,0 +1,21 @@
+package ksuid
+
+import (
+	ksuidtest "github.com/ankorstore/yokai/generate/generatetest/ksuid"
+	"github.com/ankorstore/yokai/generate/ksuid"
+)
+
+const TestKSUID = "2hKpDT9u5eBaL3lkMBhcHoqAQa1"
+
+type TestStaticKsuidGeneratorFactory struct{}
+
+func NewTestStaticKsuidGeneratorFactory() ksuid.KsuidGeneratorFactory {
+	return &TestStaticKsuidGeneratorFactory{}
+}
+
+func (f *TestStaticKsuidGeneratorFactory) Create() ksuid.KsuidGenerator {
+	//nolint:errcheck
+	generator, _ := ksuidtest.NewTestKsuidGenerator(TestKSUID)
+
+	return generator
+}
@@ -0,0 +1,16 @@
+package nanoid
+
+import (
+	nanoidtest "github.com/ankorstore/yokai/generate/generatetest/nanoid"
+	"github.com/ankorstore/yokai/generate/nanoid"
+)
+
+type TestStaticNanoidGeneratorFactory struct{}
+
+func NewTestStaticNanoidGeneratorFactory() nanoid.NanoidGeneratorFactory {
+	return &TestStaticNanoidGeneratorFactory{}
+}
+
+func (f *TestStaticNanoidGeneratorFactory) Create(...nanoid.NanoidGeneratorOption) (nanoid.NanoidGenerator, error) {
+	return nanoidtest.NewTestNanoidGenerator("static"), nil
+}
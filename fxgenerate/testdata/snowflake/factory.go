@@ -0,0 +1,18 @@
+package snowflake
+
+import (
+	snowflaketest "github.com/ankorstore/yokai/generate/generatetest/snowflake"
+	"github.com/ankorstore/yokai/generate/snowflake"
+)
+
+const TestSnowflake = int64(123456789)
+
+type TestStaticSnowflakeGeneratorFactory struct{}
+
+func NewTestStaticSnowflakeGeneratorFactory() snowflake.SnowflakeGeneratorFactory {
+	return &TestStaticSnowflakeGeneratorFactory{}
+}
+
+func (f *TestStaticSnowflakeGeneratorFactory) Create(...snowflake.SnowflakeGeneratorOption) (snowflake.SnowflakeGenerator, error) {
+	return snowflaketest.NewTestSnowflakeGenerator(TestSnowflake), nil
+}
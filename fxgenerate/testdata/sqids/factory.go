@@ -0,0 +1,15 @@
+package sqids
+
+import (
+	"github.com/ankorstore/yokai/generate/sqids"
+)
+
+type TestStaticSqidsEncoderFactory struct{}
+
+func NewTestStaticSqidsEncoderFactory() sqids.SqidsEncoderFactory {
+	return &TestStaticSqidsEncoderFactory{}
+}
+
+func (f *TestStaticSqidsEncoderFactory) Create(...sqids.SqidsEncoderOption) (sqids.SqidsEncoder, error) {
+	return sqids.NewDefaultSqidsEncoder("0123456789", sqids.DefaultMinLength, nil)
+}
@@ -0,0 +1,21 @@
+package ulid
+
+import (
+	ulidtest "github.com/ankorstore/yokai/generate/generatetest/ulid"
+	"github.com/ankorstore/yokai/generate/ulid"
+)
+
+const TestULID = "01HZ5Y3Q1N8W9K2T4V6X7Y8Z9A"
+
+type TestStaticUlidGeneratorFactory struct{}
+
+func NewTestStaticUlidGeneratorFactory() ulid.UlidGeneratorFactory {
+	return &TestStaticUlidGeneratorFactory{}
+}
+
+func (f *TestStaticUlidGeneratorFactory) Create() ulid.UlidGenerator {
+	//nolint:errcheck
+	generator, _ := ulidtest.NewTestUlidGenerator(TestULID)
+
+	return generator
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 
 	"github.com/ankorstore/yokai/config"
-	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/grpcserver"
 	"github.com/ankorstore/yokai/grpcserver/grpcservertest"
 	"github.com/ankorstore/yokai/healthcheck"
@@ -39,6 +39,8 @@ var FxGrpcServerModule = fx.Module(
 		grpcserver.NewDefaultGrpcServerFactory,
 		NewFxGrpcTestBufconnListener,
 		NewFxGrpcServerRegistry,
+		NewFxGrpcHealthCheckService,
+		NewFxGrpcServerShutdownParticipant,
 		NewFxGrpcServer,
 		fx.Annotate(
 			NewFxGrpcDefaultTestBufconnConnectionFactory,
@@ -49,6 +51,12 @@ var FxGrpcServerModule = fx.Module(
 			fx.As(new(interface{})),
 			fx.ResultTags(`group:"core-module-infos"`),
 		),
+		fx.Annotate(
+			func(participant *grpcserver.ShutdownParticipant) any {
+				return participant
+			},
+			fx.ResultTags(`group:"core-shutdown-participants"`),
+		),
 	),
 )
 
@@ -79,17 +87,45 @@ func NewFxGrpcDefaultTestBufconnConnectionFactory(p FxGrpcTestBufconnConnectionF
 	return grpcservertest.NewDefaultTestBufconnConnectionFactory(p.Listener)
 }
 
+// FxGrpcHealthCheckServiceParam allows injection of the required dependencies in [NewFxGrpcHealthCheckService].
+type FxGrpcHealthCheckServiceParam struct {
+	fx.In
+	Checker *healthcheck.Checker
+	Config  *config.Config
+}
+
+// NewFxGrpcHealthCheckService returns a new [grpcserver.GrpcHealthCheckService].
+func NewFxGrpcHealthCheckService(p FxGrpcHealthCheckServiceParam) *grpcserver.GrpcHealthCheckService {
+	options := []grpcserver.GrpcHealthCheckServiceOption{
+		grpcserver.WithWatchInterval(p.Config.GetDuration("modules.grpc.server.healthcheck.watch.interval")),
+	}
+
+	for service, kind := range p.Config.GetStringMapString("modules.grpc.server.healthcheck.services") {
+		options = append(options, grpcserver.WithServiceProbeKind(service, healthcheck.FetchProbeKind(kind)))
+	}
+
+	return grpcserver.NewGrpcHealthCheckService(p.Checker, options...)
+}
+
+// NewFxGrpcServerShutdownParticipant returns a new [grpcserver.ShutdownParticipant], for the core graceful shutdown.
+func NewFxGrpcServerShutdownParticipant() *grpcserver.ShutdownParticipant {
+	return grpcserver.NewShutdownParticipant(ModuleName)
+}
+
 // FxGrpcServerParam allows injection of the required dependencies in [NewFxGrpcBufconnListener].
 type FxGrpcServerParam struct {
 	fx.In
 	LifeCycle       fx.Lifecycle
 	Factory         grpcserver.GrpcServerFactory
-	Generator       uuid.UuidGenerator
+	Generator       correlation.CorrelationIdGenerator
+	Validator       correlation.CorrelationIdValidator
 	Listener        *bufconn.Listener
 	Registry        *GrpcServerRegistry
 	Config          *config.Config
 	Logger          *log.Logger
 	Checker         *healthcheck.Checker
+	HealthCheck     *grpcserver.GrpcHealthCheckService
+	Shutdown        *grpcserver.ShutdownParticipant
 	TracerProvider  trace.TracerProvider
 	MetricsRegistry *prometheus.Registry
 }
@@ -129,7 +165,12 @@ func NewFxGrpcServer(p FxGrpcServerParam) (*grpc.Server, error) {
 
 	// server healthcheck registration
 	if p.Config.GetBool("modules.grpc.server.healthcheck.enabled") {
-		grpcServer.RegisterService(&grpc_health_v1.Health_ServiceDesc, grpcserver.NewGrpcHealthCheckService(p.Checker))
+		grpcServer.RegisterService(&grpc_health_v1.Health_ServiceDesc, p.HealthCheck)
+	}
+
+	// shutdown participant binding (test server is not stopped)
+	if !p.Config.IsTestEnv() {
+		p.Shutdown.Bind(grpcServer, p.HealthCheck)
 	}
 
 	// server services registration
@@ -140,6 +181,9 @@ func NewFxGrpcServer(p FxGrpcServerParam) (*grpc.Server, error) {
 
 	for _, service := range resolvedServices {
 		grpcServer.RegisterService(service.Description(), service.Implementation())
+
+		// registered services are known by the healthcheck Watch RPC
+		p.HealthCheck.SetServingStatus(service.Description().ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
 	}
 
 	// lifecycles
@@ -172,7 +216,7 @@ func NewFxGrpcServer(p FxGrpcServerParam) (*grpc.Server, error) {
 		},
 		OnStop: func(ctx context.Context) error {
 			if !p.Config.IsTestEnv() {
-				grpcServer.GracefulStop()
+				return p.Shutdown.Shutdown(ctx)
 			}
 
 			return nil
@@ -189,12 +233,14 @@ func createInterceptors(p FxGrpcServerParam) ([]grpc.UnaryServerInterceptor, []g
 
 	// interceptors
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		p.Shutdown.UnaryInterceptor(),
 		recovery.UnaryServerInterceptor(
 			recovery.WithRecoveryHandlerContext(panicRecoveryHandler.Handle(p.Config.AppDebug())),
 		),
 	}
 
 	streamInterceptors := []grpc.StreamServerInterceptor{
+		p.Shutdown.StreamInterceptor(),
 		recovery.StreamServerInterceptor(
 			recovery.WithRecoveryHandlerContext(panicRecoveryHandler.Handle(p.Config.AppDebug())),
 		),
@@ -229,6 +275,7 @@ func createInterceptors(p FxGrpcServerParam) ([]grpc.UnaryServerInterceptor, []g
 	// logger
 	loggerInterceptor := grpcserver.
 		NewGrpcLoggerInterceptor(p.Generator, log.FromZerolog(p.Logger.ToZerolog().With().Str("system", ModuleName).Logger())).
+		Validator(p.Validator).
 		Metadata(p.Config.GetStringMapString("modules.grpc.server.log.metadata")).
 		Exclude(p.Config.GetStringSlice("modules.grpc.server.log.exclude")...)
 
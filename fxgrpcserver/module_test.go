@@ -20,6 +20,7 @@ import (
 	"github.com/ankorstore/yokai/fxlog"
 	"github.com/ankorstore/yokai/fxmetrics"
 	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/grpcserver"
 	"github.com/ankorstore/yokai/grpcserver/grpcservertest"
 	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ankorstore/yokai/log/logtest"
@@ -437,6 +438,130 @@ func TestModuleHealthCheck(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestModuleHealthCheckWatch(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("APP_ENV", "test")
+
+	var grpcServer *grpc.Server
+	var healthCheckService *grpcserver.GrpcHealthCheckService
+	var connFactory grpcservertest.TestBufconnConnectionFactory
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxgenerate.FxGenerateModule,
+		fxmetrics.FxMetricsModule,
+		fxhealthcheck.FxHealthcheckModule,
+		fxgrpcserver.FxGrpcServerModule,
+		fx.Provide(service.NewTestServiceDependency),
+		fx.Options(
+			fxgrpcserver.AsGrpcServerService(service.NewTestServiceServer, &proto.Service_ServiceDesc),
+			fxhealthcheck.AsCheckerProbe(probes.NewSuccessProbe),
+			fxhealthcheck.AsCheckerProbe(probes.NewFailureProbe, healthcheck.Liveness),
+		),
+		fx.Populate(&grpcServer, &healthCheckService, &connFactory),
+	).RequireStart().RequireStop()
+
+	defer func() {
+		grpcServer.GracefulStop()
+	}()
+
+	// client preparation
+	conn, err := connFactory.Create(
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// configured service kind: foo.v1.FooService is checked for liveness
+	response, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "foo.v1.FooService"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+
+	// watch of an unknown service
+	unknownStream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "bar.v1.BarService"})
+	assert.NoError(t, err)
+
+	response, err = unknownStream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, response.Status)
+
+	// watch of a registered service, with pushed serving status
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test.Service"})
+	assert.NoError(t, err)
+
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	healthCheckService.SetServingStatus("test.Service", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+}
+
+func TestModuleShutdownParticipant(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("APP_ENV", "test")
+
+	var grpcServer *grpc.Server
+	var participant *grpcserver.ShutdownParticipant
+	var participants []any
+	var connFactory grpcservertest.TestBufconnConnectionFactory
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxgenerate.FxGenerateModule,
+		fxmetrics.FxMetricsModule,
+		fxhealthcheck.FxHealthcheckModule,
+		fxgrpcserver.FxGrpcServerModule,
+		fx.Options(
+			fxhealthcheck.AsCheckerProbe(probes.NewSuccessProbe),
+		),
+		fx.Invoke(func(p struct {
+			fx.In
+			Participants []any `group:"core-shutdown-participants"`
+		}) {
+			participants = p.Participants
+		}),
+		fx.Populate(&grpcServer, &participant, &connFactory),
+	).RequireStart().RequireStop()
+
+	defer func() {
+		grpcServer.GracefulStop()
+	}()
+
+	assert.Equal(t, fxgrpcserver.ModuleName, participant.Name())
+	assert.Contains(t, participants, any(participant))
+
+	// test server is not bound to the participant
+	assert.NoError(t, participant.Shutdown(context.Background()))
+
+	conn, err := connFactory.Create(
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	response, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+	assert.Equal(t, int64(0), participant.InFlight())
+}
+
 func TestModuleDecoration(t *testing.T) {
 	t.Setenv("APP_CONFIG_PATH", "testdata/config")
 	t.Setenv("APP_ENV", "test")
@@ -8,16 +8,32 @@ type CheckerProbeDefinition interface {
 	Kinds() []healthcheck.ProbeKind
 }
 
+// CheckerProbeDefinitionWithOptions is the interface for probes definitions also providing registration options.
+type CheckerProbeDefinitionWithOptions interface {
+	CheckerProbeDefinition
+	Options() []healthcheck.CheckerProbeOption
+}
+
 type checkerProbeDefinition struct {
 	returnType string
-	kinds      []healthcheck.ProbeKind
+	options    []healthcheck.CheckerProbeOption
 }
 
 // NewCheckerProbeDefinition returns a new [CheckerProbeDefinition].
 func NewCheckerProbeDefinition(returnType string, kinds ...healthcheck.ProbeKind) CheckerProbeDefinition {
+	options := make([]healthcheck.CheckerProbeOption, 0, len(kinds))
+	for _, kind := range kinds {
+		options = append(options, kind)
+	}
+
+	return NewCheckerProbeDefinitionWithOptions(returnType, options...)
+}
+
+// NewCheckerProbeDefinitionWithOptions returns a new [CheckerProbeDefinitionWithOptions].
+func NewCheckerProbeDefinitionWithOptions(returnType string, options ...healthcheck.CheckerProbeOption) CheckerProbeDefinitionWithOptions {
 	return &checkerProbeDefinition{
 		returnType: returnType,
-		kinds:      kinds,
+		options:    options,
 	}
 }
 
@@ -28,5 +44,10 @@ func (c *checkerProbeDefinition) ReturnType() string {
 
 // Kinds returns the probe registration kinds.
 func (c *checkerProbeDefinition) Kinds() []healthcheck.ProbeKind {
-	return c.kinds
+	return healthcheck.ResolveCheckerProbeOptions(c.options...).Kinds
+}
+
+// Options returns the probe registration options.
+func (c *checkerProbeDefinition) Options() []healthcheck.CheckerProbeOption {
+	return c.options
 }
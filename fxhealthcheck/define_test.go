@@ -2,6 +2,7 @@ package fxhealthcheck_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/fxhealthcheck"
 	"github.com/ankorstore/yokai/healthcheck"
@@ -17,3 +18,22 @@ func TestNewCheckerProbeDefinition(t *testing.T) {
 	assert.Equal(t, "test", definition.ReturnType())
 	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Liveness, healthcheck.Readiness}, definition.Kinds())
 }
+
+func TestNewCheckerProbeDefinitionWithOptions(t *testing.T) {
+	t.Parallel()
+
+	definition := fxhealthcheck.NewCheckerProbeDefinitionWithOptions(
+		"test",
+		healthcheck.Readiness,
+		healthcheck.WithProbeTimeout(time.Second),
+		healthcheck.WithProbeCritical(false),
+	)
+
+	assert.Equal(t, "test", definition.ReturnType())
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Readiness}, definition.Kinds())
+	assert.Len(t, definition.Options(), 3)
+
+	options := healthcheck.ResolveCheckerProbeOptions(definition.Options()...)
+	assert.Equal(t, time.Second, options.Timeout)
+	assert.False(t, options.Critical)
+}
@@ -0,0 +1,90 @@
+package fxhealthcheck
+
+import (
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CheckerMetrics is the metrics handler for the health check probes executions, implementing [healthcheck.CheckerObserver].
+type CheckerMetrics struct {
+	registered bool
+	namespace  string
+	subsystem  string
+	gauge      *prometheus.GaugeVec
+	histogram  *prometheus.HistogramVec
+}
+
+// NewCheckerMetrics returns a new [CheckerMetrics] instance for provided metrics namespace and subsystem.
+func NewCheckerMetrics(namespace string, subsystem string) *CheckerMetrics {
+	return createCheckerMetrics(namespace, subsystem, prometheus.DefBuckets)
+}
+
+// NewCheckerMetricsWithBuckets returns a new [CheckerMetrics] instance for provided metrics namespace, subsystem and buckets.
+func NewCheckerMetricsWithBuckets(namespace string, subsystem string, buckets []float64) *CheckerMetrics {
+	return createCheckerMetrics(namespace, subsystem, buckets)
+}
+
+// Register allows the [CheckerMetrics] to register against a provided [prometheus.Registry].
+func (m *CheckerMetrics) Register(registry *prometheus.Registry) error {
+	err := registry.Register(m.gauge)
+	if err != nil {
+		return err
+	}
+
+	err = registry.Register(m.histogram)
+	if err != nil {
+		return err
+	}
+
+	m.registered = err == nil
+
+	return err
+}
+
+// ObserveProbeResult observes the status (1 for success, 0 for failure) and the duration of a probe execution.
+func (m *CheckerMetrics) ObserveProbeResult(probeName string, result *healthcheck.CheckerProbeResult) {
+	if m.registered {
+		status := 0.0
+		if result.Success {
+			status = 1.0
+		}
+
+		m.gauge.WithLabelValues(probeName).Set(status)
+		m.histogram.WithLabelValues(probeName).Observe(result.Duration.Seconds())
+	}
+}
+
+func createCheckerMetrics(namespace string, subsystem string, buckets []float64) *CheckerMetrics {
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "healthcheck_probe_status",
+			Help:      "Status of the last health check probe execution (1 for success, 0 for failure)",
+		},
+		[]string{
+			"probe",
+		},
+	)
+
+	histogram := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "healthcheck_probe_duration_seconds",
+			Help:      "Duration of health check probes executions in seconds",
+			Buckets:   buckets,
+		},
+		[]string{
+			"probe",
+		},
+	)
+
+	return &CheckerMetrics{
+		registered: false,
+		namespace:  namespace,
+		subsystem:  subsystem,
+		gauge:      gauge,
+		histogram:  histogram,
+	}
+}
@@ -1,7 +1,13 @@
 package fxhealthcheck
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ankorstore/yokai/config"
 	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/log"
 	"go.uber.org/fx"
 )
 
@@ -23,8 +29,12 @@ var FxHealthcheckModule = fx.Module(
 // FxCheckerParam allows injection of the required dependencies in [NewFxChecker].
 type FxCheckerParam struct {
 	fx.In
-	Factory  healthcheck.CheckerFactory
-	Registry *CheckerProbeRegistry
+	LifeCycle  fx.Lifecycle `optional:"true"`
+	Factory    healthcheck.CheckerFactory
+	Registry   *CheckerProbeRegistry
+	Config     *config.Config `optional:"true"`
+	Logger     *log.Logger    `optional:"true"`
+	HttpClient *http.Client   `optional:"true"`
 }
 
 // NewFxChecker returns a new [healthcheck.Checker].
@@ -35,9 +45,110 @@ func NewFxChecker(p FxCheckerParam) (*healthcheck.Checker, error) {
 	}
 
 	options := []healthcheck.CheckerOption{}
+
+	if p.Config != nil {
+		// probes from config
+		configRegistrations, err := ResolveConfigCheckerProbesRegistrations(p.Config, p.HttpClient)
+		if err != nil {
+			return nil, err
+		}
+
+		registrations = append(registrations, configRegistrations...)
+
+		// background execution
+		if p.Config.GetBool("modules.healthcheck.background.enabled") {
+			options = append(
+				options,
+				healthcheck.WithBackgroundExecution(
+					p.Config.GetDuration("modules.healthcheck.background.interval"),
+					p.Config.GetDuration("modules.healthcheck.background.max_age"),
+				),
+			)
+		}
+	}
+
 	for _, registration := range registrations {
-		options = append(options, healthcheck.WithProbe(registration.Probe(), registration.Kinds()...))
+		options = append(options, healthcheck.WithProbeWithOptions(registration.Probe(), registration.Options()...))
+	}
+
+	checker, err := p.Factory.Create(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.LifeCycle == nil {
+		return checker, nil
+	}
+
+	// startup wait
+	startupWait := p.Config != nil && p.Config.GetBool("modules.healthcheck.startup.wait.enabled")
+
+	var startupWaitOptions []healthcheck.WaitOption
+	if startupWait {
+		startupWaitOptions = createStartupWaitOptions(p)
+	}
+
+	p.LifeCycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if startupWait {
+				_, err := checker.Wait(ctx, healthcheck.Startup, startupWaitOptions...)
+				if err != nil {
+					return err
+				}
+			}
+
+			checker.Start()
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			checker.Stop()
+
+			return nil
+		},
+	})
+
+	return checker, nil
+}
+
+func createStartupWaitOptions(p FxCheckerParam) []healthcheck.WaitOption {
+	options := []healthcheck.WaitOption{
+		healthcheck.WithWaitBackoff(
+			p.Config.GetDuration("modules.healthcheck.startup.wait.backoff.initial"),
+			p.Config.GetDuration("modules.healthcheck.startup.wait.backoff.max"),
+			p.Config.GetFloat64("modules.healthcheck.startup.wait.backoff.multiplier"),
+		),
+	}
+
+	if timeout := p.Config.GetDuration("modules.healthcheck.startup.wait.timeout"); timeout > 0 {
+		options = append(options, healthcheck.WithWaitTimeout(timeout))
+	}
+
+	if p.Logger == nil {
+		return options
 	}
 
-	return p.Factory.Create(options...)
+	logger := log.FromZerolog(p.Logger.ToZerolog().With().Str("module", ModuleName).Logger())
+
+	options = append(
+		options,
+		healthcheck.WithWaitAttemptHandler(func(attempt healthcheck.WaitAttempt) {
+			if attempt.Result.Success {
+				logger.Info().Int("attempt", attempt.Number).Msg("startup checks success")
+
+				return
+			}
+
+			evt := logger.Warn().Int("attempt", attempt.Number).Dur("retry_in", attempt.NextWait)
+			for probeName, probeResult := range attempt.Result.ProbesResults {
+				if !probeResult.Success {
+					evt.Str(probeName, fmt.Sprintf("success: %v, message: %s", probeResult.Success, probeResult.Message))
+				}
+			}
+
+			evt.Msg("startup checks failure")
+		}),
+	)
+
+	return options
 }
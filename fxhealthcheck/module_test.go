@@ -3,12 +3,19 @@ package fxhealthcheck_test
 import (
 	"context"
 	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/ankorstore/yokai/fxconfig"
 	"github.com/ankorstore/yokai/fxhealthcheck"
 	"github.com/ankorstore/yokai/fxhealthcheck/testdata/factory"
 	"github.com/ankorstore/yokai/fxhealthcheck/testdata/probes"
+	"github.com/ankorstore/yokai/fxlog"
 	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/log/logtest"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxtest"
@@ -38,8 +45,8 @@ func TestModule(t *testing.T) {
 
 	data, err := json.Marshal(result)
 	assert.Nil(t, err)
-	assert.Equal(t,
-		`{"success":true,"probes":{"successProbe":{"success":true,"message":"some success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":true,"status":"healthy","probes":\{"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		string(data),
 	)
 
@@ -49,8 +56,8 @@ func TestModule(t *testing.T) {
 
 	data, err = json.Marshal(result)
 	assert.Nil(t, err)
-	assert.Equal(t,
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"some failure"},"successProbe":{"success":true,"message":"some success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		string(data),
 	)
 
@@ -60,12 +67,226 @@ func TestModule(t *testing.T) {
 
 	data, err = json.Marshal(result)
 	assert.Nil(t, err)
-	assert.Equal(t,
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"some failure"},"successProbe":{"success":true,"message":"some success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		string(data),
 	)
 }
 
+func TestModuleWithProbesOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var checker *healthcheck.Checker
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxhealthcheck.FxHealthcheckModule,
+		fx.Options(
+			fxhealthcheck.AsCheckerProbe(probes.NewSuccessProbe),
+			fxhealthcheck.AsCheckerProbe(
+				probes.NewFailureProbe,
+				healthcheck.Readiness,
+				healthcheck.WithProbeTimeout(time.Second),
+				healthcheck.WithProbeCritical(false),
+			),
+		),
+		fx.Populate(&checker),
+	).RequireStart().RequireStop()
+
+	// liveness probes checks
+	result := checker.Check(ctx, healthcheck.Liveness)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Healthy, result.Status)
+	assert.Len(t, result.ProbesResults, 1)
+
+	// readiness probes checks: non-critical failureProbe
+	result = checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Degraded, result.Status)
+	assert.Len(t, result.ProbesResults, 2)
+	assert.False(t, result.ProbesResults["failureProbe"].Success)
+	assert.False(t, result.ProbesResults["failureProbe"].Critical)
+}
+
+func TestModuleWithBackgroundExecution(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("HEALTHCHECK_BACKGROUND_ENABLED", "true")
+
+	ctx := context.Background()
+
+	var checker *healthcheck.Checker
+	var counter *probes.Counter
+
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxhealthcheck.FxHealthcheckModule,
+		fx.Provide(probes.NewCounter),
+		fxhealthcheck.AsCheckerProbe(probes.NewCountingProbe),
+		fx.Populate(&checker, &counter),
+	).RequireStart()
+
+	// background executions happen without checks
+	assert.Eventually(t, func() bool {
+		return counter.Count() >= 2
+	}, time.Second, 10*time.Millisecond)
+
+	// checks are served from the cache
+	count := counter.Count()
+	result := checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.True(t, result.ProbesResults["countingProbe"].Success)
+	assert.LessOrEqual(t, counter.Count(), count+1)
+
+	app.RequireStop()
+
+	// background executions are stopped
+	count = counter.Count()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, count, counter.Count())
+}
+
+func TestModuleWithStartupWait(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("HEALTHCHECK_STARTUP_WAIT_ENABLED", "true")
+
+	var checker *healthcheck.Checker
+	var counter *probes.Counter
+	var logBuffer logtest.TestLogBuffer
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxhealthcheck.FxHealthcheckModule,
+		fx.Provide(probes.NewCounter),
+		fxhealthcheck.AsCheckerProbe(probes.NewEventualProbe, healthcheck.Startup),
+		fx.Populate(&checker, &counter, &logBuffer),
+	).RequireStart().RequireStop()
+
+	assert.Equal(t, int64(3), counter.Count())
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":         "warn",
+		"module":        "healthcheck",
+		"attempt":       1,
+		"retry_in":      10,
+		"eventualProbe": "success: false, message: some eventual failure",
+		"message":       "startup checks failure",
+	})
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":    "warn",
+		"module":   "healthcheck",
+		"attempt":  2,
+		"retry_in": 20,
+		"message":  "startup checks failure",
+	})
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "info",
+		"module":  "healthcheck",
+		"attempt": 3,
+		"message": "startup checks success",
+	})
+}
+
+func TestModuleWithStartupWaitTimeout(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("HEALTHCHECK_STARTUP_WAIT_ENABLED", "true")
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxhealthcheck.FxHealthcheckModule,
+		fxhealthcheck.AsCheckerProbe(probes.NewFailureProbe, healthcheck.Startup),
+		fx.Invoke(func(*healthcheck.Checker) {}),
+	)
+
+	err := app.Start(context.Background())
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "startup checks did not succeed after")
+}
+
+func TestModuleWithConfigProbes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//nolint:errcheck
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	//nolint:errcheck
+	defer listener.Close()
+
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("APP_ENV", "probes")
+	t.Setenv("HTTP_PROBE_URL", server.URL)
+	t.Setenv("TCP_PROBE_ADDRESS", listener.Addr().String())
+
+	ctx := context.Background()
+
+	var checker *healthcheck.Checker
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxhealthcheck.FxHealthcheckModule,
+		fx.Populate(&checker),
+	).RequireStart().RequireStop()
+
+	// startup probes checks
+	result := checker.Check(ctx, healthcheck.Startup)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Degraded, result.Status)
+	assert.Len(t, result.ProbesResults, 2)
+	assert.True(t, result.ProbesResults["resolution"].Success)
+	assert.False(t, result.ProbesResults["disk"].Success)
+
+	// liveness probes checks
+	result = checker.Check(ctx, healthcheck.Liveness)
+	assert.True(t, result.Success)
+	assert.Len(t, result.ProbesResults, 4)
+	assert.True(t, result.ProbesResults["port"].Success)
+	assert.True(t, result.ProbesResults["memory"].Success)
+	assert.True(t, result.ProbesResults["goroutines"].Success)
+
+	// readiness probes checks
+	result = checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.Len(t, result.ProbesResults, 3)
+	assert.True(t, result.ProbesResults["upstream"].Success)
+	assert.Equal(t, "http response status 200", result.ProbesResults["upstream"].Message)
+}
+
+func TestModuleWithInvalidConfigProbes(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("APP_ENV", "invalid")
+
+	err := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxhealthcheck.FxHealthcheckModule,
+		fx.Invoke(func(checker *healthcheck.Checker) {}),
+	).Err()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid type "invalid" for healthcheck probe invalid`)
+}
+
 func TestModuleDecoration(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,131 @@
+package fxhealthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/healthcheck/probes"
+	"github.com/ankorstore/yokai/httpclient"
+	httpclienthealthcheck "github.com/ankorstore/yokai/httpclient/healthcheck"
+)
+
+const (
+	HttpProbeType       = "http"
+	TCPProbeType        = "tcp"
+	DNSProbeType        = "dns"
+	DiskProbeType       = "disk"
+	MemoryProbeType     = "memory"
+	GoroutinesProbeType = "goroutines"
+)
+
+// ResolveConfigCheckerProbesRegistrations resolves the [healthcheck.CheckerProbeRegistration] list of the built-in
+// probes configured in modules.healthcheck.probes.
+//
+// The http probes use the provided [http.Client], or a default one if nil.
+func ResolveConfigCheckerProbesRegistrations(
+	cfg *config.Config,
+	client *http.Client,
+) ([]*healthcheck.CheckerProbeRegistration, error) {
+	// names are collected from all keys, since env vars expanded values are not merged in sub maps
+	uniqueNames := map[string]struct{}{}
+	for _, key := range cfg.AllKeys() {
+		if path, ok := strings.CutPrefix(key, "modules.healthcheck.probes."); ok {
+			name, _, _ := strings.Cut(path, ".")
+			uniqueNames[name] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(uniqueNames))
+	for name := range uniqueNames {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	registrations := []*healthcheck.CheckerProbeRegistration{}
+
+	for _, name := range names {
+		key := fmt.Sprintf("modules.healthcheck.probes.%s", name)
+
+		probe, err := createConfigCheckerProbe(cfg, client, name, key)
+		if err != nil {
+			return nil, err
+		}
+
+		registrations = append(
+			registrations,
+			healthcheck.NewCheckerProbeRegistrationWithOptions(probe, ResolveConfigCheckerProbeOptions(cfg, key)...),
+		)
+	}
+
+	return registrations, nil
+}
+
+// ResolveConfigCheckerProbeOptions resolves the [healthcheck.CheckerProbeOption] list configured under the provided
+// key: the probe kinds (key.kinds), timeout (key.timeout) and criticality (key.critical).
+func ResolveConfigCheckerProbeOptions(cfg *config.Config, key string) []healthcheck.CheckerProbeOption {
+	options := []healthcheck.CheckerProbeOption{}
+
+	for _, kind := range cfg.GetStringSlice(key + ".kinds") {
+		options = append(options, healthcheck.FetchProbeKind(kind))
+	}
+
+	if cfg.IsSet(key + ".timeout") {
+		options = append(options, healthcheck.WithProbeTimeout(cfg.GetDuration(key+".timeout")))
+	}
+
+	if cfg.IsSet(key + ".critical") {
+		options = append(options, healthcheck.WithProbeCritical(cfg.GetBool(key+".critical")))
+	}
+
+	return options
+}
+
+//nolint:cyclop
+func createConfigCheckerProbe(
+	cfg *config.Config,
+	client *http.Client,
+	name string,
+	key string,
+) (healthcheck.CheckerProbe, error) {
+	switch probeType := cfg.GetString(key + ".type"); probeType {
+	case HttpProbeType:
+		if client == nil {
+			var err error
+
+			client, err = httpclient.NewDefaultHttpClientFactory().Create()
+			if err != nil {
+				return nil, fmt.Errorf("cannot create http client for healthcheck probe %s: %w", name, err)
+			}
+		}
+
+		probe := httpclienthealthcheck.NewHttpProbe(client, cfg.GetString(key+".url")).SetName(name)
+
+		if method := cfg.GetString(key + ".method"); method != "" {
+			probe.SetMethod(method)
+		}
+
+		return probe.
+			SetExpectedStatus(cfg.GetInt(key + ".expected_status")).
+			SetExpectedBody(cfg.GetString(key + ".expected_body")), nil
+	case TCPProbeType:
+		return probes.NewTCPProbe(cfg.GetString(key + ".address")).SetName(name), nil
+	case DNSProbeType:
+		return probes.NewDNSProbe(cfg.GetString(key + ".host")).SetName(name), nil
+	case DiskProbeType:
+		return probes.NewDiskProbe(cfg.GetString(key + ".path")).
+			SetName(name).
+			SetMinFreeBytes(cfg.GetUint64(key + ".min_free_bytes")).
+			SetMinFreePercent(cfg.GetFloat64(key + ".min_free_percent")), nil
+	case MemoryProbeType:
+		return probes.NewMemoryProbe(cfg.GetUint64(key + ".max_heap_bytes")).SetName(name), nil
+	case GoroutinesProbeType:
+		return probes.NewGoroutinesProbe(cfg.GetInt(key + ".max")).SetName(name), nil
+	default:
+		return nil, fmt.Errorf("invalid type %q for healthcheck probe %s", probeType, name)
+	}
+}
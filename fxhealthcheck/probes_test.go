@@ -0,0 +1,126 @@
+package fxhealthcheck_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/fxhealthcheck"
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveConfigCheckerProbesRegistrations(t *testing.T) {
+	t.Setenv("APP_ENV", "probes")
+	t.Setenv("HTTP_PROBE_URL", "http://localhost")
+	t.Setenv("TCP_PROBE_ADDRESS", "localhost:8080")
+
+	cfg, err := config.NewDefaultConfigFactory().Create(
+		config.WithFilePaths("./testdata/config"),
+	)
+	assert.NoError(t, err)
+
+	registrations, err := fxhealthcheck.ResolveConfigCheckerProbesRegistrations(cfg, nil)
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 6)
+
+	// sorted by name
+	names := []string{}
+	for _, registration := range registrations {
+		names = append(names, registration.Probe().Name())
+	}
+
+	assert.Equal(t, []string{"disk", "goroutines", "memory", "port", "resolution", "upstream"}, names)
+
+	// disk: no kinds (registered for all kinds), non-critical
+	assert.Empty(t, registrations[0].Kinds())
+	assert.False(t, registrations[0].Critical())
+
+	// port: liveness and readiness, with timeout
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Liveness, healthcheck.Readiness}, registrations[3].Kinds())
+	assert.Equal(t, time.Second, registrations[3].Timeout())
+	assert.True(t, registrations[3].Critical())
+}
+
+func TestResolveConfigCheckerProbeOptions(t *testing.T) {
+	t.Setenv("APP_ENV", "probes")
+
+	cfg, err := config.NewDefaultConfigFactory().Create(
+		config.WithFilePaths("./testdata/config"),
+	)
+	assert.NoError(t, err)
+
+	options := healthcheck.ResolveCheckerProbeOptions(
+		fxhealthcheck.ResolveConfigCheckerProbeOptions(cfg, "modules.healthcheck.probes.port")...,
+	)
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Liveness, healthcheck.Readiness}, options.Kinds)
+	assert.Equal(t, time.Second, options.Timeout)
+	assert.True(t, options.Critical)
+
+	options = healthcheck.ResolveCheckerProbeOptions(
+		fxhealthcheck.ResolveConfigCheckerProbeOptions(cfg, "modules.healthcheck.probes.disk")...,
+	)
+	assert.Empty(t, options.Kinds)
+	assert.Equal(t, time.Duration(0), options.Timeout)
+	assert.False(t, options.Critical)
+
+	assert.Empty(t, fxhealthcheck.ResolveConfigCheckerProbeOptions(cfg, "modules.healthcheck.probes.invalid"))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestResolveConfigCheckerProbesRegistrationsWithHttpClient(t *testing.T) {
+	t.Setenv("APP_ENV", "probes")
+	t.Setenv("HTTP_PROBE_URL", "http://upstream.test/health")
+	t.Setenv("TCP_PROBE_ADDRESS", "localhost:8080")
+
+	cfg, err := config.NewDefaultConfigFactory().Create(
+		config.WithFilePaths("./testdata/config"),
+	)
+	assert.NoError(t, err)
+
+	var requestedURL string
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requestedURL = req.URL.String()
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("ok")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	registrations, err := fxhealthcheck.ResolveConfigCheckerProbesRegistrations(cfg, client)
+	assert.NoError(t, err)
+
+	upstream := registrations[5].Probe()
+	assert.Equal(t, "upstream", upstream.Name())
+
+	result := upstream.Check(context.Background())
+	assert.True(t, result.Success)
+	assert.Equal(t, "http://upstream.test/health", requestedURL)
+}
+
+func TestResolveConfigCheckerProbesRegistrationsWithInvalidType(t *testing.T) {
+	t.Setenv("APP_ENV", "invalid")
+
+	cfg, err := config.NewDefaultConfigFactory().Create(
+		config.WithFilePaths("./testdata/config"),
+	)
+	assert.NoError(t, err)
+
+	_, err = fxhealthcheck.ResolveConfigCheckerProbesRegistrations(cfg, nil)
+	assert.Error(t, err)
+	assert.Equal(t, `invalid type "invalid" for healthcheck probe invalid`, err.Error())
+}
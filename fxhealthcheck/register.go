@@ -5,8 +5,9 @@ import (
 	"go.uber.org/fx"
 )
 
-// AsCheckerProbe registers a [healthcheck.CheckerProbe] into Fx.
-func AsCheckerProbe(p any, kinds ...healthcheck.ProbeKind) fx.Option {
+// AsCheckerProbe registers a [healthcheck.CheckerProbe] into Fx, with an optional list of [healthcheck.CheckerProbeOption]
+// (kinds, timeout, criticality).
+func AsCheckerProbe(p any, options ...healthcheck.CheckerProbeOption) fx.Option {
 	return fx.Options(
 		fx.Provide(
 			fx.Annotate(
@@ -17,7 +18,7 @@ func AsCheckerProbe(p any, kinds ...healthcheck.ProbeKind) fx.Option {
 		),
 		fx.Supply(
 			fx.Annotate(
-				NewCheckerProbeDefinition(GetReturnType(p), kinds...),
+				NewCheckerProbeDefinitionWithOptions(GetReturnType(p), options...),
 				fx.As(new(CheckerProbeDefinition)),
 				fx.ResultTags(`group:"healthcheck-probes-definitions"`),
 			),
@@ -9,26 +9,30 @@ import (
 
 // CheckerProbeRegistry is the registry collecting probes and their definitions.
 type CheckerProbeRegistry struct {
-	probes      []healthcheck.CheckerProbe
-	definitions []CheckerProbeDefinition
+	probes        []healthcheck.CheckerProbe
+	definitions   []CheckerProbeDefinition
+	registrations []*healthcheck.CheckerProbeRegistration
 }
 
 // FxCheckerProbeRegistryParam allows injection of the required dependencies in [NewFxCheckerProbeRegistry].
 type FxCheckerProbeRegistryParam struct {
 	fx.In
-	Probes      []healthcheck.CheckerProbe `group:"healthcheck-probes"`
-	Definitions []CheckerProbeDefinition   `group:"healthcheck-probes-definitions"`
+	Probes        []healthcheck.CheckerProbe              `group:"healthcheck-probes"`
+	Definitions   []CheckerProbeDefinition                `group:"healthcheck-probes-definitions"`
+	Registrations []*healthcheck.CheckerProbeRegistration `group:"healthcheck-probes-registrations"`
 }
 
 // NewFxCheckerProbeRegistry returns as new [CheckerProbeRegistry].
 func NewFxCheckerProbeRegistry(p FxCheckerProbeRegistryParam) *CheckerProbeRegistry {
 	return &CheckerProbeRegistry{
-		probes:      p.Probes,
-		definitions: p.Definitions,
+		probes:        p.Probes,
+		definitions:   p.Definitions,
+		registrations: p.Registrations,
 	}
 }
 
-// ResolveCheckerProbesRegistrations resolves [healthcheck.CheckerProbeRegistration] from their definitions.
+// ResolveCheckerProbesRegistrations resolves [healthcheck.CheckerProbeRegistration] from their definitions,
+// followed by the ones directly provided by modules.
 func (r *CheckerProbeRegistry) ResolveCheckerProbesRegistrations() ([]*healthcheck.CheckerProbeRegistration, error) {
 	registrations := []*healthcheck.CheckerProbeRegistration{}
 
@@ -38,10 +42,23 @@ func (r *CheckerProbeRegistry) ResolveCheckerProbesRegistrations() ([]*healthche
 			return nil, err
 		}
 
-		registrations = append(
-			registrations,
-			healthcheck.NewCheckerProbeRegistration(implementation, definition.Kinds()...),
-		)
+		if definitionWithOptions, ok := definition.(CheckerProbeDefinitionWithOptions); ok {
+			registrations = append(
+				registrations,
+				healthcheck.NewCheckerProbeRegistrationWithOptions(implementation, definitionWithOptions.Options()...),
+			)
+		} else {
+			registrations = append(
+				registrations,
+				healthcheck.NewCheckerProbeRegistration(implementation, definition.Kinds()...),
+			)
+		}
+	}
+
+	for _, registration := range r.registrations {
+		if registration != nil {
+			registrations = append(registrations, registration)
+		}
 	}
 
 	return registrations, nil
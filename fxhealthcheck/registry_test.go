@@ -65,3 +65,31 @@ func TestResolveCheckerProbesRegistrationsFailure(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "cannot find checker probe implementation for type invalid", err.Error())
 }
+
+func TestResolveCheckerProbesRegistrationsWithProvidedRegistrations(t *testing.T) {
+	t.Parallel()
+
+	param := fxhealthcheck.FxCheckerProbeRegistryParam{
+		Probes: []healthcheck.CheckerProbe{
+			probes.NewSuccessProbe(),
+		},
+		Definitions: []fxhealthcheck.CheckerProbeDefinition{
+			fxhealthcheck.NewCheckerProbeDefinition("github.com/ankorstore/yokai/fxhealthcheck/testdata/probes.SuccessProbe", healthcheck.Liveness),
+		},
+		Registrations: []*healthcheck.CheckerProbeRegistration{
+			healthcheck.NewCheckerProbeRegistration(probes.NewFailureProbe(), healthcheck.Readiness),
+			nil,
+		},
+	}
+
+	registry := fxhealthcheck.NewFxCheckerProbeRegistry(param)
+
+	registrations, err := registry.ResolveCheckerProbesRegistrations()
+	assert.NoError(t, err)
+
+	assert.Len(t, registrations, 2)
+	assert.IsType(t, &probes.SuccessProbe{}, registrations[0].Probe())
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Liveness}, registrations[0].Kinds())
+	assert.IsType(t, &probes.FailureProbe{}, registrations[1].Probe())
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Readiness}, registrations[1].Kinds())
+}
@@ -0,0 +1,62 @@
+package probes
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+type Counter struct {
+	count atomic.Int64
+}
+
+func NewCounter() *Counter {
+	return &Counter{}
+}
+
+func (c *Counter) Count() int64 {
+	return c.count.Load()
+}
+
+type CountingProbe struct {
+	counter *Counter
+}
+
+func NewCountingProbe(counter *Counter) *CountingProbe {
+	return &CountingProbe{
+		counter: counter,
+	}
+}
+
+func (p *CountingProbe) Name() string {
+	return "countingProbe"
+}
+
+func (p *CountingProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	p.counter.count.Add(1)
+
+	return healthcheck.NewCheckerProbeResult(true, "some counted success")
+}
+
+type EventualProbe struct {
+	counter *Counter
+}
+
+func NewEventualProbe(counter *Counter) *EventualProbe {
+	return &EventualProbe{
+		counter: counter,
+	}
+}
+
+func (p *EventualProbe) Name() string {
+	return "eventualProbe"
+}
+
+func (p *EventualProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	if p.counter.count.Add(1) <= 2 {
+		return healthcheck.NewCheckerProbeResult(false, "some eventual failure")
+	}
+
+	return healthcheck.NewCheckerProbeResult(true, "some eventual success")
+}
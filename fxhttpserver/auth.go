@@ -0,0 +1,265 @@
+package fxhttpserver
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
+	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+// AsAuthenticator registers an [auth.Authenticator] into Fx, tried after the authenticators configured in
+// modules.http.server.auth.
+func AsAuthenticator(authenticator any) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			authenticator,
+			fx.As(new(auth.Authenticator)),
+			fx.ResultTags(`group:"httpserver-authenticators"`),
+		),
+	)
+}
+
+// FxHttpServerAuthenticationParam allows injection of the required dependencies in [NewFxHttpServerAuthentication].
+type FxHttpServerAuthenticationParam struct {
+	fx.In
+	LifeCycle      fx.Lifecycle
+	Config         *config.Config
+	Db             *sql.DB              `optional:"true"`
+	HttpClient     *http.Client         `optional:"true"`
+	Authenticators []auth.Authenticator `group:"httpserver-authenticators"`
+}
+
+// Authentication creates the http server authentication middlewares, sharing the same authenticators.
+type Authentication struct {
+	config         *config.Config
+	authenticators []auth.Authenticator
+}
+
+// NewFxHttpServerAuthentication returns a new [Authentication], with the authenticators configured in
+// modules.http.server.auth (jwt, api_key and basic, in this order), followed by the ones registered with [AsAuthenticator].
+func NewFxHttpServerAuthentication(p FxHttpServerAuthenticationParam) (*Authentication, error) {
+	var authenticators []auth.Authenticator
+
+	if p.Config.GetBool("modules.http.server.auth.jwt.enabled") {
+		jwtAuthenticator, err := createJWTAuthenticator(p.Config, p.HttpClient)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create jwt authenticator: %w", err)
+		}
+
+		authenticators = append(authenticators, jwtAuthenticator)
+	}
+
+	if p.Config.GetBool("modules.http.server.auth.api_key.enabled") {
+		apiKeyAuthenticator, err := createAPIKeyAuthenticator(p)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create api key authenticator: %w", err)
+		}
+
+		authenticators = append(authenticators, apiKeyAuthenticator)
+	}
+
+	if p.Config.GetBool("modules.http.server.auth.basic.enabled") {
+		authenticators = append(authenticators, createBasicAuthenticator(p.Config))
+	}
+
+	authenticators = append(authenticators, p.Authenticators...)
+
+	return &Authentication{
+		config:         p.Config,
+		authenticators: authenticators,
+	}, nil
+}
+
+// Authenticators returns the configured and registered authenticators.
+func (a *Authentication) Authenticators() []auth.Authenticator {
+	return a.authenticators
+}
+
+// GlobalMiddleware returns the global authentication middleware configured in modules.http.server.auth,
+// or nil if disabled.
+func (a *Authentication) GlobalMiddleware() echo.MiddlewareFunc {
+	if !a.config.GetBool("modules.http.server.auth.enabled") {
+		return nil
+	}
+
+	exclude := a.config.GetStringSlice("modules.http.server.auth.exclude")
+
+	return httpservermiddleware.RequestAuthenticationMiddlewareWithConfig(httpservermiddleware.RequestAuthenticationMiddlewareConfig{
+		Skipper: func(c echo.Context) bool {
+			return httpserver.MatchPrefix(exclude, c.Request().URL.Path)
+		},
+		Authenticators: a.authenticators,
+		Required:       a.config.GetBool("modules.http.server.auth.required"),
+	})
+}
+
+// Middleware returns the authorization middleware of a handler: the principal is resolved by the global
+// authentication middleware, or by the handler one if disabled, and must match the provided requirements.
+func (a *Authentication) Middleware(authenticators []string, scopes []string, roles []string) []echo.MiddlewareFunc {
+	var middlewares []echo.MiddlewareFunc
+
+	if !a.config.GetBool("modules.http.server.auth.enabled") {
+		middlewares = append(
+			middlewares,
+			httpservermiddleware.RequestAuthenticationMiddlewareWithConfig(httpservermiddleware.RequestAuthenticationMiddlewareConfig{
+				Authenticators: a.authenticators,
+				Required:       false,
+			}),
+		)
+	}
+
+	return append(
+		middlewares,
+		httpservermiddleware.RequestAuthorizationMiddlewareWithConfig(httpservermiddleware.RequestAuthorizationMiddlewareConfig{
+			Authenticators: authenticators,
+			Scopes:         scopes,
+			Roles:          roles,
+		}),
+	)
+}
+
+func createJWTAuthenticator(cfg *config.Config, client *http.Client) (*auth.JWTAuthenticator, error) {
+	if client == nil {
+		timeout := cfg.GetDuration("modules.http.server.auth.jwt.jwks.timeout")
+		if timeout <= 0 {
+			timeout = auth.DefaultJWKSFetchTimeout
+		}
+
+		client = &http.Client{Timeout: timeout}
+	}
+
+	jwksOptions := []auth.RemoteJWKSOption{auth.WithRemoteJWKSClient(client)}
+	if refreshInterval := cfg.GetDuration("modules.http.server.auth.jwt.jwks.refresh_interval"); refreshInterval > 0 {
+		jwksOptions = append(jwksOptions, auth.WithRemoteJWKSRefreshInterval(refreshInterval))
+	}
+
+	issuer := cfg.GetString("modules.http.server.auth.jwt.issuer")
+
+	var keys auth.KeySet
+	switch {
+	case cfg.GetString("modules.http.server.auth.jwt.jwks.file") != "":
+		jwks, err := auth.NewJWKSFromFile(cfg.GetString("modules.http.server.auth.jwt.jwks.file"))
+		if err != nil {
+			return nil, err
+		}
+
+		keys = jwks
+	case cfg.GetString("modules.http.server.auth.jwt.jwks.url") != "":
+		keys = auth.NewRemoteJWKS(cfg.GetString("modules.http.server.auth.jwt.jwks.url"), jwksOptions...)
+	case cfg.GetBool("modules.http.server.auth.jwt.jwks.discovery") && issuer != "":
+		keys = auth.NewOIDCJWKS(issuer, jwksOptions...)
+	default:
+		return nil, fmt.Errorf("missing jwks file, url or issuer discovery")
+	}
+
+	options := []auth.JWTAuthenticatorOption{
+		auth.WithJWTIssuer(issuer),
+		auth.WithJWTAudience(cfg.GetStringSlice("modules.http.server.auth.jwt.audience")...),
+		auth.WithJWTLeeway(cfg.GetDuration("modules.http.server.auth.jwt.leeway")),
+	}
+
+	if algorithms := cfg.GetStringSlice("modules.http.server.auth.jwt.algorithms"); len(algorithms) > 0 {
+		options = append(options, auth.WithJWTAlgorithms(algorithms...))
+	}
+
+	if claim := cfg.GetString("modules.http.server.auth.jwt.claims.subject"); claim != "" {
+		options = append(options, auth.WithJWTSubjectClaim(claim))
+	}
+
+	if claim := cfg.GetString("modules.http.server.auth.jwt.claims.scopes"); claim != "" {
+		options = append(options, auth.WithJWTScopesClaim(claim))
+	}
+
+	if claim := cfg.GetString("modules.http.server.auth.jwt.claims.roles"); claim != "" {
+		options = append(options, auth.WithJWTRolesClaim(claim))
+	}
+
+	return auth.NewJWTAuthenticator(keys, options...), nil
+}
+
+func createAPIKeyAuthenticator(p FxHttpServerAuthenticationParam) (*auth.APIKeyAuthenticator, error) {
+	var store auth.APIKeyStore
+
+	switch storeType := p.Config.GetString("modules.http.server.auth.api_key.store.type"); storeType {
+	case "", "static":
+		principals := make(map[string]*auth.Principal)
+		for name := range p.Config.GetStringMap("modules.http.server.auth.api_key.keys") {
+			prefix := fmt.Sprintf("modules.http.server.auth.api_key.keys.%s", name)
+
+			key := p.Config.GetString(prefix + ".key")
+			if key == "" {
+				continue
+			}
+
+			principals[key] = &auth.Principal{
+				ID:     name,
+				Scopes: p.Config.GetStringSlice(prefix + ".scopes"),
+				Roles:  p.Config.GetStringSlice(prefix + ".roles"),
+			}
+		}
+
+		store = auth.NewStaticAPIKeyStore(principals)
+	case "sql":
+		sqlStore, err := createSQLStore(
+			p.LifeCycle,
+			p.Config,
+			p.Db,
+			"api key",
+			"modules.http.server.auth.api_key.store",
+			func(db *sql.DB, dialect sqlstore.Dialect, table string) (*auth.SQLAPIKeyStore, error) {
+				var options []auth.SQLAPIKeyStoreOption
+				if table != "" {
+					options = append(options, auth.WithSQLAPIKeyStoreTable(table))
+				}
+
+				return auth.NewSQLAPIKeyStore(db, dialect, options...)
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		store = sqlStore
+	default:
+		return nil, fmt.Errorf("invalid api key store type %s", storeType)
+	}
+
+	var options []auth.APIKeyAuthenticatorOption
+	if header := p.Config.GetString("modules.http.server.auth.api_key.header"); header != "" {
+		options = append(options, auth.WithAPIKeyHeader(header))
+	}
+
+	if queryParam := p.Config.GetString("modules.http.server.auth.api_key.query_param"); queryParam != "" {
+		options = append(options, auth.WithAPIKeyQueryParam(queryParam))
+	}
+
+	return auth.NewAPIKeyAuthenticator(store, options...), nil
+}
+
+func createBasicAuthenticator(cfg *config.Config) *auth.BasicAuthenticator {
+	users := make(map[string]auth.BasicUser)
+	for name := range cfg.GetStringMap("modules.http.server.auth.basic.users") {
+		prefix := fmt.Sprintf("modules.http.server.auth.basic.users.%s", name)
+
+		users[name] = auth.BasicUser{
+			Password:     cfg.GetString(prefix + ".password"),
+			PasswordHash: cfg.GetString(prefix + ".password_hash"),
+			Scopes:       cfg.GetStringSlice(prefix + ".scopes"),
+			Roles:        cfg.GetStringSlice(prefix + ".roles"),
+		}
+	}
+
+	var options []auth.BasicAuthenticatorOption
+	if realm := cfg.GetString("modules.http.server.auth.basic.realm"); realm != "" {
+		options = append(options, auth.WithBasicRealm(realm))
+	}
+
+	return auth.NewBasicAuthenticator(users, options...)
+}
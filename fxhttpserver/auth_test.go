@@ -0,0 +1,503 @@
+package fxhttpserver_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type testHeaderAuthenticator struct{}
+
+func newTestHeaderAuthenticator() *testHeaderAuthenticator {
+	return &testHeaderAuthenticator{}
+}
+
+func (a *testHeaderAuthenticator) Name() string {
+	return "header"
+}
+
+func (a *testHeaderAuthenticator) Authenticate(c echo.Context) (*auth.Principal, error) {
+	user := c.Request().Header.Get("X-Test-User")
+	if user == "" {
+		return nil, auth.ErrNoCredentials
+	}
+
+	return &auth.Principal{ID: user}, nil
+}
+
+func TestModuleWithAuthentication(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_JWT_ENABLED", "true")
+	t.Setenv("AUTH_JWT_JWKS_FILE", "testdata/auth/jwks.json")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+	t.Setenv("AUTH_BASIC_ENABLED", "true")
+
+	var httpServer *echo.Echo
+	var authentication *fxhttpserver.Authentication
+	var logBuffer logtest.TestLogBuffer
+
+	principalHandler := func(c echo.Context) error {
+		if principal := auth.CtxPrincipal(c.Request().Context()); principal != nil {
+			return c.String(http.StatusOK, principal.ID)
+		}
+
+		return c.String(http.StatusOK, "anonymous")
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fxhttpserver.AsAuthenticator(newTestHeaderAuthenticator),
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/anonymous", principalHandler),
+			fxhttpserver.AsHandler("GET", "/me", principalHandler, fxhttpserver.WithHandlerAuthentication()),
+			fxhttpserver.AsHandler("GET", "/keys", principalHandler, fxhttpserver.WithHandlerAuthentication("api_key")),
+			fxhttpserver.AsHandlersGroup(
+				"/admin",
+				[]*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration("GET", "/read", principalHandler),
+					fxhttpserver.NewHandlerRegistration("POST", "/write", principalHandler, fxhttpserver.WithHandlerScopes("write")),
+				},
+				fxhttpserver.WithHandlerRoles("admin"),
+			),
+		),
+		fx.Populate(&httpServer, &authentication, &logBuffer),
+	).RequireStart().RequireStop()
+
+	assert.Len(t, authentication.Authenticators(), 4)
+
+	send := func(method string, path string, prepare func(req *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if prepare != nil {
+			prepare(req)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	withAPIKey := func(key string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.Header.Set(auth.DefaultAPIKeyHeader, key)
+		}
+	}
+
+	// optional authentication
+	rec := send(http.MethodGet, "/anonymous", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "anonymous", rec.Body.String())
+
+	rec = send(http.MethodGet, "/anonymous", withAPIKey("reader-key"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "reader", rec.Body.String())
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":     "info",
+		"message":   "request logger",
+		"uri":       "/anonymous",
+		"principal": "reader",
+	})
+
+	// invalid credentials
+	rec = send(http.MethodGet, "/anonymous", withAPIKey("invalid"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, []string{"Bearer", `Basic realm="test"`}, rec.Header().Values(echo.HeaderWWWAuthenticate))
+
+	// handler authentication
+	rec = send(http.MethodGet, "/me", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = send(http.MethodGet, "/me", func(req *http.Request) {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+signTestJWT(t, jwt.MapClaims{
+			"sub":          "jwt-user",
+			"iss":          "https://issuer.example.com",
+			"aud":          "api",
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"realm_access": map[string]any{"roles": []string{"admin"}},
+		}))
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "jwt-user", rec.Body.String())
+
+	rec = send(http.MethodGet, "/me", func(req *http.Request) {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+signTestJWT(t, jwt.MapClaims{
+			"sub": "jwt-user",
+			"iss": "https://issuer.example.com",
+			"aud": "other",
+		}))
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = send(http.MethodGet, "/me?api_key=reader-key", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "reader", rec.Body.String())
+
+	rec = send(http.MethodGet, "/me", func(req *http.Request) {
+		req.SetBasicAuth("alice", "alice-password")
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", rec.Body.String())
+
+	rec = send(http.MethodGet, "/me", func(req *http.Request) {
+		req.Header.Set("X-Test-User", "custom-user")
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "custom-user", rec.Body.String())
+
+	// handler authenticators
+	rec = send(http.MethodGet, "/keys", withAPIKey("reader-key"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = send(http.MethodGet, "/keys", func(req *http.Request) {
+		req.SetBasicAuth("alice", "alice-password")
+	})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// group roles and handler scopes
+	rec = send(http.MethodGet, "/admin/read", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = send(http.MethodGet, "/admin/read", withAPIKey("reader-key"))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = send(http.MethodGet, "/admin/read", withAPIKey("admin-key"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "admin", rec.Body.String())
+
+	rec = send(http.MethodGet, "/admin/read", func(req *http.Request) {
+		req.SetBasicAuth("alice", "alice-password")
+	})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = send(http.MethodPost, "/admin/write", func(req *http.Request) {
+		req.SetBasicAuth("alice", "alice-password")
+	})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = send(http.MethodPost, "/admin/write", withAPIKey("admin-key"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestModuleWithRequiredAuthentication(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_REQUIRED", "true")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+
+	httpServer := createSecurityTestHttpServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "reader-key")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// excluded path
+	req = httptest.NewRequest(http.MethodGet, "/public/not-found", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestModuleWithRateLimitByPrincipal(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+	t.Setenv("RATELIMIT_ENABLED", "true")
+	t.Setenv("RATELIMIT_KEY", "principal")
+
+	httpServer := createSecurityTestHttpServer(t)
+
+	send := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(auth.DefaultAPIKeyHeader, key)
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("reader-key"))
+	assert.Equal(t, http.StatusOK, send("reader-key"))
+	assert.Equal(t, http.StatusTooManyRequests, send("reader-key"))
+
+	// other principal
+	assert.Equal(t, http.StatusOK, send("admin-key"))
+}
+
+func TestModuleWithHandlerAuthenticationOnly(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+
+	var httpServer *echo.Echo
+
+	principalHandler := func(c echo.Context) error {
+		if principal := auth.CtxPrincipal(c.Request().Context()); principal != nil {
+			return c.String(http.StatusOK, principal.ID)
+		}
+
+		return c.String(http.StatusOK, "anonymous")
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/anonymous", principalHandler),
+			fxhttpserver.AsHandler("GET", "/write", principalHandler, fxhttpserver.WithHandlerScopes("write")),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// global authentication disabled
+	req := httptest.NewRequest(http.MethodGet, "/anonymous", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "admin-key")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "anonymous", rec.Body.String())
+
+	// handler authentication
+	req = httptest.NewRequest(http.MethodGet, "/write", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "reader-key")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/write", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "admin-key")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "admin", rec.Body.String())
+}
+
+func TestModuleWithSQLAPIKeyStore(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_REQUIRED", "true")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+	t.Setenv("AUTH_API_KEY_STORE_TYPE", "sql")
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	defer db.Close()
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Supply(db),
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/test", func(c echo.Context) error {
+				return c.String(http.StatusOK, auth.CtxPrincipal(c.Request().Context()).ID)
+			}),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	store, err := auth.NewSQLAPIKeyStore(db, sqlstore.SQLiteDialect)
+	assert.NoError(t, err)
+
+	err = store.Save(context.Background(), "sql-key", &auth.Principal{ID: "sql-service"}, time.Time{})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "sql-key")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "sql-service", rec.Body.String())
+
+	// static keys are not used
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "reader-key")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestModuleWithAuthenticationAndHttpClient(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("AUTH_JWT_ENABLED", "true")
+	t.Setenv("AUTH_JWT_JWKS_URL", "https://issuer.example.com/keys")
+
+	jwks, err := os.ReadFile("testdata/auth/jwks.json")
+	assert.NoError(t, err)
+
+	var requested []string
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requested = append(requested, req.URL.String())
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(jwks)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Supply(client),
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/me", func(c echo.Context) error {
+				return c.String(http.StatusOK, auth.CtxPrincipal(c.Request().Context()).ID)
+			}, fxhttpserver.WithHandlerAuthentication()),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+signTestJWT(t, jwt.MapClaims{
+		"sub": "jwt-user",
+		"iss": "https://issuer.example.com",
+		"aud": "api",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}))
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "jwt-user", rec.Body.String())
+	assert.Equal(t, []string{"https://issuer.example.com/keys"}, requested)
+}
+
+func TestModuleWithInvalidAuthenticationConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{
+			name:     "jwt without jwks",
+			env:      map[string]string{"AUTH_JWT_ENABLED": "true"},
+			expected: "cannot create jwt authenticator: missing jwks file, url or issuer discovery",
+		},
+		{
+			name:     "jwt with invalid jwks file",
+			env:      map[string]string{"AUTH_JWT_ENABLED": "true", "AUTH_JWT_JWKS_FILE": "testdata/auth/invalid.json"},
+			expected: "cannot create jwt authenticator: cannot read jwks file",
+		},
+		{
+			name:     "sql api key store without database",
+			env:      map[string]string{"AUTH_API_KEY_ENABLED": "true", "AUTH_API_KEY_STORE_TYPE": "sql"},
+			expected: "cannot create api key authenticator: sql api key store requires a sql database",
+		},
+		{
+			name:     "invalid api key store",
+			env:      map[string]string{"AUTH_API_KEY_ENABLED": "true", "AUTH_API_KEY_STORE_TYPE": "invalid"},
+			expected: "cannot create api key authenticator: invalid api key store type invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_CONFIG_PATH", "testdata/config")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			app := fx.New(
+				fx.NopLogger,
+				fxconfig.FxConfigModule,
+				fxlog.FxLogModule,
+				fxtrace.FxTraceModule,
+				fxmetrics.FxMetricsModule,
+				fxgenerate.FxGenerateModule,
+				fxhttpserver.FxHttpServerModule,
+				fx.Invoke(func(*echo.Echo) {}),
+			)
+
+			assert.Error(t, app.Err())
+			assert.Contains(t, app.Err().Error(), tt.expected)
+		})
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func signTestJWT(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "test"
+
+	signed, err := token.SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+
+	return signed
+}
@@ -0,0 +1,68 @@
+package fxhttpserver
+
+import (
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// createCompressionMiddleware creates the http server compression middleware from modules.http.server.compression.
+// The global middleware is nil if disabled, and skips the modules.http.server.compression.exclude prefixes, while the
+// handlers ones (see [WithHandlerCompression]) only reuse the compression settings.
+func createCompressionMiddleware(cfg *config.Config, global bool) echo.MiddlewareFunc {
+	compressionConfig := httpservermiddleware.DefaultCompressionMiddlewareConfig
+
+	if cfg == nil {
+		return httpservermiddleware.CompressionMiddlewareWithConfig(compressionConfig)
+	}
+
+	if global {
+		if !cfg.GetBool("modules.http.server.compression.enabled") {
+			return nil
+		}
+
+		compressionConfig.Skipper = prefixesSkipper(cfg.GetStringSlice("modules.http.server.compression.exclude"))
+	}
+
+	if encodings := cfg.GetStringSlice("modules.http.server.compression.encodings"); len(encodings) > 0 {
+		compressionConfig.Encodings = encodings
+	}
+
+	if cfg.IsSet("modules.http.server.compression.min_length") {
+		compressionConfig.MinLength = cfg.GetInt("modules.http.server.compression.min_length")
+	}
+
+	if contentTypes := cfg.GetStringSlice("modules.http.server.compression.content_types"); len(contentTypes) > 0 {
+		compressionConfig.ContentTypes = contentTypes
+	}
+
+	return httpservermiddleware.CompressionMiddlewareWithConfig(compressionConfig)
+}
+
+// createETagMiddleware creates the http server ETag middleware from modules.http.server.etag.
+// The global middleware is nil if disabled, and skips the modules.http.server.etag.exclude prefixes, while the
+// handlers ones (see [WithHandlerETag]) only reuse the max size setting.
+func createETagMiddleware(cfg *config.Config, global bool) echo.MiddlewareFunc {
+	etagConfig := httpservermiddleware.DefaultETagMiddlewareConfig
+
+	if cfg != nil && global {
+		if !cfg.GetBool("modules.http.server.etag.enabled") {
+			return nil
+		}
+
+		etagConfig.Skipper = prefixesSkipper(cfg.GetStringSlice("modules.http.server.etag.exclude"))
+	}
+
+	if cfg != nil && cfg.IsSet("modules.http.server.etag.max_size") {
+		etagConfig.MaxSize = cfg.GetInt("modules.http.server.etag.max_size")
+	}
+
+	return httpservermiddleware.ETagMiddlewareWithConfig(etagConfig)
+}
+
+func prefixesSkipper(prefixes []string) func(c echo.Context) bool {
+	return func(c echo.Context) bool {
+		return httpserver.MatchPrefix(prefixes, c.Request().URL.Path)
+	}
+}
@@ -0,0 +1,139 @@
+package fxhttpserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func createCompressionTestHttpServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	var httpServer *echo.Echo
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, strings.Repeat("content ", 10))
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/test", handler),
+			fxhttpserver.AsHandler("GET", "/excluded", handler),
+			fxhttpserver.AsHandlersGroup(
+				"/group",
+				[]*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration("GET", "/test", handler),
+				},
+				fxhttpserver.WithHandlerCompression(),
+				fxhttpserver.WithHandlerETag(),
+			),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	return httpServer
+}
+
+func TestModuleWithCompressionAndETag(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("COMPRESSION_ENABLED", "true")
+	t.Setenv("ETAG_ENABLED", "true")
+
+	httpServer := createCompressionTestHttpServer(t)
+
+	// compressed response
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip, br, zstd")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, httpservermiddleware.EncodingBrotli, rec.Header().Get(echo.HeaderContentEncoding))
+
+	etag := rec.Header().Get(httpservermiddleware.HeaderETag)
+	assert.NotEmpty(t, etag)
+
+	// conditional request
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	req.Header.Set(httpservermiddleware.HeaderIfNoneMatch, etag)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// excluded
+	req = httptest.NewRequest(http.MethodGet, "/excluded", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	req.Header.Set(httpservermiddleware.HeaderIfNoneMatch, "*")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Empty(t, rec.Header().Get(httpservermiddleware.HeaderETag))
+}
+
+func TestModuleWithETagMaxSize(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("ETAG_ENABLED", "true")
+	t.Setenv("ETAG_MAX_SIZE", "10")
+
+	httpServer := createCompressionTestHttpServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, strings.Repeat("content ", 10), rec.Body.String())
+	assert.Empty(t, rec.Header().Get(httpservermiddleware.HeaderETag))
+}
+
+func TestModuleWithHandlerCompressionAndETag(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	httpServer := createCompressionTestHttpServer(t)
+
+	// not enabled globally
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Empty(t, rec.Header().Get(httpservermiddleware.HeaderETag))
+
+	// enabled on the group
+	req = httptest.NewRequest(http.MethodGet, "/group/test", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, httpservermiddleware.EncodingGzip, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.NotEmpty(t, rec.Header().Get(httpservermiddleware.HeaderETag))
+}
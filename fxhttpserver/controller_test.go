@@ -0,0 +1,157 @@
+package fxhttpserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/handler"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/middleware"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/service"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type testConcreteController struct{}
+
+func (c *testConcreteController) Routes() []*fxhttpserver.HandlerRegistration {
+	return []*fxhttpserver.HandlerRegistration{
+		fxhttpserver.NewHandlerRegistration(http.MethodGet, "/concrete-controller", c.Get),
+	}
+}
+
+func (c *testConcreteController) Get(ctx echo.Context) error {
+	return ctx.String(http.StatusOK, "concrete controller")
+}
+
+type testInvalidController struct {
+	routes []*fxhttpserver.HandlerRegistration
+}
+
+func (c *testInvalidController) Routes() []*fxhttpserver.HandlerRegistration {
+	return c.routes
+}
+
+func TestModuleWithControllers(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Provide(service.NewTestService),
+		fx.Options(
+			fxhttpserver.AsController(handler.NewTestController, middleware.NewTestHandlerMiddleware),
+			fxhttpserver.AsController(&testConcreteController{}),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// [GET] /controller
+	req := httptest.NewRequest(http.MethodGet, "/controller", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "list: test", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get("handler-middleware"))
+	assert.Empty(t, rec.Header().Get("x-route-middleware"))
+
+	// [POST] /controller
+	req = httptest.NewRequest(http.MethodPost, "/controller", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "create: test", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get("handler-middleware"))
+	assert.Equal(t, "create", rec.Header().Get("x-route-middleware"))
+
+	// [GET] /concrete-controller
+	req = httptest.NewRequest(http.MethodGet, "/concrete-controller", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "concrete controller", rec.Body.String())
+
+	// routes names
+	routesNames := map[string]string{}
+	for _, route := range httpServer.Routes() {
+		routesNames[route.Method+" "+route.Path] = route.Name
+	}
+
+	assert.Equal(t, "handler.TestController.List", routesNames["GET /controller"])
+	assert.Equal(t, "handler.TestController.Create", routesNames["POST /controller"])
+	assert.Equal(t, "handler.TestController", routesNames["GET /controller/closure"])
+	assert.Equal(t, "fxhttpserver_test.testConcreteController.Get", routesNames["GET /concrete-controller"])
+}
+
+func TestResolveControllersFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		controller    any
+		controllers   []fxhttpserver.Controller
+		expectedError string
+	}{
+		{
+			name:          "missing controller",
+			controller:    fxhttpserver.GetReturnType(handler.NewTestController),
+			expectedError: "cannot find controller for type github.com/ankorstore/yokai/fxhttpserver/testdata/handler.TestController",
+		},
+		{
+			name: "invalid route handler",
+			controller: &testInvalidController{
+				routes: []*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration(http.MethodGet, "/invalid", handler.NewTestBarHandler),
+				},
+			},
+			expectedError: "controller *fxhttpserver_test.testInvalidController route GET /invalid handler is not concrete",
+		},
+		{
+			name: "invalid route middleware",
+			controller: &testInvalidController{
+				routes: []*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration(http.MethodGet, "/invalid", testHandler, middleware.NewTestHandlerMiddleware),
+				},
+			},
+			expectedError: "controller *fxhttpserver_test.testInvalidController route GET /invalid middleware is not concrete",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			registry := fxhttpserver.NewFxHttpServerRegistry(fxhttpserver.FxHttpServerRegistryParam{
+				Controllers: tt.controllers,
+				ControllerDefinitions: []fxhttpserver.ControllerDefinition{
+					fxhttpserver.NewControllerDefinition(tt.controller, nil),
+				},
+			})
+
+			_, err := registry.ResolveHandlers()
+			assert.Error(t, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
@@ -42,6 +42,7 @@ type HandlerDefinition interface {
 	Path() string
 	Handler() any
 	Middlewares() []MiddlewareDefinition
+	Options() HandlerOptions
 }
 
 type handlerDefinition struct {
@@ -49,20 +50,27 @@ type handlerDefinition struct {
 	path        string
 	handler     any
 	middlewares []MiddlewareDefinition
+	options     HandlerOptions
 }
 
 // NewHandlerDefinition returns a new [HandlerDefinition].
-func NewHandlerDefinition(method string, path string, handler any, middlewares []MiddlewareDefinition) HandlerDefinition {
+func NewHandlerDefinition(method string, path string, handler any, middlewares []MiddlewareDefinition, options ...HandlerOption) HandlerDefinition {
 	return &handlerDefinition{
 		method:      method,
 		path:        path,
 		handler:     handler,
 		middlewares: middlewares,
+		options:     ResolveHandlerOptions(options...),
 	}
 }
 
-// Concrete returns true if the handler is a [echo.HandlerFunc] concrete implementation.
+// Concrete returns true if the handler is a [echo.HandlerFunc] concrete implementation, or a concrete stream handler
+// implementation for the stream handlers.
 func (d *handlerDefinition) Concrete() bool {
+	if d.options.Stream != "" {
+		return IsConcreteStreamHandler(d.handler, d.options.Stream)
+	}
+
 	return IsConcreteHandler(d.handler)
 }
 
@@ -86,6 +94,11 @@ func (d *handlerDefinition) Middlewares() []MiddlewareDefinition {
 	return d.middlewares
 }
 
+// Options returns the handler options.
+func (d *handlerDefinition) Options() HandlerOptions {
+	return d.options
+}
+
 // HandlersGroupDefinition is the interface for handlers groups definitions.
 type HandlersGroupDefinition interface {
 	Prefix() string
@@ -122,3 +135,46 @@ func (h *handlersGroupDefinition) Handlers() []HandlerDefinition {
 func (h *handlersGroupDefinition) Middlewares() []MiddlewareDefinition {
 	return h.middlewares
 }
+
+// ControllerDefinition is the interface for controllers definitions.
+type ControllerDefinition interface {
+	Concrete() bool
+	Controller() any
+	Middlewares() []MiddlewareDefinition
+	Options() []HandlerOption
+}
+
+type controllerDefinition struct {
+	controller  any
+	middlewares []MiddlewareDefinition
+	options     []HandlerOption
+}
+
+// NewControllerDefinition returns a new [ControllerDefinition].
+func NewControllerDefinition(controller any, middlewares []MiddlewareDefinition, options ...HandlerOption) ControllerDefinition {
+	return &controllerDefinition{
+		controller:  controller,
+		middlewares: middlewares,
+		options:     options,
+	}
+}
+
+// Concrete returns true if the controller is a concrete [Controller] implementation.
+func (d *controllerDefinition) Concrete() bool {
+	return IsConcreteController(d.controller)
+}
+
+// Controller returns the controller.
+func (d *controllerDefinition) Controller() any {
+	return d.controller
+}
+
+// Middlewares returns the controller associated middlewares, applied to all its routes.
+func (d *controllerDefinition) Middlewares() []MiddlewareDefinition {
+	return d.middlewares
+}
+
+// Options returns the controller associated options, applied before the options of each route.
+func (d *controllerDefinition) Options() []HandlerOption {
+	return d.options
+}
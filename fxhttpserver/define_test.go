@@ -3,6 +3,7 @@ package fxhttpserver_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/fxhttpserver"
 	"github.com/ankorstore/yokai/fxhttpserver/testdata/handler"
@@ -39,6 +40,22 @@ func TestHandlerDefinition(t *testing.T) {
 	assert.Equal(t, middlewares, hd.Middlewares())
 }
 
+func TestHandlerDefinitionWithOptions(t *testing.T) {
+	t.Parallel()
+
+	hd := fxhttpserver.NewHandlerDefinition(
+		http.MethodGet,
+		"/test",
+		handler.NewTestBarHandler,
+		nil,
+		fxhttpserver.WithHandlerTimeout(time.Second),
+		fxhttpserver.WithHandlerTimeoutStatus(http.StatusGatewayTimeout),
+	)
+
+	assert.Equal(t, time.Second, hd.Options().Timeout)
+	assert.Equal(t, http.StatusGatewayTimeout, hd.Options().TimeoutStatus)
+}
+
 func TestHandlersGroupDefinition(t *testing.T) {
 	t.Parallel()
 
@@ -57,3 +74,21 @@ func TestHandlersGroupDefinition(t *testing.T) {
 	assert.Equal(t, handlers, hgd.Handlers())
 	assert.Equal(t, middlewares, hgd.Middlewares())
 }
+
+func TestControllerDefinition(t *testing.T) {
+	t.Parallel()
+
+	middlewares := []fxhttpserver.MiddlewareDefinition{
+		fxhttpserver.NewMiddlewareDefinition(middleware.NewTestHandlerMiddleware, fxhttpserver.Attached),
+	}
+
+	cd := fxhttpserver.NewControllerDefinition(handler.NewTestController, middlewares, fxhttpserver.WithHandlerTags("test"))
+
+	assert.False(t, cd.Concrete())
+	assert.Equal(t, middlewares, cd.Middlewares())
+	assert.Len(t, cd.Options(), 1)
+
+	cd = fxhttpserver.NewControllerDefinition(handler.NewTestController(nil), nil)
+
+	assert.True(t, cd.Concrete())
+}
@@ -0,0 +1,112 @@
+package fxhttpserver
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver/idempotency"
+	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+// FxHttpServerIdempotencyStoreParam allows injection of the required dependencies in [NewFxHttpServerIdempotencyStore].
+type FxHttpServerIdempotencyStoreParam struct {
+	fx.In
+	LifeCycle fx.Lifecycle
+	Config    *config.Config
+	Db        *sql.DB `optional:"true"`
+}
+
+// NewFxHttpServerIdempotencyStore returns the [idempotency.Store] configured in modules.http.server.idempotency.store:
+// in memory by default, or in the SQL database provided by the fxsql module.
+func NewFxHttpServerIdempotencyStore(p FxHttpServerIdempotencyStoreParam) (idempotency.Store, error) {
+	switch storeType := p.Config.GetString("modules.http.server.idempotency.store.type"); storeType {
+	case "", "memory":
+		return idempotency.NewMemoryStore(), nil
+	case "sql":
+		return createSQLStore(
+			p.LifeCycle,
+			p.Config,
+			p.Db,
+			"idempotency",
+			"modules.http.server.idempotency.store",
+			func(db *sql.DB, dialect sqlstore.Dialect, table string) (*idempotency.SQLStore, error) {
+				var options []idempotency.SQLStoreOption
+				if table != "" {
+					options = append(options, idempotency.WithSQLStoreTable(table))
+				}
+
+				return idempotency.NewSQLStore(db, dialect, options...)
+			},
+		)
+	default:
+		return nil, fmt.Errorf("invalid idempotency store type %s", storeType)
+	}
+}
+
+// FxHttpServerIdempotencyParam allows injection of the required dependencies in [NewFxHttpServerIdempotency].
+type FxHttpServerIdempotencyParam struct {
+	fx.In
+	Config *config.Config
+	Store  idempotency.Store
+}
+
+// Idempotency creates the http server Idempotency-Key middlewares, sharing the same [idempotency.Store].
+type Idempotency struct {
+	config *config.Config
+	store  idempotency.Store
+}
+
+// NewFxHttpServerIdempotency returns a new [Idempotency].
+func NewFxHttpServerIdempotency(p FxHttpServerIdempotencyParam) *Idempotency {
+	return &Idempotency{
+		config: p.Config,
+		store:  p.Store,
+	}
+}
+
+// GlobalMiddleware returns the global Idempotency-Key middleware configured in modules.http.server.idempotency,
+// or nil if disabled.
+func (i *Idempotency) GlobalMiddleware() echo.MiddlewareFunc {
+	if !i.config.GetBool("modules.http.server.idempotency.enabled") {
+		return nil
+	}
+
+	middlewareConfig := i.middlewareConfig()
+	middlewareConfig.Skipper = prefixesSkipper(i.config.GetStringSlice("modules.http.server.idempotency.exclude"))
+
+	return httpservermiddleware.RequestIdempotencyMiddlewareWithConfig(middlewareConfig)
+}
+
+// Middleware returns the Idempotency-Key middleware of the handlers (see [WithHandlerIdempotency]).
+func (i *Idempotency) Middleware() echo.MiddlewareFunc {
+	return httpservermiddleware.RequestIdempotencyMiddlewareWithConfig(i.middlewareConfig())
+}
+
+func (i *Idempotency) middlewareConfig() httpservermiddleware.RequestIdempotencyMiddlewareConfig {
+	middlewareConfig := httpservermiddleware.DefaultRequestIdempotencyMiddlewareConfig
+	middlewareConfig.Store = i.store
+	middlewareConfig.Required = i.config.GetBool("modules.http.server.idempotency.required")
+	middlewareConfig.Headers = i.config.GetStringSlice("modules.http.server.idempotency.headers")
+
+	if ttl := i.config.GetDuration("modules.http.server.idempotency.ttl"); ttl > 0 {
+		middlewareConfig.TTL = ttl
+	}
+
+	if maxSize := i.config.GetInt("modules.http.server.idempotency.max_size"); maxSize > 0 {
+		middlewareConfig.MaxSize = maxSize
+	}
+
+	if scope := i.config.GetString("modules.http.server.idempotency.scope"); scope != "" {
+		middlewareConfig.Scope = ratelimit.FetchKeyExtractor(
+			scope,
+			i.config.GetString("modules.http.server.idempotency.scope_name"),
+		)
+	}
+
+	return middlewareConfig
+}
@@ -0,0 +1,254 @@
+package fxhttpserver_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver/idempotency"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func sendIdempotentRequest(httpServer *echo.Echo, path string, key string, tenant string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("X-Tenant", tenant)
+
+	if key != "" {
+		req.Header.Set(idempotency.HeaderIdempotencyKey, key)
+	}
+
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestModuleWithGlobalIdempotency(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("IDEMPOTENCY_ENABLED", "true")
+	t.Setenv("IDEMPOTENCY_REQUIRED", "true")
+
+	var httpServer *echo.Echo
+	var calls atomic.Int32
+
+	testHandler := func(c echo.Context) error {
+		count := calls.Add(1)
+
+		c.Response().Header().Set("X-Order", fmt.Sprintf("%d", count))
+		c.Response().Header().Set("X-Other", fmt.Sprintf("%d", count))
+
+		return c.String(http.StatusCreated, fmt.Sprintf("%d", count))
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("POST", "/orders", testHandler),
+			fxhttpserver.AsHandler("POST", "/excluded", testHandler),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// required key
+	rec := sendIdempotentRequest(httpServer, "/orders", "", "foo", "body")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// replay
+	rec = sendIdempotentRequest(httpServer, "/orders", "key", "foo", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "1", rec.Body.String())
+
+	rec = sendIdempotentRequest(httpServer, "/orders", "key", "foo", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "1", rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get("X-Order"))
+	assert.Empty(t, rec.Header().Get("X-Other"))
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	// fingerprint mismatch
+	rec = sendIdempotentRequest(httpServer, "/orders", "key", "foo", "other")
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// keys scoped by tenant
+	rec = sendIdempotentRequest(httpServer, "/orders", "key", "bar", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "2", rec.Body.String())
+
+	// excluded
+	rec = sendIdempotentRequest(httpServer, "/excluded", "", "foo", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "3", rec.Body.String())
+}
+
+func TestModuleWithGlobalIdempotencyMaxSize(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("IDEMPOTENCY_ENABLED", "true")
+	t.Setenv("IDEMPOTENCY_MAX_SIZE", "4")
+
+	var httpServer *echo.Echo
+	var calls atomic.Int32
+
+	testHandler := func(c echo.Context) error {
+		return c.String(http.StatusCreated, fmt.Sprintf("%d", calls.Add(1)))
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fxhttpserver.AsHandler("POST", "/orders", testHandler),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// stored
+	rec := sendIdempotentRequest(httpServer, "/orders", "key", "foo", "body")
+	assert.Equal(t, "1", rec.Body.String())
+
+	rec = sendIdempotentRequest(httpServer, "/orders", "key", "foo", "body")
+	assert.Equal(t, "1", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	// request body bigger than the max size
+	rec = sendIdempotentRequest(httpServer, "/orders", "other", "foo", "big body")
+	assert.Equal(t, "2", rec.Body.String())
+
+	rec = sendIdempotentRequest(httpServer, "/orders", "other", "foo", "big body")
+	assert.Equal(t, "3", rec.Body.String())
+	assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+}
+
+func TestModuleWithHandlerIdempotency(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var calls atomic.Int32
+
+	testHandler := func(c echo.Context) error {
+		return c.String(http.StatusCreated, fmt.Sprintf("%d", calls.Add(1)))
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("POST", "/idempotent", testHandler, fxhttpserver.WithHandlerIdempotency()),
+			fxhttpserver.AsHandler("POST", "/other", testHandler),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	rec := sendIdempotentRequest(httpServer, "/idempotent", "key", "foo", "body")
+	assert.Equal(t, "1", rec.Body.String())
+
+	rec = sendIdempotentRequest(httpServer, "/idempotent", "key", "foo", "body")
+	assert.Equal(t, "1", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	rec = sendIdempotentRequest(httpServer, "/other", "key", "foo", "body")
+	assert.Equal(t, "2", rec.Body.String())
+
+	rec = sendIdempotentRequest(httpServer, "/other", "key", "foo", "body")
+	assert.Equal(t, "3", rec.Body.String())
+	assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+}
+
+func TestModuleWithSQLIdempotencyStore(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("IDEMPOTENCY_ENABLED", "true")
+	t.Setenv("IDEMPOTENCY_STORE_TYPE", "sql")
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	defer db.Close()
+
+	var httpServer *echo.Echo
+	var store idempotency.Store
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Supply(db),
+		fx.Options(
+			fxhttpserver.AsHandler("POST", "/orders", func(c echo.Context) error {
+				return c.JSON(http.StatusCreated, map[string]string{"id": "1"})
+			}),
+		),
+		fx.Populate(&httpServer, &store),
+	).RequireStart().RequireStop()
+
+	assert.IsType(t, &idempotency.SQLStore{}, store)
+
+	rec := sendIdempotentRequest(httpServer, "/orders", "key", "foo", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = sendIdempotentRequest(httpServer, "/orders", "key", "foo", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":"1"}`, rec.Body.String())
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON))
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	var count int
+	err = db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM http_server_idempotency_keys").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestModuleWithSQLIdempotencyStoreWithoutDatabase(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("IDEMPOTENCY_STORE_TYPE", "sql")
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Invoke(func(*echo.Echo) {}),
+	)
+
+	assert.Error(t, app.Err())
+	assert.Contains(t, app.Err().Error(), "sql idempotency store requires a sql database")
+}
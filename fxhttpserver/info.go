@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
 	"github.com/labstack/echo/v4"
 )
 
@@ -16,11 +17,12 @@ type FxHttpServerModuleInfo struct {
 	Serializer   string
 	Renderer     string
 	ErrorHandler string
-	Routes       []*echo.Route
+	Routes       []httpserver.RouteInfo
 }
 
-// NewFxHttpServerModuleInfo returns a new [FxHttpServerModuleInfo].
-func NewFxHttpServerModuleInfo(httpServer *echo.Echo, cfg *config.Config) *FxHttpServerModuleInfo {
+// NewFxHttpServerModuleInfo returns a new [FxHttpServerModuleInfo], with the routes metadata from the
+// [httpserver.RoutesInfo].
+func NewFxHttpServerModuleInfo(httpServer *echo.Echo, cfg *config.Config, routesInfo *httpserver.RoutesInfo) *FxHttpServerModuleInfo {
 	address := cfg.GetString("modules.http.server.address")
 	if address == "" {
 		address = DefaultAddress
@@ -34,7 +36,7 @@ func NewFxHttpServerModuleInfo(httpServer *echo.Echo, cfg *config.Config) *FxHtt
 		Serializer:   fmt.Sprintf("%T", httpServer.JSONSerializer),
 		Renderer:     fmt.Sprintf("%T", httpServer.Renderer),
 		ErrorHandler: fmt.Sprintf("%T", httpServer.HTTPErrorHandler),
-		Routes:       httpServer.Routes(),
+		Routes:       routesInfo.Routes(httpServer),
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ankorstore/yokai/config"
 	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/httpserver"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,7 +21,7 @@ func TestNewFxHttpServerModuleInfo(t *testing.T) {
 	httpServer := echo.New()
 	httpServer.Debug = true
 
-	info := fxhttpserver.NewFxHttpServerModuleInfo(httpServer, cfg)
+	info := fxhttpserver.NewFxHttpServerModuleInfo(httpServer, cfg, httpserver.NewRoutesInfo())
 	assert.IsType(t, &fxhttpserver.FxHttpServerModuleInfo{}, info)
 
 	assert.Equal(t, fxhttpserver.ModuleName, info.Name())
@@ -33,7 +34,7 @@ func TestNewFxHttpServerModuleInfo(t *testing.T) {
 			"serializer":   "*echo.DefaultJSONSerializer",
 			"renderer":     "<nil>",
 			"errorHandler": "echo.HTTPErrorHandler",
-			"routes":       []*echo.Route{},
+			"routes":       []httpserver.RouteInfo{},
 		},
 		info.Data(),
 	)
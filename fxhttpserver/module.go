@@ -3,24 +3,29 @@ package fxhttpserver
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"strconv"
+	"time"
 
 	"github.com/ankorstore/yokai/config"
-	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/httpserver"
 	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
 	"github.com/ankorstore/yokai/log"
+	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	gommonlog "github.com/labstack/gommon/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
+	"golang.org/x/net/http2"
 )
 
 const (
-	ModuleName     = "httpserver"
-	DefaultAddress = ":8080"
+	ModuleName               = "httpserver"
+	DefaultAddress           = ":8080"
+	DefaultTLSReloadInterval = 10 * time.Second
 )
 
 // FxHttpServerModule is the [Fx] httpserver module.
@@ -30,27 +35,55 @@ var FxHttpServerModule = fx.Module(
 	ModuleName,
 	fx.Provide(
 		httpserver.NewDefaultHttpServerFactory,
+		httpserver.NewRoutesInfo,
 		NewFxHttpServerRegistry,
+		NewFxHttpServerRateLimitStore,
+		NewFxHttpServerRateLimiter,
+		NewFxHttpServerAuthentication,
+		NewFxHttpServerIdempotencyStore,
+		NewFxHttpServerIdempotency,
+		NewFxHttpServerStreams,
+		NewFxHttpServerShutdownParticipant,
 		NewFxHttpServer,
 		fx.Annotate(
 			NewFxHttpServerModuleInfo,
 			fx.As(new(interface{})),
 			fx.ResultTags(`group:"core-module-infos"`),
 		),
+		fx.Annotate(
+			func(participant *httpserver.ShutdownParticipant) any {
+				return participant
+			},
+			fx.ResultTags(`group:"core-shutdown-participants"`),
+		),
 	),
 )
 
+// NewFxHttpServerShutdownParticipant returns a new [httpserver.ShutdownParticipant], for the core graceful shutdown.
+func NewFxHttpServerShutdownParticipant() *httpserver.ShutdownParticipant {
+	return httpserver.NewShutdownParticipant(ModuleName)
+}
+
 // FxHttpServerParam allows injection of the required dependencies in [NewFxHttpServer].
 type FxHttpServerParam struct {
 	fx.In
-	LifeCycle       fx.Lifecycle
-	Factory         httpserver.HttpServerFactory
-	Generator       uuid.UuidGenerator
-	Registry        *HttpServerRegistry
-	Config          *config.Config
-	Logger          *log.Logger
-	TracerProvider  trace.TracerProvider
-	MetricsRegistry *prometheus.Registry
+	LifeCycle         fx.Lifecycle
+	Factory           httpserver.HttpServerFactory
+	Generator         correlation.CorrelationIdGenerator
+	Validator         correlation.CorrelationIdValidator
+	Registry          *HttpServerRegistry
+	RateLimiter       *RateLimiter
+	Authentication    *Authentication
+	Idempotency       *Idempotency
+	Shutdown          *httpserver.ShutdownParticipant
+	RoutesInfo        *httpserver.RoutesInfo
+	Config            *config.Config
+	Logger            *log.Logger
+	TracerProvider    trace.TracerProvider
+	MetricsRegistry   *prometheus.Registry
+	OpenAPIContractFS fs.FS                      `name:"httpserver-openapi-contract" optional:"true"`
+	Validate          *validator.Validate        `optional:"true"`
+	ErrorMappings     []*httpserver.ErrorMapping `group:"httpserver-error-mappings"`
 }
 
 // NewFxHttpServer returns a new [echo.Echo].
@@ -58,9 +91,8 @@ func NewFxHttpServer(p FxHttpServerParam) (*echo.Echo, error) {
 	appDebug := p.Config.AppDebug()
 
 	// logger
-	echoLogger := httpserver.NewEchoLogger(
-		log.FromZerolog(p.Logger.ToZerolog().With().Str("module", ModuleName).Logger()),
-	)
+	logger := log.FromZerolog(p.Logger.ToZerolog().With().Str("module", ModuleName).Logger())
+	echoLogger := httpserver.NewEchoLogger(logger)
 
 	// renderer
 	var echoRenderer echo.Renderer
@@ -73,6 +105,12 @@ func NewFxHttpServer(p FxHttpServerParam) (*echo.Echo, error) {
 	resolvedErrorHandlers := p.Registry.ResolveErrorHandlers()
 	if len(resolvedErrorHandlers) > 0 {
 		echoErrorHandler = resolvedErrorHandlers[0]
+	} else if p.Config.GetBool("modules.http.server.errors.problem_details") {
+		echoErrorHandler = httpserver.NewProblemDetailsErrorHandler(
+			p.Config.GetBool("modules.http.server.errors.obfuscate") || !appDebug,
+			p.Config.GetBool("modules.http.server.errors.stack") || appDebug,
+			append(p.ErrorMappings, NewValidationErrorMapping())...,
+		)
 	} else {
 		echoErrorHandler = httpserver.NewJsonErrorHandler(
 			p.Config.GetBool("modules.http.server.errors.obfuscate") || !appDebug,
@@ -80,28 +118,64 @@ func NewFxHttpServer(p FxHttpServerParam) (*echo.Echo, error) {
 		)
 	}
 
+	// validator
+	var echoValidator echo.Validator
+	if p.Validate != nil {
+		echoValidator = NewValidator(p.Validate)
+	}
+
 	// server
 	httpServer, err := p.Factory.Create(
 		httpserver.WithDebug(appDebug),
 		httpserver.WithBanner(false),
 		httpserver.WithLogger(echoLogger),
 		httpserver.WithRenderer(echoRenderer),
+		httpserver.WithValidator(echoValidator),
 		httpserver.WithHttpErrorHandler(echoErrorHandler.Handle()),
+		httpserver.WithReadTimeout(p.Config.GetDuration("modules.http.server.timeouts.read")),
+		httpserver.WithReadHeaderTimeout(p.Config.GetDuration("modules.http.server.timeouts.read_header")),
+		httpserver.WithWriteTimeout(p.Config.GetDuration("modules.http.server.timeouts.write")),
+		httpserver.WithIdleTimeout(p.Config.GetDuration("modules.http.server.timeouts.idle")),
+		httpserver.WithMaxHeaderBytes(p.Config.GetInt("modules.http.server.max_header_bytes")),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create http server: %w", err)
 	}
 
+	// shutdown participant binding
+	p.Shutdown.Bind(httpServer)
+	httpServer.Use(p.Shutdown.Middleware())
+
 	// middlewares registrations
-	httpServer = withDefaultMiddlewares(httpServer, p)
+	httpServer, err = withDefaultMiddlewares(httpServer, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register http server default middlewares: %w", err)
+	}
+
+	// request validation middleware, applied on the registered handlers after their authentication
+	requestValidationMiddleware, err := createRequestValidationMiddleware(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http server request validation middleware: %w", err)
+	}
 
 	// groups, handlers & middlewares registrations
-	httpServer, err = withRegisteredResources(httpServer, p)
+	httpServer, err = withRegisteredResources(httpServer, p, requestValidationMiddleware)
 	if err != nil {
 		return httpServer, fmt.Errorf("failed to register http server resources: %w", err)
 	}
 
+	// openapi
+	httpServer = withOpenAPI(httpServer, p)
+
+	// tls
+	tlsConfig, certificateReloader, clientCAReloader, err := createTLSConfig(p.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http server tls config: %w", err)
+	}
+
 	// lifecycles
+	reloadCtx, reloadCancel := context.WithCancel(context.Background())
+
 	p.LifeCycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			if !p.Config.IsTestEnv() {
@@ -110,15 +184,54 @@ func NewFxHttpServer(p FxHttpServerParam) (*echo.Echo, error) {
 					address = DefaultAddress
 				}
 
-				//nolint:errcheck
-				go httpServer.Start(address)
+				switch {
+				case tlsConfig != nil:
+					if p.Config.GetBool("modules.http.server.tls.reload.enabled") {
+						reloadInterval := p.Config.GetDuration("modules.http.server.tls.reload.interval")
+						if reloadInterval <= 0 {
+							reloadInterval = DefaultTLSReloadInterval
+						}
+
+						go certificateReloader.Watch(reloadCtx, reloadInterval, func(err error) {
+							if err != nil {
+								logger.Error().Err(err).Msg("http server tls certificate reload error")
+							} else {
+								logger.Info().Msg("http server tls certificate reloaded")
+							}
+						})
+
+						if clientCAReloader != nil {
+							go clientCAReloader.Watch(reloadCtx, reloadInterval, func(err error) {
+								if err != nil {
+									logger.Error().Err(err).Msg("http server tls client ca reload error")
+								} else {
+									logger.Info().Msg("http server tls client ca reloaded")
+								}
+							})
+						}
+					}
+
+					httpServer.TLSServer.Addr = address
+					httpServer.TLSServer.TLSConfig = tlsConfig
+
+					//nolint:errcheck
+					go httpServer.StartServer(httpServer.TLSServer)
+				case p.Config.GetBool("modules.http.server.h2c.enabled"):
+					//nolint:errcheck
+					go httpServer.StartH2CServer(address, &http2.Server{})
+				default:
+					//nolint:errcheck
+					go httpServer.Start(address)
+				}
 			}
 
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			reloadCancel()
+
 			if !p.Config.IsTestEnv() {
-				return httpServer.Shutdown(ctx)
+				return p.Shutdown.Shutdown(ctx)
 			}
 
 			return nil
@@ -128,11 +241,12 @@ func NewFxHttpServer(p FxHttpServerParam) (*echo.Echo, error) {
 	return httpServer, nil
 }
 
-func withDefaultMiddlewares(httpServer *echo.Echo, p FxHttpServerParam) *echo.Echo {
+func withDefaultMiddlewares(httpServer *echo.Echo, p FxHttpServerParam) (*echo.Echo, error) {
 	// request id middleware
 	httpServer.Use(httpservermiddleware.RequestIdMiddlewareWithConfig(
 		httpservermiddleware.RequestIdMiddlewareConfig{
 			Generator: p.Generator,
+			Validator: p.Validator,
 		},
 	))
 
@@ -141,6 +255,7 @@ func withDefaultMiddlewares(httpServer *echo.Echo, p FxHttpServerParam) *echo.Ec
 		httpServer.Use(httpservermiddleware.RequestTracerMiddlewareWithConfig(
 			p.Config.AppName(),
 			httpservermiddleware.RequestTracerMiddlewareConfig{
+				Skipper:                     createRoutesExcluder(p.Config, p.RoutesInfo, "trace"),
 				TracerProvider:              httpserver.AnnotateTracerProvider(p.TracerProvider),
 				RequestUriPrefixesToExclude: p.Config.GetStringSlice("modules.http.server.trace.exclude"),
 			},
@@ -160,6 +275,7 @@ func withDefaultMiddlewares(httpServer *echo.Echo, p FxHttpServerParam) *echo.Ec
 		httpservermiddleware.RequestLoggerMiddlewareConfig{
 			RequestHeadersToLog:             requestHeadersToLog,
 			RequestUriPrefixesToExclude:     p.Config.GetStringSlice("modules.http.server.log.exclude"),
+			RequestExcluder:                 createRoutesExcluder(p.Config, p.RoutesInfo, "log"),
 			LogLevelFromResponseOrErrorCode: p.Config.GetBool("modules.http.server.log.level_from_response"),
 		},
 	))
@@ -191,17 +307,71 @@ func withDefaultMiddlewares(httpServer *echo.Echo, p FxHttpServerParam) *echo.Ec
 		httpServer.Use(httpservermiddleware.RequestMetricsMiddlewareWithConfig(metricsMiddlewareConfig))
 	}
 
+	// security headers middleware
+	if securityHeadersMiddleware := createSecurityHeadersMiddleware(p.Config); securityHeadersMiddleware != nil {
+		httpServer.Use(securityHeadersMiddleware)
+	}
+
+	// cors middleware
+	if corsMiddleware := createCORSMiddleware(p.Config); corsMiddleware != nil {
+		httpServer.Use(corsMiddleware)
+	}
+
+	// authentication middleware, for the rate limit to be able to key requests by principal
+	if authenticationMiddleware := p.Authentication.GlobalMiddleware(); authenticationMiddleware != nil {
+		httpServer.Use(authenticationMiddleware)
+	}
+
+	// rate limit middleware
+	rateLimitMiddleware, err := p.RateLimiter.GlobalMiddleware()
+	if err != nil {
+		return nil, err
+	}
+
+	if rateLimitMiddleware != nil {
+		httpServer.Use(rateLimitMiddleware)
+	}
+
+	// body limit middleware
+	if bodyLimit := p.Config.GetString("modules.http.server.body_limit"); bodyLimit != "" {
+		httpServer.Use(middleware.BodyLimit(bodyLimit))
+	}
+
+	// csrf middleware
+	if csrfMiddleware := createCSRFMiddleware(p.Config); csrfMiddleware != nil {
+		httpServer.Use(csrfMiddleware)
+	}
+
+	// idempotency middleware, before compression to store the uncompressed responses
+	if idempotencyMiddleware := p.Idempotency.GlobalMiddleware(); idempotencyMiddleware != nil {
+		httpServer.Use(idempotencyMiddleware)
+	}
+
+	// compression middleware
+	if compressionMiddleware := createCompressionMiddleware(p.Config, true); compressionMiddleware != nil {
+		httpServer.Use(compressionMiddleware)
+	}
+
+	// etag middleware, after compression to be generated from the uncompressed responses
+	if etagMiddleware := createETagMiddleware(p.Config, true); etagMiddleware != nil {
+		httpServer.Use(etagMiddleware)
+	}
+
 	// recovery middleware
 	httpServer.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		DisableErrorHandler: true,
 		LogLevel:            gommonlog.ERROR,
 	}))
 
-	return httpServer
+	return httpServer, nil
 }
 
 //nolint:cyclop
-func withRegisteredResources(httpServer *echo.Echo, p FxHttpServerParam) (*echo.Echo, error) {
+func withRegisteredResources(
+	httpServer *echo.Echo,
+	p FxHttpServerParam,
+	requestValidationMiddleware echo.MiddlewareFunc,
+) (*echo.Echo, error) {
 	// register handler groups
 	resolvedHandlersGroups, err := p.Registry.ResolveHandlersGroups()
 	if err != nil {
@@ -218,12 +388,7 @@ func withRegisteredResources(httpServer *echo.Echo, p FxHttpServerParam) (*echo.
 			}
 
 			for _, method := range methods {
-				group.Add(
-					method,
-					h.Path(),
-					h.Handler(),
-					h.Middlewares()...,
-				)
+				registerRoute(httpServer, p, group.Add, g.Prefix(), method, h, requestValidationMiddleware)
 
 				httpServer.Logger.Debugf("registering handler in group for [%s] %s%s", method, g.Prefix(), h.Path())
 			}
@@ -263,12 +428,7 @@ func withRegisteredResources(httpServer *echo.Echo, p FxHttpServerParam) (*echo.
 		}
 
 		for _, method := range methods {
-			httpServer.Add(
-				method,
-				h.Path(),
-				h.Handler(),
-				h.Middlewares()...,
-			)
+			registerRoute(httpServer, p, httpServer.Add, "", method, h, requestValidationMiddleware)
 
 			httpServer.Logger.Debugf("registered handler for [%s] %s", method, h.Path())
 		}
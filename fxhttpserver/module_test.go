@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -867,6 +868,49 @@ func TestModuleWithEchoResources(t *testing.T) {
 	assert.Equal(t, "SAMEORIGIN", rec.Header().Get(echo.HeaderXFrameOptions)) // Secure middleware
 }
 
+func TestModuleWithShutdownParticipant(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var participant *httpserver.ShutdownParticipant
+	var participants []any
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/in-flight", func(c echo.Context) error {
+				return c.String(http.StatusOK, strconv.FormatInt(participant.InFlight(), 10))
+			}),
+		),
+		fx.Invoke(func(p struct {
+			fx.In
+			Participants []any `group:"core-shutdown-participants"`
+		}) {
+			participants = p.Participants
+		}),
+		fx.Populate(&httpServer, &participant),
+	).RequireStart().RequireStop()
+
+	assert.Equal(t, fxhttpserver.ModuleName, participant.Name())
+	assert.Contains(t, participants, any(participant))
+
+	// [GET] /in-flight
+	req := httptest.NewRequest(http.MethodGet, "/in-flight", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Body.String())
+	assert.Equal(t, int64(0), participant.InFlight())
+}
+
 func TestModuleWithPanicRecoveryAndDebug(t *testing.T) {
 	t.Setenv("APP_CONFIG_PATH", "testdata/config")
 	t.Setenv("APP_DEBUG", "true")
@@ -0,0 +1,304 @@
+package fxhttpserver
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/httpserver/handler"
+	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+const (
+	DefaultOpenAPIPath   = "/openapi.json"
+	DefaultSwaggerUIPath = "/swagger"
+	DefaultRedocPath     = "/redoc"
+)
+
+// AsOpenAPIContract registers into Fx the [fs.FS] (for example an [embed.FS]) to load the OpenAPI contract configured
+// in modules.http.server.openapi.validation.contract from, instead of the file system.
+func AsOpenAPIContract(fsys fs.FS) fx.Option {
+	return fx.Provide(
+		fx.Annotate(
+			func() fs.FS {
+				return fsys
+			},
+			fx.ResultTags(`name:"httpserver-openapi-contract"`),
+		),
+	)
+}
+
+func withOpenAPI(httpServer *echo.Echo, p FxHttpServerParam) *echo.Echo {
+	if !p.Config.GetBool("modules.http.server.openapi.enabled") {
+		return httpServer
+	}
+
+	document := createOpenAPIDocument(httpServer, p)
+
+	openAPIPath := p.Config.GetString("modules.http.server.openapi.path")
+	if openAPIPath == "" {
+		openAPIPath = DefaultOpenAPIPath
+	}
+
+	httpServer.GET(openAPIPath, handler.OpenAPIHandler(document))
+
+	if p.Config.GetBool("modules.http.server.openapi.swagger_ui.enabled") {
+		swaggerUIPath := p.Config.GetString("modules.http.server.openapi.swagger_ui.path")
+		if swaggerUIPath == "" {
+			swaggerUIPath = DefaultSwaggerUIPath
+		}
+
+		httpServer.GET(
+			swaggerUIPath,
+			handler.SwaggerUIHandler(openAPIPath, document.Info.Title, createOpenAPIPageOptions(p.Config, "swagger_ui")...),
+		)
+	}
+
+	if p.Config.GetBool("modules.http.server.openapi.redoc.enabled") {
+		redocPath := p.Config.GetString("modules.http.server.openapi.redoc.path")
+		if redocPath == "" {
+			redocPath = DefaultRedocPath
+		}
+
+		httpServer.GET(
+			redocPath,
+			handler.RedocHandler(openAPIPath, document.Info.Title, createOpenAPIPageOptions(p.Config, "redoc")...),
+		)
+	}
+
+	return httpServer
+}
+
+func createOpenAPIPageOptions(cfg *config.Config, page string) []handler.OpenAPIPageOption {
+	prefix := fmt.Sprintf("modules.http.server.openapi.%s", page)
+
+	var options []handler.OpenAPIPageOption
+	if assetsURL := cfg.GetString(prefix + ".assets_url"); assetsURL != "" {
+		options = append(options, handler.WithOpenAPIPageAssetsURL(assetsURL))
+	}
+
+	return append(
+		options,
+		handler.WithOpenAPIPageIntegrity(
+			cfg.GetString(prefix+".integrity.script"),
+			cfg.GetString(prefix+".integrity.stylesheet"),
+		),
+	)
+}
+
+func createOpenAPIDocument(httpServer *echo.Echo, p FxHttpServerParam) *openapi.Document {
+	info := openapi.Info{
+		Title:       p.Config.GetString("modules.http.server.openapi.title"),
+		Version:     p.Config.GetString("modules.http.server.openapi.version"),
+		Description: p.Config.GetString("modules.http.server.openapi.description"),
+	}
+
+	if info.Title == "" {
+		info.Title = p.Config.AppName()
+	}
+
+	if info.Version == "" {
+		info.Version = p.Config.AppVersion()
+	}
+
+	if info.Description == "" {
+		info.Description = p.Config.AppDescription()
+	}
+
+	validateTagName := p.Config.GetString("modules.validator.tag_name")
+	if validateTagName == "" {
+		validateTagName = openapi.DefaultValidateTagName
+	}
+
+	options := []openapi.GeneratorOption{
+		openapi.WithInfo(info),
+		openapi.WithSchemaGenerator(openapi.NewSchemaGenerator(openapi.WithValidateTagName(validateTagName))),
+	}
+
+	for _, url := range p.Config.GetStringSlice("modules.http.server.openapi.servers") {
+		options = append(options, openapi.WithServers(openapi.Server{URL: url}))
+	}
+
+	securitySchemes := createOpenAPISecuritySchemes(p.Config)
+
+	var securitySchemesNames []string
+	for _, name := range []string{auth.DefaultJWTAuthenticatorName, auth.DefaultAPIKeyAuthenticatorName, auth.DefaultBasicAuthenticatorName} {
+		if scheme, ok := securitySchemes[name]; ok {
+			securitySchemesNames = append(securitySchemesNames, name)
+			options = append(options, openapi.WithSecurityScheme(name, scheme))
+		}
+	}
+
+	if p.Config.GetBool("modules.http.server.auth.enabled") && p.Config.GetBool("modules.http.server.auth.required") {
+		options = append(options, openapi.WithSecurity(createOpenAPISecurity(securitySchemes, securitySchemesNames, nil)...))
+	}
+
+	generator := openapi.NewGenerator(options...)
+
+	exclude := p.Config.GetStringSlice("modules.http.server.openapi.exclude")
+
+	addOperations := func(path string, handlerDef HandlerDefinition) {
+		if httpserver.MatchPrefix(exclude, path) || handlerDef.Method() == AllMethods || !routeEnabled(p.Config, handlerDef.Options().Name) {
+			return
+		}
+
+		methods, err := ExtractMethods(handlerDef.Method())
+		if err != nil {
+			httpServer.Logger.Errorf("cannot document handler: %v", err)
+
+			return
+		}
+
+		handlerOptions := handlerDef.Options()
+
+		spec := openapi.OperationSpec{
+			OperationID: handlerOptions.OperationID,
+			Summary:     handlerOptions.Summary,
+			Description: handlerOptions.Description,
+			Tags:        handlerOptions.Tags,
+			Deprecated:  handlerOptions.Deprecated,
+			Request:     handlerOptions.Request,
+			Responses:   handlerOptions.Responses,
+		}
+
+		if handlerOptions.RequiresAuthentication() {
+			names := securitySchemesNames
+			if len(handlerOptions.Authenticators) > 0 {
+				names = handlerOptions.Authenticators
+			}
+
+			spec.Security = createOpenAPISecurity(securitySchemes, names, handlerOptions.Scopes)
+		}
+
+		for _, method := range methods {
+			err = generator.AddOperation(method, path, spec)
+			if err != nil {
+				httpServer.Logger.Errorf("cannot document handler: %v", err)
+			}
+		}
+	}
+
+	for _, handlersGroupDef := range p.Registry.HandlersGroupDefinitions() {
+		for _, handlerDef := range handlersGroupDef.Handlers() {
+			addOperations(handlersGroupDef.Prefix()+handlerDef.Path(), handlerDef)
+		}
+	}
+
+	for _, handlerDef := range p.Registry.HandlerDefinitions() {
+		addOperations(handlerDef.Path(), handlerDef)
+	}
+
+	controllersHandlerDefs, err := p.Registry.ControllersHandlerDefinitions()
+	if err != nil {
+		httpServer.Logger.Errorf("cannot document controllers: %v", err)
+	}
+
+	for _, handlerDef := range controllersHandlerDefs {
+		addOperations(handlerDef.Path(), handlerDef)
+	}
+
+	return generator.Document()
+}
+
+func createOpenAPISecuritySchemes(cfg *config.Config) map[string]*openapi.SecurityScheme {
+	securitySchemes := make(map[string]*openapi.SecurityScheme)
+
+	if cfg.GetBool("modules.http.server.auth.jwt.enabled") {
+		securitySchemes[auth.DefaultJWTAuthenticatorName] = &openapi.SecurityScheme{
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: "JWT",
+		}
+	}
+
+	if cfg.GetBool("modules.http.server.auth.api_key.enabled") {
+		header := cfg.GetString("modules.http.server.auth.api_key.header")
+		if header == "" {
+			header = auth.DefaultAPIKeyHeader
+		}
+
+		securitySchemes[auth.DefaultAPIKeyAuthenticatorName] = &openapi.SecurityScheme{
+			Type: "apiKey",
+			In:   "header",
+			Name: header,
+		}
+	}
+
+	if cfg.GetBool("modules.http.server.auth.basic.enabled") {
+		securitySchemes[auth.DefaultBasicAuthenticatorName] = &openapi.SecurityScheme{
+			Type:   "http",
+			Scheme: "basic",
+		}
+	}
+
+	return securitySchemes
+}
+
+// createOpenAPISecurity returns alternative security requirements, one per known security scheme
+// (authenticators registered with AsAuthenticator cannot be documented).
+func createOpenAPISecurity(securitySchemes map[string]*openapi.SecurityScheme, names []string, scopes []string) []openapi.SecurityRequirement {
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	var requirements []openapi.SecurityRequirement
+	for _, name := range names {
+		if _, ok := securitySchemes[name]; ok {
+			requirements = append(requirements, openapi.SecurityRequirement{name: scopes})
+		}
+	}
+
+	return requirements
+}
+
+// createRequestValidationMiddleware returns the middleware validating the requests against the OpenAPI contract
+// configured in modules.http.server.openapi.validation, and their responses outside of prod environment only,
+// or nil if disabled.
+func createRequestValidationMiddleware(p FxHttpServerParam) (echo.MiddlewareFunc, error) {
+	if !p.Config.GetBool("modules.http.server.openapi.validation.enabled") {
+		return nil, nil
+	}
+
+	contractPath := p.Config.GetString("modules.http.server.openapi.validation.contract")
+	if contractPath == "" {
+		return nil, fmt.Errorf("missing openapi contract")
+	}
+
+	var (
+		contract *openapi3.T
+		err      error
+	)
+
+	if p.OpenAPIContractFS != nil {
+		contract, err = openapi.LoadContractFS(p.OpenAPIContractFS, contractPath)
+	} else {
+		contract, err = openapi.LoadContract(contractPath)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := openapi.NewContractRouter(contract)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := p.Config.GetStringSlice("modules.http.server.openapi.validation.exclude")
+
+	return httpservermiddleware.RequestValidationMiddlewareWithConfig(httpservermiddleware.RequestValidationMiddlewareConfig{
+		Skipper: func(c echo.Context) bool {
+			return httpserver.MatchPrefix(exclude, c.Request().URL.Path)
+		},
+		Router: router,
+		ValidateResponses: p.Config.GetBool("modules.http.server.openapi.validation.responses.enabled") &&
+			!p.Config.IsProdEnv(),
+		FailOnInvalidResponse: p.Config.GetBool("modules.http.server.openapi.validation.responses.fail"),
+	}), nil
+}
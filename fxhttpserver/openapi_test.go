@@ -0,0 +1,361 @@
+package fxhttpserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/httpserver/handler"
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type testCreateUserRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+type testGetUserRequest struct {
+	ID string `param:"id" validate:"uuid"`
+}
+
+type testUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestModuleWithOpenAPI(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("OPENAPI_ENABLED", "true")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+	t.Setenv("AUTH_BASIC_ENABLED", "true")
+
+	var httpServer *echo.Echo
+
+	testHandler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler(
+				"POST",
+				"/users",
+				testHandler,
+				fxhttpserver.WithHandlerOperationID("createUser"),
+				fxhttpserver.WithHandlerSummary("create user"),
+				fxhttpserver.WithHandlerRequest(testCreateUserRequest{}),
+				fxhttpserver.WithHandlerResponse(http.StatusCreated, testUser{}),
+				fxhttpserver.WithHandlerResponse(http.StatusBadRequest, nil),
+				fxhttpserver.WithHandlerScopes("write"),
+			),
+			fxhttpserver.AsHandlersGroup(
+				"/users",
+				[]*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration(
+						"GET,HEAD",
+						"/:id",
+						testHandler,
+						fxhttpserver.WithHandlerDescription("get user"),
+						fxhttpserver.WithHandlerRequest(testGetUserRequest{}),
+						fxhttpserver.WithHandlerResponse(http.StatusOK, testUser{}),
+						fxhttpserver.WithHandlerDeprecated(),
+					),
+				},
+				fxhttpserver.WithHandlerTags("users"),
+				fxhttpserver.WithHandlerAuthentication("basic", "custom"),
+			),
+			fxhttpserver.AsHandler("GET", "/internal/test", testHandler),
+			fxhttpserver.AsHandler("*", "/any", testHandler),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// document
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var document openapi.Document
+	err := json.Unmarshal(rec.Body.Bytes(), &document)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3.1.0", document.OpenAPI)
+
+	// the generated document can be used as validation contract
+	_, err = openapi.LoadContractFS(fstest.MapFS{"openapi.json": {Data: rec.Body.Bytes()}}, "openapi.json")
+	assert.NoError(t, err)
+	assert.Equal(t, openapi.Info{Title: "test", Version: "0.1.0", Description: "test api"}, document.Info)
+	assert.Equal(t, []openapi.Server{{URL: "https://api.example.com"}}, document.Servers)
+	assert.Nil(t, document.Security)
+	assert.Len(t, document.Paths, 2)
+	assert.NotContains(t, document.Paths, "/internal/test")
+	assert.NotContains(t, document.Paths, "/any")
+
+	assert.Equal(t, &openapi.SecurityScheme{Type: "apiKey", In: "header", Name: "X-Api-Key"}, document.Components.SecuritySchemes["api_key"])
+	assert.Equal(t, &openapi.SecurityScheme{Type: "http", Scheme: "basic"}, document.Components.SecuritySchemes["basic"])
+	assert.NotContains(t, document.Components.SecuritySchemes, "jwt")
+
+	create := document.Paths["/users"].Post
+	assert.Equal(t, "createUser", create.OperationID)
+	assert.Equal(t, "create user", create.Summary)
+	assert.Equal(t, "#/components/schemas/testCreateUserRequest", create.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/testUser", create.Responses["201"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "Bad Request", create.Responses["400"].Description)
+	assert.Equal(t, []openapi.SecurityRequirement{{"api_key": {"write"}}, {"basic": {"write"}}}, create.Security)
+
+	get := document.Paths["/users/{id}"].Get
+	assert.Equal(t, "get user", get.Description)
+	assert.Equal(t, []string{"users"}, get.Tags)
+	assert.True(t, get.Deprecated)
+	assert.Equal(t, "uuid", get.Parameters[0].Schema.Format)
+	assert.Equal(t, []openapi.SecurityRequirement{{"basic": {}}}, get.Security)
+	assert.NotNil(t, document.Paths["/users/{id}"].Head)
+
+	schema := document.Components.Schemas["testCreateUserRequest"]
+	assert.Equal(t, []string{"name", "email"}, schema.Required)
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.Equal(t, uint64(50), *schema.Properties["name"].MaxLength)
+
+	// swagger ui
+	req = httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `url: "/openapi.json"`)
+	assert.Contains(t, rec.Body.String(), `<script src="/assets/swagger-ui/swagger-ui-bundle.js" integrity="sha384-swagger-ui-script"`)
+	assert.Contains(t, rec.Body.String(), `href="/assets/swagger-ui/swagger-ui.css" integrity="sha384-swagger-ui-stylesheet"`)
+
+	// redoc
+	req = httptest.NewRequest(http.MethodGet, "/docs", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<redoc spec-url="/openapi.json"></redoc>`)
+	assert.Contains(t, rec.Body.String(), handler.DefaultRedocAssetsURL+"/redoc.standalone.js")
+}
+
+func TestModuleWithOpenAPIAndRequiredAuthentication(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("OPENAPI_ENABLED", "true")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_REQUIRED", "true")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+
+	httpServer := createSecurityTestHttpServer(t)
+
+	// documentation endpoints are not excluded from the global authentication
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "reader-key")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var document openapi.Document
+	err := json.Unmarshal(rec.Body.Bytes(), &document)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []openapi.SecurityRequirement{{"api_key": {}}}, document.Security)
+	assert.NotNil(t, document.Paths["/test"].Get)
+	assert.NotNil(t, document.Paths["/test"].Post)
+	assert.NotNil(t, document.Paths["/webhooks/test"].Post)
+}
+
+func testValidationHandler(c echo.Context) error {
+	var body map[string]any
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+
+	if body["name"] == "invalid" {
+		return c.JSON(http.StatusCreated, map[string]any{"id": "invalid"})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"id": 1, "name": body["name"]})
+}
+
+func TestModuleWithOpenAPIValidation(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("OPENAPI_VALIDATION_ENABLED", "true")
+	t.Setenv("OPENAPI_VALIDATION_CONTRACT", "testdata/openapi/contract.yaml")
+	t.Setenv("OPENAPI_VALIDATION_RESPONSES_FAIL", "true")
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("POST", "/users", testValidationHandler),
+			fxhttpserver.AsHandler("POST", "/internal/users", testValidationHandler),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	post := func(path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// valid request
+	rec := post("/users", `{"name":"john"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":1,"name":"john"}`, rec.Body.String())
+
+	// invalid request
+	rec = post("/users", `{"name":""}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"errors":[{"name":"name","in":"body","reason":`)
+
+	// invalid response
+	rec = post("/users", `{"name":"invalid"}`)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"id":"invalid"`)
+
+	// excluded
+	rec = post("/internal/users", `{}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestModuleWithOpenAPIValidationAfterAuthentication(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("OPENAPI_VALIDATION_ENABLED", "true")
+	t.Setenv("OPENAPI_VALIDATION_CONTRACT", "testdata/openapi/contract.yaml")
+	t.Setenv("AUTH_API_KEY_ENABLED", "true")
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fxhttpserver.AsHandler("POST", "/users", testValidationHandler, fxhttpserver.WithHandlerAuthentication()),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	post := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if apiKey != "" {
+			req.Header.Set(auth.DefaultAPIKeyHeader, apiKey)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// unauthenticated requests are rejected before their validation
+	assert.Equal(t, http.StatusUnauthorized, post("").Code)
+	assert.Equal(t, http.StatusBadRequest, post("reader-key").Code)
+}
+
+func TestModuleWithOpenAPIValidationFromFS(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("OPENAPI_VALIDATION_ENABLED", "true")
+	t.Setenv("OPENAPI_VALIDATION_CONTRACT", "openapi/contract.yaml")
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fxhttpserver.AsOpenAPIContract(os.DirFS("testdata")),
+		fxhttpserver.AsHandler("POST", "/users", testValidationHandler),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// invalid request
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// invalid response, only logged
+	req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"invalid"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":"invalid"}`, rec.Body.String())
+}
+
+func TestModuleWithOpenAPIValidationWithInvalidContract(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("OPENAPI_VALIDATION_ENABLED", "true")
+	t.Setenv("OPENAPI_VALIDATION_CONTRACT", "testdata/openapi/missing.yaml")
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Invoke(func(*echo.Echo) {}),
+	)
+
+	assert.Error(t, app.Err())
+	assert.Contains(t, app.Err().Error(), "cannot read contract testdata/openapi/missing.yaml")
+}
@@ -0,0 +1,240 @@
+package fxhttpserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/ankorstore/yokai/httpserver/stream"
+)
+
+// HandlerOptions are options for the handlers registrations.
+type HandlerOptions struct {
+	Timeout               time.Duration
+	TimeoutStatus         int
+	RateLimitName         string
+	RateLimit             ratelimit.Limit
+	RateLimitKeyExtractor ratelimit.KeyExtractor
+	Authenticated         bool
+	Authenticators        []string
+	Scopes                []string
+	Roles                 []string
+	OperationID           string
+	Summary               string
+	Description           string
+	Tags                  []string
+	Deprecated            bool
+	Request               any
+	Responses             []openapi.ResponseSpec
+	Compression           bool
+	ETag                  bool
+	Idempotency           bool
+	Stream                stream.Kind
+	Name                  string
+	Metadata              map[string]any
+}
+
+// RequiresAuthentication returns true if the handler requires an authenticated principal.
+func (o HandlerOptions) RequiresAuthentication() bool {
+	return o.Authenticated || len(o.Authenticators) > 0 || len(o.Scopes) > 0 || len(o.Roles) > 0
+}
+
+// DefaultHandlerOptions are the default options for the handlers registrations.
+func DefaultHandlerOptions() HandlerOptions {
+	return HandlerOptions{
+		Timeout:       0,
+		TimeoutStatus: http.StatusServiceUnavailable,
+	}
+}
+
+// HandlerOption are functional options for the handlers registrations.
+// They can be provided among the handler middlewares, for example in [AsHandler].
+type HandlerOption func(o *HandlerOptions)
+
+// WithHandlerTimeout is used to specify a timeout for the handler executions: the request context is canceled after
+// the timeout, and if the handler did not respond by then, an error with the timeout status is returned.
+func WithHandlerTimeout(timeout time.Duration) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithHandlerTimeoutStatus is used to specify the status returned on handler timeout (503 by default).
+func WithHandlerTimeoutStatus(status int) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.TimeoutStatus = status
+	}
+}
+
+// WithHandlerRateLimit is used to rate limit the handler executions with a named limit.
+// The limit can be provided, or overridden from modules.http.server.ratelimit.limits.<name>, and its counters are
+// shared by all handlers using the same name.
+func WithHandlerRateLimit(name string, limit ratelimit.Limit) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.RateLimitName = name
+		o.RateLimit = limit
+	}
+}
+
+// WithHandlerRateLimitKey is used to specify how the handler rate limit keys requests (by client IP by default).
+func WithHandlerRateLimitKey(keyExtractor ratelimit.KeyExtractor) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.RateLimitKeyExtractor = keyExtractor
+	}
+}
+
+// WithHandlerAuthentication is used to require an authenticated principal for the handler executions.
+// If authenticators names are provided, the principal must have been resolved by one of them.
+func WithHandlerAuthentication(authenticators ...string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Authenticated = true
+		o.Authenticators = authenticators
+	}
+}
+
+// WithHandlerScopes is used to require an authenticated principal with all the provided scopes for the handler
+// executions. Scopes provided several times, for example on a handlers group and on its handlers, are all required.
+func WithHandlerScopes(scopes ...string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Scopes = append(o.Scopes, scopes...)
+	}
+}
+
+// WithHandlerRoles is used to require an authenticated principal with all the provided roles for the handler
+// executions. Roles provided several times, for example on a handlers group and on its handlers, are all required.
+func WithHandlerRoles(roles ...string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Roles = append(o.Roles, roles...)
+	}
+}
+
+// WithHandlerOperationID is used to specify the handler OpenAPI operation id.
+func WithHandlerOperationID(operationID string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.OperationID = operationID
+	}
+}
+
+// WithHandlerSummary is used to specify the handler OpenAPI summary.
+func WithHandlerSummary(summary string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Summary = summary
+	}
+}
+
+// WithHandlerDescription is used to specify the handler OpenAPI description, also displayed in the debug routes.
+func WithHandlerDescription(description string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Description = description
+	}
+}
+
+// WithHandlerTags is used to specify the handler OpenAPI tags, also displayed in the debug routes. Tags provided
+// several times, for example on a handlers group and on its handlers, are all applied.
+func WithHandlerTags(tags ...string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Tags = append(o.Tags, tags...)
+	}
+}
+
+// WithHandlerDeprecated is used to flag the handler as deprecated in OpenAPI.
+func WithHandlerDeprecated() HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Deprecated = true
+	}
+}
+
+// WithHandlerRequest is used to document the handler request in OpenAPI, from a value of the type it binds:
+// the struct fields tagged with param, query or header are documented as parameters, and the other ones as json body.
+func WithHandlerRequest(request any) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Request = request
+	}
+}
+
+// WithHandlerResponse is used to document a handler response in OpenAPI, from its status and a value of the type
+// of its body (nil for responses without body).
+func WithHandlerResponse(status int, body any) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Responses = append(o.Responses, openapi.ResponseSpec{
+			Status: status,
+			Body:   body,
+		})
+	}
+}
+
+// WithHandlerCompression is used to compress the handler responses, with the modules.http.server.compression settings,
+// when the compression is not enabled globally.
+func WithHandlerCompression() HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Compression = true
+	}
+}
+
+// WithHandlerETag is used to generate ETags for the handler responses, and to handle their conditional requests, when
+// modules.http.server.etag is not enabled globally.
+func WithHandlerETag() HandlerOption {
+	return func(o *HandlerOptions) {
+		o.ETag = true
+	}
+}
+
+// WithHandlerIdempotency is used to honour the Idempotency-Key header of the handler requests, with the
+// modules.http.server.idempotency settings, when the idempotency is not enabled globally.
+func WithHandlerIdempotency() HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Idempotency = true
+	}
+}
+
+// withHandlerStream is used to register the handler as a stream handler, see [AsWebSocketHandler] and [AsSSEHandler].
+func withHandlerStream(kind stream.Kind) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Stream = kind
+	}
+}
+
+// WithHandlerName is used to name the handler route, as displayed in the debug routes. The named routes behaviour
+// can be overridden from modules.http.server.routes.<name> (enabled, timeout, log and trace exclusion).
+func WithHandlerName(name string) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.Name = name
+	}
+}
+
+// WithHandlerMetadata is used to attach arbitrary metadata to the handler route, as displayed in the debug routes.
+func WithHandlerMetadata(key string, value any) HandlerOption {
+	return func(o *HandlerOptions) {
+		if o.Metadata == nil {
+			o.Metadata = map[string]any{}
+		}
+
+		o.Metadata[key] = value
+	}
+}
+
+// ResolveHandlerOptions resolves [HandlerOptions] from a list of [HandlerOption].
+func ResolveHandlerOptions(options ...HandlerOption) HandlerOptions {
+	resolvedOptions := DefaultHandlerOptions()
+	for _, applyOpt := range options {
+		applyOpt(&resolvedOptions)
+	}
+
+	return resolvedOptions
+}
+
+func splitHandlerOptions(middlewares []any) ([]any, []HandlerOption) {
+	var handlerMiddlewares []any
+	var handlerOptions []HandlerOption
+
+	for _, middleware := range middlewares {
+		if option, ok := middleware.(HandlerOption); ok {
+			handlerOptions = append(handlerOptions, option)
+		} else {
+			handlerMiddlewares = append(handlerMiddlewares, middleware)
+		}
+	}
+
+	return handlerMiddlewares, handlerOptions
+}
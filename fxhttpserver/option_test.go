@@ -0,0 +1,119 @@
+package fxhttpserver_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultHandlerOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := fxhttpserver.DefaultHandlerOptions()
+
+	assert.Equal(t, time.Duration(0), opts.Timeout)
+	assert.Equal(t, http.StatusServiceUnavailable, opts.TimeoutStatus)
+	assert.False(t, opts.RequiresAuthentication())
+}
+
+func TestResolveHandlerOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := fxhttpserver.ResolveHandlerOptions(
+		fxhttpserver.WithHandlerTimeout(5*time.Second),
+		fxhttpserver.WithHandlerTimeoutStatus(http.StatusGatewayTimeout),
+	)
+
+	assert.Equal(t, 5*time.Second, opts.Timeout)
+	assert.Equal(t, http.StatusGatewayTimeout, opts.TimeoutStatus)
+}
+
+func TestResolveHandlerOptionsWithRateLimit(t *testing.T) {
+	t.Parallel()
+
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+
+	opts := fxhttpserver.ResolveHandlerOptions(
+		fxhttpserver.WithHandlerRateLimit("test", limit),
+		fxhttpserver.WithHandlerRateLimitKey(ratelimit.KeyByRoute()),
+	)
+
+	assert.Equal(t, "test", opts.RateLimitName)
+	assert.Equal(t, limit, opts.RateLimit)
+	assert.NotNil(t, opts.RateLimitKeyExtractor)
+}
+
+func TestResolveHandlerOptionsWithAuthentication(t *testing.T) {
+	t.Parallel()
+
+	opts := fxhttpserver.ResolveHandlerOptions(fxhttpserver.WithHandlerAuthentication())
+
+	assert.True(t, opts.RequiresAuthentication())
+	assert.Empty(t, opts.Authenticators)
+
+	opts = fxhttpserver.ResolveHandlerOptions(
+		fxhttpserver.WithHandlerAuthentication("jwt", "api_key"),
+		fxhttpserver.WithHandlerScopes("read"),
+		fxhttpserver.WithHandlerScopes("write"),
+		fxhttpserver.WithHandlerRoles("admin"),
+	)
+
+	assert.True(t, opts.RequiresAuthentication())
+	assert.Equal(t, []string{"jwt", "api_key"}, opts.Authenticators)
+	assert.Equal(t, []string{"read", "write"}, opts.Scopes)
+	assert.Equal(t, []string{"admin"}, opts.Roles)
+
+	opts = fxhttpserver.ResolveHandlerOptions(fxhttpserver.WithHandlerRoles("admin"))
+
+	assert.True(t, opts.RequiresAuthentication())
+}
+
+func TestResolveHandlerOptionsWithOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	opts := fxhttpserver.ResolveHandlerOptions(
+		fxhttpserver.WithHandlerOperationID("createUser"),
+		fxhttpserver.WithHandlerSummary("summary"),
+		fxhttpserver.WithHandlerDescription("description"),
+		fxhttpserver.WithHandlerTags("users"),
+		fxhttpserver.WithHandlerTags("admin"),
+		fxhttpserver.WithHandlerDeprecated(),
+		fxhttpserver.WithHandlerRequest("request"),
+		fxhttpserver.WithHandlerResponse(http.StatusCreated, "response"),
+		fxhttpserver.WithHandlerResponse(http.StatusNoContent, nil),
+	)
+
+	assert.Equal(t, "createUser", opts.OperationID)
+	assert.Equal(t, "summary", opts.Summary)
+	assert.Equal(t, "description", opts.Description)
+	assert.Equal(t, []string{"users", "admin"}, opts.Tags)
+	assert.True(t, opts.Deprecated)
+	assert.Equal(t, "request", opts.Request)
+	assert.Equal(
+		t,
+		[]openapi.ResponseSpec{
+			{Status: http.StatusCreated, Body: "response"},
+			{Status: http.StatusNoContent},
+		},
+		opts.Responses,
+	)
+	assert.False(t, opts.RequiresAuthentication())
+}
+
+func TestResolveHandlerOptionsWithRouteMetadata(t *testing.T) {
+	t.Parallel()
+
+	opts := fxhttpserver.ResolveHandlerOptions(
+		fxhttpserver.WithHandlerName("list-users"),
+		fxhttpserver.WithHandlerMetadata("owner", "team-a"),
+		fxhttpserver.WithHandlerMetadata("internal", true),
+	)
+
+	assert.Equal(t, "list-users", opts.Name)
+	assert.Equal(t, map[string]any{"owner": "team-a", "internal": true}, opts.Metadata)
+}
@@ -0,0 +1,38 @@
+package fxhttpserver
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/go-playground/validator/v10"
+)
+
+// NewValidationErrorMapping returns an [httpserver.ErrorMapping] for the [validator.ValidationErrors], mapped to a
+// 400 status code with the details of each invalid field under the invalid-params extension member.
+//
+// The errors returned by the [Validator] keep the validated struct type, so their fields are reported with their json,
+// param, query or header names (and in body, path, query or header).
+func NewValidationErrorMapping() *httpserver.ErrorMapping {
+	mapping := httpserver.NewErrorTypeMapping[validator.ValidationErrors](http.StatusBadRequest, "", "")
+	mapping.Extensions = func(err error) map[string]any {
+		var structValidationErr *StructValidationError
+		if errors.As(err, &structValidationErr) {
+			return map[string]any{
+				httpserver.InvalidParamsExtension: typedInvalidParams(
+					structValidationErr.Errors,
+					structValidationErr.Type,
+				),
+			}
+		}
+
+		var validationErrs validator.ValidationErrors
+		errors.As(err, &validationErrs)
+
+		return map[string]any{
+			httpserver.InvalidParamsExtension: typedInvalidParams(validationErrs, nil),
+		}
+	}
+
+	return mapping
+}
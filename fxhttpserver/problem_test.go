@@ -0,0 +1,107 @@
+package fxhttpserver_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+var errTestOutOfStock = errors.New("out of stock")
+
+func TestModuleWithProblemDetails(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("ERRORS_PROBLEM_DETAILS", "true")
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Supply(validator.New()),
+		fx.Options(
+			fxhttpserver.AsErrorMapping(
+				httpserver.NewErrorMapping(
+					errTestOutOfStock,
+					http.StatusConflict,
+					"https://example.com/problems/out-of-stock",
+					"Out of stock",
+				),
+			),
+			fxhttpserver.AsHandler("GET", "/order", func(c echo.Context) error {
+				return fmt.Errorf("cannot order: %w", errTestOutOfStock)
+			}),
+			fxhttpserver.AsHandler("GET", "/validate", func(c echo.Context) error {
+				return c.Validate(&struct {
+					Page     int    `query:"page" validate:"gte=1"`
+					UserName string `json:"user_name" validate:"required"`
+				}{})
+			}),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// mapped error
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, httpserver.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(
+		t,
+		fmt.Sprintf(
+			`{
+				"type":"https://example.com/problems/out-of-stock",
+				"title":"Out of stock",
+				"status":409,
+				"detail":"cannot order: out of stock",
+				"instance":"%s"
+			}`,
+			rec.Header().Get(echo.HeaderXRequestID),
+		),
+		rec.Body.String(),
+	)
+
+	// validator error
+	req = httptest.NewRequest(http.MethodGet, "/validate", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(
+		t,
+		rec.Body.String(),
+		`"invalid-params":[`+
+			`{"name":"page","in":"query","reason":"failed on the gte=1 validation"},`+
+			`{"name":"user_name","in":"body","reason":"failed on the required validation"}]`,
+	)
+
+	// not found
+	req = httptest.NewRequest(http.MethodGet, "/not-found", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"title":"Not Found"`)
+}
@@ -0,0 +1,160 @@
+package fxhttpserver
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+// GlobalRateLimitName is the name of the global rate limit, configured in modules.http.server.ratelimit.
+const GlobalRateLimitName = "global"
+
+// FxHttpServerRateLimitStoreParam allows injection of the required dependencies in [NewFxHttpServerRateLimitStore].
+type FxHttpServerRateLimitStoreParam struct {
+	fx.In
+	LifeCycle fx.Lifecycle
+	Config    *config.Config
+	Db        *sql.DB `optional:"true"`
+}
+
+// NewFxHttpServerRateLimitStore returns the [ratelimit.Store] configured in modules.http.server.ratelimit.store:
+// in memory by default, or in the SQL database provided by the fxsql module.
+func NewFxHttpServerRateLimitStore(p FxHttpServerRateLimitStoreParam) (ratelimit.Store, error) {
+	switch storeType := p.Config.GetString("modules.http.server.ratelimit.store.type"); storeType {
+	case "", "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "sql":
+		return createSQLStore(
+			p.LifeCycle,
+			p.Config,
+			p.Db,
+			"rate limit",
+			"modules.http.server.ratelimit.store",
+			func(db *sql.DB, dialect sqlstore.Dialect, table string) (*ratelimit.SQLStore, error) {
+				var options []ratelimit.SQLStoreOption
+				if table != "" {
+					options = append(options, ratelimit.WithSQLStoreTable(table))
+				}
+
+				return ratelimit.NewSQLStore(db, dialect, options...)
+			},
+		)
+	default:
+		return nil, fmt.Errorf("invalid rate limit store type %s", storeType)
+	}
+}
+
+// FxHttpServerRateLimiterParam allows injection of the required dependencies in [NewFxHttpServerRateLimiter].
+type FxHttpServerRateLimiterParam struct {
+	fx.In
+	Config          *config.Config
+	Store           ratelimit.Store
+	MetricsRegistry *prometheus.Registry
+}
+
+// RateLimiter creates the http server rate limiting middlewares, sharing the same [ratelimit.Store].
+type RateLimiter struct {
+	config   *config.Config
+	store    ratelimit.Store
+	registry *prometheus.Registry
+}
+
+// NewFxHttpServerRateLimiter returns a new [RateLimiter].
+func NewFxHttpServerRateLimiter(p FxHttpServerRateLimiterParam) *RateLimiter {
+	return &RateLimiter{
+		config:   p.Config,
+		store:    p.Store,
+		registry: p.MetricsRegistry,
+	}
+}
+
+// GlobalMiddleware returns the global rate limiting middleware configured in modules.http.server.ratelimit,
+// or nil if disabled.
+func (r *RateLimiter) GlobalMiddleware() (echo.MiddlewareFunc, error) {
+	if !r.config.GetBool("modules.http.server.ratelimit.enabled") {
+		return nil, nil
+	}
+
+	config, err := r.middlewareConfig(GlobalRateLimitName, "modules.http.server.ratelimit", ratelimit.Limit{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if exclude := r.config.GetStringSlice("modules.http.server.ratelimit.exclude"); len(exclude) > 0 {
+		config.Skipper = func(c echo.Context) bool {
+			return httpserver.MatchPrefix(exclude, c.Request().URL.Path)
+		}
+	}
+
+	return httpservermiddleware.RequestRateLimitMiddlewareWithConfig(config), nil
+}
+
+// Middleware returns the rate limiting middleware of a named limit.
+// The limit and key extractor can be overridden in modules.http.server.ratelimit.limits.<name>.
+func (r *RateLimiter) Middleware(name string, limit ratelimit.Limit, keyExtractor ratelimit.KeyExtractor) (echo.MiddlewareFunc, error) {
+	config, err := r.middlewareConfig(name, fmt.Sprintf("modules.http.server.ratelimit.limits.%s", name), limit, keyExtractor)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpservermiddleware.RequestRateLimitMiddlewareWithConfig(config), nil
+}
+
+func (r *RateLimiter) middlewareConfig(
+	name string,
+	prefix string,
+	limit ratelimit.Limit,
+	keyExtractor ratelimit.KeyExtractor,
+) (httpservermiddleware.RequestRateLimitMiddlewareConfig, error) {
+	if r.config.IsSet(prefix + ".algorithm") {
+		limit.Algorithm = ratelimit.FetchAlgorithm(r.config.GetString(prefix + ".algorithm"))
+	}
+
+	if r.config.IsSet(prefix + ".requests") {
+		limit.Requests = r.config.GetInt(prefix + ".requests")
+	}
+
+	if r.config.IsSet(prefix + ".period") {
+		limit.Period = r.config.GetDuration(prefix + ".period")
+	}
+
+	if r.config.IsSet(prefix + ".burst") {
+		limit.Burst = r.config.GetInt(prefix + ".burst")
+	}
+
+	if r.config.IsSet(prefix + ".key") {
+		keyExtractor = ratelimit.FetchKeyExtractor(
+			r.config.GetString(prefix+".key"),
+			r.config.GetString(prefix+".key_name"),
+		)
+	}
+
+	if err := limit.Validate(); err != nil {
+		return httpservermiddleware.RequestRateLimitMiddlewareConfig{}, fmt.Errorf("invalid %s rate limit: %w", name, err)
+	}
+
+	var registry prometheus.Registerer
+	if r.registry != nil {
+		registry = r.registry
+	}
+
+	return httpservermiddleware.RequestRateLimitMiddlewareConfig{
+		Name:                 name,
+		Store:                r.store,
+		Limit:                limit,
+		KeyExtractor:         keyExtractor,
+		DenyOnStoreError:     r.config.GetBool("modules.http.server.ratelimit.deny_on_store_error"),
+		Registry:             registry,
+		Namespace:            Sanitize(r.config.GetString("modules.http.server.metrics.collect.namespace")),
+		Subsystem:            Sanitize(r.config.GetString("modules.http.server.metrics.collect.subsystem")),
+		NormalizeRequestPath: true,
+	}, nil
+}
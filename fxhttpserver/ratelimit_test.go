@@ -0,0 +1,289 @@
+package fxhttpserver_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModuleWithGlobalRateLimit(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("RATELIMIT_ENABLED", "true")
+
+	var httpServer *echo.Echo
+	var metricsRegistry *prometheus.Registry
+
+	testHandler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/limited", testHandler),
+			fxhttpserver.AsHandler("GET", "/excluded", testHandler),
+		),
+		fx.Populate(&httpServer, &metricsRegistry),
+	).RequireStart().RequireStop()
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		assert.Equal(t, "2", rec.Header().Get(middleware.HeaderRateLimitLimit))
+
+		if i < 2 {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+			assert.NotEmpty(t, rec.Header().Get(echo.HeaderRetryAfter))
+		}
+	}
+
+	// other client
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// excluded
+	for i := 0; i < 3; i++ {
+		req = httptest.NewRequest(http.MethodGet, "/excluded", nil)
+		rec = httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(middleware.HeaderRateLimitLimit))
+	}
+
+	expectedMetric := `
+		# HELP http_server_requests_rate_limited_total Number of rate limited HTTP requests
+		# TYPE http_server_requests_rate_limited_total counter
+		http_server_requests_rate_limited_total{limit="global",method="GET",path="/limited"} 1
+	`
+
+	err := testutil.GatherAndCompare(
+		metricsRegistry,
+		strings.NewReader(expectedMetric),
+		"http_server_requests_rate_limited_total",
+	)
+	assert.NoError(t, err)
+
+	expectedMetric = `
+		# HELP http_server_requests_total Number of processed HTTP requests
+		# TYPE http_server_requests_total counter
+		http_server_requests_total{method="GET",path="/excluded",status="2xx"} 3
+		http_server_requests_total{method="GET",path="/limited",status="2xx"} 3
+		http_server_requests_total{method="GET",path="/limited",status="4xx"} 1
+	`
+
+	err = testutil.GatherAndCompare(
+		metricsRegistry,
+		strings.NewReader(expectedMetric),
+		"http_server_requests_total",
+	)
+	assert.NoError(t, err)
+}
+
+func TestModuleWithHandlerRateLimit(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+
+	testHandler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			// limit declared in code
+			fxhttpserver.AsHandler(
+				"GET",
+				"/search",
+				testHandler,
+				fxhttpserver.WithHandlerRateLimit("search", ratelimit.Limit{Requests: 1, Period: time.Hour}),
+				fxhttpserver.WithHandlerRateLimitKey(ratelimit.KeyByHeader("X-Api-Key")),
+			),
+			// limit overridden in config
+			fxhttpserver.AsHandlersGroup(
+				"/auth",
+				[]*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration(
+						"POST",
+						"/login",
+						testHandler,
+						fxhttpserver.WithHandlerRateLimit("login", ratelimit.Limit{Requests: 1, Period: time.Hour}),
+					),
+				},
+			),
+			fxhttpserver.AsHandler("GET", "/unlimited", testHandler),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	send := func(method string, path string, apiKey string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-Api-Key", apiKey)
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/search", "key1"))
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodGet, "/search", "key1"))
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/search", "key2"))
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, send(http.MethodPost, "/auth/login", ""))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost, "/auth/login", ""))
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/unlimited", ""))
+	}
+}
+
+func TestModuleWithSQLRateLimitStore(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("RATELIMIT_ENABLED", "true")
+	t.Setenv("RATELIMIT_STORE_TYPE", "sql")
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	defer db.Close()
+
+	var httpServer *echo.Echo
+	var store ratelimit.Store
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Supply(db),
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/limited", func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			}),
+		),
+		fx.Populate(&httpServer, &store),
+	).RequireStart().RequireStop()
+
+	assert.IsType(t, &ratelimit.SQLStore{}, store)
+
+	codes := []int{}
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		codes = append(codes, rec.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+
+	var count int
+	err = db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM http_server_rate_limits").Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestModuleWithSQLRateLimitStoreWithoutDatabase(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("RATELIMIT_STORE_TYPE", "sql")
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Invoke(func(*echo.Echo) {}),
+	)
+
+	assert.Error(t, app.Err())
+	assert.Contains(t, app.Err().Error(), "sql rate limit store requires a sql database")
+}
+
+func TestModuleWithInvalidHandlerRateLimit(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var logBuffer logtest.TestLogBuffer
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/search", func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			}, fxhttpserver.WithHandlerRateLimit("search", ratelimit.Limit{})),
+		),
+		fx.Populate(&httpServer, &logBuffer),
+	).RequireStart().RequireStop()
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "error",
+		"message": "cannot resolve router handlers: invalid search rate limit: invalid rate limit of 0 requests per 0s",
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/search", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
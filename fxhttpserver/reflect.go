@@ -1,8 +1,12 @@
 package fxhttpserver
 
 import (
+	"fmt"
 	"reflect"
+	"runtime"
+	"strings"
 
+	"github.com/ankorstore/yokai/httpserver/stream"
 	"github.com/labstack/echo/v4"
 )
 
@@ -52,3 +56,49 @@ func IsConcreteMiddleware(middleware any) bool {
 func IsConcreteHandler(handler any) bool {
 	return reflect.TypeOf(handler).ConvertibleTo(reflect.TypeOf(echo.HandlerFunc(nil)))
 }
+
+// IsConcreteController returns true if the controller is a concrete [Controller] implementation.
+func IsConcreteController(controller any) bool {
+	_, ok := controller.(Controller)
+
+	return ok
+}
+
+// IsConcreteStreamHandler returns true if the handler is a concrete [stream.WebSocketHandlerFunc] or
+// [stream.SSEHandlerFunc] implementation, depending on the stream kind.
+func IsConcreteStreamHandler(handler any, kind stream.Kind) bool {
+	switch kind {
+	case stream.WebSocket:
+		return reflect.TypeOf(handler).ConvertibleTo(reflect.TypeOf(stream.WebSocketHandlerFunc(nil)))
+	case stream.SSE:
+		return reflect.TypeOf(handler).ConvertibleTo(reflect.TypeOf(stream.SSEHandlerFunc(nil)))
+	default:
+		return false
+	}
+}
+
+// GetControllerRouteName returns the route name of a controller handler, in the form "<package>.<Controller>.<Method>"
+// for the controller methods, or "<package>.<Controller>" for other handlers.
+func GetControllerRouteName(controller any, handler any) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", controller), "*")
+
+	value := reflect.ValueOf(handler)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return name
+	}
+
+	fn := runtime.FuncForPC(value.Pointer())
+	if fn == nil {
+		return name
+	}
+
+	// method values are named "<package path>.(<receiver>).<Method>-fm"
+	funcName := fn.Name()
+	if !strings.HasSuffix(funcName, "-fm") {
+		return name
+	}
+
+	funcName = strings.TrimSuffix(funcName, "-fm")
+
+	return name + "." + funcName[strings.LastIndex(funcName, ".")+1:]
+}
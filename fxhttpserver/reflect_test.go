@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/handler"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -108,3 +109,29 @@ func TestIsConcreteHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetControllerRouteName(t *testing.T) {
+	t.Parallel()
+
+	controller := handler.NewTestController(nil)
+
+	tests := []struct {
+		name     string
+		handler  any
+		expected string
+	}{
+		{"method", controller.List, "handler.TestController.List"},
+		{"closure", func(c echo.Context) error { return nil }, "handler.TestController"},
+		{"nil", nil, "handler.TestController"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, fxhttpserver.GetControllerRouteName(controller, tt.handler))
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package fxhttpserver
 
 import (
+	"net/http"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/stream"
 	"go.uber.org/fx"
 )
 
@@ -71,15 +75,20 @@ type HandlerRegistration struct {
 	path        string
 	handler     any
 	middlewares []any
+	options     []HandlerOption
 }
 
 // NewHandlerRegistration returns a new [HandlerRegistration].
+// The provided middlewares can contain [HandlerOption] to configure the handler.
 func NewHandlerRegistration(method string, path string, handler any, middlewares ...any) *HandlerRegistration {
+	handlerMiddlewares, handlerOptions := splitHandlerOptions(middlewares)
+
 	return &HandlerRegistration{
 		method:      method,
 		path:        path,
 		handler:     handler,
-		middlewares: middlewares,
+		middlewares: handlerMiddlewares,
+		options:     handlerOptions,
 	}
 }
 
@@ -103,7 +112,13 @@ func (h *HandlerRegistration) Middlewares() []any {
 	return h.middlewares
 }
 
+// Options returns the handler associated options.
+func (h *HandlerRegistration) Options() []HandlerOption {
+	return h.options
+}
+
 // AsHandler registers a handler into Fx.
+// The provided middlewares can contain [HandlerOption] to configure the handler, for example [WithHandlerTimeout].
 func AsHandler(method string, path string, handler any, middlewares ...any) fx.Option {
 	return RegisterHandler(NewHandlerRegistration(method, path, handler, middlewares...))
 }
@@ -131,20 +146,14 @@ func RegisterHandler(handlerRegistration *HandlerRegistration) fx.Option {
 	}
 
 	var handlerDef HandlerDefinition
-	if !IsConcreteHandler(handlerRegistration.Handler()) {
-		providers = append(
-			providers,
-			fx.Annotate(
-				handlerRegistration.Handler(),
-				fx.As(new(Handler)),
-				fx.ResultTags(`group:"httpserver-handlers"`),
-			),
-		)
+	if !isConcreteHandler(handlerRegistration.Handler(), handlerRegistration.Options()) {
+		providers = append(providers, handlerProvider(handlerRegistration.Handler(), handlerRegistration.Options()))
 		handlerDef = NewHandlerDefinition(
 			handlerRegistration.Method(),
 			handlerRegistration.Path(),
 			GetReturnType(handlerRegistration.Handler()),
 			middlewareDefs,
+			handlerRegistration.Options()...,
 		)
 	} else {
 		handlerDef = NewHandlerDefinition(
@@ -152,6 +161,7 @@ func RegisterHandler(handlerRegistration *HandlerRegistration) fx.Option {
 			handlerRegistration.Path(),
 			handlerRegistration.Handler(),
 			middlewareDefs,
+			handlerRegistration.Options()...,
 		)
 	}
 
@@ -167,19 +177,81 @@ func RegisterHandler(handlerRegistration *HandlerRegistration) fx.Option {
 	)
 }
 
+// NewWebSocketHandlerRegistration returns a new [HandlerRegistration] for a WebSocket handler on GET requests.
+// The handler can be a [stream.WebSocketHandlerFunc], or the constructor of a [WebSocketHandler] implementation.
+func NewWebSocketHandlerRegistration(path string, handler any, middlewares ...any) *HandlerRegistration {
+	return NewHandlerRegistration(http.MethodGet, path, handler, append(middlewares, withHandlerStream(stream.WebSocket))...)
+}
+
+// AsWebSocketHandler registers a WebSocket handler into Fx, see [NewWebSocketHandlerRegistration].
+// The provided middlewares can contain [HandlerOption] to configure the handler, for example [WithHandlerAuthentication].
+func AsWebSocketHandler(path string, handler any, middlewares ...any) fx.Option {
+	return RegisterHandler(NewWebSocketHandlerRegistration(path, handler, middlewares...))
+}
+
+// NewSSEHandlerRegistration returns a new [HandlerRegistration] for a Server-Sent Events handler on GET requests.
+// The handler can be a [stream.SSEHandlerFunc], or the constructor of a [SSEHandler] implementation.
+func NewSSEHandlerRegistration(path string, handler any, middlewares ...any) *HandlerRegistration {
+	return NewHandlerRegistration(http.MethodGet, path, handler, append(middlewares, withHandlerStream(stream.SSE))...)
+}
+
+// AsSSEHandler registers a Server-Sent Events handler into Fx, see [NewSSEHandlerRegistration].
+// The provided middlewares can contain [HandlerOption] to configure the handler, for example [WithHandlerAuthentication].
+func AsSSEHandler(path string, handler any, middlewares ...any) fx.Option {
+	return RegisterHandler(NewSSEHandlerRegistration(path, handler, middlewares...))
+}
+
+// isConcreteHandler returns true if the handler is a concrete implementation for its kind of handler.
+func isConcreteHandler(handler any, options []HandlerOption) bool {
+	if kind := ResolveHandlerOptions(options...).Stream; kind != "" {
+		return IsConcreteStreamHandler(handler, kind)
+	}
+
+	return IsConcreteHandler(handler)
+}
+
+// handlerProvider returns the Fx provider of a handler constructor, for its kind of handler.
+func handlerProvider(handler any, options []HandlerOption) any {
+	switch ResolveHandlerOptions(options...).Stream {
+	case stream.WebSocket:
+		return fx.Annotate(
+			handler,
+			fx.As(new(WebSocketHandler)),
+			fx.ResultTags(`group:"httpserver-websocket-handlers"`),
+		)
+	case stream.SSE:
+		return fx.Annotate(
+			handler,
+			fx.As(new(SSEHandler)),
+			fx.ResultTags(`group:"httpserver-sse-handlers"`),
+		)
+	default:
+		return fx.Annotate(
+			handler,
+			fx.As(new(Handler)),
+			fx.ResultTags(`group:"httpserver-handlers"`),
+		)
+	}
+}
+
 // HandlersGroupRegistration is a handlers group registration.
 type HandlersGroupRegistration struct {
 	prefix                string
 	handlersRegistrations []*HandlerRegistration
 	middlewares           []any
+	options               []HandlerOption
 }
 
 // NewHandlersGroupRegistration returns a new [HandlersGroupRegistration].
+// The provided middlewares can contain [HandlerOption] to configure all the group handlers.
 func NewHandlersGroupRegistration(prefix string, handlersRegistrations []*HandlerRegistration, middlewares ...any) *HandlersGroupRegistration {
+	groupMiddlewares, groupOptions := splitHandlerOptions(middlewares)
+
 	return &HandlersGroupRegistration{
 		prefix:                prefix,
 		handlersRegistrations: handlersRegistrations,
-		middlewares:           middlewares,
+		middlewares:           groupMiddlewares,
+		options:               groupOptions,
 	}
 }
 
@@ -198,7 +270,13 @@ func (h *HandlersGroupRegistration) Middlewares() []any {
 	return h.middlewares
 }
 
+// Options returns the handlers group associated options, applied before the options of each handler.
+func (h *HandlersGroupRegistration) Options() []HandlerOption {
+	return h.options
+}
+
 // AsHandlersGroup registers a handlers group into Fx.
+// The provided middlewares can contain [HandlerOption] to configure all the group handlers, for example [WithHandlerScopes].
 func AsHandlersGroup(prefix string, handlersRegistrations []*HandlerRegistration, middlewares ...any) fx.Option {
 	return RegisterHandlersGroup(NewHandlersGroupRegistration(prefix, handlersRegistrations, middlewares...))
 }
@@ -230,6 +308,10 @@ func RegisterHandlersGroup(handlersGroupRegistration *HandlersGroupRegistration)
 		var handlerDef HandlerDefinition
 		var middlewareDefs []MiddlewareDefinition
 
+		var handlerOptions []HandlerOption
+		handlerOptions = append(handlerOptions, handlersGroupRegistration.Options()...)
+		handlerOptions = append(handlerOptions, handlerRegistration.Options()...)
+
 		for _, middleware := range handlerRegistration.Middlewares() {
 			if !IsConcreteMiddleware(middleware) {
 				providers = append(
@@ -247,20 +329,14 @@ func RegisterHandlersGroup(handlersGroupRegistration *HandlersGroupRegistration)
 			}
 		}
 
-		if !IsConcreteHandler(handlerRegistration.Handler()) {
-			providers = append(
-				providers,
-				fx.Annotate(
-					handlerRegistration.Handler(),
-					fx.As(new(Handler)),
-					fx.ResultTags(`group:"httpserver-handlers"`),
-				),
-			)
+		if !isConcreteHandler(handlerRegistration.Handler(), handlerOptions) {
+			providers = append(providers, handlerProvider(handlerRegistration.Handler(), handlerOptions))
 			handlerDef = NewHandlerDefinition(
 				handlerRegistration.Method(),
 				handlerRegistration.Path(),
 				GetReturnType(handlerRegistration.Handler()),
 				middlewareDefs,
+				handlerOptions...,
 			)
 		} else {
 			handlerDef = NewHandlerDefinition(
@@ -268,6 +344,7 @@ func RegisterHandlersGroup(handlersGroupRegistration *HandlersGroupRegistration)
 				handlerRegistration.Path(),
 				handlerRegistration.Handler(),
 				middlewareDefs,
+				handlerOptions...,
 			)
 		}
 
@@ -292,6 +369,106 @@ func RegisterHandlersGroup(handlersGroupRegistration *HandlersGroupRegistration)
 	)
 }
 
+// ControllerRegistration is a controller registration.
+type ControllerRegistration struct {
+	controller  any
+	middlewares []any
+	options     []HandlerOption
+}
+
+// NewControllerRegistration returns a new [ControllerRegistration].
+// The provided middlewares can contain [HandlerOption] to configure all the controller routes.
+func NewControllerRegistration(controller any, middlewares ...any) *ControllerRegistration {
+	controllerMiddlewares, controllerOptions := splitHandlerOptions(middlewares)
+
+	return &ControllerRegistration{
+		controller:  controller,
+		middlewares: controllerMiddlewares,
+		options:     controllerOptions,
+	}
+}
+
+// Controller returns the controller.
+func (c *ControllerRegistration) Controller() any {
+	return c.controller
+}
+
+// Middlewares returns the controller associated middlewares.
+func (c *ControllerRegistration) Middlewares() []any {
+	return c.middlewares
+}
+
+// Options returns the controller associated options, applied before the options of each route.
+func (c *ControllerRegistration) Options() []HandlerOption {
+	return c.options
+}
+
+// AsController registers a controller into Fx.
+// The controller can be a [Controller] implementation, or its constructor (will be autowired from Fx container), and
+// its routes are registered as handlers named after the controller and their method.
+// The provided middlewares can contain [HandlerOption] to configure all the controller routes, for example [WithHandlerTags].
+func AsController(controller any, middlewares ...any) fx.Option {
+	return RegisterController(NewControllerRegistration(controller, middlewares...))
+}
+
+// RegisterController registers a controller registration into Fx.
+func RegisterController(controllerRegistration *ControllerRegistration) fx.Option {
+	var providers []any
+
+	var middlewareDefs []MiddlewareDefinition
+	for _, middleware := range controllerRegistration.Middlewares() {
+		if !IsConcreteMiddleware(middleware) {
+			providers = append(
+				providers,
+				fx.Annotate(
+					middleware,
+					fx.As(new(Middleware)),
+					fx.ResultTags(`group:"httpserver-middlewares"`),
+				),
+			)
+
+			middlewareDefs = append(middlewareDefs, NewMiddlewareDefinition(GetReturnType(middleware), Attached))
+		} else {
+			middlewareDefs = append(middlewareDefs, NewMiddlewareDefinition(middleware, Attached))
+		}
+	}
+
+	var controllerDef ControllerDefinition
+	if !IsConcreteController(controllerRegistration.Controller()) {
+		providers = append(
+			providers,
+			fx.Annotate(
+				controllerRegistration.Controller(),
+				fx.As(new(Controller)),
+				fx.ResultTags(`group:"httpserver-controllers"`),
+			),
+		)
+
+		controllerDef = NewControllerDefinition(
+			GetReturnType(controllerRegistration.Controller()),
+			middlewareDefs,
+			controllerRegistration.Options()...,
+		)
+	} else {
+		controllerDef = NewControllerDefinition(
+			controllerRegistration.Controller(),
+			middlewareDefs,
+			controllerRegistration.Options()...,
+		)
+	}
+
+	return fx.Options(
+		fx.Provide(providers...),
+		fx.Supply(
+			fx.Annotate(
+				controllerDef,
+				fx.As(new(ControllerDefinition)),
+				fx.ResultTags(`group:"httpserver-controller-definitions"`),
+			),
+		),
+	)
+}
+
 // AsErrorHandler replaces the default error handler.
 func AsErrorHandler(errorHandler any) fx.Option {
 	return fx.Provide(
@@ -302,3 +479,14 @@ func AsErrorHandler(errorHandler any) fx.Option {
 		),
 	)
 }
+
+// AsErrorMapping registers an [httpserver.ErrorMapping], to map errors to problem details when
+// modules.http.server.errors.problem_details=true.
+func AsErrorMapping(mapping *httpserver.ErrorMapping) fx.Option {
+	return fx.Supply(
+		fx.Annotate(
+			mapping,
+			fx.ResultTags(`group:"httpserver-error-mappings"`),
+		),
+	)
+}
@@ -3,9 +3,11 @@ package fxhttpserver_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/fxhttpserver"
 	"github.com/ankorstore/yokai/fxhttpserver/testdata/errorhandler"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/handler"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -82,6 +84,28 @@ func TestHandlerRegistration(t *testing.T) {
 	}
 }
 
+func TestHandlerRegistrationWithOptions(t *testing.T) {
+	t.Parallel()
+
+	type exampleMiddleware struct {
+		name string
+	}
+
+	mw := exampleMiddleware{name: "middleware"}
+
+	hr := fxhttpserver.NewHandlerRegistration(
+		"GET",
+		"/path",
+		"handler",
+		mw,
+		fxhttpserver.WithHandlerTimeout(time.Second),
+	)
+
+	assert.Equal(t, []any{mw}, hr.Middlewares())
+	assert.Len(t, hr.Options(), 1)
+	assert.Equal(t, time.Second, fxhttpserver.ResolveHandlerOptions(hr.Options()...).Timeout)
+}
+
 func TestHandlersGroupRegistration(t *testing.T) {
 	t.Parallel()
 
@@ -125,6 +149,29 @@ func TestHandlersGroupRegistration(t *testing.T) {
 	}
 }
 
+func TestHandlersGroupRegistrationWithOptions(t *testing.T) {
+	t.Parallel()
+
+	type exampleMiddleware struct {
+		name string
+	}
+
+	mw := exampleMiddleware{name: "middleware"}
+
+	hgr := fxhttpserver.NewHandlersGroupRegistration(
+		"/group",
+		[]*fxhttpserver.HandlerRegistration{
+			fxhttpserver.NewHandlerRegistration("GET", "/path", "handler"),
+		},
+		mw,
+		fxhttpserver.WithHandlerRoles("admin"),
+	)
+
+	assert.Equal(t, []any{mw}, hgr.Middlewares())
+	assert.Len(t, hgr.Options(), 1)
+	assert.Equal(t, []string{"admin"}, fxhttpserver.ResolveHandlerOptions(hgr.Options()...).Roles)
+}
+
 func TestErrorHandlerRegistration(t *testing.T) {
 	t.Parallel()
 
@@ -132,3 +179,20 @@ func TestErrorHandlerRegistration(t *testing.T) {
 
 	assert.Equal(t, "fx.provideOption", fmt.Sprintf("%T", result))
 }
+
+func TestControllerRegistration(t *testing.T) {
+	t.Parallel()
+
+	type exampleMiddleware struct {
+		name string
+	}
+
+	controller := handler.NewTestController
+	mw := exampleMiddleware{name: "middleware"}
+
+	cr := fxhttpserver.NewControllerRegistration(controller, mw, fxhttpserver.WithHandlerTags("test"))
+
+	assert.Equal(t, fxhttpserver.GetType(controller), fxhttpserver.GetType(cr.Controller()))
+	assert.Equal(t, []any{mw}, cr.Middlewares())
+	assert.Len(t, cr.Options(), 1)
+}
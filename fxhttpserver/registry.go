@@ -1,8 +1,13 @@
 package fxhttpserver
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 
+	"github.com/ankorstore/yokai/config"
+	httpservermiddleware "github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/stream"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
 )
@@ -17,6 +22,22 @@ type Handler interface {
 	Handle() echo.HandlerFunc
 }
 
+// WebSocketHandler is the interface for WebSocket handlers.
+type WebSocketHandler interface {
+	Handle(ctx context.Context, conn *stream.WebSocketConn) error
+}
+
+// SSEHandler is the interface for Server-Sent Events handlers.
+type SSEHandler interface {
+	Handle(ctx context.Context, stream *stream.SSEStream) error
+}
+
+// Controller is the interface for controllers, exposing several routes.
+// The routes handlers and middlewares must be concrete, for example the controller methods.
+type Controller interface {
+	Routes() []*HandlerRegistration
+}
+
 // ErrorHandler is the interface for error handlers.
 type ErrorHandler interface {
 	Handle() echo.HTTPErrorHandler
@@ -27,9 +48,18 @@ type HttpServerRegistry struct {
 	middlewares              []Middleware
 	middlewareDefinitions    []MiddlewareDefinition
 	handlers                 []Handler
+	webSocketHandlers        []WebSocketHandler
+	sseHandlers              []SSEHandler
 	handlerDefinitions       []HandlerDefinition
 	handlersGroupDefinitions []HandlersGroupDefinition
+	controllers              []Controller
+	controllerDefinitions    []ControllerDefinition
 	errorHandlers            []ErrorHandler
+	rateLimiter              *RateLimiter
+	authentication           *Authentication
+	idempotency              *Idempotency
+	streams                  *Streams
+	config                   *config.Config
 }
 
 // FxHttpServerRegistryParam allows injection of the required dependencies in [NewFxHttpServerRegistry].
@@ -38,9 +68,18 @@ type FxHttpServerRegistryParam struct {
 	Middlewares              []Middleware              `group:"httpserver-middlewares"`
 	MiddlewareDefinitions    []MiddlewareDefinition    `group:"httpserver-middleware-definitions"`
 	Handlers                 []Handler                 `group:"httpserver-handlers"`
+	WebSocketHandlers        []WebSocketHandler        `group:"httpserver-websocket-handlers"`
+	SSEHandlers              []SSEHandler              `group:"httpserver-sse-handlers"`
 	HandlerDefinitions       []HandlerDefinition       `group:"httpserver-handler-definitions"`
 	HandlersGroupDefinitions []HandlersGroupDefinition `group:"httpserver-handlers-group-definitions"`
+	Controllers              []Controller              `group:"httpserver-controllers"`
+	ControllerDefinitions    []ControllerDefinition    `group:"httpserver-controller-definitions"`
 	ErrorHandlers            []ErrorHandler            `group:"httpserver-error-handlers"`
+	RateLimiter              *RateLimiter              `optional:"true"`
+	Authentication           *Authentication           `optional:"true"`
+	Idempotency              *Idempotency              `optional:"true"`
+	Streams                  *Streams                  `optional:"true"`
+	Config                   *config.Config            `optional:"true"`
 }
 
 // NewFxHttpServerRegistry returns as new [HttpServerRegistry].
@@ -50,12 +89,52 @@ func NewFxHttpServerRegistry(p FxHttpServerRegistryParam) *HttpServerRegistry {
 		middlewares:              p.Middlewares,
 		middlewareDefinitions:    p.MiddlewareDefinitions,
 		handlers:                 p.Handlers,
+		webSocketHandlers:        p.WebSocketHandlers,
+		sseHandlers:              p.SSEHandlers,
 		handlerDefinitions:       p.HandlerDefinitions,
 		handlersGroupDefinitions: p.HandlersGroupDefinitions,
+		controllers:              p.Controllers,
+		controllerDefinitions:    p.ControllerDefinitions,
 		errorHandlers:            p.ErrorHandlers,
+		rateLimiter:              p.RateLimiter,
+		authentication:           p.Authentication,
+		idempotency:              p.Idempotency,
+		streams:                  p.Streams,
+		config:                   p.Config,
 	}
 }
 
+// HandlerDefinitions returns the registered handlers definitions.
+func (r *HttpServerRegistry) HandlerDefinitions() []HandlerDefinition {
+	return r.handlerDefinitions
+}
+
+// HandlersGroupDefinitions returns the registered handlers groups definitions.
+func (r *HttpServerRegistry) HandlersGroupDefinitions() []HandlersGroupDefinition {
+	return r.handlersGroupDefinitions
+}
+
+// ControllerDefinitions returns the registered controllers definitions.
+func (r *HttpServerRegistry) ControllerDefinitions() []ControllerDefinition {
+	return r.controllerDefinitions
+}
+
+// ControllersHandlerDefinitions returns the handlers definitions of the registered controllers routes.
+func (r *HttpServerRegistry) ControllersHandlerDefinitions() ([]HandlerDefinition, error) {
+	var handlerDefs []HandlerDefinition
+
+	for _, controllerDef := range r.controllerDefinitions {
+		controllerHandlerDefs, err := r.resolveControllerDefinition(controllerDef)
+		if err != nil {
+			return nil, err
+		}
+
+		handlerDefs = append(handlerDefs, controllerHandlerDefs...)
+	}
+
+	return handlerDefs, nil
+}
+
 // ResolveMiddlewares resolves a list of [ResolvedMiddleware] from their definitions.
 func (r *HttpServerRegistry) ResolveMiddlewares() ([]ResolvedMiddleware, error) {
 	var resolvedMiddlewares []ResolvedMiddleware
@@ -78,7 +157,16 @@ func (r *HttpServerRegistry) ResolveMiddlewares() ([]ResolvedMiddleware, error)
 func (r *HttpServerRegistry) ResolveHandlers() ([]ResolvedHandler, error) {
 	var resolvedHandlers []ResolvedHandler
 
-	for _, handlerDef := range r.handlerDefinitions {
+	controllersHandlerDefs, err := r.ControllersHandlerDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	handlerDefs := make([]HandlerDefinition, 0, len(r.handlerDefinitions)+len(controllersHandlerDefs))
+	handlerDefs = append(handlerDefs, r.handlerDefinitions...)
+	handlerDefs = append(handlerDefs, controllersHandlerDefs...)
+
+	for _, handlerDef := range handlerDefs {
 		var handlerMiddlewares []echo.MiddlewareFunc
 
 		for _, middlewareDef := range handlerDef.Middlewares() {
@@ -185,19 +273,104 @@ func (r *HttpServerRegistry) resolveMiddlewareDefinition(middlewareDefinition Mi
 }
 
 func (r *HttpServerRegistry) resolveHandlerDefinition(handlerDefinition HandlerDefinition, handlerMiddlewares []echo.MiddlewareFunc) (ResolvedHandler, error) {
+	handlerOptions := handlerDefinition.Options()
+
+	// named route timeout override
+	if handlerOptions.Name != "" && r.config != nil {
+		if timeoutKey := routeConfigKey(handlerOptions.Name, "timeout"); r.config.IsSet(timeoutKey) {
+			handlerOptions.Timeout = r.config.GetDuration(timeoutKey)
+		}
+	}
+
+	if handlerOptions.Timeout > 0 {
+		handlerMiddlewares = append(
+			[]echo.MiddlewareFunc{
+				httpservermiddleware.RequestTimeoutMiddlewareWithConfig(httpservermiddleware.RequestTimeoutMiddlewareConfig{
+					Timeout:    handlerOptions.Timeout,
+					StatusCode: handlerOptions.TimeoutStatus,
+				}),
+			},
+			handlerMiddlewares...,
+		)
+	}
+
+	// etag after compression, to be generated from the uncompressed responses
+	if handlerOptions.ETag && !r.globallyEnabled("modules.http.server.etag.enabled") {
+		handlerMiddlewares = append([]echo.MiddlewareFunc{createETagMiddleware(r.config, false)}, handlerMiddlewares...)
+	}
+
+	if handlerOptions.Compression && !r.globallyEnabled("modules.http.server.compression.enabled") {
+		handlerMiddlewares = append([]echo.MiddlewareFunc{createCompressionMiddleware(r.config, false)}, handlerMiddlewares...)
+	}
+
+	// idempotency before compression, to store the uncompressed responses
+	if handlerOptions.Idempotency && !r.globallyEnabled("modules.http.server.idempotency.enabled") {
+		if r.idempotency == nil {
+			return nil, fmt.Errorf("cannot make handler idempotent without idempotency")
+		}
+
+		handlerMiddlewares = append([]echo.MiddlewareFunc{r.idempotency.Middleware()}, handlerMiddlewares...)
+	}
+
+	if handlerOptions.RateLimitName != "" {
+		if r.rateLimiter == nil {
+			return nil, fmt.Errorf("cannot rate limit handler without rate limiter")
+		}
+
+		rateLimitMiddleware, err := r.rateLimiter.Middleware(
+			handlerOptions.RateLimitName,
+			handlerOptions.RateLimit,
+			handlerOptions.RateLimitKeyExtractor,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		handlerMiddlewares = append([]echo.MiddlewareFunc{rateLimitMiddleware}, handlerMiddlewares...)
+	}
+
+	// authentication first, for the rate limit to be able to key requests by principal
+	if handlerOptions.RequiresAuthentication() {
+		if r.authentication == nil {
+			return nil, fmt.Errorf("cannot authenticate handler without authentication")
+		}
+
+		handlerMiddlewares = append(
+			r.authentication.Middleware(handlerOptions.Authenticators, handlerOptions.Scopes, handlerOptions.Roles),
+			handlerMiddlewares...,
+		)
+	}
+
+	if handlerOptions.Stream != "" {
+		streamHandler, err := r.resolveStreamHandler(handlerDefinition)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewResolvedHandlerWithOptions(
+			handlerDefinition.Method(),
+			handlerDefinition.Path(),
+			streamHandler,
+			handlerOptions,
+			handlerMiddlewares...,
+		), nil
+	}
+
 	if handlerDefinition.Concrete() {
 		if castHandler, ok := handlerDefinition.Handler().(func(echo.Context) error); ok {
-			return NewResolvedHandler(
+			return NewResolvedHandlerWithOptions(
 				handlerDefinition.Method(),
 				handlerDefinition.Path(),
 				castHandler,
+				handlerOptions,
 				handlerMiddlewares...,
 			), nil
 		} else if castHandler, ok = handlerDefinition.Handler().(echo.HandlerFunc); ok {
-			return NewResolvedHandler(
+			return NewResolvedHandlerWithOptions(
 				handlerDefinition.Method(),
 				handlerDefinition.Path(),
 				castHandler,
+				handlerOptions,
 				handlerMiddlewares...,
 			), nil
 		} else {
@@ -215,14 +388,113 @@ func (r *HttpServerRegistry) resolveHandlerDefinition(handlerDefinition HandlerD
 		return nil, fmt.Errorf("cannot lookup registered handler")
 	}
 
-	return NewResolvedHandler(
+	return NewResolvedHandlerWithOptions(
 		handlerDefinition.Method(),
 		handlerDefinition.Path(),
 		registeredHandler.Handle(),
+		handlerOptions,
 		handlerMiddlewares...,
 	), nil
 }
 
+//nolint:cyclop
+func (r *HttpServerRegistry) resolveStreamHandler(handlerDefinition HandlerDefinition) (echo.HandlerFunc, error) {
+	if r.streams == nil {
+		return nil, fmt.Errorf("cannot resolve stream handler without streams")
+	}
+
+	handlerName, _ := handlerDefinition.Handler().(string)
+
+	switch kind := handlerDefinition.Options().Stream; kind {
+	case stream.WebSocket:
+		if handlerDefinition.Concrete() {
+			return r.streams.WebSocketHandler(
+				reflect.ValueOf(handlerDefinition.Handler()).
+					Convert(reflect.TypeOf(stream.WebSocketHandlerFunc(nil))).
+					Interface().(stream.WebSocketHandlerFunc),
+			), nil
+		}
+
+		for _, h := range r.webSocketHandlers {
+			if GetType(h) == handlerName {
+				return r.streams.WebSocketHandler(h.Handle), nil
+			}
+		}
+
+		return nil, fmt.Errorf("cannot find websocket handler for type %s", handlerName)
+	case stream.SSE:
+		if handlerDefinition.Concrete() {
+			return r.streams.SSEHandler(
+				reflect.ValueOf(handlerDefinition.Handler()).
+					Convert(reflect.TypeOf(stream.SSEHandlerFunc(nil))).
+					Interface().(stream.SSEHandlerFunc),
+			), nil
+		}
+
+		for _, h := range r.sseHandlers {
+			if GetType(h) == handlerName {
+				return r.streams.SSEHandler(h.Handle), nil
+			}
+		}
+
+		return nil, fmt.Errorf("cannot find sse handler for type %s", handlerName)
+	default:
+		return nil, fmt.Errorf("invalid stream kind %s", kind)
+	}
+}
+
+func (r *HttpServerRegistry) resolveControllerDefinition(controllerDefinition ControllerDefinition) ([]HandlerDefinition, error) {
+	var controller Controller
+
+	if controllerDefinition.Concrete() {
+		controller, _ = controllerDefinition.Controller().(Controller)
+	} else {
+		controllerName, ok := controllerDefinition.Controller().(string)
+		if !ok {
+			return nil, fmt.Errorf("controller definition is not a registered controller name")
+		}
+
+		registeredController, err := r.lookupRegisteredController(controllerName)
+		if err != nil {
+			return nil, err
+		}
+
+		controller = registeredController
+	}
+
+	var handlerDefs []HandlerDefinition
+
+	for _, route := range controller.Routes() {
+		middlewareDefs := append([]MiddlewareDefinition{}, controllerDefinition.Middlewares()...)
+
+		for _, middleware := range route.Middlewares() {
+			if !IsConcreteMiddleware(middleware) {
+				return nil, fmt.Errorf("controller %T route %s %s middleware is not concrete", controller, route.Method(), route.Path())
+			}
+
+			middlewareDefs = append(middlewareDefs, NewMiddlewareDefinition(middleware, Attached))
+		}
+
+		var handlerOptions []HandlerOption
+		handlerOptions = append(handlerOptions, WithHandlerName(GetControllerRouteName(controller, route.Handler())))
+		handlerOptions = append(handlerOptions, controllerDefinition.Options()...)
+		handlerOptions = append(handlerOptions, route.Options()...)
+
+		handlerDef := NewHandlerDefinition(route.Method(), route.Path(), route.Handler(), middlewareDefs, handlerOptions...)
+		if !handlerDef.Concrete() {
+			return nil, fmt.Errorf("controller %T route %s %s handler is not concrete", controller, route.Method(), route.Path())
+		}
+
+		handlerDefs = append(handlerDefs, handlerDef)
+	}
+
+	return handlerDefs, nil
+}
+
+func (r *HttpServerRegistry) globallyEnabled(key string) bool {
+	return r.config != nil && r.config.GetBool(key)
+}
+
 func (r *HttpServerRegistry) lookupRegisteredMiddleware(middleware string) (Middleware, error) {
 	for _, m := range r.middlewares {
 		if GetType(m) == middleware {
@@ -233,6 +505,16 @@ func (r *HttpServerRegistry) lookupRegisteredMiddleware(middleware string) (Midd
 	return nil, fmt.Errorf("cannot find middleware for type %s", middleware)
 }
 
+func (r *HttpServerRegistry) lookupRegisteredController(controller string) (Controller, error) {
+	for _, c := range r.controllers {
+		if GetType(c) == controller {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot find controller for type %s", controller)
+}
+
 func (r *HttpServerRegistry) lookupRegisteredHandler(handler string) (Handler, error) {
 	for _, h := range r.handlers {
 		if GetType(h) == handler {
@@ -78,6 +78,13 @@ func (m *testHandlerDefinitionMock) Middlewares() []fxhttpserver.MiddlewareDefin
 	return args.Get(0).([]fxhttpserver.MiddlewareDefinition)
 }
 
+func (m *testHandlerDefinitionMock) Options() fxhttpserver.HandlerOptions {
+	args := m.Called()
+
+	//nolint:forcetypeassert
+	return args.Get(0).(fxhttpserver.HandlerOptions)
+}
+
 type testMiddlewareImplementation struct{}
 
 func (m testMiddlewareImplementation) Handle() echo.MiddlewareFunc {
@@ -211,6 +218,7 @@ func TestResolveHandlersSuccessWithHandlerFuncType(t *testing.T) {
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return(h)
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{
@@ -239,6 +247,7 @@ func TestResolveHandlersFailureOnMissingHandlerImplementation(t *testing.T) {
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return("invalid")
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{},
@@ -262,6 +271,7 @@ func TestResolveHandlersFailureOnInvalidHandlerImplementation(t *testing.T) {
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return("invalid")
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{
@@ -292,6 +302,7 @@ func TestResolveHandlersFailureOnInvalidMiddlewareImplementation(t *testing.T) {
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return("invalid")
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{middlewareDefinitionMock})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{
@@ -322,6 +333,7 @@ func TestResolveHandlersFailureOnMissingMiddlewareImplementation(t *testing.T) {
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return("invalid")
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{middlewareDefinitionMock})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{
@@ -385,6 +397,7 @@ func TestResolveHandlersGroupFailureOnMissingGroupMiddlewareImplementation(t *te
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return("invalid")
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{middlewareDefinitionMock})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{
@@ -418,6 +431,7 @@ func TestResolveHandlersGroupFailureOnInvalidHandlerImplementation(t *testing.T)
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return("invalid")
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{
@@ -454,6 +468,7 @@ func TestResolveHandlersGroupFailureOnInvalidHandlerMiddlewareImplementation(t *
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return("invalid")
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{middlewareDefinitionMock})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{
@@ -523,6 +538,7 @@ func TestResolveHandlersFailureOnNonStringHandlerName(t *testing.T) {
 	handlerDefinitionMock.On("Path").Return("/path")
 	handlerDefinitionMock.On("Handler").Return(123)
 	handlerDefinitionMock.On("Middlewares").Return([]fxhttpserver.MiddlewareDefinition{})
+	handlerDefinitionMock.On("Options").Return(fxhttpserver.DefaultHandlerOptions())
 
 	param := fxhttpserver.FxHttpServerRegistryParam{
 		Handlers: []fxhttpserver.Handler{},
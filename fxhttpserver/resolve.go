@@ -33,10 +33,12 @@ func (r *resolvedMiddleware) Kind() MiddlewareKind {
 
 // ResolvedHandler is an interface for the resolved handlers.
 type ResolvedHandler interface {
+	Name() string
 	Method() string
 	Path() string
 	Handler() echo.HandlerFunc
 	Middlewares() []echo.MiddlewareFunc
+	Options() HandlerOptions
 }
 
 type resolvedHandler struct {
@@ -44,18 +46,44 @@ type resolvedHandler struct {
 	path        string
 	handler     echo.HandlerFunc
 	middlewares []echo.MiddlewareFunc
+	options     HandlerOptions
 }
 
 // NewResolvedHandler returns a new [ResolvedHandler].
 func NewResolvedHandler(method string, path string, handler echo.HandlerFunc, middlewares ...echo.MiddlewareFunc) ResolvedHandler {
+	return NewResolvedHandlerWithOptions(method, path, handler, DefaultHandlerOptions(), middlewares...)
+}
+
+// NewNamedResolvedHandler returns a new [ResolvedHandler], with a route name.
+func NewNamedResolvedHandler(name string, method string, path string, handler echo.HandlerFunc, middlewares ...echo.MiddlewareFunc) ResolvedHandler {
+	options := DefaultHandlerOptions()
+	options.Name = name
+
+	return NewResolvedHandlerWithOptions(method, path, handler, options, middlewares...)
+}
+
+// NewResolvedHandlerWithOptions returns a new [ResolvedHandler], with its [HandlerOptions].
+func NewResolvedHandlerWithOptions(
+	method string,
+	path string,
+	handler echo.HandlerFunc,
+	options HandlerOptions,
+	middlewares ...echo.MiddlewareFunc,
+) ResolvedHandler {
 	return &resolvedHandler{
 		method:      method,
 		path:        path,
 		handler:     handler,
 		middlewares: middlewares,
+		options:     options,
 	}
 }
 
+// Name return the resolved handler route name, empty if not named.
+func (r *resolvedHandler) Name() string {
+	return r.options.Name
+}
+
 // Method return the resolved handler http method.
 func (r *resolvedHandler) Method() string {
 	return r.method
@@ -76,6 +104,11 @@ func (r *resolvedHandler) Middlewares() []echo.MiddlewareFunc {
 	return r.middlewares
 }
 
+// Options return the resolved handler options.
+func (r *resolvedHandler) Options() HandlerOptions {
+	return r.options
+}
+
 // ResolvedHandlersGroup is an interface for the resolved handlers groups.
 type ResolvedHandlersGroup interface {
 	Prefix() string
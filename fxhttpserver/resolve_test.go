@@ -67,6 +67,8 @@ func TestResolvedHandler(t *testing.T) {
 
 			rh := fxhttpserver.NewResolvedHandler(tt.method, tt.path, tt.handler, tt.middlewares...)
 
+			assert.Empty(t, rh.Name())
+			assert.Equal(t, fxhttpserver.DefaultHandlerOptions(), rh.Options())
 			assert.Equal(t, tt.method, rh.Method())
 			assert.Equal(t, tt.path, rh.Path())
 			assert.Equal(t, "custom error", rh.Handler()(nil).Error())
@@ -75,6 +77,32 @@ func TestResolvedHandler(t *testing.T) {
 	}
 }
 
+func TestNamedResolvedHandler(t *testing.T) {
+	t.Parallel()
+
+	rh := fxhttpserver.NewNamedResolvedHandler("test", "GET", "/path", testHandlerFunc, testMiddlewareFunc)
+
+	assert.Equal(t, "test", rh.Name())
+	assert.Equal(t, "test", rh.Options().Name)
+	assert.Equal(t, "GET", rh.Method())
+	assert.Equal(t, "/path", rh.Path())
+	assert.Len(t, rh.Middlewares(), 1)
+}
+
+func TestResolvedHandlerWithOptions(t *testing.T) {
+	t.Parallel()
+
+	options := fxhttpserver.ResolveHandlerOptions(fxhttpserver.WithHandlerName("test"))
+
+	rh := fxhttpserver.NewResolvedHandlerWithOptions("GET", "/path", testHandlerFunc, options, testMiddlewareFunc)
+
+	assert.Equal(t, "test", rh.Name())
+	assert.Equal(t, "test", rh.Options().Name)
+	assert.Equal(t, "GET", rh.Method())
+	assert.Equal(t, "/path", rh.Path())
+	assert.Len(t, rh.Middlewares(), 1)
+}
+
 func TestResolvedHandlersGroup(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,105 @@
+package fxhttpserver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// routeConfigKey returns the modules.http.server.routes.<name>.<setting> config key of a named route, with the dots of
+// the name (like in the controllers routes names) replaced by underscores, to not be read as nested keys.
+func routeConfigKey(name string, setting string) string {
+	return fmt.Sprintf("modules.http.server.routes.%s.%s", strings.ReplaceAll(name, ".", "_"), setting)
+}
+
+// routeEnabled returns false if the named route is disabled with modules.http.server.routes.<name>.enabled=false.
+func routeEnabled(cfg *config.Config, name string) bool {
+	if name == "" || cfg == nil {
+		return true
+	}
+
+	enabledKey := routeConfigKey(name, "enabled")
+
+	return !cfg.IsSet(enabledKey) || cfg.GetBool(enabledKey)
+}
+
+// createRoutesExcluder returns a [middleware.Skipper] matching the requests of the named routes excluded with
+// modules.http.server.routes.<name>.<setting>.exclude=true, for example for the log or trace settings.
+func createRoutesExcluder(cfg *config.Config, routesInfo *httpserver.RoutesInfo, setting string) middleware.Skipper {
+	var excludedRoutes sync.Map
+
+	return func(c echo.Context) bool {
+		route, ok := routesInfo.Get(c.Request().Method, c.Path())
+		if !ok || route.Name == "" {
+			return false
+		}
+
+		if excluded, ok := excludedRoutes.Load(route.Name); ok {
+			//nolint:forcetypeassert
+			return excluded.(bool)
+		}
+
+		excluded := cfg.GetBool(routeConfigKey(route.Name, setting+".exclude"))
+
+		excludedRoutes.Store(route.Name, excluded)
+
+		return excluded
+	}
+}
+
+// registerRoute registers a resolved handler route for a http method, unless disabled from its config, and collects
+// its metadata. The add function registers the route on the http server, or on a group of the provided prefix.
+func registerRoute(
+	httpServer *echo.Echo,
+	p FxHttpServerParam,
+	add func(method string, path string, handler echo.HandlerFunc, middlewares ...echo.MiddlewareFunc) *echo.Route,
+	prefix string,
+	method string,
+	h ResolvedHandler,
+	requestValidationMiddleware echo.MiddlewareFunc,
+) {
+	options := h.Options()
+
+	if !routeEnabled(p.Config, options.Name) {
+		httpServer.Logger.Debugf("skipping disabled handler %s for [%s] %s%s", options.Name, method, prefix, h.Path())
+
+		return
+	}
+
+	middlewares := h.Middlewares()
+
+	// request validation last, after the group and handler authentication
+	if requestValidationMiddleware != nil {
+		middlewares = append(slices.Clone(middlewares), requestValidationMiddleware)
+	}
+
+	if httpServer.Validator == nil && isValidatedTypedHandler(h.Handler()) {
+		httpServer.Logger.Warnf(
+			"typed handler for [%s] %s%s is registered without validator, its requests will not be validated",
+			method,
+			prefix,
+			h.Path(),
+		)
+	}
+
+	route := add(method, h.Path(), h.Handler(), middlewares...)
+
+	if options.Name != "" {
+		route.Name = options.Name
+	}
+
+	p.RoutesInfo.Add(httpserver.RouteInfo{
+		Method:      route.Method,
+		Path:        route.Path,
+		Name:        route.Name,
+		Tags:        options.Tags,
+		Description: options.Description,
+		Metadata:    options.Metadata,
+	})
+}
@@ -0,0 +1,159 @@
+package fxhttpserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/ankorstore/yokai/trace/tracetest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModuleWithNamedRoutes(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var routesInfo *httpserver.RoutesInfo
+	var cfg *config.Config
+	var logBuffer logtest.TestLogBuffer
+	var traceExporter tracetest.TestTraceExporter
+
+	okHandler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	slowHandler := func(c echo.Context) error {
+		select {
+		case <-c.Request().Context().Done():
+			return c.Request().Context().Err()
+		case <-time.After(time.Second):
+			return c.String(http.StatusOK, "slow")
+		}
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler(
+				"GET",
+				"/named",
+				okHandler,
+				fxhttpserver.WithHandlerName("named-route"),
+				fxhttpserver.WithHandlerTags("named"),
+				fxhttpserver.WithHandlerDescription("named route"),
+				fxhttpserver.WithHandlerMetadata("owner", "team-a"),
+			),
+			fxhttpserver.AsHandler("GET", "/disabled", okHandler, fxhttpserver.WithHandlerName("disabled-route")),
+			fxhttpserver.AsHandler("GET", "/slow", slowHandler, fxhttpserver.WithHandlerName("slow-route")),
+			fxhttpserver.AsHandler("GET", "/dotted", okHandler, fxhttpserver.WithHandlerName("handler.Dotted.Route")),
+			fxhttpserver.AsHandlersGroup(
+				"/group",
+				[]*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration("GET", "/excluded/:id", okHandler, fxhttpserver.WithHandlerName("excluded-route")),
+				},
+				fxhttpserver.WithHandlerTags("group"),
+			),
+		),
+		fx.Populate(&httpServer, &routesInfo, &cfg, &logBuffer, &traceExporter),
+	).RequireStart().RequireStop()
+
+	// [GET] /named
+	req := httptest.NewRequest(http.MethodGet, "/named", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "info",
+		"uri":     "/named",
+		"message": "request logger",
+	})
+
+	tracetest.AssertHasTraceSpan(t, traceExporter, "GET /named")
+
+	// [GET] /disabled
+	req = httptest.NewRequest(http.MethodGet, "/disabled", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// [GET] /dotted
+	req = httptest.NewRequest(http.MethodGet, "/dotted", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// [GET] /slow
+	req = httptest.NewRequest(http.MethodGet, "/slow", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	// [GET] /group/excluded/:id
+	req = httptest.NewRequest(http.MethodGet, "/group/excluded/1", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	logtest.AssertHasNotLogRecord(t, logBuffer, map[string]interface{}{
+		"uri":     "/group/excluded/1",
+		"message": "request logger",
+	})
+
+	_, err := traceExporter.Span("GET /group/excluded/:id")
+	assert.Error(t, err)
+
+	// routes info
+	route, ok := routesInfo.Get(http.MethodGet, "/named")
+	assert.True(t, ok)
+	assert.Equal(
+		t,
+		httpserver.RouteInfo{
+			Method:      http.MethodGet,
+			Path:        "/named",
+			Name:        "named-route",
+			Tags:        []string{"named"},
+			Description: "named route",
+			Metadata:    map[string]any{"owner": "team-a"},
+		},
+		route,
+	)
+
+	route, ok = routesInfo.Get(http.MethodGet, "/group/excluded/:id")
+	assert.True(t, ok)
+	assert.Equal(t, "excluded-route", route.Name)
+	assert.Equal(t, []string{"group"}, route.Tags)
+
+	_, ok = routesInfo.Get(http.MethodGet, "/disabled")
+	assert.False(t, ok)
+
+	// module info
+	moduleInfo := fxhttpserver.NewFxHttpServerModuleInfo(httpServer, cfg, routesInfo)
+	assert.Equal(t, routesInfo.Routes(httpServer), moduleInfo.Data()["routes"])
+	assert.Len(t, moduleInfo.Routes, 3)
+}
@@ -0,0 +1,119 @@
+package fxhttpserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// createCORSMiddleware creates the http server CORS middleware from modules.http.server.cors, returning nil if disabled.
+func createCORSMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	if !cfg.GetBool("modules.http.server.cors.enabled") {
+		return nil
+	}
+
+	corsConfig := middleware.DefaultCORSConfig
+
+	if allowOrigins := cfg.GetStringSlice("modules.http.server.cors.allow_origins"); len(allowOrigins) > 0 {
+		corsConfig.AllowOrigins = allowOrigins
+	}
+
+	if allowMethods := cfg.GetStringSlice("modules.http.server.cors.allow_methods"); len(allowMethods) > 0 {
+		corsConfig.AllowMethods = allowMethods
+	}
+
+	corsConfig.AllowHeaders = cfg.GetStringSlice("modules.http.server.cors.allow_headers")
+	corsConfig.ExposeHeaders = cfg.GetStringSlice("modules.http.server.cors.expose_headers")
+	corsConfig.AllowCredentials = cfg.GetBool("modules.http.server.cors.allow_credentials")
+	corsConfig.MaxAge = cfg.GetInt("modules.http.server.cors.max_age")
+
+	return middleware.CORSWithConfig(corsConfig)
+}
+
+// createSecurityHeadersMiddleware creates the http server security headers middleware from
+// modules.http.server.security_headers, returning nil if disabled.
+func createSecurityHeadersMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	if !cfg.GetBool("modules.http.server.security_headers.enabled") {
+		return nil
+	}
+
+	secureConfig := middleware.DefaultSecureConfig
+
+	if cfg.IsSet("modules.http.server.security_headers.xss_protection") {
+		secureConfig.XSSProtection = cfg.GetString("modules.http.server.security_headers.xss_protection")
+	}
+
+	if cfg.IsSet("modules.http.server.security_headers.content_type_nosniff") {
+		secureConfig.ContentTypeNosniff = cfg.GetString("modules.http.server.security_headers.content_type_nosniff")
+	}
+
+	if cfg.IsSet("modules.http.server.security_headers.frame_options") {
+		secureConfig.XFrameOptions = cfg.GetString("modules.http.server.security_headers.frame_options")
+	}
+
+	secureConfig.HSTSMaxAge = cfg.GetInt("modules.http.server.security_headers.hsts.max_age")
+	secureConfig.HSTSExcludeSubdomains = !cfg.GetBool("modules.http.server.security_headers.hsts.include_subdomains")
+	secureConfig.HSTSPreloadEnabled = cfg.GetBool("modules.http.server.security_headers.hsts.preload")
+	secureConfig.ContentSecurityPolicy = cfg.GetString("modules.http.server.security_headers.content_security_policy")
+	secureConfig.CSPReportOnly = cfg.GetBool("modules.http.server.security_headers.content_security_policy_report_only")
+	secureConfig.ReferrerPolicy = cfg.GetString("modules.http.server.security_headers.referrer_policy")
+
+	return middleware.SecureWithConfig(secureConfig)
+}
+
+// createCSRFMiddleware creates the http server CSRF middleware from modules.http.server.csrf, returning nil if disabled.
+func createCSRFMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	if !cfg.GetBool("modules.http.server.csrf.enabled") {
+		return nil
+	}
+
+	csrfConfig := middleware.DefaultCSRFConfig
+
+	if tokenLookup := cfg.GetString("modules.http.server.csrf.token_lookup"); tokenLookup != "" {
+		csrfConfig.TokenLookup = tokenLookup
+	}
+
+	if contextKey := cfg.GetString("modules.http.server.csrf.context_key"); contextKey != "" {
+		csrfConfig.ContextKey = contextKey
+	}
+
+	if cookieName := cfg.GetString("modules.http.server.csrf.cookie.name"); cookieName != "" {
+		csrfConfig.CookieName = cookieName
+	}
+
+	if cfg.IsSet("modules.http.server.csrf.cookie.max_age") {
+		csrfConfig.CookieMaxAge = cfg.GetInt("modules.http.server.csrf.cookie.max_age")
+	}
+
+	csrfConfig.CookieDomain = cfg.GetString("modules.http.server.csrf.cookie.domain")
+	csrfConfig.CookiePath = cfg.GetString("modules.http.server.csrf.cookie.path")
+	csrfConfig.CookieSecure = cfg.GetBool("modules.http.server.csrf.cookie.secure")
+	csrfConfig.CookieHTTPOnly = cfg.GetBool("modules.http.server.csrf.cookie.http_only")
+	csrfConfig.CookieSameSite = fetchSameSite(cfg.GetString("modules.http.server.csrf.cookie.same_site"))
+
+	if exclude := cfg.GetStringSlice("modules.http.server.csrf.exclude"); len(exclude) > 0 {
+		csrfConfig.Skipper = func(c echo.Context) bool {
+			return httpserver.MatchPrefix(exclude, c.Request().URL.Path)
+		}
+	}
+
+	return middleware.CSRFWithConfig(csrfConfig)
+}
+
+// fetchSameSite returns a [http.SameSite] for a given value (lax, strict or none), defaults to [http.SameSiteDefaultMode].
+func fetchSameSite(sameSite string) http.SameSite {
+	switch strings.ToLower(sameSite) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
@@ -0,0 +1,185 @@
+package fxhttpserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModuleWithCORS(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("CORS_ENABLED", "true")
+
+	httpServer := createSecurityTestHttpServer(t)
+
+	// preflight request
+	req := httptest.NewRequest(http.MethodOptions, "/test", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://example.com")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "GET,POST", rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+	assert.Equal(t, "X-Foo", rec.Header().Get(echo.HeaderAccessControlAllowHeaders))
+	assert.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+	assert.Equal(t, "3600", rec.Header().Get(echo.HeaderAccessControlMaxAge))
+
+	// allowed origin request
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://example.com")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "X-Bar", rec.Header().Get(echo.HeaderAccessControlExposeHeaders))
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+
+	// not allowed origin request
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://other.com")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
+
+func TestModuleWithSecurityHeaders(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("SECURITY_HEADERS_ENABLED", "true")
+
+	httpServer := createSecurityTestHttpServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1; mode=block", rec.Header().Get(echo.HeaderXXSSProtection))
+	assert.Equal(t, "nosniff", rec.Header().Get(echo.HeaderXContentTypeOptions))
+	assert.Equal(t, "DENY", rec.Header().Get(echo.HeaderXFrameOptions))
+	assert.Equal(t, "max-age=31536000; includeSubdomains; preload", rec.Header().Get(echo.HeaderStrictTransportSecurity))
+	assert.Equal(t, "default-src 'self'", rec.Header().Get(echo.HeaderContentSecurityPolicy))
+	assert.Equal(t, "no-referrer", rec.Header().Get(echo.HeaderReferrerPolicy))
+
+	// security headers are also set on errors
+	req = httptest.NewRequest(http.MethodGet, "/not-found", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "DENY", rec.Header().Get(echo.HeaderXFrameOptions))
+}
+
+func TestModuleWithCSRF(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("CSRF_ENABLED", "true")
+
+	httpServer := createSecurityTestHttpServer(t)
+
+	// token generation
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, "_csrf", cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+
+	token := cookies[0].Value
+
+	// missing token
+	req = httptest.NewRequest(http.MethodPost, "/test", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// invalid token
+	req = httptest.NewRequest(http.MethodPost, "/test", nil)
+	req.AddCookie(&http.Cookie{Name: "_csrf", Value: token})
+	req.Header.Set("X-CSRF-Token", "invalid")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// valid token
+	req = httptest.NewRequest(http.MethodPost, "/test", nil)
+	req.AddCookie(&http.Cookie{Name: "_csrf", Value: token})
+	req.Header.Set("X-CSRF-Token", token)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// excluded path
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/test", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestModuleWithSecurityMiddlewaresDisabled(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	httpServer := createSecurityTestHttpServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/test", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://example.com")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Empty(t, rec.Header().Get(echo.HeaderXFrameOptions))
+	assert.Empty(t, rec.Result().Cookies())
+}
+
+func createSecurityTestHttpServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	var httpServer *echo.Echo
+
+	testHandler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET,POST", "/test", testHandler),
+			fxhttpserver.AsHandler("POST", "/webhooks/test", testHandler),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	return httpServer
+}
@@ -0,0 +1,56 @@
+package fxhttpserver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	"go.uber.org/fx"
+)
+
+// sqlTableStore is implemented by the SQL stores able to create their table.
+type sqlTableStore interface {
+	CreateTable(ctx context.Context) error
+}
+
+// createSQLStore creates a SQL store configured under the provided config prefix (for example
+// modules.http.server.ratelimit.store), with:
+//   - the dialect from <prefix>.dialect, or from modules.sql.driver if not set
+//   - the table from <prefix>.table, passed as empty to the factory if not set
+//   - the table creation on start if <prefix>.create_table is enabled
+func createSQLStore[S sqlTableStore](
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	db *sql.DB,
+	name string,
+	prefix string,
+	factory func(db *sql.DB, dialect sqlstore.Dialect, table string) (S, error),
+) (S, error) {
+	var store S
+
+	if db == nil {
+		return store, fmt.Errorf("sql %s store requires a sql database", name)
+	}
+
+	dialect := cfg.GetString(prefix + ".dialect")
+	if dialect == "" {
+		dialect = cfg.GetString("modules.sql.driver")
+	}
+
+	store, err := factory(db, sqlstore.FetchDialect(dialect), cfg.GetString(prefix+".table"))
+	if err != nil {
+		return store, err
+	}
+
+	if cfg.GetBool(prefix + ".create_table") {
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				return store.CreateTable(ctx)
+			},
+		})
+	}
+
+	return store, nil
+}
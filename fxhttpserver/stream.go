@@ -0,0 +1,105 @@
+package fxhttpserver
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/stream"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/fx"
+)
+
+// FxHttpServerStreamsParam allows injection of the required dependencies in [NewFxHttpServerStreams].
+type FxHttpServerStreamsParam struct {
+	fx.In
+	Config          *config.Config
+	Shutdown        *httpserver.ShutdownParticipant
+	MetricsRegistry *prometheus.Registry
+}
+
+// Streams creates the http server WebSocket and Server-Sent Events handlers, closed on the server shutdown and
+// sharing the same metrics.
+type Streams struct {
+	config  *config.Config
+	metrics *stream.Metrics
+	done    <-chan struct{}
+}
+
+// NewFxHttpServerStreams returns a new [Streams].
+func NewFxHttpServerStreams(p FxHttpServerStreamsParam) (*Streams, error) {
+	streams := &Streams{
+		config: p.Config,
+		done:   p.Shutdown.Done(),
+	}
+
+	if p.Config.GetBool("modules.http.server.metrics.collect.enabled") {
+		metrics, err := stream.NewMetrics(
+			p.MetricsRegistry,
+			Sanitize(p.Config.GetString("modules.http.server.metrics.collect.namespace")),
+			Sanitize(p.Config.GetString("modules.http.server.metrics.collect.subsystem")),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		streams.metrics = metrics
+	}
+
+	return streams, nil
+}
+
+// WebSocketHandler returns the [echo.HandlerFunc] of a [stream.WebSocketHandlerFunc], configured in
+// modules.http.server.websocket.
+func (s *Streams) WebSocketHandler(handler stream.WebSocketHandlerFunc) echo.HandlerFunc {
+	return stream.WebSocketHandlerWithConfig(handler, stream.WebSocketConfig{
+		CheckOrigin:       checkWebSocketOrigin(s.config.GetStringSlice("modules.http.server.websocket.allowed_origins")),
+		EnableCompression: s.config.GetBool("modules.http.server.websocket.compression"),
+		ReadLimit:         s.config.GetInt64("modules.http.server.websocket.read_limit"),
+		PingInterval:      s.config.GetDuration("modules.http.server.websocket.ping_interval"),
+		PongTimeout:       s.config.GetDuration("modules.http.server.websocket.pong_timeout"),
+		WriteTimeout:      s.config.GetDuration("modules.http.server.websocket.write_timeout"),
+		Metrics:           s.metrics,
+		Done:              s.done,
+	})
+}
+
+// SSEHandler returns the [echo.HandlerFunc] of a [stream.SSEHandlerFunc], configured in modules.http.server.sse.
+func (s *Streams) SSEHandler(handler stream.SSEHandlerFunc) echo.HandlerFunc {
+	return stream.SSEHandlerWithConfig(handler, stream.SSEConfig{
+		KeepAliveInterval: s.config.GetDuration("modules.http.server.sse.keepalive_interval"),
+		Metrics:           s.metrics,
+		Done:              s.done,
+	})
+}
+
+// checkWebSocketOrigin returns the WebSocket upgrade requests origin check for a list of allowed origins, or nil
+// (same origin only) if empty.
+func checkWebSocketOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	if len(allowedOrigins) == 0 {
+		return nil
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get(echo.HeaderOrigin)
+		if origin == "" {
+			return true
+		}
+
+		originURL, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+
+		for _, allowedOrigin := range allowedOrigins {
+			if allowedOrigin == "*" || strings.EqualFold(allowedOrigin, origin) || strings.EqualFold(allowedOrigin, originURL.Host) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
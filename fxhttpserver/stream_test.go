@@ -0,0 +1,232 @@
+package fxhttpserver_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/handler"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/service"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/stream"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModuleWithWebSocketHandlers(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var participant *httpserver.ShutdownParticipant
+	var metricsRegistry *prometheus.Registry
+	var logBuffer logtest.TestLogBuffer
+
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Provide(service.NewTestService),
+		fx.Options(
+			fxhttpserver.AsWebSocketHandler("/ws", handler.NewTestWebSocketHandler),
+			fxhttpserver.AsHandlersGroup(
+				"/group",
+				[]*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewWebSocketHandlerRegistration(
+						"/ws",
+						func(ctx context.Context, conn *stream.WebSocketConn) error {
+							return conn.WriteMessage(stream.TextMessage, []byte("concrete"))
+						},
+					),
+				},
+			),
+		),
+		fx.Populate(&httpServer, &participant, &metricsRegistry, &logBuffer),
+	).RequireStart()
+
+	defer app.RequireStop()
+
+	server := httptest.NewServer(httpServer)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	// autowired handler
+	header := http.Header{}
+	header.Set(echo.HeaderOrigin, "https://example.com")
+	header.Set(echo.HeaderXRequestID, testRequestId)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"/ws", header)
+	assert.NoError(t, err)
+
+	defer conn.Close()
+
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+
+	_, data, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "test: hello ("+testRequestId+")", string(data))
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":     "info",
+		"requestID": testRequestId,
+		"message":   "websocket message: hello",
+	})
+
+	expectedMetric := `
+		# HELP http_server_stream_connections_active Number of active HTTP streams connections
+		# TYPE http_server_stream_connections_active gauge
+		http_server_stream_connections_active{kind="websocket",path="/ws"} 1
+	`
+
+	assert.NoError(t, testutil.GatherAndCompare(
+		metricsRegistry,
+		strings.NewReader(expectedMetric),
+		"http_server_stream_connections_active",
+	))
+
+	// concrete handler, in group
+	groupConn, _, err := websocket.DefaultDialer.Dial(url+"/group/ws", nil)
+	assert.NoError(t, err)
+
+	defer groupConn.Close()
+
+	_, data, err = groupConn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "concrete", string(data))
+
+	_, _, err = groupConn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+
+	// disallowed origin
+	header.Set(echo.HeaderOrigin, "https://other.com")
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"/ws", header)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// graceful close on shutdown
+	assert.NoError(t, participant.Shutdown(context.Background()))
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+}
+
+func TestModuleWithSSEHandlers(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var participant *httpserver.ShutdownParticipant
+
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsSSEHandler("/events", func(ctx context.Context, s *stream.SSEStream) error {
+				requestId, _ := ctx.Value(httpserver.CtxRequestIdKey{}).(string)
+
+				if err := s.Send(stream.Event{Event: "started", Data: requestId}); err != nil {
+					return err
+				}
+
+				<-ctx.Done()
+
+				return ctx.Err()
+			}),
+		),
+		fx.Populate(&httpServer, &participant),
+	).RequireStart()
+
+	defer app.RequireStop()
+
+	server := httptest.NewServer(httpServer)
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/events", nil)
+	assert.NoError(t, err)
+
+	req.Header.Set(echo.HeaderXRequestID, testRequestId)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, stream.MIMETextEventStream, resp.Header.Get(echo.HeaderContentType))
+
+	reader := bufio.NewReader(resp.Body)
+
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: started\n", line)
+
+	line, err = reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "data: "+testRequestId+"\n", line)
+
+	// the stream ends on shutdown
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- participant.Shutdown(context.Background())
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		//nolint:errcheck
+		reader.ReadString(0)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("the stream should end on shutdown")
+	}
+
+	assert.NoError(t, <-shutdown)
+}
+
+func TestModuleWithStreamHandlerNotFound(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fxhttpserver.AsSSEHandler("/events", "invalid"),
+		fx.Invoke(func(*echo.Echo) {}),
+	)
+
+	assert.Error(t, app.Err())
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/service"
+	"github.com/ankorstore/yokai/log"
+	"github.com/labstack/echo/v4"
+)
+
+type TestController struct {
+	service *service.TestService
+}
+
+func NewTestController(service *service.TestService) *TestController {
+	return &TestController{
+		service: service,
+	}
+}
+
+func (c *TestController) Routes() []*fxhttpserver.HandlerRegistration {
+	return []*fxhttpserver.HandlerRegistration{
+		fxhttpserver.NewHandlerRegistration(http.MethodGet, "/controller", c.List),
+		fxhttpserver.NewHandlerRegistration(
+			http.MethodPost,
+			"/controller",
+			c.Create,
+			func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(ctx echo.Context) error {
+					ctx.Response().Header().Set("x-route-middleware", "create")
+
+					return next(ctx)
+				}
+			},
+			fxhttpserver.WithHandlerSummary("create"),
+		),
+		fxhttpserver.NewHandlerRegistration(http.MethodGet, "/controller/closure", func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, "closure")
+		}),
+	}
+}
+
+func (c *TestController) List(ctx echo.Context) error {
+	log.CtxLogger(ctx.Request().Context()).Info().Msg("in controller list")
+
+	return ctx.String(http.StatusOK, fmt.Sprintf("list: %s", c.service.GetAppName()))
+}
+
+func (c *TestController) Create(ctx echo.Context) error {
+	return ctx.String(http.StatusCreated, fmt.Sprintf("create: %s", c.service.GetAppName()))
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/service"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/stream"
+	"github.com/ankorstore/yokai/log"
+)
+
+type TestWebSocketHandler struct {
+	service *service.TestService
+}
+
+func NewTestWebSocketHandler(service *service.TestService) *TestWebSocketHandler {
+	return &TestWebSocketHandler{
+		service: service,
+	}
+}
+
+func (h *TestWebSocketHandler) Handle(ctx context.Context, conn *stream.WebSocketConn) error {
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		log.CtxLogger(ctx).Info().Msgf("websocket message: %s", data)
+
+		requestId, _ := ctx.Value(httpserver.CtxRequestIdKey{}).(string)
+
+		err = conn.WriteMessage(messageType, []byte(fmt.Sprintf("%s: %s (%s)", h.service.GetAppName(), data, requestId)))
+		if err != nil {
+			return err
+		}
+	}
+}
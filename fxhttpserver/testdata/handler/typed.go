@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/service"
+	"github.com/labstack/echo/v4"
+)
+
+type TestTypedRequest struct {
+	Name string `query:"name" validate:"required,min=3"`
+}
+
+type TestTypedResponse struct {
+	App     string `json:"app"`
+	Message string `json:"message"`
+}
+
+type TestTypedHandler struct {
+	service *service.TestService
+}
+
+func NewTestTypedHandler(service *service.TestService) *TestTypedHandler {
+	return &TestTypedHandler{
+		service: service,
+	}
+}
+
+func (h *TestTypedHandler) Handle() echo.HandlerFunc {
+	return fxhttpserver.Typed(h.handle)
+}
+
+func (h *TestTypedHandler) handle(ctx context.Context, req TestTypedRequest) (TestTypedResponse, error) {
+	return TestTypedResponse{
+		App:     h.service.GetAppName(),
+		Message: "hello " + req.Name,
+	}, nil
+}
@@ -0,0 +1,149 @@
+package fxhttpserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestModuleWithServerTimeouts(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	assert.Equal(t, 10*time.Second, httpServer.Server.ReadTimeout)
+	assert.Equal(t, 5*time.Second, httpServer.Server.ReadHeaderTimeout)
+	assert.Equal(t, 15*time.Second, httpServer.Server.WriteTimeout)
+	assert.Equal(t, 60*time.Second, httpServer.Server.IdleTimeout)
+	assert.Equal(t, 4096, httpServer.Server.MaxHeaderBytes)
+}
+
+func TestModuleWithHandlerTimeout(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+
+	slowHandler := func(c echo.Context) error {
+		select {
+		case <-c.Request().Context().Done():
+			return c.Request().Context().Err()
+		case <-time.After(time.Second):
+			return c.String(http.StatusOK, "slow")
+		}
+	}
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/fast", func(c echo.Context) error {
+				return c.String(http.StatusOK, "fast")
+			}, fxhttpserver.WithHandlerTimeout(time.Second)),
+			fxhttpserver.AsHandler("GET", "/slow", slowHandler, fxhttpserver.WithHandlerTimeout(10*time.Millisecond)),
+			fxhttpserver.AsHandlersGroup(
+				"/group",
+				[]*fxhttpserver.HandlerRegistration{
+					fxhttpserver.NewHandlerRegistration(
+						"GET",
+						"/slow",
+						slowHandler,
+						fxhttpserver.WithHandlerTimeout(10*time.Millisecond),
+						fxhttpserver.WithHandlerTimeoutStatus(http.StatusGatewayTimeout),
+					),
+				},
+			),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// [GET] /fast
+	req := httptest.NewRequest(http.MethodGet, "/fast", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "fast", rec.Body.String())
+
+	// [GET] /slow
+	req = httptest.NewRequest(http.MethodGet, "/slow", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	// [GET] /group/slow
+	req = httptest.NewRequest(http.MethodGet, "/group/slow", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+}
+
+func TestModuleWithBodyLimit(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("BODY_LIMIT", "10B")
+
+	var httpServer *echo.Echo
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("POST", "/body", func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			}),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart().RequireStop()
+
+	// within limit
+	req := httptest.NewRequest(http.MethodPost, "/body", strings.NewReader("small"))
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// above limit
+	req = httptest.NewRequest(http.MethodPost, "/body", strings.NewReader("this body is too large"))
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -0,0 +1,67 @@
+package fxhttpserver
+
+import (
+	"crypto/tls"
+	"errors"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/httpserver"
+)
+
+// createTLSConfig creates the http server [tls.Config] from modules.http.server.tls, returning a nil config if disabled.
+// The client CA reloader is nil if no client CA file is configured.
+func createTLSConfig(
+	cfg *config.Config,
+) (*tls.Config, *httpserver.CertificateReloader, *httpserver.CertPoolReloader, error) {
+	if !cfg.GetBool("modules.http.server.tls.enabled") {
+		return nil, nil, nil, nil
+	}
+
+	// http/2 is negotiated over tls (ALPN), h2c would not be served
+	if cfg.GetBool("modules.http.server.h2c.enabled") {
+		return nil, nil, nil, errors.New("tls and h2c cannot be both enabled")
+	}
+
+	reloader, err := httpserver.NewCertificateReloader(
+		cfg.GetString("modules.http.server.tls.cert_file"),
+		cfg.GetString("modules.http.server.tls.key_file"),
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	//nolint:gosec
+	tlsConfig := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     httpserver.FetchTLSVersion(cfg.GetString("modules.http.server.tls.min_version")),
+		ClientAuth:     httpserver.FetchTLSClientAuth(cfg.GetString("modules.http.server.tls.client_auth")),
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	if cipherSuites := cfg.GetStringSlice("modules.http.server.tls.cipher_suites"); len(cipherSuites) > 0 {
+		tlsConfig.CipherSuites, err = httpserver.FetchTLSCipherSuites(cipherSuites)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	clientCAFile := cfg.GetString("modules.http.server.tls.client_ca_file")
+	if clientCAFile == "" {
+		return tlsConfig, reloader, nil, nil
+	}
+
+	clientCAReloader, err := httpserver.NewCertPoolReloader(clientCAFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// client certificates are verified against the client CA by default
+	if cfg.GetString("modules.http.server.tls.client_auth") == "" {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	tlsConfig.ClientCAs = clientCAReloader.CertPool()
+	tlsConfig.GetConfigForClient = clientCAReloader.GetConfigForClient(tlsConfig)
+
+	return tlsConfig, reloader, clientCAReloader, nil
+}
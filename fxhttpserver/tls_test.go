@@ -0,0 +1,250 @@
+package fxhttpserver_test
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	testtls "github.com/ankorstore/yokai/httpserver/testdata/tls"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"golang.org/x/net/http2"
+)
+
+func TestModuleWithTLS(t *testing.T) {
+	dir := t.TempDir()
+	authority := testtls.NewTestAuthority(t)
+	certFile, keyFile := authority.WriteIssued(t, dir, "server")
+
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("HTTP_SERVER_ADDRESS", "127.0.0.1:0")
+	t.Setenv("TLS_ENABLED", "true")
+	t.Setenv("TLS_CERT_FILE", certFile)
+	t.Setenv("TLS_KEY_FILE", keyFile)
+	// client_auth defaults to require_and_verify with a client ca
+	t.Setenv("TLS_CLIENT_CA_FILE", authority.WriteCA(t, dir))
+
+	var httpServer *echo.Echo
+	var logBuffer logtest.TestLogBuffer
+
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/tls", func(c echo.Context) error {
+				return c.String(http.StatusOK, c.Request().TLS.PeerCertificates[0].Subject.CommonName)
+			}),
+		),
+		fx.Populate(&httpServer, &logBuffer),
+	).RequireStart()
+
+	defer app.RequireStop()
+
+	assert.Eventually(t, func() bool {
+		return httpServer.TLSListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	url := fmt.Sprintf("https://%s/tls", httpServer.TLSListenerAddr().String())
+
+	// with client certificate
+	client := newTLSClient(authority, authority.ClientCertificate(t, "client"))
+
+	resp, err := client.Get(url)
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, "client", string(body))
+
+	serial := resp.TLS.PeerCertificates[0].SerialNumber
+
+	// without client certificate
+	_, err = newTLSClient(authority).Get(url)
+	assert.Error(t, err)
+
+	// certificate rotation
+	authority.WriteIssued(t, dir, "server")
+
+	assert.Eventually(t, func() bool {
+		resp, err := newTLSClient(authority, authority.ClientCertificate(t, "client")).Get(url)
+		if err != nil {
+			return false
+		}
+
+		//nolint:errcheck
+		defer resp.Body.Close()
+
+		return resp.TLS.PeerCertificates[0].SerialNumber.Cmp(serial) != 0
+	}, time.Second, 10*time.Millisecond)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "info",
+		"module":  "httpserver",
+		"message": "http server tls certificate reloaded",
+	})
+
+	// client ca rotation
+	otherAuthority := testtls.NewTestAuthority(t)
+
+	_, err = newTLSClient(authority, otherAuthority.ClientCertificate(t, "other")).Get(url)
+	assert.Error(t, err)
+
+	otherAuthority.WriteCA(t, dir)
+
+	assert.Eventually(t, func() bool {
+		resp, err := newTLSClient(authority, otherAuthority.ClientCertificate(t, "other")).Get(url)
+		if err != nil {
+			return false
+		}
+
+		//nolint:errcheck
+		defer resp.Body.Close()
+
+		return resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = newTLSClient(authority, authority.ClientCertificate(t, "client")).Get(url)
+	assert.Error(t, err)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "info",
+		"module":  "httpserver",
+		"message": "http server tls client ca reloaded",
+	})
+}
+
+func TestModuleWithTLSAndInvalidCertificate(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("TLS_ENABLED", "true")
+	t.Setenv("TLS_CERT_FILE", filepath.Join(t.TempDir(), "missing.pem"))
+	t.Setenv("TLS_KEY_FILE", filepath.Join(t.TempDir(), "missing-key.pem"))
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Invoke(func(*echo.Echo) {}),
+	)
+
+	assert.Error(t, app.Err())
+	assert.Contains(t, app.Err().Error(), "failed to create http server tls config: cannot read tls certificate file")
+}
+
+func TestModuleWithTLSAndH2C(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := testtls.NewTestAuthority(t).WriteIssued(t, dir, "server")
+
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("TLS_ENABLED", "true")
+	t.Setenv("TLS_CERT_FILE", certFile)
+	t.Setenv("TLS_KEY_FILE", keyFile)
+	t.Setenv("H2C_ENABLED", "true")
+
+	app := fx.New(
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Invoke(func(*echo.Echo) {}),
+	)
+
+	assert.Error(t, app.Err())
+	assert.Contains(t, app.Err().Error(), "failed to create http server tls config: tls and h2c cannot be both enabled")
+}
+
+func TestModuleWithH2C(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("HTTP_SERVER_ADDRESS", "127.0.0.1:0")
+	t.Setenv("H2C_ENABLED", "true")
+
+	var httpServer *echo.Echo
+
+	app := fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/h2c", func(c echo.Context) error {
+				return c.String(http.StatusOK, c.Request().Proto)
+			}),
+		),
+		fx.Populate(&httpServer),
+	).RequireStart()
+
+	defer app.RequireStop()
+
+	assert.Eventually(t, func() bool {
+		return httpServer.ListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network string, addr string, _ *tls.Config) (net.Conn, error) {
+				var dialer net.Dialer
+
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	resp, err := client.Get(fmt.Sprintf("http://%s/h2c", httpServer.ListenerAddr().String()))
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "HTTP/2.0", string(body))
+}
+
+func newTLSClient(authority *testtls.TestAuthority, certificates ...tls.Certificate) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			ForceAttemptHTTP2: true,
+			TLSClientConfig: &tls.Config{
+				RootCAs:      authority.Pool(),
+				Certificates: certificates,
+				MinVersion:   tls.VersionTLS12,
+			},
+		},
+	}
+}
@@ -0,0 +1,379 @@
+package fxhttpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+const InvalidTypedRequestMessage = "invalid request"
+
+// validatedTypedHandlers records the code pointers of the [Typed] handlers binding a struct, to warn at their
+// registration if the server has no validator.
+var validatedTypedHandlers sync.Map
+
+// StatusCoder is implemented by the typed handlers responses and errors providing their own http status code.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// TypedHandlerOptions are options for the [Typed] handlers.
+type TypedHandlerOptions struct {
+	Status int
+}
+
+// DefaultTypedHandlerOptions are the default options used in the [Typed] handlers.
+func DefaultTypedHandlerOptions() TypedHandlerOptions {
+	return TypedHandlerOptions{
+		Status: http.StatusOK,
+	}
+}
+
+// TypedHandlerOption are functional options for the [Typed] handlers.
+type TypedHandlerOption func(o *TypedHandlerOptions)
+
+// WithTypedHandlerStatus is used to specify the http status code of the typed handler responses (200 by default).
+func WithTypedHandlerStatus(status int) TypedHandlerOption {
+	return func(o *TypedHandlerOptions) {
+		o.Status = status
+	}
+}
+
+// Typed returns an [echo.HandlerFunc] from a typed handler function, to be registered with [AsHandler].
+//
+// The request is bound into Req, from the body first, and then from the path params, query params and headers (for the
+// fields tagged with param, query and header), and validated with the server validator (see [Validator]): invalid
+// requests get a 400 error, with the details of each invalid field.
+// The response is encoded in json, or in xml if preferred by the request Accept header (or in plain text for strings),
+// with the status code of the response if it implements [StatusCoder], or 200 by default (see [WithTypedHandlerStatus]).
+//
+// The returned errors are mapped to http errors: [echo.HTTPError] are left untouched, [httpserver.ValidationError] and
+// [validator.ValidationErrors] get a 400 status code, errors implementing [StatusCoder] get their status code, and
+// the other ones a 500 status code.
+func Typed[Req any, Resp any](handler func(context.Context, Req) (Resp, error), options ...TypedHandlerOption) echo.HandlerFunc {
+	typedOptions := DefaultTypedHandlerOptions()
+	for _, opt := range options {
+		opt(&typedOptions)
+	}
+
+	handlerFunc := func(c echo.Context) error {
+		var req Req
+
+		err := bindTypedRequest(c, &req)
+		if err != nil {
+			return err
+		}
+
+		err = validateTypedRequest(c, req)
+		if err != nil {
+			var validationErrs validator.ValidationErrors
+			if errors.As(err, &validationErrs) {
+				return newTypedValidationError(validationErrs, reflect.TypeOf(req))
+			}
+
+			return MapTypedError(err)
+		}
+
+		resp, err := handler(c.Request().Context(), req)
+		if err != nil {
+			return MapTypedError(err)
+		}
+
+		return writeTypedResponse(c, typedOptions.Status, resp)
+	}
+
+	if reqType := reflect.TypeFor[Req](); reqType.Kind() == reflect.Struct ||
+		(reqType.Kind() == reflect.Pointer && reqType.Elem().Kind() == reflect.Struct) {
+		validatedTypedHandlers.Store(reflect.ValueOf(handlerFunc).Pointer(), true)
+	}
+
+	return handlerFunc
+}
+
+// isValidatedTypedHandler returns true if the handler is a [Typed] handler binding a struct.
+func isValidatedTypedHandler(handler echo.HandlerFunc) bool {
+	_, ok := validatedTypedHandlers.Load(reflect.ValueOf(handler).Pointer())
+
+	return ok
+}
+
+// MapTypedError maps an error returned by a typed handler to an [echo.HTTPError].
+func MapTypedError(err error) error {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return err
+	}
+
+	var validationErr *httpserver.ValidationError
+	if errors.As(err, &validationErr) {
+		return echo.NewHTTPError(http.StatusBadRequest, validationErr.Message).SetInternal(err)
+	}
+
+	var structValidationErr *StructValidationError
+	if errors.As(err, &structValidationErr) {
+		return newTypedValidationError(structValidationErr.Errors, structValidationErr.Type)
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return newTypedValidationError(validationErrs, nil)
+	}
+
+	var statusCoder StatusCoder
+	if errors.As(err, &statusCoder) {
+		return echo.NewHTTPError(statusCoder.StatusCode(), err.Error()).SetInternal(err)
+	}
+
+	return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+}
+
+// typedBinder is implemented by the binders able to bind each request part, like the [echo.DefaultBinder].
+type typedBinder interface {
+	BindBody(c echo.Context, i any) error
+	BindPathParams(c echo.Context, i any) error
+	BindQueryParams(c echo.Context, i any) error
+	BindHeaders(c echo.Context, i any) error
+}
+
+func bindTypedRequest(c echo.Context, req any) error {
+	binder, ok := c.Echo().Binder.(typedBinder)
+	if !ok {
+		return c.Bind(req)
+	}
+
+	// body first, for the path params, query params and headers to take precedence
+	err := binder.BindBody(c, req)
+	if err != nil {
+		return err
+	}
+
+	err = binder.BindPathParams(c, req)
+	if err != nil {
+		return err
+	}
+
+	err = binder.BindQueryParams(c, req)
+	if err != nil {
+		return err
+	}
+
+	return binder.BindHeaders(c, req)
+}
+
+func validateTypedRequest(c echo.Context, req any) error {
+	if reflect.Indirect(reflect.ValueOf(req)).Kind() != reflect.Struct {
+		return nil
+	}
+
+	switch v := c.Echo().Validator.(type) {
+	case nil:
+		return nil
+	case *Validator:
+		return v.ValidateCtx(c.Request().Context(), req)
+	default:
+		return v.Validate(req)
+	}
+}
+
+func writeTypedResponse(c echo.Context, status int, resp any) error {
+	if statusCoder, ok := resp.(StatusCoder); ok {
+		status = statusCoder.StatusCode()
+	}
+
+	if status == http.StatusNoContent {
+		return c.NoContent(status)
+	}
+
+	offers := []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML, echo.MIMETextXML}
+
+	str, isString := resp.(string)
+	if isString {
+		offers = append(offers, echo.MIMETextPlain)
+	}
+
+	switch NegotiateContentType(c.Request().Header.Get(echo.HeaderAccept), offers...) {
+	case echo.MIMEApplicationJSON:
+		return c.JSON(status, resp)
+	case echo.MIMEApplicationXML, echo.MIMETextXML:
+		return c.XML(status, resp)
+	case echo.MIMETextPlain:
+		return c.String(status, str)
+	default:
+		return echo.NewHTTPError(http.StatusNotAcceptable)
+	}
+}
+
+// NegotiateContentType returns the offered content type preferred by an Accept header, or an empty string if none
+// is acceptable. Each offer gets the quality of its most specific matching media range, and the first offer is
+// preferred if the Accept header is empty, or in case of equal qualities.
+func NegotiateContentType(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	// media ranges qualities
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		value, params, _ := strings.Cut(part, ";")
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if q, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		qualities[strings.ToLower(strings.TrimSpace(value))] = quality
+	}
+
+	bestOffer := ""
+	bestQuality := 0.0
+
+	for _, offer := range offers {
+		mainType, _, _ := strings.Cut(offer, "/")
+
+		for _, mediaRange := range []string{offer, mainType + "/*", "*/*"} {
+			if quality, ok := qualities[mediaRange]; ok {
+				if quality > bestQuality {
+					bestOffer, bestQuality = offer, quality
+				}
+
+				break
+			}
+		}
+	}
+
+	return bestOffer
+}
+
+// newTypedValidationError returns a 400 [echo.HTTPError], with an [httpserver.ValidationError] detailing each invalid
+// field (see [typedInvalidParams]).
+func newTypedValidationError(validationErrs validator.ValidationErrors, requestType reflect.Type) error {
+	validationErr := httpserver.NewValidationError(
+		InvalidTypedRequestMessage,
+		typedInvalidParams(validationErrs, requestType)...,
+	)
+
+	return echo.NewHTTPError(http.StatusBadRequest, InvalidTypedRequestMessage).SetInternal(validationErr)
+}
+
+// typedInvalidParams converts validation errors into [httpserver.InvalidParam]: the fields of the request type tagged
+// with param, query or header are reported in path, query or header with their tag names, and the other ones in body
+// with their json names.
+func typedInvalidParams(validationErrs validator.ValidationErrors, requestType reflect.Type) []httpserver.InvalidParam {
+	invalidParams := make([]httpserver.InvalidParam, len(validationErrs))
+
+	for i, fieldErr := range validationErrs {
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule = fmt.Sprintf("%s=%s", rule, fieldErr.Param())
+		}
+
+		in, name := locateTypedField(requestType, fieldErr.StructNamespace())
+
+		invalidParams[i] = httpserver.InvalidParam{
+			Name:   name,
+			In:     in,
+			Reason: fmt.Sprintf("failed on the %s validation", rule),
+		}
+	}
+
+	return invalidParams
+}
+
+//nolint:cyclop
+func locateTypedField(requestType reflect.Type, namespace string) (string, string) {
+	if requestType == nil {
+		// the namespace starts with the validated struct name, if not anonymous
+		if _, fieldsNamespace, found := strings.Cut(namespace, "."); found {
+			namespace = fieldsNamespace
+		}
+
+		return "", namespace
+	}
+
+	// the namespace starts with the request struct name, if not anonymous
+	structType := requestType
+	for structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType.Name() != "" {
+		namespace = strings.TrimPrefix(namespace, structType.Name()+".")
+	}
+
+	in := "body"
+	names := []string{}
+	currentType := requestType
+
+	for i, part := range strings.Split(namespace, ".") {
+		fieldName, index, indexed := strings.Cut(part, "[")
+
+		for currentType.Kind() == reflect.Pointer {
+			currentType = currentType.Elem()
+		}
+
+		if currentType.Kind() != reflect.Struct {
+			names = append(names, part)
+
+			continue
+		}
+
+		field, ok := currentType.FieldByName(fieldName)
+		if !ok {
+			names = append(names, part)
+
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "" || jsonName == "-" {
+			jsonName = field.Name
+		}
+
+		name := jsonName
+
+		if i == 0 {
+			for _, location := range [][2]string{{"param", "path"}, {"query", "query"}, {"header", "header"}} {
+				if tagName := field.Tag.Get(location[0]); tagName != "" {
+					in, name = location[1], tagName
+				}
+			}
+		}
+
+		if indexed {
+			name = fmt.Sprintf("%s[%s", name, index)
+		}
+
+		// embedded structs fields are json encoded in their parent
+		if !field.Anonymous || field.Tag.Get("json") != "" {
+			names = append(names, name)
+		}
+
+		currentType = field.Type
+		for currentType.Kind() == reflect.Pointer {
+			currentType = currentType.Elem()
+		}
+
+		if indexed && (currentType.Kind() == reflect.Slice || currentType.Kind() == reflect.Array || currentType.Kind() == reflect.Map) {
+			currentType = currentType.Elem()
+		}
+	}
+
+	return in, strings.Join(names, ".")
+}
@@ -0,0 +1,458 @@
+package fxhttpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/fxconfig"
+	"github.com/ankorstore/yokai/fxgenerate"
+	"github.com/ankorstore/yokai/fxhttpserver"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/handler"
+	"github.com/ankorstore/yokai/fxhttpserver/testdata/service"
+	"github.com/ankorstore/yokai/fxlog"
+	"github.com/ankorstore/yokai/fxmetrics"
+	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+type testTypedAddress struct {
+	Street string `json:"street" validate:"required"`
+}
+
+type testTypedRequest struct {
+	ID      int                `param:"id" validate:"gt=0"`
+	Verbose bool               `query:"verbose"`
+	Tenant  string             `header:"X-Tenant" validate:"required"`
+	Name    string             `json:"name" validate:"required,max=10"`
+	Address testTypedAddress   `json:"address"`
+	Tags    []testTypedAddress `json:"tags" validate:"dive"`
+}
+
+type testTypedResponse struct {
+	ID      int    `json:"id" xml:"id"`
+	Verbose bool   `json:"verbose" xml:"verbose"`
+	Tenant  string `json:"tenant" xml:"tenant"`
+	Name    string `json:"name" xml:"name"`
+}
+
+type testStatusError struct{}
+
+func (e testStatusError) Error() string {
+	return "conflict"
+}
+
+func (e testStatusError) StatusCode() int {
+	return http.StatusConflict
+}
+
+type testAcceptedResponse struct{}
+
+func (r testAcceptedResponse) StatusCode() int {
+	return http.StatusAccepted
+}
+
+func newTestTypedServer() *echo.Echo {
+	httpServer := echo.New()
+	httpServer.Validator = fxhttpserver.NewValidator(validator.New())
+	httpServer.HTTPErrorHandler = httpserver.NewJsonErrorHandler(false, false).Handle()
+
+	return httpServer
+}
+
+func testTypedHandle(ctx context.Context, req testTypedRequest) (testTypedResponse, error) {
+	return testTypedResponse{
+		ID:      req.ID,
+		Verbose: req.Verbose,
+		Tenant:  req.Tenant,
+		Name:    req.Name,
+	}, nil
+}
+
+func TestTyped(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestTypedServer()
+	httpServer.PUT("/users/:id", fxhttpserver.Typed(testTypedHandle, fxhttpserver.WithTypedHandlerStatus(http.StatusCreated)))
+
+	req := httptest.NewRequest(http.MethodPut, "/users/12?verbose=true", strings.NewReader(`{"id":99,"name":"john","address":{"street":"main"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Tenant", "acme")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(t, `{"id":12,"verbose":true,"tenant":"acme","name":"john"}`, rec.Body.String())
+}
+
+func TestTypedWithInvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestTypedServer()
+	httpServer.PUT("/users/:id", fxhttpserver.Typed(testTypedHandle))
+
+	req := httptest.NewRequest(http.MethodPut, "/users/0", strings.NewReader(`{"name":"too long name","tags":[{"street":""}]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body struct {
+		Message string                    `json:"message"`
+		Errors  []httpserver.InvalidParam `json:"errors"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, fxhttpserver.InvalidTypedRequestMessage, body.Message)
+	assert.Equal(
+		t,
+		[]httpserver.InvalidParam{
+			{Name: "id", In: "path", Reason: "failed on the gt=0 validation"},
+			{Name: "X-Tenant", In: "header", Reason: "failed on the required validation"},
+			{Name: "name", In: "body", Reason: "failed on the max=10 validation"},
+			{Name: "address.street", In: "body", Reason: "failed on the required validation"},
+			{Name: "tags[0].street", In: "body", Reason: "failed on the required validation"},
+		},
+		body.Errors,
+	)
+}
+
+func TestTypedWithInvalidAnonymousRequest(t *testing.T) {
+	t.Parallel()
+
+	type anonymousRequest = struct {
+		Name    string           `json:"name" validate:"required"`
+		Address testTypedAddress `json:"address"`
+	}
+
+	httpServer := newTestTypedServer()
+	httpServer.POST("/users", fxhttpserver.Typed(func(ctx context.Context, req anonymousRequest) (testTypedResponse, error) {
+		return testTypedResponse{Name: req.Name}, nil
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body struct {
+		Errors []httpserver.InvalidParam `json:"errors"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	assert.NoError(t, err)
+
+	// the validation namespaces of anonymous structs do not start with a struct name
+	assert.Equal(
+		t,
+		[]httpserver.InvalidParam{
+			{Name: "name", In: "body", Reason: "failed on the required validation"},
+			{Name: "address.street", In: "body", Reason: "failed on the required validation"},
+		},
+		body.Errors,
+	)
+}
+
+func TestTypedWithInvalidBinding(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestTypedServer()
+	httpServer.PUT("/users/:id", fxhttpserver.Typed(testTypedHandle))
+
+	req := httptest.NewRequest(http.MethodPut, "/users/abc", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTypedWithoutValidator(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.PUT("/users/:id", fxhttpserver.Typed(testTypedHandle))
+
+	req := httptest.NewRequest(http.MethodPut, "/users/0", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+type testTypedBinder struct {
+	echo.DefaultBinder
+}
+
+func (b *testTypedBinder) BindBody(c echo.Context, i any) error {
+	if err := b.DefaultBinder.BindBody(c, i); err != nil {
+		return err
+	}
+
+	if req, ok := i.(*testTypedRequest); ok {
+		req.Name = strings.ToUpper(req.Name)
+	}
+
+	return nil
+}
+
+type testBodyOnlyBinder struct{}
+
+func (b *testBodyOnlyBinder) Bind(i any, c echo.Context) error {
+	return (&echo.DefaultBinder{}).BindBody(c, i)
+}
+
+func TestTypedWithServerBinder(t *testing.T) {
+	t.Parallel()
+
+	send := func(binder echo.Binder) *httptest.ResponseRecorder {
+		httpServer := newTestTypedServer()
+		httpServer.Binder = binder
+		httpServer.PUT("/users/:id", fxhttpserver.Typed(testTypedHandle))
+
+		req := httptest.NewRequest(http.MethodPut, "/users/1", strings.NewReader(`{"name":"john","address":{"street":"main"}}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Tenant", "acme")
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// binder binding each request part
+	rec := send(&testTypedBinder{})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":1,"verbose":false,"tenant":"acme","name":"JOHN"}`, rec.Body.String())
+
+	// other binders
+	rec = send(&testBodyOnlyBinder{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `{"name":"id","in":"path","reason":"failed on the gt=0 validation"}`)
+}
+
+func TestTypedContentNegotiation(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestTypedServer()
+	httpServer.GET("/struct", fxhttpserver.Typed(func(ctx context.Context, req struct{}) (testTypedResponse, error) {
+		return testTypedResponse{ID: 1, Name: "john"}, nil
+	}))
+	httpServer.GET("/string", fxhttpserver.Typed(func(ctx context.Context, req struct{}) (string, error) {
+		return "hello", nil
+	}))
+
+	tests := []struct {
+		path        string
+		accept      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"/struct", "", http.StatusOK, echo.MIMEApplicationJSON, `{"id":1,"verbose":false,"tenant":"","name":"john"}`},
+		{"/struct", "*/*", http.StatusOK, echo.MIMEApplicationJSON, `{"id":1,"verbose":false,"tenant":"","name":"john"}`},
+		{"/struct", "application/xml", http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, `<testTypedResponse><id>1</id>`},
+		{"/struct", "text/html, application/json;q=0.5, application/xml;q=0.9", http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, `<testTypedResponse>`},
+		{"/struct", "text/html", http.StatusNotAcceptable, echo.MIMEApplicationJSON, `{"message":"Not Acceptable"}`},
+		{"/string", "text/plain", http.StatusOK, echo.MIMETextPlainCharsetUTF8, `hello`},
+		{"/string", "", http.StatusOK, echo.MIMEApplicationJSON, `"hello"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.path, tt.accept), func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Contains(t, rec.Body.String(), tt.body)
+		})
+	}
+}
+
+func TestTypedResponseStatus(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newTestTypedServer()
+	httpServer.POST("/accepted", fxhttpserver.Typed(func(ctx context.Context, req struct{}) (testAcceptedResponse, error) {
+		return testAcceptedResponse{}, nil
+	}))
+	httpServer.DELETE("/deleted", fxhttpserver.Typed(func(ctx context.Context, req struct{}) (any, error) {
+		return nil, nil
+	}, fxhttpserver.WithTypedHandlerStatus(http.StatusNoContent)))
+
+	req := httptest.NewRequest(http.MethodPost, "/accepted", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/deleted", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestTypedErrorMapping(t *testing.T) {
+	t.Parallel()
+
+	type testValidated struct {
+		Name string `validate:"required"`
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+		body string
+	}{
+		{"http error", echo.NewHTTPError(http.StatusForbidden, "forbidden"), http.StatusForbidden, `{"message":"forbidden"}`},
+		{"validation error", httpserver.NewValidationError("invalid", httpserver.InvalidParam{Name: "name", Reason: "taken"}), http.StatusBadRequest, `{"message":"invalid","errors":[{"name":"name","reason":"taken"}]}`},
+		{"validator errors", validator.New().Struct(testValidated{}), http.StatusBadRequest, `{"message":"invalid request","errors":[{"name":"Name","reason":"failed on the required validation"}]}`},
+		{"status coder", fmt.Errorf("wrapped: %w", testStatusError{}), http.StatusConflict, `{"message":"wrapped: conflict"}`},
+		{"other", errors.New("failure"), http.StatusInternalServerError, `{"message":"Internal Server Error"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			httpServer := newTestTypedServer()
+			httpServer.GET("/test", fxhttpserver.Typed(func(ctx context.Context, req struct{}) (any, error) {
+				return nil, tt.err
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.JSONEq(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestNegotiateContentType(t *testing.T) {
+	t.Parallel()
+
+	offers := []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML}
+
+	assert.Equal(t, echo.MIMEApplicationJSON, fxhttpserver.NegotiateContentType("", offers...))
+	assert.Equal(t, echo.MIMEApplicationJSON, fxhttpserver.NegotiateContentType("application/*", offers...))
+	assert.Equal(t, echo.MIMEApplicationXML, fxhttpserver.NegotiateContentType("Application/XML", offers...))
+	assert.Equal(t, echo.MIMEApplicationXML, fxhttpserver.NegotiateContentType("application/json;q=0.1, */*;q=0.5", offers...))
+	assert.Equal(t, echo.MIMEApplicationXML, fxhttpserver.NegotiateContentType("application/json;q=0, */*", offers...))
+	assert.Equal(t, "", fxhttpserver.NegotiateContentType("text/html", offers...))
+	assert.Equal(t, "", fxhttpserver.NegotiateContentType("", []string{}...))
+}
+
+func TestModuleWithTypedHandlers(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var logBuffer logtest.TestLogBuffer
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Supply(validator.New()),
+		fx.Provide(service.NewTestService),
+		fx.Options(
+			fxhttpserver.AsHandler("GET", "/hello", handler.NewTestTypedHandler),
+			fxhttpserver.AsHandler("PUT", "/users/:id", fxhttpserver.Typed(testTypedHandle)),
+		),
+		fx.Populate(&httpServer, &logBuffer),
+	).RequireStart().RequireStop()
+
+	assert.IsType(t, &fxhttpserver.Validator{}, httpServer.Validator)
+
+	logtest.AssertHasNotLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "warn",
+		"message": "typed handler for [PUT] /users/:id is registered without validator, its requests will not be validated",
+	})
+
+	// autowired typed handler
+	req := httptest.NewRequest(http.MethodGet, "/hello?name=john", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"app":"test","message":"hello john"}`, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/hello?name=jo", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"errors":[{"name":"name","in":"query","reason":"failed on the min=3 validation"}]`)
+
+	// concrete typed handler
+	req = httptest.NewRequest(http.MethodPut, "/users/1", strings.NewReader(`{"name":"john","address":{"street":"main"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Tenant", "acme")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestModuleWithTypedHandlersWithoutValidator(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+
+	var httpServer *echo.Echo
+	var logBuffer logtest.TestLogBuffer
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxhttpserver.FxHttpServerModule,
+		fx.Options(
+			fxhttpserver.AsHandler("PUT", "/users/:id", fxhttpserver.Typed(testTypedHandle)),
+			fxhttpserver.AsHandler("GET", "/raw", fxhttpserver.Typed(func(ctx context.Context, req string) (string, error) {
+				return req, nil
+			})),
+		),
+		fx.Populate(&httpServer, &logBuffer),
+	).RequireStart().RequireStop()
+
+	assert.Nil(t, httpServer.Validator)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "warn",
+		"message": "typed handler for [PUT] /users/:id is registered without validator, its requests will not be validated",
+	})
+	logtest.AssertHasNotLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "warn",
+		"message": "typed handler for [GET] /raw is registered without validator, its requests will not be validated",
+	})
+}
@@ -0,0 +1,61 @@
+package fxhttpserver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validator is an [echo.Validator] validating structs with a [validator.Validate], like the one provided by the
+// fxvalidator module.
+type Validator struct {
+	validate *validator.Validate
+}
+
+// StructValidationError is returned by the [Validator] for invalid structs, with the [validator.ValidationErrors]
+// and the type of the validated struct (to report the invalid fields with their json, param, query or header names).
+type StructValidationError struct {
+	Type   reflect.Type
+	Errors validator.ValidationErrors
+}
+
+// Error returns the validation errors message.
+func (e *StructValidationError) Error() string {
+	return e.Errors.Error()
+}
+
+// Unwrap returns the [validator.ValidationErrors].
+func (e *StructValidationError) Unwrap() error {
+	return e.Errors
+}
+
+// NewValidator returns a new [Validator].
+func NewValidator(validate *validator.Validate) *Validator {
+	return &Validator{
+		validate: validate,
+	}
+}
+
+// Validate validates a struct.
+func (v *Validator) Validate(i any) error {
+	return wrapValidationErrors(v.validate.Struct(i), i)
+}
+
+// ValidateCtx validates a struct, with a context passed to the context aware validations.
+func (v *Validator) ValidateCtx(ctx context.Context, i any) error {
+	return wrapValidationErrors(v.validate.StructCtx(ctx, i), i)
+}
+
+func wrapValidationErrors(err error, i any) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return &StructValidationError{
+			Type:   reflect.TypeOf(i),
+			Errors: validationErrs,
+		}
+	}
+
+	return err
+}
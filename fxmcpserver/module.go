@@ -9,7 +9,7 @@ import (
 	fs "github.com/ankorstore/yokai/fxmcpserver/server"
 	"github.com/ankorstore/yokai/fxmcpserver/server/sse"
 	"github.com/ankorstore/yokai/fxmcpserver/server/stdio"
-	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/log"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/prometheus/client_golang/prometheus"
@@ -32,6 +32,7 @@ var FxMCPServerModule = fx.Module(
 		ProvideMCPSSEServer,
 		ProvideMCPSSETestServer,
 		ProvideMCPStdioServer,
+		ProvideMCPServerShutdownParticipant,
 		// module overridable dependencies
 		fx.Annotate(
 			ProvideDefaultMCPServerHooksProvider,
@@ -71,6 +72,13 @@ var FxMCPServerModule = fx.Module(
 			fx.As(new(any)),
 			fx.ResultTags(`group:"core-module-infos"`),
 		),
+		// module shutdown participant
+		fx.Annotate(
+			func(participant *MCPServerShutdownParticipant) any {
+				return participant
+			},
+			fx.ResultTags(`group:"core-shutdown-participants"`),
+		),
 	),
 )
 
@@ -97,6 +105,11 @@ func ProvideDefaultMCPServerFactory(p ProvideDefaultMCPServerFactoryParams) *fs.
 	return fs.NewDefaultMCPServerFactory(p.Config)
 }
 
+// ProvideMCPServerShutdownParticipant provides the MCPServerShutdownParticipant, for the core graceful shutdown.
+func ProvideMCPServerShutdownParticipant() *MCPServerShutdownParticipant {
+	return NewMCPServerShutdownParticipant()
+}
+
 // ProvideMCPServerRegistryParams allows injection of the required dependencies in ProvideMCPServerRegistry.
 type ProvideMCPServerRegistryParams struct {
 	fx.In
@@ -139,7 +152,7 @@ func ProvideMCPServer(p ProvideMCPServerParam) *server.MCPServer {
 // ProvideDefaultMCPStreamableHTTPContextHandlerParam allows injection of the required dependencies in ProvideDefaultMCPStreamableHTTPServerContextHandler.
 type ProvideDefaultMCPStreamableHTTPContextHandlerParam struct {
 	fx.In
-	Generator                           uuid.UuidGenerator
+	Generator                           correlation.CorrelationIdGenerator
 	TracerProvider                      trace.TracerProvider
 	Logger                              *log.Logger
 	MCPStreamableHTTPServerContextHooks []stream.MCPStreamableHTTPServerContextHook `group:"mcp-streamable-http-server-context-hooks"`
@@ -178,6 +191,7 @@ type ProvideMCPStreamableHTTPServerParam struct {
 	LifeCycle                             fx.Lifecycle
 	Logger                                *log.Logger
 	Config                                *config.Config
+	Shutdown                              *MCPServerShutdownParticipant
 	MCPServer                             *server.MCPServer
 	MCPStreamableHTTPServerFactory        stream.MCPStreamableHTTPServerFactory
 	MCPStreamableHTTPServerContextHandler stream.MCPStreamableHTTPServerContextHandler
@@ -204,12 +218,16 @@ func ProvideMCPStreamableHTTPServer(p ProvideMCPStreamableHTTPServerParam) *stre
 			},
 			OnStop: func(ctx context.Context) error {
 				if !p.Config.IsTestEnv() {
-					return streamableHTTPServer.Stop(ctx)
+					return p.Shutdown.Shutdown(ctx)
 				}
 
 				return nil
 			},
 		})
+
+		if !p.Config.IsTestEnv() {
+			p.Shutdown.Bind(streamableHTTPServer.Stop)
+		}
 	}
 
 	return streamableHTTPServer
@@ -231,7 +249,7 @@ func ProvideMCPStreamableHTTPTestServer(p ProvideMCPStreamableHTTPTestServerPara
 // ProvideDefaultMCPSSEContextHandlerParam allows injection of the required dependencies in ProvideDefaultMCPSSEServerContextHandler.
 type ProvideDefaultMCPSSEContextHandlerParam struct {
 	fx.In
-	Generator                uuid.UuidGenerator
+	Generator                correlation.CorrelationIdGenerator
 	TracerProvider           trace.TracerProvider
 	Logger                   *log.Logger
 	MCPSSEServerContextHooks []sse.MCPSSEServerContextHook `group:"mcp-sse-server-context-hooks"`
@@ -270,6 +288,7 @@ type ProvideMCPSSEServerParam struct {
 	LifeCycle                  fx.Lifecycle
 	Logger                     *log.Logger
 	Config                     *config.Config
+	Shutdown                   *MCPServerShutdownParticipant
 	MCPServer                  *server.MCPServer
 	MCPSSEServerFactory        sse.MCPSSEServerFactory
 	MCPSSEServerContextHandler sse.MCPSSEServerContextHandler
@@ -296,12 +315,16 @@ func ProvideMCPSSEServer(p ProvideMCPSSEServerParam) *sse.MCPSSEServer {
 			},
 			OnStop: func(ctx context.Context) error {
 				if !p.Config.IsTestEnv() {
-					return sseServer.Stop(ctx)
+					return p.Shutdown.Shutdown(ctx)
 				}
 
 				return nil
 			},
 		})
+
+		if !p.Config.IsTestEnv() {
+			p.Shutdown.Bind(sseServer.Stop)
+		}
 	}
 
 	return sseServer
@@ -323,7 +346,7 @@ func ProvideMCPSSETestServer(p ProvideMCPSSEServerParam) *fxmcpservertest.MCPSSE
 // ProvideDefaultMCPStdioContextHandlerParam allows injection of the required dependencies in ProvideDefaultMCPStdioServerContextHandler.
 type ProvideDefaultMCPStdioContextHandlerParam struct {
 	fx.In
-	Generator      uuid.UuidGenerator
+	Generator      correlation.CorrelationIdGenerator
 	TracerProvider trace.TracerProvider
 	Logger         *log.Logger
 }
@@ -0,0 +1,54 @@
+package fxmcpserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// MCPServerShutdownParticipant handles the graceful shutdown of the exposed MCP servers.
+type MCPServerShutdownParticipant struct {
+	mutex    sync.Mutex
+	stoppers []func(context.Context) error
+	once     sync.Once
+	err      error
+}
+
+// NewMCPServerShutdownParticipant returns a new MCPServerShutdownParticipant instance.
+func NewMCPServerShutdownParticipant() *MCPServerShutdownParticipant {
+	return &MCPServerShutdownParticipant{}
+}
+
+// Name returns the name of the MCPServerShutdownParticipant.
+func (p *MCPServerShutdownParticipant) Name() string {
+	return ModuleName
+}
+
+// Bind binds a MCP server stop function to the MCPServerShutdownParticipant.
+func (p *MCPServerShutdownParticipant) Bind(stop func(context.Context) error) *MCPServerShutdownParticipant {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stoppers = append(p.stoppers, stop)
+
+	return p
+}
+
+// Shutdown stops the bound MCP servers.
+// Only the first call performs the shutdown, next calls return its result.
+func (p *MCPServerShutdownParticipant) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		p.mutex.Lock()
+		stoppers := p.stoppers
+		p.mutex.Unlock()
+
+		var errs []error
+		for _, stop := range stoppers {
+			errs = append(errs, stop(ctx))
+		}
+
+		p.err = errors.Join(errs...)
+	})
+
+	return p.err
+}
@@ -0,0 +1,50 @@
+package fxmcpserver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ankorstore/yokai/fxmcpserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMCPServerShutdownParticipant(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without bound servers", func(t *testing.T) {
+		t.Parallel()
+
+		participant := fxmcpserver.NewMCPServerShutdownParticipant()
+
+		assert.Equal(t, fxmcpserver.ModuleName, participant.Name())
+		assert.NoError(t, participant.Shutdown(context.Background()))
+	})
+
+	t.Run("with bound servers", func(t *testing.T) {
+		t.Parallel()
+
+		stops := 0
+
+		participant := fxmcpserver.NewMCPServerShutdownParticipant().
+			Bind(func(context.Context) error {
+				stops++
+
+				return nil
+			}).
+			Bind(func(context.Context) error {
+				stops++
+
+				return errors.New("stop error")
+			})
+
+		err := participant.Shutdown(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, "stop error", err.Error())
+
+		// next calls return the first shutdown result
+		err = participant.Shutdown(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 2, stops)
+	})
+}
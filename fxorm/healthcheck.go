@@ -0,0 +1,40 @@
+package fxorm
+
+import (
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/fxhealthcheck"
+	"github.com/ankorstore/yokai/healthcheck"
+	ormhealthcheck "github.com/ankorstore/yokai/orm/healthcheck"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// FxOrmCheckerProbesRegistrationsParam allows injection of the required dependencies in [NewFxOrmCheckerProbesRegistrations].
+type FxOrmCheckerProbesRegistrationsParam struct {
+	fx.In
+	Config *config.Config
+	DB     *gorm.DB
+}
+
+// NewFxOrmCheckerProbesRegistrations returns the [healthcheck.CheckerProbeRegistration] list of the ORM, when enabled
+// in modules.orm.healthcheck.
+func NewFxOrmCheckerProbesRegistrations(p FxOrmCheckerProbesRegistrationsParam) []*healthcheck.CheckerProbeRegistration {
+	registrations := []*healthcheck.CheckerProbeRegistration{}
+
+	if !p.Config.GetBool("modules.orm.healthcheck.enabled") {
+		return registrations
+	}
+
+	name := p.Config.GetString("modules.orm.healthcheck.name")
+	if name == "" {
+		name = ormhealthcheck.DefaultProbeName
+	}
+
+	return append(
+		registrations,
+		healthcheck.NewCheckerProbeRegistrationWithOptions(
+			ormhealthcheck.NewOrmProbe(p.DB).SetName(name),
+			fxhealthcheck.ResolveConfigCheckerProbeOptions(p.Config, "modules.orm.healthcheck")...,
+		),
+	)
+}
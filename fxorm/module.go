@@ -24,6 +24,10 @@ var FxOrmModule = fx.Module(
 	fx.Provide(
 		orm.NewDefaultOrmFactory,
 		NewFxOrm,
+		fx.Annotate(
+			NewFxOrmCheckerProbesRegistrations,
+			fx.ResultTags(`group:"healthcheck-probes-registrations,flatten"`),
+		),
 	),
 )
 
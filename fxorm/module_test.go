@@ -11,6 +11,7 @@ import (
 	"github.com/ankorstore/yokai/fxorm/testdata/factory"
 	"github.com/ankorstore/yokai/fxorm/testdata/model"
 	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ankorstore/yokai/log"
 	"github.com/ankorstore/yokai/log/logtest"
 	"github.com/ankorstore/yokai/trace/tracetest"
@@ -302,3 +303,65 @@ func TestModuleDecoration(t *testing.T) {
 	err = db.Close()
 	assert.NoError(t, err)
 }
+
+type checkerProbesRegistrationsParam struct {
+	fx.In
+	Registrations []*healthcheck.CheckerProbeRegistration `group:"healthcheck-probes-registrations"`
+}
+
+func TestModuleCheckerProbesRegistrations(t *testing.T) {
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("ORM_DRIVER", "sqlite")
+	t.Setenv("ORM_DSN", ":memory:")
+	t.Setenv("ORM_HEALTHCHECK_ENABLED", "true")
+
+	var registrations []*healthcheck.CheckerProbeRegistration
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxorm.FxOrmModule,
+		fx.Invoke(func(p checkerProbesRegistrationsParam) {
+			registrations = p.Registrations
+		}),
+	).RequireStart().RequireStop()
+
+	assert.Len(t, registrations, 1)
+
+	registration := registrations[0]
+	assert.Equal(t, "database", registration.Probe().Name())
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Startup, healthcheck.Readiness}, registration.Kinds())
+	assert.False(t, registration.Critical())
+
+	result := registration.Probe().Check(context.Background())
+	assert.True(t, result.Success)
+	assert.Equal(t, "database ping success", result.Message)
+}
+
+func TestModuleCheckerProbesRegistrationsWhenDisabled(t *testing.T) {
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("ORM_DRIVER", "sqlite")
+	t.Setenv("ORM_DSN", ":memory:")
+	t.Setenv("ORM_HEALTHCHECK_ENABLED", "false")
+
+	var registrations []*healthcheck.CheckerProbeRegistration
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxorm.FxOrmModule,
+		fx.Invoke(func(p checkerProbesRegistrationsParam) {
+			registrations = p.Registrations
+		}),
+	).RequireStart().RequireStop()
+
+	assert.Empty(t, registrations)
+}
@@ -7,8 +7,10 @@ toolchain go1.26.4
 require (
 	github.com/ankorstore/yokai/config v1.3.0
 	github.com/ankorstore/yokai/fxconfig v1.1.0
+	github.com/ankorstore/yokai/fxhealthcheck v1.1.0
 	github.com/ankorstore/yokai/fxlog v1.1.0
 	github.com/ankorstore/yokai/fxtrace v1.2.0
+	github.com/ankorstore/yokai/healthcheck v1.1.0
 	github.com/ankorstore/yokai/log v1.2.0
 	github.com/ankorstore/yokai/sql v1.2.0
 	github.com/ankorstore/yokai/trace v1.3.0
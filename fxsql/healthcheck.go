@@ -0,0 +1,66 @@
+package fxsql
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/fxhealthcheck"
+	"github.com/ankorstore/yokai/healthcheck"
+	sqlhealthcheck "github.com/ankorstore/yokai/sql/healthcheck"
+	"go.uber.org/fx"
+)
+
+// FxSQLCheckerProbesRegistrationsParam allows injection of the required dependencies in [NewFxSQLCheckerProbesRegistrations].
+type FxSQLCheckerProbesRegistrationsParam struct {
+	fx.In
+	Config *config.Config
+	Pool   *DatabasePool
+}
+
+// NewFxSQLCheckerProbesRegistrations returns the [healthcheck.CheckerProbeRegistration] list of the [DatabasePool]
+// databases, when enabled in modules.sql.healthcheck.
+// The primary database probe is named after the configured name, auxiliaries ones are suffixed by their database name.
+func NewFxSQLCheckerProbesRegistrations(p FxSQLCheckerProbesRegistrationsParam) []*healthcheck.CheckerProbeRegistration {
+	registrations := []*healthcheck.CheckerProbeRegistration{}
+
+	if !p.Config.GetBool("modules.sql.healthcheck.enabled") {
+		return registrations
+	}
+
+	name := p.Config.GetString("modules.sql.healthcheck.name")
+	if name == "" {
+		name = sqlhealthcheck.DefaultProbeName
+	}
+
+	options := fxhealthcheck.ResolveConfigCheckerProbeOptions(p.Config, "modules.sql.healthcheck")
+
+	registrations = append(
+		registrations,
+		healthcheck.NewCheckerProbeRegistrationWithOptions(
+			sqlhealthcheck.NewSQLProbe(p.Pool.Primary().DB()).SetName(name),
+			options...,
+		),
+	)
+
+	auxiliaries := p.Pool.Auxiliaries()
+
+	auxiliariesNames := make([]string, 0, len(auxiliaries))
+	for auxiliaryName := range auxiliaries {
+		auxiliariesNames = append(auxiliariesNames, auxiliaryName)
+	}
+
+	sort.Strings(auxiliariesNames)
+
+	for _, auxiliaryName := range auxiliariesNames {
+		registrations = append(
+			registrations,
+			healthcheck.NewCheckerProbeRegistrationWithOptions(
+				sqlhealthcheck.NewSQLProbe(auxiliaries[auxiliaryName].DB()).SetName(fmt.Sprintf("%s-%s", name, auxiliaryName)),
+				options...,
+			),
+		)
+	}
+
+	return registrations
+}
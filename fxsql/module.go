@@ -30,6 +30,10 @@ var FxSQLModule = fx.Module(
 		NewFxSQLPrimaryDatabase,
 		NewFxSQLMigrator,
 		NewFxSQLSeeder,
+		fx.Annotate(
+			NewFxSQLCheckerProbesRegistrations,
+			fx.ResultTags(`group:"healthcheck-probes-registrations,flatten"`),
+		),
 	),
 )
 
@@ -102,7 +106,10 @@ func NewFxSQLDatabasePool(p FxSQLDatabasePoolParam) (*DatabasePool, error) {
 			return nil, err
 		}
 
-		auxiliaryDB, err := sql.Open(auxiliaryDriverName, fmt.Sprintf("modules.sql.auxiliaries.%s.dsn", auxiliaryDatabaseName))
+		auxiliaryDB, err := sql.Open(
+			auxiliaryDriverName,
+			p.Config.GetString(fmt.Sprintf("modules.sql.auxiliaries.%s.dsn", auxiliaryDatabaseName)),
+		)
 		if err != nil {
 			return nil, err
 		}
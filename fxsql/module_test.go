@@ -3,7 +3,10 @@ package fxsql_test
 import (
 	"context"
 	"database/sql"
+	"path/filepath"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/fxconfig"
 	"github.com/ankorstore/yokai/fxlog"
@@ -11,6 +14,7 @@ import (
 	"github.com/ankorstore/yokai/fxsql/testdata/hook"
 	"github.com/ankorstore/yokai/fxsql/testdata/seed"
 	"github.com/ankorstore/yokai/fxtrace"
+	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ankorstore/yokai/log/logtest"
 	"github.com/ankorstore/yokai/trace"
 	"github.com/ankorstore/yokai/trace/tracetest"
@@ -477,6 +481,46 @@ func TestModuleDatabasePoolWithDedicatedConnections(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestModuleDatabasePoolWithAuxiliariesDsn(t *testing.T) {
+	auxiliaryDsn := filepath.Join(t.TempDir(), "auxiliary1.db")
+
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("SQL_PRIMARY_DRIVER", "sqlite")
+	t.Setenv("SQL_PRIMARY_DSN", ":memory:")
+	t.Setenv("SQL_AUXILIARY1_DRIVER", "sqlite")
+	t.Setenv("SQL_AUXILIARY1_DSN", auxiliaryDsn)
+	t.Setenv("SQL_AUXILIARY2_DRIVER", "sqlite")
+	t.Setenv("SQL_AUXILIARY2_DSN", ":memory:")
+
+	var pool *fxsql.DatabasePool
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		// provide context
+		fx.Provide(func() context.Context {
+			return context.Background()
+		}),
+		// load module and dependencies
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxsql.FxSQLModule,
+		// populate test components
+		fx.Populate(&pool),
+	).RequireStart().RequireStop()
+
+	aux1, err := pool.Auxiliary("auxiliary1")
+	assert.NoError(t, err)
+
+	_, err = aux1.DB().Exec("CREATE TABLE auxiliary_table (id INTEGER PRIMARY KEY)")
+	assert.NoError(t, err)
+
+	// verify the auxiliary database was opened with its configured dsn
+	assert.FileExists(t, auxiliaryDsn)
+}
+
 func TestModuleDatabasePoolAuxiliaryNotFound(t *testing.T) {
 	t.Setenv("APP_ENV", "test")
 	t.Setenv("APP_CONFIG_PATH", "testdata/config")
@@ -514,3 +558,81 @@ func TestModuleDatabasePoolAuxiliaryNotFound(t *testing.T) {
 	err = pool.Primary().DB().Close()
 	assert.NoError(t, err)
 }
+
+type checkerProbesRegistrationsParam struct {
+	fx.In
+	Registrations []*healthcheck.CheckerProbeRegistration `group:"healthcheck-probes-registrations"`
+}
+
+func TestModuleCheckerProbesRegistrations(t *testing.T) {
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("SQL_PRIMARY_DRIVER", "sqlite")
+	t.Setenv("SQL_PRIMARY_DSN", ":memory:")
+	t.Setenv("SQL_AUXILIARY1_DRIVER", "sqlite")
+	t.Setenv("SQL_AUXILIARY1_DSN", ":memory:")
+	t.Setenv("SQL_AUXILIARY2_DRIVER", "sqlite")
+	t.Setenv("SQL_AUXILIARY2_DSN", ":memory:")
+	t.Setenv("SQL_HEALTHCHECK_ENABLED", "true")
+
+	var registrations []*healthcheck.CheckerProbeRegistration
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxsql.FxSQLModule,
+		fx.Invoke(func(p checkerProbesRegistrationsParam) {
+			registrations = p.Registrations
+		}),
+	).RequireStart().RequireStop()
+
+	assert.Len(t, registrations, 3)
+
+	var names []string
+	for _, registration := range registrations {
+		names = append(names, registration.Probe().Name())
+
+		assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Startup, healthcheck.Readiness}, registration.Kinds())
+		assert.Equal(t, time.Second, registration.Timeout())
+		assert.True(t, registration.Critical())
+
+		result := registration.Probe().Check(context.Background())
+		assert.True(t, result.Success)
+		assert.Equal(t, "database ping success", result.Message)
+	}
+
+	sort.Strings(names)
+
+	assert.Equal(t, []string{"db", "db-auxiliary1", "db-auxiliary2"}, names)
+}
+
+func TestModuleCheckerProbesRegistrationsWhenDisabled(t *testing.T) {
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("SQL_PRIMARY_DRIVER", "sqlite")
+	t.Setenv("SQL_PRIMARY_DSN", ":memory:")
+	t.Setenv("SQL_AUXILIARY1_DRIVER", "sqlite")
+	t.Setenv("SQL_AUXILIARY1_DSN", ":memory:")
+	t.Setenv("SQL_AUXILIARY2_DRIVER", "sqlite")
+	t.Setenv("SQL_AUXILIARY2_DSN", ":memory:")
+	t.Setenv("SQL_HEALTHCHECK_ENABLED", "false")
+
+	var registrations []*healthcheck.CheckerProbeRegistration
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxsql.FxSQLModule,
+		fx.Invoke(func(p checkerProbesRegistrationsParam) {
+			registrations = p.Registrations
+		}),
+	).RequireStart().RequireStop()
+
+	assert.Empty(t, registrations)
+}
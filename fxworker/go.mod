@@ -8,10 +8,12 @@ require (
 	github.com/ankorstore/yokai/config v1.3.0
 	github.com/ankorstore/yokai/fxconfig v1.1.0
 	github.com/ankorstore/yokai/fxgenerate v1.2.0
+	github.com/ankorstore/yokai/fxhealthcheck v1.1.0
 	github.com/ankorstore/yokai/fxlog v1.1.0
 	github.com/ankorstore/yokai/fxmetrics v1.1.0
 	github.com/ankorstore/yokai/fxtrace v1.2.0
 	github.com/ankorstore/yokai/generate v1.2.0
+	github.com/ankorstore/yokai/healthcheck v1.1.0
 	github.com/ankorstore/yokai/log v1.2.0
 	github.com/ankorstore/yokai/trace v1.3.0
 	github.com/ankorstore/yokai/worker v1.3.0
@@ -0,0 +1,40 @@
+package fxworker
+
+import (
+	"github.com/ankorstore/yokai/config"
+	"github.com/ankorstore/yokai/fxhealthcheck"
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/worker"
+	workerhealthcheck "github.com/ankorstore/yokai/worker/healthcheck"
+	"go.uber.org/fx"
+)
+
+// FxWorkerCheckerProbesRegistrationsParam allows injection of the required dependencies in [NewFxWorkerCheckerProbesRegistrations].
+type FxWorkerCheckerProbesRegistrationsParam struct {
+	fx.In
+	Config *config.Config
+	Pool   *worker.WorkerPool
+}
+
+// NewFxWorkerCheckerProbesRegistrations returns the [healthcheck.CheckerProbeRegistration] list of the worker pool,
+// when enabled in modules.worker.healthcheck.
+func NewFxWorkerCheckerProbesRegistrations(p FxWorkerCheckerProbesRegistrationsParam) []*healthcheck.CheckerProbeRegistration {
+	registrations := []*healthcheck.CheckerProbeRegistration{}
+
+	if !p.Config.GetBool("modules.worker.healthcheck.enabled") {
+		return registrations
+	}
+
+	name := p.Config.GetString("modules.worker.healthcheck.name")
+	if name == "" {
+		name = workerhealthcheck.DefaultProbeName
+	}
+
+	return append(
+		registrations,
+		healthcheck.NewCheckerProbeRegistrationWithOptions(
+			workerhealthcheck.NewWorkerProbe(p.Pool).SetName(name),
+			fxhealthcheck.ResolveConfigCheckerProbeOptions(p.Config, "modules.worker.healthcheck")...,
+		),
+	)
+}
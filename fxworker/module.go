@@ -4,7 +4,7 @@ import (
 	"context"
 
 	"github.com/ankorstore/yokai/config"
-	"github.com/ankorstore/yokai/generate/uuid"
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/log"
 	"github.com/ankorstore/yokai/trace"
 	"github.com/ankorstore/yokai/worker"
@@ -30,6 +30,10 @@ var FxWorkerModule = fx.Module(
 			fx.As(new(interface{})),
 			fx.ResultTags(`group:"core-module-infos"`),
 		),
+		fx.Annotate(
+			NewFxWorkerCheckerProbesRegistrations,
+			fx.ResultTags(`group:"healthcheck-probes-registrations,flatten"`),
+		),
 	),
 )
 
@@ -37,7 +41,7 @@ var FxWorkerModule = fx.Module(
 type FxWorkerPoolParam struct {
 	fx.In
 	LifeCycle       fx.Lifecycle
-	Generator       uuid.UuidGenerator
+	Generator       correlation.CorrelationIdGenerator
 	TracerProvider  oteltrace.TracerProvider
 	Factory         worker.WorkerPoolFactory
 	Config          *config.Config
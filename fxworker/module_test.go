@@ -14,6 +14,7 @@ import (
 	"github.com/ankorstore/yokai/fxtrace"
 	"github.com/ankorstore/yokai/fxworker"
 	"github.com/ankorstore/yokai/fxworker/testdata/factory"
+	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ankorstore/yokai/log/logtest"
 	"github.com/ankorstore/yokai/trace/tracetest"
 	"github.com/ankorstore/yokai/worker"
@@ -231,3 +232,62 @@ func TestModuleDecoration(t *testing.T) {
 
 	assert.Equal(t, 99, pool.Options().GlobalMaxExecutionsAttempts)
 }
+
+type checkerProbesRegistrationsParam struct {
+	fx.In
+	Registrations []*healthcheck.CheckerProbeRegistration `group:"healthcheck-probes-registrations"`
+}
+
+func TestModuleCheckerProbesRegistrations(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("WORKER_HEALTHCHECK_ENABLED", "true")
+
+	var registrations []*healthcheck.CheckerProbeRegistration
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxworker.FxWorkerModule,
+		fx.Invoke(func(p checkerProbesRegistrationsParam) {
+			registrations = p.Registrations
+		}),
+	).RequireStart().RequireStop()
+
+	assert.Len(t, registrations, 1)
+
+	registration := registrations[0]
+	assert.Equal(t, "workers", registration.Probe().Name())
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Readiness}, registration.Kinds())
+	assert.Equal(t, time.Second, registration.Timeout())
+	assert.True(t, registration.Critical())
+}
+
+func TestModuleCheckerProbesRegistrationsWhenDisabled(t *testing.T) {
+	t.Setenv("APP_CONFIG_PATH", "testdata/config")
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("WORKER_HEALTHCHECK_ENABLED", "false")
+
+	var registrations []*healthcheck.CheckerProbeRegistration
+
+	fxtest.New(
+		t,
+		fx.NopLogger,
+		fxconfig.FxConfigModule,
+		fxlog.FxLogModule,
+		fxtrace.FxTraceModule,
+		fxmetrics.FxMetricsModule,
+		fxgenerate.FxGenerateModule,
+		fxworker.FxWorkerModule,
+		fx.Invoke(func(p checkerProbesRegistrationsParam) {
+			registrations = p.Registrations
+		}),
+	).RequireStart().RequireStop()
+
+	assert.Empty(t, registrations)
+}
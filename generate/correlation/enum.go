@@ -0,0 +1,68 @@
+package correlation
+
+import "strings"
+
+const (
+	Uuid      = "uuid"
+	UuidV6    = "uuidv6"
+	UuidV7    = "uuidv7"
+	Ulid      = "ulid"
+	Ksuid     = "ksuid"
+	Snowflake = "snowflake"
+	Nanoid    = "nanoid"
+)
+
+// GeneratorKind is an enum for the supported kinds of generators backing correlation ids.
+type GeneratorKind int
+
+const (
+	UuidGeneratorKind GeneratorKind = iota
+	UuidV6GeneratorKind
+	UuidV7GeneratorKind
+	UlidGeneratorKind
+	KsuidGeneratorKind
+	SnowflakeGeneratorKind
+	NanoidGeneratorKind
+)
+
+// String returns a string representation of the [GeneratorKind].
+//
+//nolint:exhaustive
+func (k GeneratorKind) String() string {
+	switch k {
+	case UuidV6GeneratorKind:
+		return UuidV6
+	case UuidV7GeneratorKind:
+		return UuidV7
+	case UlidGeneratorKind:
+		return Ulid
+	case KsuidGeneratorKind:
+		return Ksuid
+	case SnowflakeGeneratorKind:
+		return Snowflake
+	case NanoidGeneratorKind:
+		return Nanoid
+	default:
+		return Uuid
+	}
+}
+
+// FetchGeneratorKind returns a [GeneratorKind] for a given value.
+func FetchGeneratorKind(k string) GeneratorKind {
+	switch strings.ToLower(k) {
+	case UuidV6:
+		return UuidV6GeneratorKind
+	case UuidV7:
+		return UuidV7GeneratorKind
+	case Ulid:
+		return UlidGeneratorKind
+	case Ksuid:
+		return KsuidGeneratorKind
+	case Snowflake:
+		return SnowflakeGeneratorKind
+	case Nanoid:
+		return NanoidGeneratorKind
+	default:
+		return UuidGeneratorKind
+	}
+}
@@ -0,0 +1,52 @@
+package correlation_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/correlation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratorKindAsString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		kind     correlation.GeneratorKind
+		expected string
+	}{
+		{correlation.UuidGeneratorKind, correlation.Uuid},
+		{correlation.UuidV6GeneratorKind, correlation.UuidV6},
+		{correlation.UuidV7GeneratorKind, correlation.UuidV7},
+		{correlation.UlidGeneratorKind, correlation.Ulid},
+		{correlation.KsuidGeneratorKind, correlation.Ksuid},
+		{correlation.SnowflakeGeneratorKind, correlation.Snowflake},
+		{correlation.NanoidGeneratorKind, correlation.Nanoid},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.kind.String())
+	}
+}
+
+func TestFetchGeneratorKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected correlation.GeneratorKind
+	}{
+		{correlation.Uuid, correlation.UuidGeneratorKind},
+		{correlation.UuidV6, correlation.UuidV6GeneratorKind},
+		{"UUIDv7", correlation.UuidV7GeneratorKind},
+		{correlation.Ulid, correlation.UlidGeneratorKind},
+		{correlation.Ksuid, correlation.KsuidGeneratorKind},
+		{correlation.Snowflake, correlation.SnowflakeGeneratorKind},
+		{correlation.Nanoid, correlation.NanoidGeneratorKind},
+		{"", correlation.UuidGeneratorKind},
+		{"invalid", correlation.UuidGeneratorKind},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, correlation.FetchGeneratorKind(tt.input))
+	}
+}
@@ -0,0 +1,53 @@
+package correlation
+
+import (
+	"fmt"
+
+	"github.com/ankorstore/yokai/generate/uuid"
+)
+
+// CorrelationIdGenerator is the interface for correlation ids generators (request ids, execution ids, session ids, ...).
+type CorrelationIdGenerator interface {
+	Generate() string
+}
+
+// DefaultCorrelationIdGenerator is the default [CorrelationIdGenerator] implementation.
+//
+// It generates correlation ids from a generate function, and falls back on UUIDs V4 if this function fails.
+type DefaultCorrelationIdGenerator struct {
+	generateFunc func() (string, error)
+	fallback     uuid.UuidGenerator
+}
+
+// NewDefaultCorrelationIdGenerator returns a [DefaultCorrelationIdGenerator], implementing [CorrelationIdGenerator].
+//
+// It accepts the function used to generate the correlation ids.
+func NewDefaultCorrelationIdGenerator(generateFunc func() (string, error)) *DefaultCorrelationIdGenerator {
+	return &DefaultCorrelationIdGenerator{
+		generateFunc: generateFunc,
+		fallback:     uuid.NewDefaultUuidGenerator(),
+	}
+}
+
+// Generate returns a new correlation id.
+func (g *DefaultCorrelationIdGenerator) Generate() string {
+	id, err := g.generateFunc()
+	if err != nil || id == "" {
+		return g.fallback.Generate()
+	}
+
+	return id
+}
+
+// Stringify adapts a generate function returning a [fmt.Stringer] (UUID, ULID, KSUID, ...) to be used with
+// [NewDefaultCorrelationIdGenerator].
+func Stringify[T fmt.Stringer](generateFunc func() (T, error)) func() (string, error) {
+	return func() (string, error) {
+		id, err := generateFunc()
+		if err != nil {
+			return "", err
+		}
+
+		return id.String(), nil
+	}
+}
@@ -0,0 +1,69 @@
+package correlation_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/correlation"
+	"github.com/ankorstore/yokai/generate/uuidv7"
+	googleuuid "github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultCorrelationIdGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := correlation.NewDefaultCorrelationIdGenerator(func() (string, error) {
+		return "test", nil
+	})
+
+	assert.IsType(t, &correlation.DefaultCorrelationIdGenerator{}, generator)
+	assert.Implements(t, (*correlation.CorrelationIdGenerator)(nil), generator)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	generator := correlation.NewDefaultCorrelationIdGenerator(func() (string, error) {
+		return "test", nil
+	})
+
+	assert.Equal(t, "test", generator.Generate())
+}
+
+func TestGenerateWithStringify(t *testing.T) {
+	t.Parallel()
+
+	generator := correlation.NewDefaultCorrelationIdGenerator(
+		correlation.Stringify(uuidv7.NewDefaultUuidV7Generator().Generate),
+	)
+
+	value1 := generator.Generate()
+	value2 := generator.Generate()
+
+	assert.NotEqual(t, value1, value2)
+
+	parsedValue1, err := googleuuid.Parse(value1)
+	assert.NoError(t, err)
+	assert.Equal(t, googleuuid.Version(7), parsedValue1.Version())
+
+	parsedValue2, err := googleuuid.Parse(value2)
+	assert.NoError(t, err)
+	assert.Equal(t, googleuuid.Version(7), parsedValue2.Version())
+}
+
+func TestGenerateWithFallback(t *testing.T) {
+	t.Parallel()
+
+	generator := correlation.NewDefaultCorrelationIdGenerator(
+		correlation.Stringify(func() (googleuuid.UUID, error) {
+			return googleuuid.Nil, errors.New("test error")
+		}),
+	)
+
+	value := generator.Generate()
+
+	parsedValue, err := googleuuid.Parse(value)
+	assert.NoError(t, err)
+	assert.Equal(t, googleuuid.Version(4), parsedValue.Version())
+}
@@ -0,0 +1,59 @@
+package correlation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// CorrelationIdValidator is the interface for incoming correlation ids validators.
+type CorrelationIdValidator interface {
+	Validate(id string) error
+}
+
+// DefaultCorrelationIdValidator is the default [CorrelationIdValidator] implementation.
+type DefaultCorrelationIdValidator struct {
+	maxLength int
+	format    *regexp.Regexp
+}
+
+// NewDefaultCorrelationIdValidator returns a [DefaultCorrelationIdValidator], implementing [CorrelationIdValidator].
+//
+// It accepts a max length (0 to disable the check) and a format regular expression (empty to disable the check).
+func NewDefaultCorrelationIdValidator(maxLength int, format string) (*DefaultCorrelationIdValidator, error) {
+	if maxLength < 0 {
+		return nil, fmt.Errorf("invalid correlation id max length %d, must not be negative", maxLength)
+	}
+
+	validator := &DefaultCorrelationIdValidator{
+		maxLength: maxLength,
+	}
+
+	if format != "" {
+		compiledFormat, err := regexp.Compile(format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid correlation id format: %w", err)
+		}
+
+		validator.format = compiledFormat
+	}
+
+	return validator, nil
+}
+
+// Validate returns an error if the provided correlation id is empty, too long, or does not match the format.
+func (v *DefaultCorrelationIdValidator) Validate(id string) error {
+	if id == "" {
+		return errors.New("correlation id is empty")
+	}
+
+	if v.maxLength > 0 && len(id) > v.maxLength {
+		return fmt.Errorf("correlation id length %d exceeds max length %d", len(id), v.maxLength)
+	}
+
+	if v.format != nil && !v.format.MatchString(id) {
+		return fmt.Errorf("correlation id does not match format %s", v.format.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package correlation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/correlation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultCorrelationIdValidator(t *testing.T) {
+	t.Parallel()
+
+	validator, err := correlation.NewDefaultCorrelationIdValidator(0, "")
+	assert.NoError(t, err)
+
+	assert.IsType(t, &correlation.DefaultCorrelationIdValidator{}, validator)
+	assert.Implements(t, (*correlation.CorrelationIdValidator)(nil), validator)
+}
+
+func TestNewDefaultCorrelationIdValidatorFailure(t *testing.T) {
+	t.Parallel()
+
+	_, err := correlation.NewDefaultCorrelationIdValidator(-1, "")
+	assert.Error(t, err)
+	assert.Equal(t, "invalid correlation id max length -1, must not be negative", err.Error())
+
+	_, err = correlation.NewDefaultCorrelationIdValidator(0, "[")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid correlation id format")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		maxLength int
+		format    string
+		id        string
+		expected  string
+	}{
+		{"no checks", 0, "", strings.Repeat("a", 1024), ""},
+		{"empty", 0, "", "", "correlation id is empty"},
+		{"valid length", 5, "", "abcde", ""},
+		{"invalid length", 5, "", "abcdef", "correlation id length 6 exceeds max length 5"},
+		{"valid format", 0, "^[a-z]+$", "abc", ""},
+		{"invalid format", 0, "^[a-z]+$", "abc\n", "correlation id does not match format ^[a-z]+$"},
+		{"valid length and format", 5, "^[a-z]+$", "abc", ""},
+		{"invalid length and valid format", 2, "^[a-z]+$", "abc", "correlation id length 3 exceeds max length 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			validator, err := correlation.NewDefaultCorrelationIdValidator(tt.maxLength, tt.format)
+			assert.NoError(t, err)
+
+			err = validator.Validate(tt.id)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected, err.Error())
+			}
+		})
+	}
+}
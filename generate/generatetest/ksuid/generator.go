@@ -0,0 +1,39 @@
+package ksuid
+
+import segmentioksuid "github.com/segmentio/ksuid"
+
+// TestKsuidGenerator is a [KsuidGenerator] implementation allowing deterministic generations (for testing).
+type TestKsuidGenerator struct {
+	value string
+}
+
+// NewTestKsuidGenerator returns a [TestKsuidGenerator], implementing [KsuidGenerator].
+//
+// It accepts a value that will be used for deterministic generation results.
+func NewTestKsuidGenerator(value string) (*TestKsuidGenerator, error) {
+	_, err := segmentioksuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TestKsuidGenerator{
+		value: value,
+	}, nil
+}
+
+// SetValue sets the value to use for deterministic generations.
+func (g *TestKsuidGenerator) SetValue(value string) error {
+	_, err := segmentioksuid.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	g.value = value
+
+	return nil
+}
+
+// Generate returns the configured deterministic value.
+func (g *TestKsuidGenerator) Generate() (segmentioksuid.KSUID, error) {
+	return segmentioksuid.Parse(g.value)
+}
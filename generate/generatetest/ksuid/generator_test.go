@@ -0,0 +1,68 @@
+package ksuid_test
+
+import (
+	"testing"
+
+	ksuidtest "github.com/ankorstore/yokai/generate/generatetest/ksuid"
+	"github.com/ankorstore/yokai/generate/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ksuid1 = "2hKpDT9u5eBaL3lkMBhcHoqAQa1"
+	ksuid2 = "2hKpDTO8yQnLXkxVbqXkgHQBC6j"
+	ksuid3 = "2hKpDVTgvuqNMXgPbsXB7RdXHA4"
+)
+
+func TestNewTestKsuidGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator, err := ksuidtest.NewTestKsuidGenerator(ksuid1)
+	assert.NoError(t, err)
+
+	assert.IsType(t, &ksuidtest.TestKsuidGenerator{}, generator)
+	assert.Implements(t, (*ksuid.KsuidGenerator)(nil), generator)
+}
+
+func TestGenerateSuccess(t *testing.T) {
+	t.Parallel()
+
+	generator, err := ksuidtest.NewTestKsuidGenerator(ksuid2)
+	assert.NoError(t, err)
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ksuid2, value1.String())
+	assert.Equal(t, ksuid2, value2.String())
+
+	err = generator.SetValue(ksuid3)
+	assert.NoError(t, err)
+
+	value1, err = generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err = generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ksuid3, value1.String())
+	assert.Equal(t, ksuid3, value2.String())
+}
+
+func TestGenerateFailure(t *testing.T) {
+	t.Parallel()
+
+	_, err := ksuidtest.NewTestKsuidGenerator("invalid")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Valid encoded KSUIDs are 27 characters")
+
+	generator, err := ksuidtest.NewTestKsuidGenerator(ksuid1)
+	assert.NoError(t, err)
+
+	err = generator.SetValue("invalid")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Valid encoded KSUIDs are 27 characters")
+}
@@ -0,0 +1,27 @@
+package nanoid
+
+// TestNanoidGenerator is a [NanoidGenerator] implementation allowing deterministic generations (for testing).
+type TestNanoidGenerator struct {
+	value string
+}
+
+// NewTestNanoidGenerator returns a [TestNanoidGenerator], implementing [NanoidGenerator].
+//
+// It accepts a value that will be used for deterministic generation results.
+func NewTestNanoidGenerator(value string) *TestNanoidGenerator {
+	return &TestNanoidGenerator{
+		value: value,
+	}
+}
+
+// SetValue sets the value to use for deterministic generations.
+func (g *TestNanoidGenerator) SetValue(value string) *TestNanoidGenerator {
+	g.value = value
+
+	return g
+}
+
+// Generate returns the configured deterministic value.
+func (g *TestNanoidGenerator) Generate() (string, error) {
+	return g.value, nil
+}
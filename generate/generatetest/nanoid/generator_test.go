@@ -0,0 +1,44 @@
+package nanoid_test
+
+import (
+	"testing"
+
+	nanoidtest "github.com/ankorstore/yokai/generate/generatetest/nanoid"
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTestNanoidGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := nanoidtest.NewTestNanoidGenerator("random")
+
+	assert.IsType(t, &nanoidtest.TestNanoidGenerator{}, generator)
+	assert.Implements(t, (*nanoid.NanoidGenerator)(nil), generator)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	generator := nanoidtest.NewTestNanoidGenerator("test")
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test", value1)
+	assert.Equal(t, "test", value2)
+
+	generator.SetValue("other test")
+
+	value1, err = generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err = generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "other test", value1)
+	assert.Equal(t, "other test", value2)
+}
@@ -0,0 +1,27 @@
+package snowflake
+
+// TestSnowflakeGenerator is a [SnowflakeGenerator] implementation allowing deterministic generations (for testing).
+type TestSnowflakeGenerator struct {
+	value int64
+}
+
+// NewTestSnowflakeGenerator returns a [TestSnowflakeGenerator], implementing [SnowflakeGenerator].
+//
+// It accepts a value that will be used for deterministic generation results.
+func NewTestSnowflakeGenerator(value int64) *TestSnowflakeGenerator {
+	return &TestSnowflakeGenerator{
+		value: value,
+	}
+}
+
+// SetValue sets the value to use for deterministic generations.
+func (g *TestSnowflakeGenerator) SetValue(value int64) *TestSnowflakeGenerator {
+	g.value = value
+
+	return g
+}
+
+// Generate returns the configured deterministic value.
+func (g *TestSnowflakeGenerator) Generate() (int64, error) {
+	return g.value, nil
+}
@@ -0,0 +1,44 @@
+package snowflake_test
+
+import (
+	"testing"
+
+	snowflaketest "github.com/ankorstore/yokai/generate/generatetest/snowflake"
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTestSnowflakeGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := snowflaketest.NewTestSnowflakeGenerator(1)
+
+	assert.IsType(t, &snowflaketest.TestSnowflakeGenerator{}, generator)
+	assert.Implements(t, (*snowflake.SnowflakeGenerator)(nil), generator)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	generator := snowflaketest.NewTestSnowflakeGenerator(123)
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(123), value1)
+	assert.Equal(t, int64(123), value2)
+
+	generator.SetValue(456)
+
+	value1, err = generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err = generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(456), value1)
+	assert.Equal(t, int64(456), value2)
+}
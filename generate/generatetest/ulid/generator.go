@@ -0,0 +1,39 @@
+package ulid
+
+import oklogulid "github.com/oklog/ulid/v2"
+
+// TestUlidGenerator is a [UlidGenerator] implementation allowing deterministic generations (for testing).
+type TestUlidGenerator struct {
+	value string
+}
+
+// NewTestUlidGenerator returns a [TestUlidGenerator], implementing [UlidGenerator].
+//
+// It accepts a value that will be used for deterministic generation results.
+func NewTestUlidGenerator(value string) (*TestUlidGenerator, error) {
+	_, err := oklogulid.ParseStrict(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TestUlidGenerator{
+		value: value,
+	}, nil
+}
+
+// SetValue sets the value to use for deterministic generations.
+func (g *TestUlidGenerator) SetValue(value string) error {
+	_, err := oklogulid.ParseStrict(value)
+	if err != nil {
+		return err
+	}
+
+	g.value = value
+
+	return nil
+}
+
+// Generate returns the configured deterministic value.
+func (g *TestUlidGenerator) Generate() (oklogulid.ULID, error) {
+	return oklogulid.ParseStrict(g.value)
+}
@@ -0,0 +1,68 @@
+package ulid_test
+
+import (
+	"testing"
+
+	ulidtest "github.com/ankorstore/yokai/generate/generatetest/ulid"
+	"github.com/ankorstore/yokai/generate/ulid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ulid1 = "01HZ5Y3Q1N8W9K2T4V6X7Y8Z9A"
+	ulid2 = "01HZ5Y3Q1N8W9K2T4V6X7Y8Z9B"
+	ulid3 = "01HZ5Y3Q1N8W9K2T4V6X7Y8Z9C"
+)
+
+func TestNewTestUlidGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator, err := ulidtest.NewTestUlidGenerator(ulid1)
+	assert.NoError(t, err)
+
+	assert.IsType(t, &ulidtest.TestUlidGenerator{}, generator)
+	assert.Implements(t, (*ulid.UlidGenerator)(nil), generator)
+}
+
+func TestGenerateSuccess(t *testing.T) {
+	t.Parallel()
+
+	generator, err := ulidtest.NewTestUlidGenerator(ulid2)
+	assert.NoError(t, err)
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ulid2, value1.String())
+	assert.Equal(t, ulid2, value2.String())
+
+	err = generator.SetValue(ulid3)
+	assert.NoError(t, err)
+
+	value1, err = generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err = generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ulid3, value1.String())
+	assert.Equal(t, ulid3, value2.String())
+}
+
+func TestGenerateFailure(t *testing.T) {
+	t.Parallel()
+
+	_, err := ulidtest.NewTestUlidGenerator("invalid")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bad data size when unmarshaling")
+
+	generator, err := ulidtest.NewTestUlidGenerator(ulid1)
+	assert.NoError(t, err)
+
+	err = generator.SetValue("invalid")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bad data size when unmarshaling")
+}
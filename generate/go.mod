@@ -6,6 +6,10 @@ toolchain go1.26.4
 
 require (
 	github.com/google/uuid v1.6.0
+	github.com/matoous/go-nanoid/v2 v2.1.0
+	github.com/oklog/ulid/v2 v2.1.0
+	github.com/segmentio/ksuid v1.0.4
+	github.com/sqids/sqids-go v0.4.1
 	github.com/stretchr/testify v1.11.1
 )
 
@@ -0,0 +1,19 @@
+package ksuid
+
+// KsuidGeneratorFactory is the interface for [KsuidGenerator] factories.
+type KsuidGeneratorFactory interface {
+	Create() KsuidGenerator
+}
+
+// DefaultKsuidGeneratorFactory is the default [KsuidGeneratorFactory] implementation.
+type DefaultKsuidGeneratorFactory struct{}
+
+// NewDefaultKsuidGeneratorFactory returns a [DefaultKsuidGeneratorFactory], implementing [KsuidGeneratorFactory].
+func NewDefaultKsuidGeneratorFactory() KsuidGeneratorFactory {
+	return &DefaultKsuidGeneratorFactory{}
+}
+
+// Create returns a new [KsuidGenerator].
+func (g *DefaultKsuidGeneratorFactory) Create() KsuidGenerator {
+	return NewDefaultKsuidGenerator()
+}
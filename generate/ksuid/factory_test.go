@@ -0,0 +1,41 @@
+package ksuid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/ksuid"
+	segmentioksuid "github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultKsuidGeneratorFactory(t *testing.T) {
+	t.Parallel()
+
+	factory := ksuid.NewDefaultKsuidGeneratorFactory()
+
+	assert.IsType(t, &ksuid.DefaultKsuidGeneratorFactory{}, factory)
+	assert.Implements(t, (*ksuid.KsuidGeneratorFactory)(nil), factory)
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	generator := ksuid.NewDefaultKsuidGeneratorFactory().Create()
+
+	ksuid1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	ksuid2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, ksuid1, ksuid2)
+
+	parsedValue1, err := segmentioksuid.Parse(ksuid1.String())
+	assert.NoError(t, err)
+
+	parsedValue2, err := segmentioksuid.Parse(ksuid2.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, ksuid1.String(), parsedValue1.String())
+	assert.Equal(t, ksuid2.String(), parsedValue2.String())
+}
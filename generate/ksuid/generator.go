@@ -0,0 +1,23 @@
+package ksuid
+
+import segmentioksuid "github.com/segmentio/ksuid"
+
+// KsuidGenerator is the interface for KSUID generators.
+type KsuidGenerator interface {
+	Generate() (segmentioksuid.KSUID, error)
+}
+
+// DefaultKsuidGenerator is the default [KsuidGenerator] implementation.
+type DefaultKsuidGenerator struct{}
+
+// NewDefaultKsuidGenerator returns a [DefaultKsuidGenerator], implementing [KsuidGenerator].
+func NewDefaultKsuidGenerator() *DefaultKsuidGenerator {
+	return &DefaultKsuidGenerator{}
+}
+
+// Generate returns a new KSUID, using [Segment KSUID].
+//
+// [Segment KSUID]: https://github.com/segmentio/ksuid
+func (g *DefaultKsuidGenerator) Generate() (segmentioksuid.KSUID, error) {
+	return segmentioksuid.NewRandom()
+}
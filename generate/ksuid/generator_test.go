@@ -0,0 +1,41 @@
+package ksuid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/ksuid"
+	segmentioksuid "github.com/segmentio/ksuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultKsuidGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := ksuid.NewDefaultKsuidGenerator()
+
+	assert.IsType(t, &ksuid.DefaultKsuidGenerator{}, generator)
+	assert.Implements(t, (*ksuid.KsuidGenerator)(nil), generator)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	generator := ksuid.NewDefaultKsuidGenerator()
+
+	ksuid1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	ksuid2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, ksuid1.String(), ksuid2.String())
+
+	parsedKsuid1, err := segmentioksuid.Parse(ksuid1.String())
+	assert.NoError(t, err)
+
+	parsedKsuid2, err := segmentioksuid.Parse(ksuid2.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, ksuid1.String(), parsedKsuid1.String())
+	assert.Equal(t, ksuid2.String(), parsedKsuid2.String())
+}
@@ -0,0 +1,39 @@
+package nanoid
+
+// NanoidGeneratorFactory is the interface for [NanoidGenerator] factories.
+type NanoidGeneratorFactory interface {
+	Create(options ...NanoidGeneratorOption) (NanoidGenerator, error)
+}
+
+// DefaultNanoidGeneratorFactory is the default [NanoidGeneratorFactory] implementation.
+type DefaultNanoidGeneratorFactory struct{}
+
+// NewDefaultNanoidGeneratorFactory returns a [DefaultNanoidGeneratorFactory], implementing [NanoidGeneratorFactory].
+func NewDefaultNanoidGeneratorFactory() NanoidGeneratorFactory {
+	return &DefaultNanoidGeneratorFactory{}
+}
+
+// Create returns a new [NanoidGenerator], and accepts a list of [NanoidGeneratorOption].
+// For example:
+//
+//	var generator, _ = nanoid.NewDefaultNanoidGeneratorFactory().Create()
+//
+// is equivalent to:
+//
+//	var generator, _ = nanoid.NewDefaultNanoidGeneratorFactory().Create(
+//		nanoid.WithAlphabet(nanoid.DefaultAlphabet), // URL friendly alphabet by default
+//		nanoid.WithSize(nanoid.DefaultSize),         // 21 characters by default
+//	)
+func (g *DefaultNanoidGeneratorFactory) Create(options ...NanoidGeneratorOption) (NanoidGenerator, error) {
+	appliedOpts := DefaultNanoidGeneratorOptions()
+	for _, applyOpt := range options {
+		applyOpt(&appliedOpts)
+	}
+
+	generator, err := NewDefaultNanoidGenerator(appliedOpts.Alphabet, appliedOpts.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return generator, nil
+}
@@ -0,0 +1,43 @@
+package nanoid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultNanoidGeneratorFactory(t *testing.T) {
+	t.Parallel()
+
+	factory := nanoid.NewDefaultNanoidGeneratorFactory()
+
+	assert.IsType(t, &nanoid.DefaultNanoidGeneratorFactory{}, factory)
+	assert.Implements(t, (*nanoid.NanoidGeneratorFactory)(nil), factory)
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	generator, err := nanoid.NewDefaultNanoidGeneratorFactory().Create()
+	assert.NoError(t, err)
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, value1, value2)
+	assert.Len(t, value1, nanoid.DefaultSize)
+	assert.Len(t, value2, nanoid.DefaultSize)
+}
+
+func TestCreateFailure(t *testing.T) {
+	t.Parallel()
+
+	generator, err := nanoid.NewDefaultNanoidGeneratorFactory().Create(nanoid.WithSize(-1))
+	assert.Error(t, err)
+	assert.Nil(t, generator)
+	assert.Equal(t, "invalid nanoid size, must be positive", err.Error())
+}
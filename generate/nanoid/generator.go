@@ -0,0 +1,51 @@
+package nanoid
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	// DefaultAlphabet is the default URL friendly alphabet used to generate the ids.
+	DefaultAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// DefaultSize is the default size of the generated ids.
+	DefaultSize = 21
+)
+
+// NanoidGenerator is the interface for NanoID generators.
+type NanoidGenerator interface {
+	Generate() (string, error)
+}
+
+// DefaultNanoidGenerator is the default [NanoidGenerator] implementation.
+type DefaultNanoidGenerator struct {
+	alphabet string
+	size     int
+}
+
+// NewDefaultNanoidGenerator returns a [DefaultNanoidGenerator], implementing [NanoidGenerator].
+//
+// It returns an error if the alphabet is empty or contains more than 255 characters, or if the size is not positive.
+func NewDefaultNanoidGenerator(alphabet string, size int) (*DefaultNanoidGenerator, error) {
+	if alphabet == "" || utf8.RuneCountInString(alphabet) > 255 {
+		return nil, errors.New("invalid nanoid alphabet, must contain between 1 and 255 characters")
+	}
+
+	if size <= 0 {
+		return nil, errors.New("invalid nanoid size, must be positive")
+	}
+
+	return &DefaultNanoidGenerator{
+		alphabet: alphabet,
+		size:     size,
+	}, nil
+}
+
+// Generate returns a new NanoID, using [Go Nanoid].
+//
+// [Go Nanoid]: https://github.com/matoous/go-nanoid
+func (g *DefaultNanoidGenerator) Generate() (string, error) {
+	return gonanoid.Generate(g.alphabet, g.size)
+}
@@ -0,0 +1,55 @@
+package nanoid_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultNanoidGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator, err := nanoid.NewDefaultNanoidGenerator(nanoid.DefaultAlphabet, nanoid.DefaultSize)
+	assert.NoError(t, err)
+
+	assert.IsType(t, &nanoid.DefaultNanoidGenerator{}, generator)
+	assert.Implements(t, (*nanoid.NanoidGenerator)(nil), generator)
+}
+
+func TestNewDefaultNanoidGeneratorFailure(t *testing.T) {
+	t.Parallel()
+
+	_, err := nanoid.NewDefaultNanoidGenerator("", nanoid.DefaultSize)
+	assert.Error(t, err)
+	assert.Equal(t, "invalid nanoid alphabet, must contain between 1 and 255 characters", err.Error())
+
+	_, err = nanoid.NewDefaultNanoidGenerator(strings.Repeat("a", 256), nanoid.DefaultSize)
+	assert.Error(t, err)
+	assert.Equal(t, "invalid nanoid alphabet, must contain between 1 and 255 characters", err.Error())
+
+	_, err = nanoid.NewDefaultNanoidGenerator(nanoid.DefaultAlphabet, 0)
+	assert.Error(t, err)
+	assert.Equal(t, "invalid nanoid size, must be positive", err.Error())
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	generator, err := nanoid.NewDefaultNanoidGenerator("abcdef", 12)
+	assert.NoError(t, err)
+
+	value1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	value2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, value1, value2)
+
+	for _, value := range []string{value1, value2} {
+		assert.Len(t, value, 12)
+		assert.Empty(t, strings.Trim(value, "abcdef"))
+	}
+}
@@ -0,0 +1,32 @@
+package nanoid
+
+// Options are options for the [NanoidGeneratorFactory] implementations.
+type Options struct {
+	Alphabet string
+	Size     int
+}
+
+// DefaultNanoidGeneratorOptions are the default options used in the [DefaultNanoidGeneratorFactory].
+func DefaultNanoidGeneratorOptions() Options {
+	return Options{
+		Alphabet: DefaultAlphabet,
+		Size:     DefaultSize,
+	}
+}
+
+// NanoidGeneratorOption are functional options for the [NanoidGeneratorFactory] implementations.
+type NanoidGeneratorOption func(o *Options)
+
+// WithAlphabet is used to specify the alphabet used to generate the ids.
+func WithAlphabet(a string) NanoidGeneratorOption {
+	return func(o *Options) {
+		o.Alphabet = a
+	}
+}
+
+// WithSize is used to specify the size of the generated ids.
+func WithSize(s int) NanoidGeneratorOption {
+	return func(o *Options) {
+		o.Size = s
+	}
+}
@@ -0,0 +1,26 @@
+package nanoid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/nanoid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAlphabet(t *testing.T) {
+	t.Parallel()
+
+	opt := nanoid.DefaultNanoidGeneratorOptions()
+	nanoid.WithAlphabet("abc")(&opt)
+
+	assert.Equal(t, "abc", opt.Alphabet)
+}
+
+func TestWithSize(t *testing.T) {
+	t.Parallel()
+
+	opt := nanoid.DefaultNanoidGeneratorOptions()
+	nanoid.WithSize(10)(&opt)
+
+	assert.Equal(t, 10, opt.Size)
+}
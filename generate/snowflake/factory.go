@@ -0,0 +1,39 @@
+package snowflake
+
+// SnowflakeGeneratorFactory is the interface for [SnowflakeGenerator] factories.
+type SnowflakeGeneratorFactory interface {
+	Create(options ...SnowflakeGeneratorOption) (SnowflakeGenerator, error)
+}
+
+// DefaultSnowflakeGeneratorFactory is the default [SnowflakeGeneratorFactory] implementation.
+type DefaultSnowflakeGeneratorFactory struct{}
+
+// NewDefaultSnowflakeGeneratorFactory returns a [DefaultSnowflakeGeneratorFactory], implementing [SnowflakeGeneratorFactory].
+func NewDefaultSnowflakeGeneratorFactory() SnowflakeGeneratorFactory {
+	return &DefaultSnowflakeGeneratorFactory{}
+}
+
+// Create returns a new [SnowflakeGenerator], and accepts a list of [SnowflakeGeneratorOption].
+// For example:
+//
+//	var generator, _ = snowflake.NewDefaultSnowflakeGeneratorFactory().Create()
+//
+// is equivalent to:
+//
+//	var generator, _ = snowflake.NewDefaultSnowflakeGeneratorFactory().Create(
+//		snowflake.WithNodeId(0),                     // node id 0 by default
+//		snowflake.WithEpoch(snowflake.DefaultEpoch), // 2024-01-01T00:00:00Z epoch by default
+//	)
+func (g *DefaultSnowflakeGeneratorFactory) Create(options ...SnowflakeGeneratorOption) (SnowflakeGenerator, error) {
+	appliedOpts := DefaultSnowflakeGeneratorOptions()
+	for _, applyOpt := range options {
+		applyOpt(&appliedOpts)
+	}
+
+	generator, err := NewDefaultSnowflakeGenerator(appliedOpts.NodeId, appliedOpts.Epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	return generator, nil
+}
@@ -0,0 +1,47 @@
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultSnowflakeGeneratorFactory(t *testing.T) {
+	t.Parallel()
+
+	factory := snowflake.NewDefaultSnowflakeGeneratorFactory()
+
+	assert.IsType(t, &snowflake.DefaultSnowflakeGeneratorFactory{}, factory)
+	assert.Implements(t, (*snowflake.SnowflakeGeneratorFactory)(nil), factory)
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	generator, err := snowflake.NewDefaultSnowflakeGeneratorFactory().Create(
+		snowflake.WithNodeId(7),
+		snowflake.WithEpoch(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)),
+	)
+	assert.NoError(t, err)
+
+	id1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	id2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, id1, id2)
+	assert.Equal(t, int64(7), (id1>>snowflake.SequenceBits)&snowflake.MaxNodeId)
+	assert.Equal(t, int64(7), (id2>>snowflake.SequenceBits)&snowflake.MaxNodeId)
+}
+
+func TestCreateFailure(t *testing.T) {
+	t.Parallel()
+
+	generator, err := snowflake.NewDefaultSnowflakeGeneratorFactory().Create(snowflake.WithNodeId(2048))
+	assert.Error(t, err)
+	assert.Nil(t, generator)
+	assert.Equal(t, "invalid snowflake node id 2048, must be between 0 and 1023", err.Error())
+}
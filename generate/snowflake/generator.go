@@ -0,0 +1,94 @@
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// TimestampBits is the number of bits used for the timestamp part of the generated ids.
+	TimestampBits = 41
+	// NodeIdBits is the number of bits used for the node id part of the generated ids.
+	NodeIdBits = 10
+	// SequenceBits is the number of bits used for the sequence part of the generated ids.
+	SequenceBits = 12
+
+	// MaxNodeId is the maximum node id value.
+	MaxNodeId = int64(1<<NodeIdBits - 1)
+	// MaxSequence is the maximum sequence value, per millisecond and per node.
+	MaxSequence = int64(1<<SequenceBits - 1)
+	// MaxTimestamp is the maximum timestamp value, in milliseconds since the epoch.
+	MaxTimestamp = int64(1<<TimestampBits - 1)
+)
+
+// DefaultEpoch is the default epoch used for the generated ids timestamps (2024-01-01T00:00:00Z).
+var DefaultEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeGenerator is the interface for Snowflake ids generators.
+type SnowflakeGenerator interface {
+	Generate() (int64, error)
+}
+
+// DefaultSnowflakeGenerator is the default [SnowflakeGenerator] implementation.
+//
+// The generated 63 bits ids are composed of a 41 bits timestamp (milliseconds since the configured epoch),
+// a 10 bits node id and a 12 bits sequence, making them sortable by generation time.
+type DefaultSnowflakeGenerator struct {
+	mutex     sync.Mutex
+	nodeId    int64
+	epoch     time.Time
+	timestamp int64
+	sequence  int64
+}
+
+// NewDefaultSnowflakeGenerator returns a [DefaultSnowflakeGenerator], implementing [SnowflakeGenerator].
+//
+// It returns an error if the node id is out of range, or if the epoch is in the future.
+func NewDefaultSnowflakeGenerator(nodeId int64, epoch time.Time) (*DefaultSnowflakeGenerator, error) {
+	if nodeId < 0 || nodeId > MaxNodeId {
+		return nil, fmt.Errorf("invalid snowflake node id %d, must be between 0 and %d", nodeId, MaxNodeId)
+	}
+
+	now := time.Now()
+	if epoch.After(now) {
+		return nil, fmt.Errorf("invalid snowflake epoch %s, must not be in the future", epoch.Format(time.RFC3339))
+	}
+
+	return &DefaultSnowflakeGenerator{
+		nodeId: nodeId,
+		// adding the epoch offset to now keeps the monotonic clock reading,
+		// making generations safe against wall clock adjustments
+		epoch: now.Add(epoch.Sub(now)),
+	}, nil
+}
+
+// Generate returns a new Snowflake id.
+//
+// If the sequence is exhausted for the current millisecond, it waits for the next one.
+func (g *DefaultSnowflakeGenerator) Generate() (int64, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	timestamp := time.Since(g.epoch).Milliseconds()
+
+	if timestamp == g.timestamp {
+		g.sequence = (g.sequence + 1) & MaxSequence
+
+		if g.sequence == 0 {
+			for timestamp <= g.timestamp {
+				timestamp = time.Since(g.epoch).Milliseconds()
+			}
+		}
+	} else {
+		g.sequence = 0
+	}
+
+	if timestamp > MaxTimestamp {
+		return 0, fmt.Errorf("snowflake timestamp overflow, epoch %s is too old", g.epoch.UTC().Format(time.RFC3339))
+	}
+
+	g.timestamp = timestamp
+
+	return timestamp<<(NodeIdBits+SequenceBits) | g.nodeId<<SequenceBits | g.sequence, nil
+}
@@ -0,0 +1,105 @@
+package snowflake_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultSnowflakeGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator, err := snowflake.NewDefaultSnowflakeGenerator(1, snowflake.DefaultEpoch)
+	assert.NoError(t, err)
+
+	assert.IsType(t, &snowflake.DefaultSnowflakeGenerator{}, generator)
+	assert.Implements(t, (*snowflake.SnowflakeGenerator)(nil), generator)
+}
+
+func TestNewDefaultSnowflakeGeneratorFailure(t *testing.T) {
+	t.Parallel()
+
+	_, err := snowflake.NewDefaultSnowflakeGenerator(-1, snowflake.DefaultEpoch)
+	assert.Error(t, err)
+	assert.Equal(t, "invalid snowflake node id -1, must be between 0 and 1023", err.Error())
+
+	_, err = snowflake.NewDefaultSnowflakeGenerator(1024, snowflake.DefaultEpoch)
+	assert.Error(t, err)
+	assert.Equal(t, "invalid snowflake node id 1024, must be between 0 and 1023", err.Error())
+
+	_, err = snowflake.NewDefaultSnowflakeGenerator(1, time.Now().Add(time.Hour))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be in the future")
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	epoch := time.Now().Add(-1 * time.Hour)
+
+	generator, err := snowflake.NewDefaultSnowflakeGenerator(42, epoch)
+	assert.NoError(t, err)
+
+	id1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	id2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.Less(t, id1, id2)
+
+	for _, id := range []int64{id1, id2} {
+		timestamp := id >> (snowflake.NodeIdBits + snowflake.SequenceBits)
+		nodeId := (id >> snowflake.SequenceBits) & snowflake.MaxNodeId
+
+		assert.Equal(t, int64(42), nodeId)
+		assert.InDelta(t, time.Hour.Milliseconds(), timestamp, float64(time.Minute.Milliseconds()))
+	}
+}
+
+func TestGenerateConcurrently(t *testing.T) {
+	t.Parallel()
+
+	generator, err := snowflake.NewDefaultSnowflakeGenerator(1, snowflake.DefaultEpoch)
+	assert.NoError(t, err)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	ids := make(map[int64]struct{})
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				id, gErr := generator.Generate()
+				assert.NoError(t, gErr)
+
+				mutex.Lock()
+				ids[id] = struct{}{}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, ids, 10000)
+}
+
+func TestGenerateOverflow(t *testing.T) {
+	t.Parallel()
+
+	generator, err := snowflake.NewDefaultSnowflakeGenerator(1, time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+
+	_, err = generator.Generate()
+	assert.Error(t, err)
+	assert.Equal(t, "snowflake timestamp overflow, epoch 1900-01-01T00:00:00Z is too old", err.Error())
+}
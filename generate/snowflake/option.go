@@ -0,0 +1,34 @@
+package snowflake
+
+import "time"
+
+// Options are options for the [SnowflakeGeneratorFactory] implementations.
+type Options struct {
+	NodeId int64
+	Epoch  time.Time
+}
+
+// DefaultSnowflakeGeneratorOptions are the default options used in the [DefaultSnowflakeGeneratorFactory].
+func DefaultSnowflakeGeneratorOptions() Options {
+	return Options{
+		NodeId: 0,
+		Epoch:  DefaultEpoch,
+	}
+}
+
+// SnowflakeGeneratorOption are functional options for the [SnowflakeGeneratorFactory] implementations.
+type SnowflakeGeneratorOption func(o *Options)
+
+// WithNodeId is used to specify the node id (between 0 and [MaxNodeId]) encoded in the generated ids.
+func WithNodeId(n int64) SnowflakeGeneratorOption {
+	return func(o *Options) {
+		o.NodeId = n
+	}
+}
+
+// WithEpoch is used to specify the custom epoch the generated ids timestamps are relative to.
+func WithEpoch(e time.Time) SnowflakeGeneratorOption {
+	return func(o *Options) {
+		o.Epoch = e
+	}
+}
@@ -0,0 +1,29 @@
+package snowflake_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/generate/snowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithNodeId(t *testing.T) {
+	t.Parallel()
+
+	opt := snowflake.DefaultSnowflakeGeneratorOptions()
+	snowflake.WithNodeId(12)(&opt)
+
+	assert.Equal(t, int64(12), opt.NodeId)
+}
+
+func TestWithEpoch(t *testing.T) {
+	t.Parallel()
+
+	epoch := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	opt := snowflake.DefaultSnowflakeGeneratorOptions()
+	snowflake.WithEpoch(epoch)(&opt)
+
+	assert.Equal(t, epoch, opt.Epoch)
+}
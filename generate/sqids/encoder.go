@@ -0,0 +1,90 @@
+package sqids
+
+import (
+	"errors"
+	"fmt"
+
+	sqidslib "github.com/sqids/sqids-go"
+)
+
+const (
+	// DefaultAlphabet is the default alphabet used to encode the ids.
+	DefaultAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// DefaultMinLength is the default minimum length of the encoded ids (no padding).
+	DefaultMinLength = 0
+	// MaxMinLength is the maximum value accepted for the minimum length of the encoded ids.
+	MaxMinLength = 255
+)
+
+// DefaultBlocklist returns the default list of words that must not appear in the encoded ids, extended with the provided words.
+func DefaultBlocklist(words ...string) []string {
+	return sqidslib.Blocklist(words...)
+}
+
+// SqidsEncoder is the interface for Sqids encoders, turning numbers into short public ids and back.
+type SqidsEncoder interface {
+	Encode(numbers ...uint64) (string, error)
+	Decode(id string) ([]uint64, error)
+}
+
+// DefaultSqidsEncoder is the default [SqidsEncoder] implementation.
+type DefaultSqidsEncoder struct {
+	sqids *sqidslib.Sqids
+}
+
+// NewDefaultSqidsEncoder returns a [DefaultSqidsEncoder], implementing [SqidsEncoder].
+//
+// It returns an error if the alphabet is invalid (less than 3 unique single byte characters), or if the minimum length is out of range.
+func NewDefaultSqidsEncoder(alphabet string, minLength int, blocklist []string) (*DefaultSqidsEncoder, error) {
+	if minLength < 0 || minLength > MaxMinLength {
+		return nil, fmt.Errorf("invalid sqids min length %d, must be between 0 and %d", minLength, MaxMinLength)
+	}
+
+	if blocklist == nil {
+		blocklist = []string{}
+	}
+
+	sqids, err := sqidslib.New(sqidslib.Options{
+		Alphabet:  alphabet,
+		MinLength: uint8(minLength),
+		Blocklist: blocklist,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid sqids alphabet: %w", err)
+	}
+
+	return &DefaultSqidsEncoder{
+		sqids: sqids,
+	}, nil
+}
+
+// Encode returns the id encoding the provided numbers, using [Sqids].
+//
+// [Sqids]: https://github.com/sqids/sqids-go
+func (e *DefaultSqidsEncoder) Encode(numbers ...uint64) (string, error) {
+	if len(numbers) == 0 {
+		return "", errors.New("cannot encode sqids id without numbers")
+	}
+
+	return e.sqids.Encode(numbers)
+}
+
+// Decode returns the numbers encoded in the provided id, using [Sqids].
+//
+// It returns an error if the id cannot be decoded, or if it is not the canonical encoding of the decoded numbers:
+// this ensures a single id can be resolved for given numbers.
+//
+// [Sqids]: https://github.com/sqids/sqids-go
+func (e *DefaultSqidsEncoder) Decode(id string) ([]uint64, error) {
+	numbers := e.sqids.Decode(id)
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("invalid sqids id %q", id)
+	}
+
+	canonical, err := e.sqids.Encode(numbers)
+	if err != nil || canonical != id {
+		return nil, fmt.Errorf("invalid sqids id %q, not canonical", id)
+	}
+
+	return numbers, nil
+}
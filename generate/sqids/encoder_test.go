@@ -0,0 +1,178 @@
+package sqids_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/sqids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultSqidsEncoder(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoder(sqids.DefaultAlphabet, sqids.DefaultMinLength, sqids.DefaultBlocklist())
+	assert.NoError(t, err)
+
+	assert.IsType(t, &sqids.DefaultSqidsEncoder{}, encoder)
+	assert.Implements(t, (*sqids.SqidsEncoder)(nil), encoder)
+}
+
+func TestNewDefaultSqidsEncoderFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		alphabet  string
+		minLength int
+		expected  string
+	}{
+		{
+			name:      "too short alphabet",
+			alphabet:  "ab",
+			minLength: 0,
+			expected:  "invalid sqids alphabet: alphabet length must be at least 3",
+		},
+		{
+			name:      "duplicated alphabet characters",
+			alphabet:  "aabc",
+			minLength: 0,
+			expected:  "invalid sqids alphabet: alphabet must contain unique characters",
+		},
+		{
+			name:      "negative min length",
+			alphabet:  sqids.DefaultAlphabet,
+			minLength: -1,
+			expected:  "invalid sqids min length -1, must be between 0 and 255",
+		},
+		{
+			name:      "too big min length",
+			alphabet:  sqids.DefaultAlphabet,
+			minLength: 256,
+			expected:  "invalid sqids min length 256, must be between 0 and 255",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoder, err := sqids.NewDefaultSqidsEncoder(tt.alphabet, tt.minLength, nil)
+			assert.Error(t, err)
+			assert.Nil(t, encoder)
+			assert.Equal(t, tt.expected, err.Error())
+		})
+	}
+}
+
+func TestEncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoder(sqids.DefaultAlphabet, sqids.DefaultMinLength, sqids.DefaultBlocklist())
+	assert.NoError(t, err)
+
+	id, err := encoder.Encode(1, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "86Rf07", id)
+
+	numbers, err := encoder.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, numbers)
+}
+
+func TestEncodeWithMinLength(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoder(sqids.DefaultAlphabet, 10, nil)
+	assert.NoError(t, err)
+
+	id, err := encoder.Encode(1)
+	assert.NoError(t, err)
+	assert.Len(t, id, 10)
+
+	numbers, err := encoder.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, numbers)
+}
+
+func TestEncodeWithCustomAlphabet(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoder("abcdef", sqids.DefaultMinLength, nil)
+	assert.NoError(t, err)
+
+	id, err := encoder.Encode(123456789)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^[abcdef]+$", id)
+
+	numbers, err := encoder.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{123456789}, numbers)
+}
+
+func TestEncodeWithBlocklist(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoder(sqids.DefaultAlphabet, sqids.DefaultMinLength, nil)
+	assert.NoError(t, err)
+
+	id, err := encoder.Encode(1, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "86Rf07", id)
+
+	encoder, err = sqids.NewDefaultSqidsEncoder(sqids.DefaultAlphabet, sqids.DefaultMinLength, []string{"86Rf07"})
+	assert.NoError(t, err)
+
+	id, err = encoder.Encode(1, 2, 3)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "86Rf07", id)
+
+	numbers, err := encoder.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, numbers)
+}
+
+func TestEncodeFailure(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoder(sqids.DefaultAlphabet, sqids.DefaultMinLength, nil)
+	assert.NoError(t, err)
+
+	id, err := encoder.Encode()
+	assert.Error(t, err)
+	assert.Equal(t, "cannot encode sqids id without numbers", err.Error())
+	assert.Empty(t, id)
+}
+
+func TestDecodeFailure(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoder(sqids.DefaultAlphabet, 10, nil)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{
+			id:       "",
+			expected: `invalid sqids id ""`,
+		},
+		{
+			id:       "invalid-id",
+			expected: `invalid sqids id "invalid-id"`,
+		},
+		{
+			id:       "86Rf07",
+			expected: `invalid sqids id "86Rf07", not canonical`,
+		},
+	}
+
+	for _, tt := range tests {
+		numbers, err := encoder.Decode(tt.id)
+		assert.Error(t, err)
+		assert.Equal(t, tt.expected, err.Error())
+		assert.Nil(t, numbers)
+	}
+}
@@ -0,0 +1,40 @@
+package sqids
+
+// SqidsEncoderFactory is the interface for [SqidsEncoder] factories.
+type SqidsEncoderFactory interface {
+	Create(options ...SqidsEncoderOption) (SqidsEncoder, error)
+}
+
+// DefaultSqidsEncoderFactory is the default [SqidsEncoderFactory] implementation.
+type DefaultSqidsEncoderFactory struct{}
+
+// NewDefaultSqidsEncoderFactory returns a [DefaultSqidsEncoderFactory], implementing [SqidsEncoderFactory].
+func NewDefaultSqidsEncoderFactory() SqidsEncoderFactory {
+	return &DefaultSqidsEncoderFactory{}
+}
+
+// Create returns a new [SqidsEncoder], and accepts a list of [SqidsEncoderOption].
+// For example:
+//
+//	var encoder, _ = sqids.NewDefaultSqidsEncoderFactory().Create()
+//
+// is equivalent to:
+//
+//	var encoder, _ = sqids.NewDefaultSqidsEncoderFactory().Create(
+//		sqids.WithAlphabet(sqids.DefaultAlphabet),    // alphanumeric alphabet by default
+//		sqids.WithMinLength(sqids.DefaultMinLength),  // no padding by default
+//		sqids.WithBlocklist(sqids.DefaultBlocklist()), // default Sqids blocklist by default
+//	)
+func (f *DefaultSqidsEncoderFactory) Create(options ...SqidsEncoderOption) (SqidsEncoder, error) {
+	appliedOpts := DefaultSqidsEncoderOptions()
+	for _, applyOpt := range options {
+		applyOpt(&appliedOpts)
+	}
+
+	encoder, err := NewDefaultSqidsEncoder(appliedOpts.Alphabet, appliedOpts.MinLength, appliedOpts.Blocklist)
+	if err != nil {
+		return nil, err
+	}
+
+	return encoder, nil
+}
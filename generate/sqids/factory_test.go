@@ -0,0 +1,41 @@
+package sqids_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/sqids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultSqidsEncoderFactory(t *testing.T) {
+	t.Parallel()
+
+	factory := sqids.NewDefaultSqidsEncoderFactory()
+
+	assert.IsType(t, &sqids.DefaultSqidsEncoderFactory{}, factory)
+	assert.Implements(t, (*sqids.SqidsEncoderFactory)(nil), factory)
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoderFactory().Create(sqids.WithMinLength(8))
+	assert.NoError(t, err)
+
+	id, err := encoder.Encode(1)
+	assert.NoError(t, err)
+	assert.Len(t, id, 8)
+
+	numbers, err := encoder.Decode(id)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, numbers)
+}
+
+func TestCreateFailure(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoderFactory().Create(sqids.WithMinLength(-1))
+	assert.Error(t, err)
+	assert.Nil(t, encoder)
+	assert.Equal(t, "invalid sqids min length -1, must be between 0 and 255", err.Error())
+}
@@ -0,0 +1,41 @@
+package sqids
+
+// Options are options for the [SqidsEncoderFactory] implementations.
+type Options struct {
+	Alphabet  string
+	MinLength int
+	Blocklist []string
+}
+
+// DefaultSqidsEncoderOptions are the default options used in the [DefaultSqidsEncoderFactory].
+func DefaultSqidsEncoderOptions() Options {
+	return Options{
+		Alphabet:  DefaultAlphabet,
+		MinLength: DefaultMinLength,
+		Blocklist: DefaultBlocklist(),
+	}
+}
+
+// SqidsEncoderOption are functional options for the [SqidsEncoderFactory] implementations.
+type SqidsEncoderOption func(o *Options)
+
+// WithAlphabet is used to specify the alphabet used to encode the ids.
+func WithAlphabet(a string) SqidsEncoderOption {
+	return func(o *Options) {
+		o.Alphabet = a
+	}
+}
+
+// WithMinLength is used to specify the minimum length of the encoded ids.
+func WithMinLength(l int) SqidsEncoderOption {
+	return func(o *Options) {
+		o.MinLength = l
+	}
+}
+
+// WithBlocklist is used to specify the list of words that must not appear in the encoded ids (empty to disable).
+func WithBlocklist(b []string) SqidsEncoderOption {
+	return func(o *Options) {
+		o.Blocklist = b
+	}
+}
@@ -0,0 +1,35 @@
+package sqids_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/sqids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAlphabet(t *testing.T) {
+	t.Parallel()
+
+	opt := sqids.DefaultSqidsEncoderOptions()
+	sqids.WithAlphabet("abc")(&opt)
+
+	assert.Equal(t, "abc", opt.Alphabet)
+}
+
+func TestWithMinLength(t *testing.T) {
+	t.Parallel()
+
+	opt := sqids.DefaultSqidsEncoderOptions()
+	sqids.WithMinLength(10)(&opt)
+
+	assert.Equal(t, 10, opt.MinLength)
+}
+
+func TestWithBlocklist(t *testing.T) {
+	t.Parallel()
+
+	opt := sqids.DefaultSqidsEncoderOptions()
+	sqids.WithBlocklist([]string{"foo"})(&opt)
+
+	assert.Equal(t, []string{"foo"}, opt.Blocklist)
+}
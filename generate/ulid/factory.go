@@ -0,0 +1,19 @@
+package ulid
+
+// UlidGeneratorFactory is the interface for [UlidGenerator] factories.
+type UlidGeneratorFactory interface {
+	Create() UlidGenerator
+}
+
+// DefaultUlidGeneratorFactory is the default [UlidGeneratorFactory] implementation.
+type DefaultUlidGeneratorFactory struct{}
+
+// NewDefaultUlidGeneratorFactory returns a [DefaultUlidGeneratorFactory], implementing [UlidGeneratorFactory].
+func NewDefaultUlidGeneratorFactory() UlidGeneratorFactory {
+	return &DefaultUlidGeneratorFactory{}
+}
+
+// Create returns a new [UlidGenerator].
+func (g *DefaultUlidGeneratorFactory) Create() UlidGenerator {
+	return NewDefaultUlidGenerator()
+}
@@ -0,0 +1,41 @@
+package ulid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/ulid"
+	oklogulid "github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultUlidGeneratorFactory(t *testing.T) {
+	t.Parallel()
+
+	factory := ulid.NewDefaultUlidGeneratorFactory()
+
+	assert.IsType(t, &ulid.DefaultUlidGeneratorFactory{}, factory)
+	assert.Implements(t, (*ulid.UlidGeneratorFactory)(nil), factory)
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	generator := ulid.NewDefaultUlidGeneratorFactory().Create()
+
+	ulid1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	ulid2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, ulid1, ulid2)
+
+	parsedValue1, err := oklogulid.Parse(ulid1.String())
+	assert.NoError(t, err)
+
+	parsedValue2, err := oklogulid.Parse(ulid2.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, ulid1.String(), parsedValue1.String())
+	assert.Equal(t, ulid2.String(), parsedValue2.String())
+}
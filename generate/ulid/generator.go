@@ -0,0 +1,23 @@
+package ulid
+
+import oklogulid "github.com/oklog/ulid/v2"
+
+// UlidGenerator is the interface for ULID generators.
+type UlidGenerator interface {
+	Generate() (oklogulid.ULID, error)
+}
+
+// DefaultUlidGenerator is the default [UlidGenerator] implementation.
+type DefaultUlidGenerator struct{}
+
+// NewDefaultUlidGenerator returns a [DefaultUlidGenerator], implementing [UlidGenerator].
+func NewDefaultUlidGenerator() *DefaultUlidGenerator {
+	return &DefaultUlidGenerator{}
+}
+
+// Generate returns a new ULID, using [OKLog ULID] with a monotonic entropy source.
+//
+// [OKLog ULID]: https://github.com/oklog/ulid
+func (g *DefaultUlidGenerator) Generate() (oklogulid.ULID, error) {
+	return oklogulid.New(oklogulid.Now(), oklogulid.DefaultEntropy())
+}
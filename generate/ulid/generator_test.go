@@ -0,0 +1,44 @@
+package ulid_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/ulid"
+	oklogulid "github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefaultUlidGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := ulid.NewDefaultUlidGenerator()
+
+	assert.IsType(t, &ulid.DefaultUlidGenerator{}, generator)
+	assert.Implements(t, (*ulid.UlidGenerator)(nil), generator)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	generator := ulid.NewDefaultUlidGenerator()
+
+	ulid1, err := generator.Generate()
+	assert.NoError(t, err)
+
+	ulid2, err := generator.Generate()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, ulid1.String(), ulid2.String())
+
+	// monotonic: generated values are lexicographically sortable
+	assert.Less(t, ulid1.String(), ulid2.String())
+
+	parsedUlid1, err := oklogulid.Parse(ulid1.String())
+	assert.NoError(t, err)
+
+	parsedUlid2, err := oklogulid.Parse(ulid2.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, ulid1.String(), parsedUlid1.String())
+	assert.Equal(t, ulid2.String(), parsedUlid2.String())
+}
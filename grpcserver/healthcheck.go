@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ankorstore/yokai/healthcheck"
 	"google.golang.org/grpc/codes"
@@ -11,36 +13,89 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// DefaultHealthCheckWatchInterval is the default interval between the checker runs on Watch streams.
+const DefaultHealthCheckWatchInterval = 5 * time.Second
+
 // GrpcHealthCheckService is a default gRPC health check server implementation working with the [healthcheck.Checker].
 type GrpcHealthCheckService struct {
 	grpc_health_v1.UnimplementedHealthServer
-	checker *healthcheck.Checker
+	checker      *healthcheck.Checker
+	options      GrpcHealthCheckServiceOptions
+	mutex        sync.RWMutex
+	shutdown     bool
+	services     map[string]struct{}
+	statuses     map[string]grpc_health_v1.HealthCheckResponse_ServingStatus
+	watchers     map[string]map[chan struct{}]struct{}
+	watchResults map[healthcheck.ProbeKind]grpc_health_v1.HealthCheckResponse_ServingStatus
+	watchCancel  context.CancelFunc
 }
 
 // NewGrpcHealthCheckService returns a new [GrpcHealthCheckService] instance.
-func NewGrpcHealthCheckService(checker *healthcheck.Checker) *GrpcHealthCheckService {
+func NewGrpcHealthCheckService(checker *healthcheck.Checker, options ...GrpcHealthCheckServiceOption) *GrpcHealthCheckService {
+	appliedOptions := DefaultGrpcHealthCheckServiceOptions()
+	for _, opt := range options {
+		opt(&appliedOptions)
+	}
+
 	return &GrpcHealthCheckService{
-		checker: checker,
+		checker:      checker,
+		options:      appliedOptions,
+		services:     map[string]struct{}{},
+		statuses:     map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{},
+		watchers:     map[string]map[chan struct{}]struct{}{},
+		watchResults: map[healthcheck.ProbeKind]grpc_health_v1.HealthCheckResponse_ServingStatus{},
+	}
+}
+
+// SetServingStatus sets the serving status of a given gRPC service name, and notifies its watchers.
+// A NOT_SERVING status takes precedence over the probes checks, while a SERVING status restores them.
+// The service becomes known for the Watch RPC, which reports SERVICE_UNKNOWN for unknown services.
+func (s *GrpcHealthCheckService) SetServingStatus(service string, servingStatus grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.services[service] = struct{}{}
+
+	if servingStatus == grpc_health_v1.HealthCheckResponse_SERVING {
+		delete(s.statuses, service)
+	} else {
+		s.statuses[service] = servingStatus
+	}
+
+	for watcher := range s.watchers[service] {
+		notify(watcher)
 	}
 }
 
+// Shutdown sets all gRPC services as NOT_SERVING, and notifies all watchers.
+func (s *GrpcHealthCheckService) Shutdown() {
+	s.setShutdown(true)
+}
+
+// Resume restores the serving status of all gRPC services after a [GrpcHealthCheckService.Shutdown], and notifies all watchers.
+func (s *GrpcHealthCheckService) Resume() {
+	s.setShutdown(false)
+}
+
 // Check performs checks on the registered [healthcheck.CheckerProbe].
 func (s *GrpcHealthCheckService) Check(ctx context.Context, in *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
 	logger := CtxLogger(ctx)
 
 	serviceName := strings.ToLower(in.Service)
 
-	var kind healthcheck.ProbeKind
-	switch {
-	case strings.Contains(serviceName, healthcheck.Liveness.String()):
-		kind = healthcheck.Liveness
-	case strings.Contains(serviceName, healthcheck.Readiness.String()):
-		kind = healthcheck.Readiness
-	default:
-		kind = healthcheck.Startup
+	servingStatus, kind, result := s.evaluate(ctx, in.Service)
+	if result == nil {
+		logger.
+			Warn().
+			Str("caller", serviceName).
+			Str("status", servingStatus.String()).
+			Msg("grpc health check not serving")
+
+		return &grpc_health_v1.HealthCheckResponse{
+			Status: servingStatus,
+		}, nil
 	}
 
-	result := s.checker.Check(ctx, kind)
 	if !result.Success {
 		evt := logger.Error()
 		evt.
@@ -58,23 +113,291 @@ func (s *GrpcHealthCheckService) Check(ctx context.Context, in *grpc_health_v1.H
 		}, nil
 	}
 
-	logger.
-		Info().
-		Str("kind", kind.String()).
-		Str("caller", serviceName).
-		Msg("grpc health check success")
+	if result.Status == healthcheck.Degraded {
+		evt := logger.Warn()
+		evt.
+			Str("kind", kind.String()).
+			Str("caller", serviceName)
+
+		for probeName, probeResult := range result.ProbesResults {
+			evt.Str(probeName, fmt.Sprintf("success: %v, message: %s", probeResult.Success, probeResult.Message))
+		}
+
+		evt.Msg("grpc health check degraded")
+	} else {
+		logger.
+			Info().
+			Str("kind", kind.String()).
+			Str("caller", serviceName).
+			Msg("grpc health check success")
+	}
 
 	return &grpc_health_v1.HealthCheckResponse{
 		Status: grpc_health_v1.HealthCheckResponse_SERVING,
 	}, nil
 }
 
-// Watch is not implemented.
+// Watch streams the serving status transitions of a gRPC service, evaluated on each serving status change, and on
+// each run of the checker shared by all Watch streams, every watch interval.
+//
+// The SERVICE_UNKNOWN status is sent for unknown services, until they become known.
 func (s *GrpcHealthCheckService) Watch(in *grpc_health_v1.HealthCheckRequest, watchServer grpc_health_v1.Health_WatchServer) error {
-	CtxLogger(watchServer.Context()).
-		Warn().
-		Str("caller", in.Service).
-		Msg("grpc health watch not implemented")
+	ctx := watchServer.Context()
+	logger := CtxLogger(ctx)
+
+	serviceName := strings.ToLower(in.Service)
+
+	watcher := s.subscribe(in.Service)
+	defer s.unsubscribe(in.Service, watcher)
+
+	sent := false
+	lastServingStatus := grpc_health_v1.HealthCheckResponse_UNKNOWN
+
+	for {
+		servingStatus := s.watchStatus(ctx, in.Service)
+
+		if !sent || servingStatus != lastServingStatus {
+			err := watchServer.Send(&grpc_health_v1.HealthCheckResponse{
+				Status: servingStatus,
+			})
+			if err != nil {
+				logger.Error().Err(err).Str("caller", serviceName).Msg("grpc health watch send failure")
+
+				return status.Error(codes.Canceled, "watch stream send failure")
+			}
+
+			logger.
+				Info().
+				Str("caller", serviceName).
+				Str("status", servingStatus.String()).
+				Msg("grpc health watch status change")
+
+			sent = true
+			lastServingStatus = servingStatus
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, "watch stream has ended")
+		case <-watcher:
+		}
+	}
+}
+
+func (s *GrpcHealthCheckService) watchStatus(ctx context.Context, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	kind := s.kind(service)
+
+	s.mutex.RLock()
+	known := s.known(service)
+	servingStatus, overridden := s.override(service)
+	result, cached := s.watchResults[kind]
+	s.mutex.RUnlock()
+
+	switch {
+	case !known:
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	case overridden:
+		return servingStatus
+	case cached:
+		return result
+	}
+
+	result = s.checkServingStatus(ctx, kind)
+
+	s.mutex.Lock()
+	if s.watchCancel != nil {
+		s.watchResults[kind] = result
+	}
+	s.mutex.Unlock()
+
+	return result
+}
+
+func (s *GrpcHealthCheckService) runWatchLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.options.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		kinds := map[healthcheck.ProbeKind]struct{}{}
+
+		s.mutex.RLock()
+		for service := range s.watchers {
+			if s.known(service) {
+				kinds[s.kind(service)] = struct{}{}
+			}
+		}
+		s.mutex.RUnlock()
+
+		results := map[healthcheck.ProbeKind]grpc_health_v1.HealthCheckResponse_ServingStatus{}
+		for kind := range kinds {
+			results[kind] = s.checkServingStatus(ctx, kind)
+		}
+
+		s.mutex.Lock()
+		if ctx.Err() == nil {
+			for kind, result := range results {
+				s.watchResults[kind] = result
+			}
+
+			for _, watchers := range s.watchers {
+				for watcher := range watchers {
+					notify(watcher)
+				}
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
+
+func (s *GrpcHealthCheckService) evaluate(
+	ctx context.Context,
+	service string,
+) (grpc_health_v1.HealthCheckResponse_ServingStatus, healthcheck.ProbeKind, *healthcheck.CheckerResult) {
+	kind := s.kind(service)
+
+	s.mutex.RLock()
+	servingStatus, overridden := s.override(service)
+	s.mutex.RUnlock()
+
+	if overridden {
+		return servingStatus, kind, nil
+	}
+
+	result := s.checker.Check(ctx, kind)
+	if !result.Success {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, kind, result
+	}
+
+	return grpc_health_v1.HealthCheckResponse_SERVING, kind, result
+}
+
+func (s *GrpcHealthCheckService) checkServingStatus(ctx context.Context, kind healthcheck.ProbeKind) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if s.checker.Check(ctx, kind).Success {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
+
+// override must be called with the mutex held.
+func (s *GrpcHealthCheckService) override(service string) (grpc_health_v1.HealthCheckResponse_ServingStatus, bool) {
+	if s.shutdown {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING, true
+	}
+
+	servingStatus, overridden := s.statuses[service]
+
+	return servingStatus, overridden
+}
+
+// known must be called with the mutex held: the server itself (empty name), the services named after a probe kind,
+// configured with a probe kind, or given a serving status, are known.
+func (s *GrpcHealthCheckService) known(service string) bool {
+	if service == "" {
+		return true
+	}
+
+	if _, ok := s.services[service]; ok {
+		return true
+	}
+
+	serviceName := strings.ToLower(service)
+
+	if _, ok := s.options.ServicesKinds[serviceName]; ok {
+		return true
+	}
+
+	for _, kind := range []healthcheck.ProbeKind{healthcheck.Startup, healthcheck.Liveness, healthcheck.Readiness} {
+		if strings.Contains(serviceName, kind.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *GrpcHealthCheckService) kind(service string) healthcheck.ProbeKind {
+	serviceName := strings.ToLower(service)
+
+	if kind, ok := s.options.ServicesKinds[serviceName]; ok {
+		return kind
+	}
 
-	return status.Error(codes.Unimplemented, "watch is not implemented")
+	switch {
+	case strings.Contains(serviceName, healthcheck.Liveness.String()):
+		return healthcheck.Liveness
+	case strings.Contains(serviceName, healthcheck.Readiness.String()):
+		return healthcheck.Readiness
+	default:
+		return healthcheck.Startup
+	}
+}
+
+func (s *GrpcHealthCheckService) setShutdown(shutdown bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.shutdown = shutdown
+
+	for _, watchers := range s.watchers {
+		for watcher := range watchers {
+			notify(watcher)
+		}
+	}
+}
+
+func (s *GrpcHealthCheckService) subscribe(service string) chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	watcher := make(chan struct{}, 1)
+
+	// first watcher: start the checker loop shared by all watchers
+	if len(s.watchers) == 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		s.watchCancel = cancel
+
+		go s.runWatchLoop(ctx)
+	}
+
+	if _, ok := s.watchers[service]; !ok {
+		s.watchers[service] = map[chan struct{}]struct{}{}
+	}
+
+	s.watchers[service][watcher] = struct{}{}
+
+	return watcher
+}
+
+func (s *GrpcHealthCheckService) unsubscribe(service string, watcher chan struct{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.watchers[service], watcher)
+
+	if len(s.watchers[service]) == 0 {
+		delete(s.watchers, service)
+	}
+
+	// last watcher: stop the shared checker loop, and forget its results
+	if len(s.watchers) == 0 && s.watchCancel != nil {
+		s.watchCancel()
+
+		s.watchCancel = nil
+		s.watchResults = map[healthcheck.ProbeKind]grpc_health_v1.HealthCheckResponse_ServingStatus{}
+	}
+}
+
+func notify(watcher chan struct{}) {
+	select {
+	case watcher <- struct{}{}:
+	default:
+	}
 }
@@ -3,6 +3,7 @@ package grpcserver_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/generate/generatetest/uuid"
 	"github.com/ankorstore/yokai/grpcserver"
@@ -32,7 +33,7 @@ func TestCheckSuccess(t *testing.T) {
 	assert.NoError(t, err)
 
 	// startup call assertions
-	client, closer := prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
+	client, _, closer := prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
 
 	response, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test::startup"})
 	assert.NoError(t, err)
@@ -48,7 +49,7 @@ func TestCheckSuccess(t *testing.T) {
 	closer()
 
 	// liveness call assertions
-	client, closer = prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
+	client, _, closer = prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
 
 	response, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test::liveness"})
 	assert.NoError(t, err)
@@ -64,7 +65,7 @@ func TestCheckSuccess(t *testing.T) {
 	closer()
 
 	// readiness call assertions
-	client, closer = prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
+	client, _, closer = prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
 
 	response, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test::readiness"})
 	assert.NoError(t, err)
@@ -97,7 +98,7 @@ func TestCheckFailure(t *testing.T) {
 	assert.NoError(t, err)
 
 	// client
-	client, closer := prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
+	client, _, closer := prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
 	defer closer()
 
 	// call assertions
@@ -116,12 +117,48 @@ func TestCheckFailure(t *testing.T) {
 	})
 }
 
-func TestWatch(t *testing.T) {
+func TestCheckDegraded(t *testing.T) {
+	// checker
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+		healthcheck.WithProbeWithOptions(probes.NewFailureProbe(), healthcheck.WithProbeCritical(false)),
+	)
+	assert.NoError(t, err)
+
+	// logger
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	// client
+	client, _, closer := prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
+	defer closer()
+
+	// call assertions
+	response, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	// logs assertions
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":        "warn",
+		"kind":         "startup",
+		"caller":       "test",
+		"successProbe": "success: true, message: some success",
+		"failureProbe": "success: false, message: some failure",
+		"message":      "grpc health check degraded",
+	})
+}
+
+func TestCheckWithServiceProbeKind(t *testing.T) {
 	t.Parallel()
 
 	// checker
 	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
 		healthcheck.WithProbe(probes.NewSuccessProbe()),
+		healthcheck.WithProbe(probes.NewFailureProbe(), healthcheck.Readiness),
 	)
 	assert.NoError(t, err)
 
@@ -133,26 +170,333 @@ func TestWatch(t *testing.T) {
 	assert.NoError(t, err)
 
 	// client
-	client, closer := prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
+	client, _, closer := prepareHealthCheckServiceGrpcServerAndClient(
+		t,
+		checker,
+		logger,
+		grpcserver.WithServiceProbeKind("foo.v1.FooService", healthcheck.Readiness),
+	)
 	defer closer()
 
 	// call assertions
-	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test"})
+	response, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "foo.v1.FooService"})
 	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
 
-	_, err = stream.Recv()
-	assert.Error(t, err)
-	assert.Equal(t, "rpc error: code = Unimplemented desc = watch is not implemented", err.Error())
+	response, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "bar.v1.BarService"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
 
 	// logs assertions
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "error",
+		"kind":    "readiness",
+		"caller":  "foo.v1.fooservice",
+		"message": "grpc health check failure",
+	})
+}
+
+func TestCheckWithServingStatus(t *testing.T) {
+	t.Parallel()
+
+	// checker
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+	)
+	assert.NoError(t, err)
+
+	// logger
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	// client
+	client, service, closer := prepareHealthCheckServiceGrpcServerAndClient(t, checker, logger)
+	defer closer()
+
+	// per service status
+	service.SetServingStatus("foo.v1.FooService", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	response, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "foo.v1.FooService"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+
+	response, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "bar.v1.BarService"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
 	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
 		"level":   "warn",
-		"caller":  "test",
-		"message": "grpc health watch not implemented",
+		"caller":  "foo.v1.fooservice",
+		"status":  "NOT_SERVING",
+		"message": "grpc health check not serving",
+	})
+
+	service.SetServingStatus("foo.v1.FooService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	response, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "foo.v1.FooService"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	// shutdown
+	service.Shutdown()
+
+	response, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "bar.v1.BarService"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+
+	service.Resume()
+
+	response, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "bar.v1.BarService"})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	// checker
+	switchProbe := probes.NewSwitchProbe(true)
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(switchProbe),
+	)
+	assert.NoError(t, err)
+
+	// logger
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	// client
+	client, _, closer := prepareHealthCheckServiceGrpcServerAndClient(
+		t,
+		checker,
+		logger,
+		grpcserver.WithWatchInterval(10*time.Millisecond),
+	)
+	defer closer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test::readiness"})
+	assert.NoError(t, err)
+
+	// initial status
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	// status transitions on checker runs
+	switchProbe.Switch(false)
+
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+
+	switchProbe.Switch(true)
+
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	// logs assertions
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "info",
+		"caller":  "test::readiness",
+		"status":  "NOT_SERVING",
+		"message": "grpc health watch status change",
 	})
 }
 
-func prepareHealthCheckServiceGrpcServerAndClient(t *testing.T, checker *healthcheck.Checker, logger *log.Logger) (grpc_health_v1.HealthClient, func()) {
+func TestWatchWithServingStatus(t *testing.T) {
+	t.Parallel()
+
+	// checker
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+	)
+	assert.NoError(t, err)
+
+	// logger
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logtest.NewDefaultTestLogBuffer()),
+	)
+	assert.NoError(t, err)
+
+	// client, with watch interval long enough to only rely on pushed changes
+	client, service, closer := prepareHealthCheckServiceGrpcServerAndClient(
+		t,
+		checker,
+		logger,
+		grpcserver.WithWatchInterval(time.Hour),
+	)
+	defer closer()
+
+	// known services
+	service.SetServingStatus("foo.v1.FooService", grpc_health_v1.HealthCheckResponse_SERVING)
+	service.SetServingStatus("bar.v1.BarService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fooStream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "foo.v1.FooService"})
+	assert.NoError(t, err)
+
+	barStream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "bar.v1.BarService"})
+	assert.NoError(t, err)
+
+	// initial statuses
+	response, err := fooStream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	response, err = barStream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	// pushed per service status
+	service.SetServingStatus("foo.v1.FooService", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	response, err = fooStream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+
+	// pushed shutdown
+	service.Shutdown()
+
+	response, err = barStream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+
+	// pushed resume
+	service.Resume()
+	service.SetServingStatus("foo.v1.FooService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	response, err = fooStream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+	response, err = barStream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+}
+
+func TestWatchWithUnknownService(t *testing.T) {
+	t.Parallel()
+
+	// checker
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+	)
+	assert.NoError(t, err)
+
+	// logger
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logtest.NewDefaultTestLogBuffer()),
+	)
+	assert.NoError(t, err)
+
+	// client
+	client, service, closer := prepareHealthCheckServiceGrpcServerAndClient(
+		t,
+		checker,
+		logger,
+		grpcserver.WithWatchInterval(time.Hour),
+	)
+	defer closer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "foo.v1.FooService"})
+	assert.NoError(t, err)
+
+	// unknown service
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN, response.Status)
+
+	// service becoming known
+	service.SetServingStatus("foo.v1.FooService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	response, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+}
+
+func TestWatchSharesCheckerRuns(t *testing.T) {
+	t.Parallel()
+
+	// checker
+	switchProbe := probes.NewSwitchProbe(true)
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(switchProbe),
+	)
+	assert.NoError(t, err)
+
+	// logger
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logtest.NewDefaultTestLogBuffer()),
+	)
+	assert.NoError(t, err)
+
+	// client
+	client, _, closer := prepareHealthCheckServiceGrpcServerAndClient(
+		t,
+		checker,
+		logger,
+		grpcserver.WithWatchInterval(20*time.Millisecond),
+	)
+	defer closer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var streams []grpc_health_v1.Health_WatchClient
+
+	for i := 0; i < 5; i++ {
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test::readiness"})
+		assert.NoError(t, err)
+
+		response, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+
+		streams = append(streams, stream)
+	}
+
+	// all streams are notified from the shared checker runs
+	start := time.Now()
+	count := switchProbe.Count()
+
+	switchProbe.Switch(false)
+
+	for _, stream := range streams {
+		response, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+	}
+
+	// a single checker run per interval, whatever the number of streams
+	time.Sleep(100 * time.Millisecond)
+
+	maxRuns := int64(time.Since(start)/(20*time.Millisecond)) + 1
+	assert.LessOrEqual(t, switchProbe.Count()-count, maxRuns)
+}
+
+func prepareHealthCheckServiceGrpcServerAndClient(
+	t *testing.T,
+	checker *healthcheck.Checker,
+	logger *log.Logger,
+	options ...grpcserver.GrpcHealthCheckServiceOption,
+) (grpc_health_v1.HealthClient, *grpcserver.GrpcHealthCheckService, func()) {
 	t.Helper()
 
 	// bufconn listener preparation
@@ -166,10 +510,9 @@ func prepareHealthCheckServiceGrpcServerAndClient(t *testing.T, checker *healthc
 		grpc.StreamInterceptor(loggerInterceptor.StreamInterceptor()),
 	)
 
-	server.RegisterService(
-		&grpc_health_v1.Health_ServiceDesc,
-		grpcserver.NewGrpcHealthCheckService(checker),
-	)
+	service := grpcserver.NewGrpcHealthCheckService(checker, options...)
+
+	server.RegisterService(&grpc_health_v1.Health_ServiceDesc, service)
 
 	go func() {
 		//nolint:errcheck
@@ -192,5 +535,5 @@ func prepareHealthCheckServiceGrpcServerAndClient(t *testing.T, checker *healthc
 		server.Stop()
 	}
 
-	return client, closer
+	return client, service, closer
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/generate/uuid"
 	"github.com/ankorstore/yokai/log"
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
@@ -22,6 +23,7 @@ const (
 // GrpcLoggerInterceptor is a gRPC unary and stream server interceptor to produce correlated logs.
 type GrpcLoggerInterceptor struct {
 	generator  uuid.UuidGenerator
+	validator  correlation.CorrelationIdValidator
 	logger     *log.Logger
 	metadata   map[string]string
 	exclusions []string
@@ -46,6 +48,13 @@ func (i *GrpcLoggerInterceptor) Metadata(metadata map[string]string) *GrpcLogger
 	return i
 }
 
+// Validator configures a validator for the incoming request ids, invalid ones are replaced by generated ones.
+func (i *GrpcLoggerInterceptor) Validator(validator correlation.CorrelationIdValidator) *GrpcLoggerInterceptor {
+	i.validator = validator
+
+	return i
+}
+
 // Exclude configures a list of method names to exclude from logging.
 func (i *GrpcLoggerInterceptor) Exclude(methods ...string) *GrpcLoggerInterceptor {
 	i.exclusions = append(i.exclusions, methods...)
@@ -288,7 +297,7 @@ func (i *GrpcLoggerInterceptor) extractLogFieldsFromContextMetadata(ctx context.
 
 	md := make(map[string]interface{})
 	for mk, mv := range i.metadata {
-		if val, ok := ctxMd[mk]; ok && len(val) > 0 {
+		if val, ok := ctxMd[mk]; ok && len(val) > 0 && (mk != HeaderXRequestId || i.isValidRequestId(val[0])) {
 			md[mv] = val[0]
 		} else if mk == HeaderXRequestId {
 			md[mv] = i.generator.Generate()
@@ -297,3 +306,11 @@ func (i *GrpcLoggerInterceptor) extractLogFieldsFromContextMetadata(ctx context.
 
 	return md
 }
+
+func (i *GrpcLoggerInterceptor) isValidRequestId(requestId string) bool {
+	if i.validator == nil {
+		return true
+	}
+
+	return i.validator.Validate(requestId) == nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/generate/generatetest/uuid"
 	"github.com/ankorstore/yokai/grpcserver"
 	"github.com/ankorstore/yokai/grpcserver/grpcservertest"
@@ -100,6 +101,66 @@ func TestUnarySuccess(t *testing.T) {
 	})
 }
 
+func TestUnaryWithRequestIdValidation(t *testing.T) {
+	t.Parallel()
+
+	validator, err := correlation.NewDefaultCorrelationIdValidator(36, "^[a-f0-9-]+$")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		requestId         string
+		expectedRequestId string
+	}{
+		{"valid request id", testRequestId, testRequestId},
+		{"too long request id", testRequestId + "-too-long", "test"},
+		{"invalid format request id", "invalid request id", "test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// logger
+			logBuffer := logtest.NewDefaultTestLogBuffer()
+			logger, err := log.NewDefaultLoggerFactory().Create(
+				log.WithLevel(zerolog.DebugLevel),
+				log.WithOutputWriter(logBuffer),
+			)
+			assert.NoError(t, err)
+
+			// client
+			client, closer := prepareTestServiceGrpcServerAndClient(
+				t,
+				logger,
+				[]string{},
+				map[string]string{},
+				true,
+				validator,
+			)
+			defer closer()
+
+			// call assertions
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", tt.requestId)
+
+			response, err := client.Unary(ctx, &proto.Request{
+				ShouldFail: false,
+				Message:    "test",
+			})
+			assert.NoError(t, err)
+
+			assert.True(t, response.Success)
+
+			// logs assertions
+			logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+				"level":     "info",
+				"message":   "grpc call success",
+				"requestID": tt.expectedRequestId,
+			})
+		})
+	}
+}
+
 func TestUnaryFailure(t *testing.T) {
 	t.Parallel()
 
@@ -749,7 +810,7 @@ func TestBidiWithExclusionAndError(t *testing.T) {
 	})
 }
 
-func prepareTestServiceGrpcServerAndClient(t *testing.T, logger *log.Logger, exclusions []string, metadata map[string]string, withTestInterceptors bool) (proto.ServiceClient, func()) {
+func prepareTestServiceGrpcServerAndClient(t *testing.T, logger *log.Logger, exclusions []string, metadata map[string]string, withTestInterceptors bool, validators ...correlation.CorrelationIdValidator) (proto.ServiceClient, func()) {
 	t.Helper()
 
 	// bufconn listener preparation
@@ -758,6 +819,10 @@ func prepareTestServiceGrpcServerAndClient(t *testing.T, logger *log.Logger, exc
 	// gRPC server preparation
 	loggerInterceptor := grpcserver.NewGrpcLoggerInterceptor(uuid.NewTestUuidGenerator("test"), logger)
 
+	for _, validator := range validators {
+		loggerInterceptor.Validator(validator)
+	}
+
 	if len(exclusions) != 0 {
 		loggerInterceptor.Exclude(exclusions...)
 	}
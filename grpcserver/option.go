@@ -1,6 +1,10 @@
 package grpcserver
 
 import (
+	"strings"
+	"time"
+
+	"github.com/ankorstore/yokai/healthcheck"
 	"google.golang.org/grpc"
 )
 
@@ -34,3 +38,36 @@ func WithReflection(r bool) GrpcServerOption {
 		o.Reflection = r
 	}
 }
+
+// GrpcHealthCheckServiceOptions are options for the [GrpcHealthCheckService].
+type GrpcHealthCheckServiceOptions struct {
+	WatchInterval time.Duration
+	ServicesKinds map[string]healthcheck.ProbeKind
+}
+
+// DefaultGrpcHealthCheckServiceOptions are the default options used in the [GrpcHealthCheckService].
+func DefaultGrpcHealthCheckServiceOptions() GrpcHealthCheckServiceOptions {
+	return GrpcHealthCheckServiceOptions{
+		WatchInterval: DefaultHealthCheckWatchInterval,
+		ServicesKinds: map[string]healthcheck.ProbeKind{},
+	}
+}
+
+// GrpcHealthCheckServiceOption are functional options for the [GrpcHealthCheckService].
+type GrpcHealthCheckServiceOption func(o *GrpcHealthCheckServiceOptions)
+
+// WithWatchInterval is used to configure the interval between the checker runs on Watch streams.
+func WithWatchInterval(d time.Duration) GrpcHealthCheckServiceOption {
+	return func(o *GrpcHealthCheckServiceOptions) {
+		if d > 0 {
+			o.WatchInterval = d
+		}
+	}
+}
+
+// WithServiceProbeKind is used to configure the [healthcheck.ProbeKind] to check for a given gRPC service name (case-insensitive).
+func WithServiceProbeKind(service string, kind healthcheck.ProbeKind) GrpcHealthCheckServiceOption {
+	return func(o *GrpcHealthCheckServiceOptions) {
+		o.ServicesKinds[strings.ToLower(service)] = kind
+	}
+}
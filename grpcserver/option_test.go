@@ -2,8 +2,10 @@ package grpcserver_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/grpcserver"
+	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 )
@@ -25,3 +27,25 @@ func TestWithReflection(t *testing.T) {
 
 	assert.True(t, opt.Reflection)
 }
+
+func TestWithWatchInterval(t *testing.T) {
+	t.Parallel()
+
+	opt := grpcserver.DefaultGrpcHealthCheckServiceOptions()
+	assert.Equal(t, grpcserver.DefaultHealthCheckWatchInterval, opt.WatchInterval)
+
+	grpcserver.WithWatchInterval(time.Second)(&opt)
+	assert.Equal(t, time.Second, opt.WatchInterval)
+
+	grpcserver.WithWatchInterval(0)(&opt)
+	assert.Equal(t, time.Second, opt.WatchInterval)
+}
+
+func TestWithServiceProbeKind(t *testing.T) {
+	t.Parallel()
+
+	opt := grpcserver.DefaultGrpcHealthCheckServiceOptions()
+	grpcserver.WithServiceProbeKind("foo.v1.FooService", healthcheck.Readiness)(&opt)
+
+	assert.Equal(t, map[string]healthcheck.ProbeKind{"foo.v1.fooservice": healthcheck.Readiness}, opt.ServicesKinds)
+}
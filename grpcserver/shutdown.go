@@ -0,0 +1,109 @@
+package grpcserver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// ShutdownParticipant handles the graceful shutdown of a [grpc.Server], and tracks its in-flight calls.
+type ShutdownParticipant struct {
+	name        string
+	mutex       sync.Mutex
+	server      *grpc.Server
+	healthCheck *GrpcHealthCheckService
+	inFlight    atomic.Int64
+	once        sync.Once
+	err         error
+}
+
+// NewShutdownParticipant returns a new [ShutdownParticipant] instance.
+func NewShutdownParticipant(name string) *ShutdownParticipant {
+	return &ShutdownParticipant{
+		name: name,
+	}
+}
+
+// Name returns the name of the [ShutdownParticipant].
+func (p *ShutdownParticipant) Name() string {
+	return p.name
+}
+
+// Bind binds a [grpc.Server], and its optional [GrpcHealthCheckService], to the [ShutdownParticipant].
+func (p *ShutdownParticipant) Bind(server *grpc.Server, healthCheck *GrpcHealthCheckService) *ShutdownParticipant {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.server = server
+	p.healthCheck = healthCheck
+
+	return p
+}
+
+// UnaryInterceptor returns a [grpc.UnaryServerInterceptor] tracking the in-flight unary calls.
+func (p *ShutdownParticipant) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		p.inFlight.Add(1)
+		defer p.inFlight.Add(-1)
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns a [grpc.StreamServerInterceptor] tracking the in-flight streams.
+func (p *ShutdownParticipant) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		p.inFlight.Add(1)
+		defer p.inFlight.Add(-1)
+
+		return handler(srv, ss)
+	}
+}
+
+// InFlight returns the number of in-flight calls.
+func (p *ShutdownParticipant) InFlight() int64 {
+	return p.inFlight.Load()
+}
+
+// Drain sets all gRPC services of the bound [GrpcHealthCheckService] as NOT_SERVING.
+func (p *ShutdownParticipant) Drain() {
+	p.mutex.Lock()
+	healthCheck := p.healthCheck
+	p.mutex.Unlock()
+
+	if healthCheck != nil {
+		healthCheck.Shutdown()
+	}
+}
+
+// Shutdown gracefully stops the bound gRPC server, and forces its stop if the context ends before completion.
+// Only the first call performs the shutdown, next calls return its result.
+func (p *ShutdownParticipant) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		p.mutex.Lock()
+		server := p.server
+		p.mutex.Unlock()
+
+		if server == nil {
+			return
+		}
+
+		stopped := make(chan struct{})
+
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			server.Stop()
+			p.err = ctx.Err()
+		}
+	})
+
+	return p.err
+}
@@ -0,0 +1,123 @@
+package grpcserver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/grpcserver"
+	"github.com/ankorstore/yokai/grpcserver/grpcservertest"
+	"github.com/ankorstore/yokai/grpcserver/testdata/probes"
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestShutdownParticipantWithoutServer(t *testing.T) {
+	t.Parallel()
+
+	participant := grpcserver.NewShutdownParticipant("test")
+
+	participant.Drain()
+
+	assert.Equal(t, "test", participant.Name())
+	assert.Equal(t, int64(0), participant.InFlight())
+	assert.NoError(t, participant.Shutdown(context.Background()))
+}
+
+func TestShutdownParticipantWithServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("graceful shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		client, participant := prepareShutdownParticipantGrpcServerAndClient(t)
+
+		response, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test"})
+		assert.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+		assert.Equal(t, int64(0), participant.InFlight())
+
+		assert.NoError(t, participant.Shutdown(context.Background()))
+
+		_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "test"})
+		assert.Error(t, err)
+	})
+
+	t.Run("drain and forced shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		client, participant := prepareShutdownParticipantGrpcServerAndClient(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "test::readiness"})
+		assert.NoError(t, err)
+
+		response, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+		assert.Equal(t, int64(1), participant.InFlight())
+
+		participant.Drain()
+
+		response, err = stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.Status)
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer shutdownCancel()
+
+		assert.ErrorIs(t, participant.Shutdown(shutdownCtx), context.DeadlineExceeded)
+		assert.ErrorIs(t, participant.Shutdown(context.Background()), context.DeadlineExceeded)
+
+		assert.Eventually(t, func() bool {
+			return participant.InFlight() == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func prepareShutdownParticipantGrpcServerAndClient(t *testing.T) (grpc_health_v1.HealthClient, *grpcserver.ShutdownParticipant) {
+	t.Helper()
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+	)
+	assert.NoError(t, err)
+
+	lis := grpcservertest.NewBufconnListener(1024 * 1024)
+
+	participant := grpcserver.NewShutdownParticipant("test")
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(participant.UnaryInterceptor()),
+		grpc.StreamInterceptor(participant.StreamInterceptor()),
+	)
+
+	service := grpcserver.NewGrpcHealthCheckService(checker)
+
+	server.RegisterService(&grpc_health_v1.Health_ServiceDesc, service)
+
+	participant.Bind(server, service)
+
+	go func() {
+		//nolint:errcheck
+		server.Serve(lis)
+	}()
+
+	conn, err := grpcservertest.NewDefaultTestBufconnConnectionFactory(lis).Create(
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		//nolint:errcheck
+		conn.Close()
+		server.Stop()
+	})
+
+	return grpc_health_v1.NewHealthClient(conn), participant
+}
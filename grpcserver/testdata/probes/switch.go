@@ -0,0 +1,42 @@
+package probes
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+type SwitchProbe struct {
+	success atomic.Bool
+	count   atomic.Int64
+}
+
+func NewSwitchProbe(success bool) *SwitchProbe {
+	p := &SwitchProbe{}
+	p.success.Store(success)
+
+	return p
+}
+
+func (p *SwitchProbe) Name() string {
+	return "switchProbe"
+}
+
+func (p *SwitchProbe) Switch(success bool) {
+	p.success.Store(success)
+}
+
+func (p *SwitchProbe) Count() int64 {
+	return p.count.Load()
+}
+
+func (p *SwitchProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	p.count.Add(1)
+
+	if p.success.Load() {
+		return healthcheck.NewCheckerProbeResult(true, "some switched success")
+	}
+
+	return healthcheck.NewCheckerProbeResult(false, "some switched failure")
+}
@@ -1,27 +1,42 @@
 package healthcheck
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
 
 // CheckerResult is the result of a [Checker] check.
 // It contains a global status, and a list of [CheckerProbeResult] corresponding to each probe execution.
 type CheckerResult struct {
 	Success       bool                           `json:"success"`
+	Status        CheckerStatus                  `json:"status"`
 	ProbesResults map[string]*CheckerProbeResult `json:"probes"`
 }
 
 // CheckerProbeRegistration represents a registration of a [CheckerProbe] in the [Checker].
 type CheckerProbeRegistration struct {
-	probe CheckerProbe
-	kinds []ProbeKind
+	probe    CheckerProbe
+	kinds    []ProbeKind
+	timeout  time.Duration
+	critical bool
+	cached   bool
 }
 
 // NewCheckerProbeRegistration returns a [CheckerProbeRegistration], and accepts a [CheckerProbe] and an optional list of [ProbeKind].
 // If no [ProbeKind] is provided, the [CheckerProbe] will be registered to be executed on all kinds of checks.
 func NewCheckerProbeRegistration(probe CheckerProbe, kinds ...ProbeKind) *CheckerProbeRegistration {
-	return &CheckerProbeRegistration{
+	return NewCheckerProbeRegistrationWithOptions(probe, kindsOptions(kinds)...)
+}
+
+// NewCheckerProbeRegistrationWithOptions returns a [CheckerProbeRegistration], and accepts a [CheckerProbe] and an optional list of [CheckerProbeOption].
+func NewCheckerProbeRegistrationWithOptions(probe CheckerProbe, options ...CheckerProbeOption) *CheckerProbeRegistration {
+	registration := &CheckerProbeRegistration{
 		probe: probe,
-		kinds: kinds,
 	}
+
+	return registration.configure(options...)
 }
 
 // Probe returns the [CheckerProbe] of the [CheckerProbeRegistration].
@@ -34,6 +49,36 @@ func (r *CheckerProbeRegistration) Kinds() []ProbeKind {
 	return r.kinds
 }
 
+// Timeout returns the timeout of the [CheckerProbeRegistration] (zero if not defined).
+func (r *CheckerProbeRegistration) Timeout() time.Duration {
+	return r.timeout
+}
+
+// Critical returns true if the [CheckerProbeRegistration] is critical.
+func (r *CheckerProbeRegistration) Critical() bool {
+	return r.critical
+}
+
+// Cached returns true if the [CheckerProbeRegistration] results can be served from the background execution cache.
+func (r *CheckerProbeRegistration) Cached() bool {
+	return r.cached
+}
+
+// Options returns the list of [CheckerProbeOption] of the [CheckerProbeRegistration].
+func (r *CheckerProbeRegistration) Options() []CheckerProbeOption {
+	options := []CheckerProbeOption{
+		WithProbeTimeout(r.timeout),
+		WithProbeCritical(r.critical),
+		WithProbeCached(r.cached),
+	}
+
+	for _, kind := range r.kinds {
+		options = append(options, kind)
+	}
+
+	return options
+}
+
 // Match returns true if the [CheckerProbeRegistration] match any of the provided [ProbeKind] list.
 func (r *CheckerProbeRegistration) Match(kinds ...ProbeKind) bool {
 	for _, kind := range kinds {
@@ -47,9 +92,38 @@ func (r *CheckerProbeRegistration) Match(kinds ...ProbeKind) bool {
 	return false
 }
 
+func (r *CheckerProbeRegistration) configure(options ...CheckerProbeOption) *CheckerProbeRegistration {
+	appliedOpts := ResolveCheckerProbeOptions(options...)
+
+	r.kinds = appliedOpts.Kinds
+	r.timeout = appliedOpts.Timeout
+	r.critical = appliedOpts.Critical
+	r.cached = appliedOpts.Cached
+
+	return r
+}
+
 // Checker provides the possibility to register several [CheckerProbe] and execute them.
 type Checker struct {
-	registrations map[string]*CheckerProbeRegistration
+	registrations       map[string]*CheckerProbeRegistration
+	observers           []CheckerObserver
+	defaultProbeTimeout time.Duration
+	background          *checkerBackground
+}
+
+type checkerBackground struct {
+	interval time.Duration
+	maxAge   time.Duration
+	mutex    sync.RWMutex
+	cache    map[string]*cachedCheckerProbeResult
+	runMutex sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+type cachedCheckerProbeResult struct {
+	result    *CheckerProbeResult
+	checkedAt time.Time
 }
 
 // NewChecker returns a [Checker] instance.
@@ -64,14 +138,8 @@ func NewChecker() *Checker {
 func (c *Checker) Probes(kinds ...ProbeKind) []CheckerProbe {
 	var probes []CheckerProbe
 
-	if len(kinds) == 0 {
-		kinds = []ProbeKind{Startup, Liveness, Readiness}
-	}
-
-	for _, registration := range c.registrations {
-		if registration.Match(kinds...) {
-			probes = append(probes, registration.probe)
-		}
+	for _, registration := range c.registrationsMatching(kinds...) {
+		probes = append(probes, registration.probe)
 	}
 
 	return probes
@@ -80,36 +148,309 @@ func (c *Checker) Probes(kinds ...ProbeKind) []CheckerProbe {
 // RegisterProbe registers a [CheckerProbe] for an optional list of [ProbeKind].
 // If no [ProbeKind] is provided, the [CheckerProbe] will be registered for all kinds.
 func (c *Checker) RegisterProbe(probe CheckerProbe, kinds ...ProbeKind) *Checker {
-	if len(kinds) == 0 {
-		kinds = []ProbeKind{Startup, Liveness, Readiness}
-	}
+	return c.RegisterProbeWithOptions(probe, kindsOptions(kinds)...)
+}
+
+// RegisterProbeWithOptions registers a [CheckerProbe] with an optional list of [CheckerProbeOption].
+// If no [ProbeKind] is provided, the [CheckerProbe] will be registered for all kinds.
+func (c *Checker) RegisterProbeWithOptions(probe CheckerProbe, options ...CheckerProbeOption) *Checker {
+	options = withAllKindsIfNone(options)
 
 	if _, ok := c.registrations[probe.Name()]; ok {
-		c.registrations[probe.Name()].kinds = kinds
+		c.registrations[probe.Name()].configure(options...)
 	} else {
-		c.registrations[probe.Name()] = NewCheckerProbeRegistration(probe, kinds...)
+		c.registrations[probe.Name()] = NewCheckerProbeRegistrationWithOptions(probe, options...)
+	}
+
+	return c
+}
+
+// RegisterObserver registers a [CheckerObserver], notified of each probe execution.
+func (c *Checker) RegisterObserver(observer CheckerObserver) *Checker {
+	c.observers = append(c.observers, observer)
+
+	return c
+}
+
+// SetDefaultProbeTimeout sets the timeout applied to the probes executions, when they don't define their own.
+// A zero timeout means no timeout.
+func (c *Checker) SetDefaultProbeTimeout(timeout time.Duration) *Checker {
+	c.defaultProbeTimeout = timeout
+
+	return c
+}
+
+// SetBackgroundExecution enables the background execution of the probes, every given interval, once the [Checker] is started.
+//
+// Checks will then be served from the cached probes results, as long as they are not older than the given max age
+// (twice the interval if zero): stale or missing probes results are executed synchronously.
+// A zero interval disables the background execution.
+func (c *Checker) SetBackgroundExecution(interval time.Duration, maxAge time.Duration) *Checker {
+	if interval <= 0 {
+		c.background = nil
+
+		return c
+	}
+
+	if maxAge <= 0 {
+		maxAge = 2 * interval
+	}
+
+	c.background = &checkerBackground{
+		interval: interval,
+		maxAge:   maxAge,
+		cache:    map[string]*cachedCheckerProbeResult{},
+	}
+
+	return c
+}
+
+// Start starts the background execution of the probes, if enabled.
+func (c *Checker) Start() *Checker {
+	if c.background == nil {
+		return c
+	}
+
+	c.background.runMutex.Lock()
+	defer c.background.runMutex.Unlock()
+
+	if c.background.cancel != nil {
+		return c
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	c.background.cancel = cancel
+	c.background.done = done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(c.background.interval)
+		defer ticker.Stop()
+
+		for {
+			c.executeProbes(ctx, c.cachedRegistrations(c.registrationsMatching()))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return c
+}
+
+// Stop stops the background execution of the probes, if started.
+func (c *Checker) Stop() *Checker {
+	if c.background == nil {
+		return c
 	}
 
+	c.background.runMutex.Lock()
+	defer c.background.runMutex.Unlock()
+
+	if c.background.cancel == nil {
+		return c
+	}
+
+	c.background.cancel()
+	<-c.background.done
+
+	c.background.cancel = nil
+	c.background.done = nil
+
 	return c
 }
 
-// Check executes all the registered probes for a [ProbeKind], passes a [context.Context] to each of them, and returns a [CheckerResult].
-// The [CheckerResult] is successful if all probes executed with success.
+// Check executes concurrently all the registered probes for a [ProbeKind], passes a [context.Context] to each of them, and returns a [CheckerResult].
+// The [CheckerResult] is successful if all critical probes executed with success: if only non-critical probes failed, its status is [Degraded].
+//
+// If the background execution is enabled, the fresh enough cached probes results are used instead of executing the probes,
+// except for the probes registered with [WithProbeCached] false.
 func (c *Checker) Check(ctx context.Context, kind ProbeKind) *CheckerResult {
+	registrations := c.registrationsMatching(kind)
+
 	probeResults := map[string]*CheckerProbeResult{}
 
-	success := true
-	for name, registration := range c.registrations {
-		if registration.Match(kind) {
-			pr := registration.probe.Check(ctx)
+	if c.background != nil {
+		var staleRegistrations []*CheckerProbeRegistration
 
-			success = success && pr.Success
-			probeResults[name] = pr
+		c.background.mutex.RLock()
+		for _, registration := range registrations {
+			cached, ok := c.background.cache[registration.probe.Name()]
+			if ok && registration.cached && time.Since(cached.checkedAt) <= c.background.maxAge {
+				result := *cached.result
+				probeResults[registration.probe.Name()] = &result
+			} else {
+				staleRegistrations = append(staleRegistrations, registration)
+			}
+		}
+		c.background.mutex.RUnlock()
+
+		registrations = staleRegistrations
+	}
+
+	for name, pr := range c.executeProbes(ctx, registrations) {
+		probeResults[name] = pr
+	}
+
+	success := true
+	status := Healthy
+	for _, pr := range probeResults {
+		if !pr.Success {
+			if pr.Critical {
+				success = false
+				status = Unhealthy
+			} else if status == Healthy {
+				status = Degraded
+			}
 		}
 	}
 
 	return &CheckerResult{
 		Success:       success,
+		Status:        status,
 		ProbesResults: probeResults,
 	}
 }
+
+func (c *Checker) registrationsMatching(kinds ...ProbeKind) []*CheckerProbeRegistration {
+	if len(kinds) == 0 {
+		kinds = []ProbeKind{Startup, Liveness, Readiness}
+	}
+
+	var registrations []*CheckerProbeRegistration
+
+	for _, registration := range c.registrations {
+		if registration.Match(kinds...) {
+			registrations = append(registrations, registration)
+		}
+	}
+
+	return registrations
+}
+
+func (c *Checker) cachedRegistrations(registrations []*CheckerProbeRegistration) []*CheckerProbeRegistration {
+	var cachedRegistrations []*CheckerProbeRegistration
+
+	for _, registration := range registrations {
+		if registration.cached {
+			cachedRegistrations = append(cachedRegistrations, registration)
+		}
+	}
+
+	return cachedRegistrations
+}
+
+func (c *Checker) executeProbes(ctx context.Context, registrations []*CheckerProbeRegistration) map[string]*CheckerProbeResult {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	probeResults := map[string]*CheckerProbeResult{}
+
+	for _, registration := range registrations {
+		wg.Add(1)
+
+		go func(registration *CheckerProbeRegistration) {
+			defer wg.Done()
+
+			pr := c.executeProbe(ctx, registration)
+
+			mutex.Lock()
+			probeResults[registration.probe.Name()] = pr
+			mutex.Unlock()
+		}(registration)
+	}
+
+	wg.Wait()
+
+	if c.background != nil {
+		checkedAt := time.Now()
+
+		c.background.mutex.Lock()
+		for name, pr := range probeResults {
+			if !c.registrations[name].cached {
+				continue
+			}
+
+			result := *pr
+			c.background.cache[name] = &cachedCheckerProbeResult{
+				result:    &result,
+				checkedAt: checkedAt,
+			}
+		}
+		c.background.mutex.Unlock()
+	}
+
+	for name, pr := range probeResults {
+		for _, observer := range c.observers {
+			observer.ObserveProbeResult(name, pr)
+		}
+	}
+
+	return probeResults
+}
+
+func (c *Checker) executeProbe(ctx context.Context, registration *CheckerProbeRegistration) *CheckerProbeResult {
+	timeout := registration.timeout
+	if timeout <= 0 {
+		timeout = c.defaultProbeTimeout
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	// buffered, to not leak the probe goroutine if the result is not consumed anymore
+	resultChan := make(chan *CheckerProbeResult, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resultChan <- &CheckerProbeResult{
+					Success: false,
+					Message: "probe panic",
+					Error:   fmt.Sprintf("%v", r),
+				}
+			}
+		}()
+
+		pr := registration.probe.Check(ctx)
+		if pr == nil {
+			pr = NewCheckerProbeResult(false, "probe returned no result")
+		}
+
+		resultChan <- pr
+	}()
+
+	var result CheckerProbeResult
+
+	select {
+	case pr := <-resultChan:
+		// copy, to not alter results that may be shared by the probe
+		result = *pr
+	case <-ctx.Done():
+		result = CheckerProbeResult{
+			Success: false,
+			Message: "probe execution interrupted",
+			Error:   ctx.Err().Error(),
+		}
+	}
+
+	result.Critical = registration.critical
+	result.Duration = time.Since(start)
+
+	if !result.Success && result.Error == "" && ctx.Err() != nil {
+		result.Error = ctx.Err().Error()
+	}
+
+	return &result
+}
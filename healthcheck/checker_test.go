@@ -3,9 +3,12 @@ package healthcheck_test
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/healthcheck/testdata/observer"
 	"github.com/ankorstore/yokai/healthcheck/testdata/probes"
 	"github.com/stretchr/testify/assert"
 )
@@ -21,6 +24,34 @@ func TestNewCheckerProbeRegistration(t *testing.T) {
 
 	assert.Equal(t, successProbe, registration.Probe())
 	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Startup, healthcheck.Liveness}, registration.Kinds())
+	assert.Equal(t, time.Duration(0), registration.Timeout())
+	assert.True(t, registration.Critical())
+}
+
+func TestNewCheckerProbeRegistrationWithOptions(t *testing.T) {
+	t.Parallel()
+
+	successProbe := probes.NewSuccessProbe()
+
+	registration := healthcheck.NewCheckerProbeRegistrationWithOptions(
+		successProbe,
+		healthcheck.Readiness,
+		healthcheck.WithProbeTimeout(time.Second),
+		healthcheck.WithProbeCritical(false),
+		healthcheck.WithProbeCached(false),
+	)
+
+	assert.Equal(t, successProbe, registration.Probe())
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Readiness}, registration.Kinds())
+	assert.Equal(t, time.Second, registration.Timeout())
+	assert.False(t, registration.Critical())
+	assert.False(t, registration.Cached())
+
+	options := healthcheck.ResolveCheckerProbeOptions(registration.Options()...)
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Readiness}, options.Kinds)
+	assert.Equal(t, time.Second, options.Timeout)
+	assert.False(t, options.Critical)
+	assert.False(t, options.Cached)
 }
 
 func TestNewChecker(t *testing.T) {
@@ -84,8 +115,8 @@ func TestCheckerCheck(t *testing.T) {
 
 	data, err := json.Marshal(result)
 	assert.Nil(t, err)
-	assert.Equal(t,
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"some failure"},"successProbe":{"success":true,"message":"some success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		string(data),
 	)
 
@@ -94,8 +125,8 @@ func TestCheckerCheck(t *testing.T) {
 
 	data, err = json.Marshal(result)
 	assert.Nil(t, err)
-	assert.Equal(t,
-		`{"success":true,"probes":{"successProbe":{"success":true,"message":"some success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":true,"status":"healthy","probes":\{"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		string(data),
 	)
 
@@ -104,8 +135,8 @@ func TestCheckerCheck(t *testing.T) {
 
 	data, err = json.Marshal(result)
 	assert.Nil(t, err)
-	assert.Equal(t,
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"some failure"}}}`,
+	assert.Regexp(t,
+		`^\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		string(data),
 	)
 
@@ -116,8 +147,290 @@ func TestCheckerCheck(t *testing.T) {
 
 	data, err = json.Marshal(result)
 	assert.Nil(t, err)
-	assert.Equal(t,
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"some failure"},"successProbe":{"success":true,"message":"some success"}}}`,
+	assert.Regexp(t,
+		`^\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
+		string(data),
+	)
+}
+
+func TestCheckerCheckWithNonCriticalProbe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	checker := healthcheck.NewChecker()
+
+	checker.RegisterProbe(probes.NewSuccessProbe())
+	checker.RegisterProbeWithOptions(probes.NewFailureProbe(), healthcheck.Readiness, healthcheck.WithProbeCritical(false))
+
+	result := checker.Check(ctx, healthcheck.Liveness)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Healthy, result.Status)
+
+	result = checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Degraded, result.Status)
+
+	data, err := json.Marshal(result)
+	assert.Nil(t, err)
+	assert.Regexp(t,
+		`^\{"success":true,"status":"degraded","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":false,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}$`,
 		string(data),
 	)
 }
+
+func TestCheckerCheckRunsProbesConcurrently(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	checker := healthcheck.NewChecker()
+
+	checker.RegisterProbe(probes.NewSlowProbe("slowProbe1", 100*time.Millisecond))
+	checker.RegisterProbe(probes.NewSlowProbe("slowProbe2", 100*time.Millisecond))
+	checker.RegisterProbe(probes.NewSlowProbe("slowProbe3", 100*time.Millisecond))
+
+	start := time.Now()
+	result := checker.Check(ctx, healthcheck.Readiness)
+	duration := time.Since(start)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Healthy, result.Status)
+	assert.Len(t, result.ProbesResults, 3)
+	assert.Less(t, duration, 300*time.Millisecond)
+
+	for _, probeResult := range result.ProbesResults {
+		assert.GreaterOrEqual(t, probeResult.Duration, 100*time.Millisecond)
+		assert.Empty(t, probeResult.Error)
+	}
+}
+
+func TestCheckerCheckWithProbeTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	checker := healthcheck.NewChecker()
+
+	checker.RegisterProbe(probes.NewSuccessProbe())
+	checker.RegisterProbeWithOptions(probes.NewSlowProbe("slowProbe", time.Second), healthcheck.WithProbeTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	result := checker.Check(ctx, healthcheck.Readiness)
+	duration := time.Since(start)
+
+	assert.False(t, result.Success)
+	assert.Equal(t, healthcheck.Unhealthy, result.Status)
+	assert.Less(t, duration, time.Second)
+
+	assert.True(t, result.ProbesResults["successProbe"].Success)
+
+	slowProbeResult := result.ProbesResults["slowProbe"]
+	assert.False(t, slowProbeResult.Success)
+	assert.True(t, slowProbeResult.Critical)
+	assert.Equal(t, "probe execution interrupted", slowProbeResult.Message)
+	assert.Equal(t, "context deadline exceeded", slowProbeResult.Error)
+	assert.GreaterOrEqual(t, slowProbeResult.Duration, 50*time.Millisecond)
+}
+
+func TestCheckerCheckWithDefaultProbeTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	checker := healthcheck.NewChecker().SetDefaultProbeTimeout(50 * time.Millisecond)
+
+	checker.RegisterProbeWithOptions(probes.NewSlowProbe("slowProbe", time.Second), healthcheck.WithProbeCritical(false))
+
+	result := checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Degraded, result.Status)
+
+	slowProbeResult := result.ProbesResults["slowProbe"]
+	assert.False(t, slowProbeResult.Success)
+	assert.False(t, slowProbeResult.Critical)
+	assert.Equal(t, "context deadline exceeded", slowProbeResult.Error)
+}
+
+func TestCheckerCheckWithPanickingProbe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	checker := healthcheck.NewChecker()
+
+	checker.RegisterProbe(probes.NewPanicProbe())
+
+	result := checker.Check(ctx, healthcheck.Readiness)
+	assert.False(t, result.Success)
+	assert.Equal(t, healthcheck.Unhealthy, result.Status)
+
+	panicProbeResult := result.ProbesResults["panicProbe"]
+	assert.False(t, panicProbeResult.Success)
+	assert.Equal(t, "probe panic", panicProbeResult.Message)
+	assert.Equal(t, "some panic", panicProbeResult.Error)
+}
+
+func TestCheckerCheckWithObserver(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testObserver := observer.NewTestCheckerObserver()
+
+	checker := healthcheck.NewChecker().RegisterObserver(testObserver)
+
+	checker.RegisterProbe(probes.NewSuccessProbe())
+	checker.RegisterProbe(probes.NewFailureProbe(), healthcheck.Readiness)
+
+	checker.Check(ctx, healthcheck.Liveness)
+	checker.Check(ctx, healthcheck.Readiness)
+
+	assert.Len(t, testObserver.Observations("successProbe"), 2)
+	assert.True(t, testObserver.Observations("successProbe")[0].Success)
+	assert.Len(t, testObserver.Observations("failureProbe"), 1)
+	assert.False(t, testObserver.Observations("failureProbe")[0].Success)
+}
+
+func TestCheckerCheckWithCachedResults(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	countingProbe := probes.NewCountingProbe()
+
+	checker := healthcheck.NewChecker().SetBackgroundExecution(time.Hour, time.Hour)
+	checker.RegisterProbe(countingProbe)
+
+	// not started: missing result executed synchronously, then cached
+	result := checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.Equal(t, "some counted success", result.ProbesResults["countingProbe"].Message)
+	assert.Equal(t, int64(1), countingProbe.Count())
+
+	result = checker.Check(ctx, healthcheck.Liveness)
+	assert.True(t, result.Success)
+	assert.Equal(t, "some counted success", result.ProbesResults["countingProbe"].Message)
+	assert.Equal(t, int64(1), countingProbe.Count())
+}
+
+func TestCheckerCheckWithNonCachedProbe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	countingProbe := probes.NewCountingProbe()
+
+	checker := healthcheck.NewChecker().SetBackgroundExecution(10*time.Millisecond, time.Hour)
+	checker.RegisterProbeWithOptions(countingProbe, healthcheck.WithProbeCached(false))
+
+	// not executed in background
+	checker.Start()
+	time.Sleep(50 * time.Millisecond)
+	checker.Stop()
+
+	assert.Equal(t, int64(0), countingProbe.Count())
+
+	// executed on each check
+	checker.Check(ctx, healthcheck.Readiness)
+	checker.Check(ctx, healthcheck.Readiness)
+	assert.Equal(t, int64(2), countingProbe.Count())
+}
+
+func TestCheckerCheckWithStaleCachedResults(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	countingProbe := probes.NewCountingProbe()
+
+	checker := healthcheck.NewChecker().SetBackgroundExecution(time.Hour, time.Nanosecond)
+	checker.RegisterProbe(countingProbe)
+
+	checker.Check(ctx, healthcheck.Readiness)
+	assert.Equal(t, int64(1), countingProbe.Count())
+
+	time.Sleep(time.Millisecond)
+
+	checker.Check(ctx, healthcheck.Readiness)
+	assert.Equal(t, int64(2), countingProbe.Count())
+}
+
+func TestCheckerBackgroundExecution(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	countingProbe := probes.NewCountingProbe()
+
+	checker := healthcheck.NewChecker().SetBackgroundExecution(10*time.Millisecond, time.Hour)
+	checker.RegisterProbe(countingProbe)
+
+	checker.Start()
+
+	assert.Eventually(t, func() bool {
+		return countingProbe.Count() >= 3
+	}, time.Second, 5*time.Millisecond)
+
+	checker.Stop()
+
+	count := countingProbe.Count()
+
+	// served from cache, background execution stopped
+	result := checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, count, countingProbe.Count())
+}
+
+func TestCheckerBackgroundExecutionConcurrentStartStop(t *testing.T) {
+	t.Parallel()
+
+	checker := healthcheck.NewChecker().SetBackgroundExecution(time.Millisecond, time.Hour)
+	checker.RegisterProbe(probes.NewCountingProbe())
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			checker.Start()
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			checker.Stop()
+		}()
+	}
+
+	wg.Wait()
+
+	checker.Stop()
+}
+
+func TestCheckerBackgroundExecutionDisabled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	countingProbe := probes.NewCountingProbe()
+
+	checker := healthcheck.NewChecker().SetBackgroundExecution(0, time.Hour)
+	checker.RegisterProbe(countingProbe)
+
+	checker.Start()
+	defer checker.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(0), countingProbe.Count())
+
+	checker.Check(ctx, healthcheck.Readiness)
+	checker.Check(ctx, healthcheck.Readiness)
+	assert.Equal(t, int64(2), countingProbe.Count())
+}
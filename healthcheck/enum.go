@@ -1,5 +1,10 @@
 package healthcheck
 
+import (
+	"strconv"
+	"strings"
+)
+
 // ProbeKind is an enum for the supported kind of checks.
 type ProbeKind int
 
@@ -22,3 +27,48 @@ func (k ProbeKind) String() string {
 		return "startup"
 	}
 }
+
+// FetchProbeKind returns a [ProbeKind] for a given value.
+func FetchProbeKind(k string) ProbeKind {
+	switch strings.ToLower(k) {
+	case "liveness":
+		return Liveness
+	case "readiness":
+		return Readiness
+	default:
+		return Startup
+	}
+}
+
+// ApplyCheckerProbeOption implements [CheckerProbeOption], to register a probe for the [ProbeKind].
+func (k ProbeKind) ApplyCheckerProbeOption(o *CheckerProbeOptions) {
+	o.Kinds = append(o.Kinds, k)
+}
+
+// CheckerStatus is an enum for the possible statuses of a [CheckerResult].
+type CheckerStatus int
+
+const (
+	Healthy CheckerStatus = iota
+	Degraded
+	Unhealthy
+)
+
+// String returns a string representation of the [CheckerStatus].
+//
+//nolint:exhaustive
+func (s CheckerStatus) String() string {
+	switch s {
+	case Degraded:
+		return "degraded"
+	case Unhealthy:
+		return "unhealthy"
+	default:
+		return "healthy"
+	}
+}
+
+// MarshalJSON marshals the [CheckerStatus] as a JSON string.
+func (s CheckerStatus) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
@@ -1,6 +1,7 @@
 package healthcheck_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/ankorstore/yokai/healthcheck"
@@ -23,3 +24,42 @@ func TestProbeKindAsString(t *testing.T) {
 		assert.Equal(t, tt.expected, tt.kind.String())
 	}
 }
+
+func TestFetchProbeKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		kind     string
+		expected healthcheck.ProbeKind
+	}{
+		{"startup", healthcheck.Startup},
+		{"Liveness", healthcheck.Liveness},
+		{"READINESS", healthcheck.Readiness},
+		{"invalid", healthcheck.Startup},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, healthcheck.FetchProbeKind(tt.kind))
+	}
+}
+
+func TestCheckerStatusAsString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status   healthcheck.CheckerStatus
+		expected string
+	}{
+		{healthcheck.Healthy, "healthy"},
+		{healthcheck.Degraded, "degraded"},
+		{healthcheck.Unhealthy, "unhealthy"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.status.String())
+
+		data, err := json.Marshal(tt.status)
+		assert.NoError(t, err)
+		assert.Equal(t, `"`+tt.expected+`"`, string(data))
+	}
+}
@@ -19,6 +19,14 @@ func NewDefaultCheckerFactory() CheckerFactory {
 //	checker, _ := healthcheck.NewDefaultCheckerFactory().Create(
 //		healthcheck.WithProbe(NewSomeProbe()),                        // registers for startup, readiness and liveness
 //		healthcheck.WithProbe(NewOtherProbe(), healthcheck.Liveness), // registers for liveness  only
+//		healthcheck.WithProbeWithOptions(
+//			NewSlowProbe(),
+//			healthcheck.Readiness,                     // registers for readiness only
+//			healthcheck.WithProbeTimeout(time.Second), // with a 1 second execution timeout
+//			healthcheck.WithProbeCritical(false),      // reporting a degraded status instead of failing the check
+//		),
+//		healthcheck.WithDefaultProbeTimeout(5*time.Second),                 // timeout for the probes not defining their own
+//		healthcheck.WithBackgroundExecution(10*time.Second, 30*time.Second), // probes executed in background, results cached for 30s max
 //	)
 func (f *DefaultCheckerFactory) Create(options ...CheckerOption) (*Checker, error) {
 	appliedOpts := DefaultCheckerOptions()
@@ -26,10 +34,16 @@ func (f *DefaultCheckerFactory) Create(options ...CheckerOption) (*Checker, erro
 		applyOpt(&appliedOpts)
 	}
 
-	checker := NewChecker()
+	checker := NewChecker().
+		SetDefaultProbeTimeout(appliedOpts.DefaultProbeTimeout).
+		SetBackgroundExecution(appliedOpts.BackgroundInterval, appliedOpts.BackgroundMaxAge)
 
 	for _, registration := range appliedOpts.Registrations {
-		checker.RegisterProbe(registration.Probe(), registration.Kinds()...)
+		checker.RegisterProbeWithOptions(registration.Probe(), registration.Options()...)
+	}
+
+	for _, observer := range appliedOpts.Observers {
+		checker.RegisterObserver(observer)
 	}
 
 	return checker, nil
@@ -3,8 +3,10 @@ package healthcheck_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/healthcheck/testdata/observer"
 	"github.com/ankorstore/yokai/healthcheck/testdata/probes"
 	"github.com/stretchr/testify/assert"
 )
@@ -49,3 +51,60 @@ func TestCreate(t *testing.T) {
 	assert.True(t, checker.Check(ctx, healthcheck.Liveness).Success)
 	assert.False(t, checker.Check(ctx, healthcheck.Readiness).Success)
 }
+
+func TestCreateWithProbesOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+		healthcheck.WithProbeWithOptions(probes.NewFailureProbe(), healthcheck.Readiness, healthcheck.WithProbeCritical(false)),
+		healthcheck.WithProbe(probes.NewSlowProbe("slowProbe", time.Second), healthcheck.Liveness),
+		healthcheck.WithDefaultProbeTimeout(50*time.Millisecond),
+	)
+	assert.Nil(t, err)
+
+	result := checker.Check(ctx, healthcheck.Startup)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Healthy, result.Status)
+
+	result = checker.Check(ctx, healthcheck.Readiness)
+	assert.True(t, result.Success)
+	assert.Equal(t, healthcheck.Degraded, result.Status)
+
+	result = checker.Check(ctx, healthcheck.Liveness)
+	assert.False(t, result.Success)
+	assert.Equal(t, healthcheck.Unhealthy, result.Status)
+	assert.Equal(t, "context deadline exceeded", result.ProbesResults["slowProbe"].Error)
+}
+
+func TestCreateWithBackgroundExecutionAndObserver(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	countingProbe := probes.NewCountingProbe()
+	testObserver := observer.NewTestCheckerObserver()
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(countingProbe),
+		healthcheck.WithObserver(testObserver),
+		healthcheck.WithBackgroundExecution(time.Hour, 0),
+	)
+	assert.Nil(t, err)
+
+	checker.Start()
+	defer checker.Stop()
+
+	// observers are notified once the results are cached
+	assert.Eventually(t, func() bool {
+		return len(testObserver.Observations("countingProbe")) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	assert.True(t, checker.Check(ctx, healthcheck.Startup).Success)
+	assert.True(t, checker.Check(ctx, healthcheck.Readiness).Success)
+
+	assert.Equal(t, int64(1), countingProbe.Count())
+	assert.Len(t, testObserver.Observations("countingProbe"), 1)
+}
@@ -0,0 +1,6 @@
+package healthcheck
+
+// CheckerObserver is the interface for the observers of the [Checker] probes executions (for example to collect metrics).
+type CheckerObserver interface {
+	ObserveProbeResult(probeName string, result *CheckerProbeResult)
+}
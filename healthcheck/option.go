@@ -1,14 +1,24 @@
 package healthcheck
 
+import "time"
+
 // Options are options for the [CheckerFactory] implementations.
 type Options struct {
-	Registrations map[string]*CheckerProbeRegistration
+	Registrations       map[string]*CheckerProbeRegistration
+	Observers           []CheckerObserver
+	DefaultProbeTimeout time.Duration
+	BackgroundInterval  time.Duration
+	BackgroundMaxAge    time.Duration
 }
 
 // DefaultCheckerOptions are the default options used in the [DefaultCheckerFactory].
 func DefaultCheckerOptions() Options {
 	return Options{
-		Registrations: map[string]*CheckerProbeRegistration{},
+		Registrations:       map[string]*CheckerProbeRegistration{},
+		Observers:           []CheckerObserver{},
+		DefaultProbeTimeout: 0,
+		BackgroundInterval:  0,
+		BackgroundMaxAge:    0,
 	}
 }
 
@@ -18,15 +28,180 @@ type CheckerOption func(o *Options)
 // WithProbe is used to register a [CheckerProbe] for an optional list of [ProbeKind].
 // If no [ProbeKind] was provided, the [CheckerProbe] will be registered for all kinds.
 func WithProbe(probe CheckerProbe, kinds ...ProbeKind) CheckerOption {
-	return func(o *Options) {
-		if len(kinds) == 0 {
-			kinds = []ProbeKind{Startup, Liveness, Readiness}
-		}
+	return WithProbeWithOptions(probe, kindsOptions(kinds)...)
+}
 
+// WithProbeWithOptions is used to register a [CheckerProbe] with an optional list of [CheckerProbeOption].
+// If no [ProbeKind] was provided, the [CheckerProbe] will be registered for all kinds.
+func WithProbeWithOptions(probe CheckerProbe, options ...CheckerProbeOption) CheckerOption {
+	return func(o *Options) {
 		if _, ok := o.Registrations[probe.Name()]; ok {
-			o.Registrations[probe.Name()].kinds = kinds
+			o.Registrations[probe.Name()].configure(withAllKindsIfNone(options)...)
 		} else {
-			o.Registrations[probe.Name()] = NewCheckerProbeRegistration(probe, kinds...)
+			o.Registrations[probe.Name()] = NewCheckerProbeRegistrationWithOptions(probe, withAllKindsIfNone(options)...)
+		}
+	}
+}
+
+// WithDefaultProbeTimeout is used to specify the timeout applied to the probes executions, when they don't define their own.
+// A zero timeout (default) means no timeout.
+func WithDefaultProbeTimeout(timeout time.Duration) CheckerOption {
+	return func(o *Options) {
+		o.DefaultProbeTimeout = timeout
+	}
+}
+
+// WithObserver is used to register a [CheckerObserver], notified of each probe execution.
+func WithObserver(observer CheckerObserver) CheckerOption {
+	return func(o *Options) {
+		o.Observers = append(o.Observers, observer)
+	}
+}
+
+// WithBackgroundExecution is used to enable the background execution of the probes every given interval,
+// and to serve checks from cached probes results not older than the given max age (twice the interval if zero).
+// A zero interval (default) means no background execution.
+func WithBackgroundExecution(interval time.Duration, maxAge time.Duration) CheckerOption {
+	return func(o *Options) {
+		o.BackgroundInterval = interval
+		o.BackgroundMaxAge = maxAge
+	}
+}
+
+// CheckerProbeOptions are options for the [CheckerProbeRegistration].
+type CheckerProbeOptions struct {
+	Kinds    []ProbeKind
+	Timeout  time.Duration
+	Critical bool
+	Cached   bool
+}
+
+// DefaultCheckerProbeOptions are the default options used for a [CheckerProbeRegistration].
+func DefaultCheckerProbeOptions() CheckerProbeOptions {
+	return CheckerProbeOptions{
+		Kinds:    []ProbeKind{},
+		Timeout:  0,
+		Critical: true,
+		Cached:   true,
+	}
+}
+
+// CheckerProbeOption is the interface for [CheckerProbeRegistration] options.
+// It is implemented by [ProbeKind], to register a [CheckerProbe] for a given kind of checks.
+type CheckerProbeOption interface {
+	ApplyCheckerProbeOption(o *CheckerProbeOptions)
+}
+
+type checkerProbeOptionFunc func(o *CheckerProbeOptions)
+
+// ApplyCheckerProbeOption implements [CheckerProbeOption].
+func (f checkerProbeOptionFunc) ApplyCheckerProbeOption(o *CheckerProbeOptions) {
+	f(o)
+}
+
+// WithProbeTimeout is used to specify a timeout for the [CheckerProbe] executions.
+// A zero timeout (default) means the [Checker] default probe timeout will be used.
+func WithProbeTimeout(timeout time.Duration) CheckerProbeOption {
+	return checkerProbeOptionFunc(func(o *CheckerProbeOptions) {
+		o.Timeout = timeout
+	})
+}
+
+// WithProbeCritical is used to specify if a [CheckerProbe] is critical (default) or not.
+// A failing non-critical probe does not fail the check, but reports it as [Degraded].
+func WithProbeCritical(critical bool) CheckerProbeOption {
+	return checkerProbeOptionFunc(func(o *CheckerProbeOptions) {
+		o.Critical = critical
+	})
+}
+
+// WithProbeCached is used to specify if the [CheckerProbe] results can be served from the background execution cache (default).
+// A non-cached probe is executed on each check, which suits cheap probes that must reflect state changes immediately.
+func WithProbeCached(cached bool) CheckerProbeOption {
+	return checkerProbeOptionFunc(func(o *CheckerProbeOptions) {
+		o.Cached = cached
+	})
+}
+
+// ResolveCheckerProbeOptions returns the [CheckerProbeOptions] resulting from the application of a list of [CheckerProbeOption].
+func ResolveCheckerProbeOptions(options ...CheckerProbeOption) CheckerProbeOptions {
+	appliedOpts := DefaultCheckerProbeOptions()
+	for _, applyOpt := range options {
+		applyOpt.ApplyCheckerProbeOption(&appliedOpts)
+	}
+
+	return appliedOpts
+}
+
+func kindsOptions(kinds []ProbeKind) []CheckerProbeOption {
+	options := make([]CheckerProbeOption, 0, len(kinds))
+	for _, kind := range kinds {
+		options = append(options, kind)
+	}
+
+	return options
+}
+
+func withAllKindsIfNone(options []CheckerProbeOption) []CheckerProbeOption {
+	if len(ResolveCheckerProbeOptions(options...).Kinds) == 0 {
+		options = append(options, Startup, Liveness, Readiness)
+	}
+
+	return options
+}
+
+// WaitOptions are options for the [Checker.Wait] executions.
+type WaitOptions struct {
+	Timeout         time.Duration
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	AttemptHandler  func(attempt WaitAttempt)
+}
+
+// DefaultWaitOptions are the default options used in the [Checker.Wait] executions.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Timeout:         DefaultWaitTimeout,
+		InitialInterval: DefaultWaitInitialInterval,
+		MaxInterval:     DefaultWaitMaxInterval,
+		Multiplier:      DefaultWaitMultiplier,
+		AttemptHandler:  func(WaitAttempt) {},
+	}
+}
+
+// WaitOption are functional options for the [Checker.Wait] executions.
+type WaitOption func(o *WaitOptions)
+
+// WithWaitTimeout is used to specify the deadline of the [Checker.Wait] executions.
+// A zero timeout means waiting until the context ends.
+func WithWaitTimeout(timeout time.Duration) WaitOption {
+	return func(o *WaitOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// WithWaitBackoff is used to specify the exponential backoff between the [Checker.Wait] attempts:
+// the interval starts from the initial interval, and is multiplied after each attempt, up to the max interval.
+func WithWaitBackoff(initialInterval time.Duration, maxInterval time.Duration, multiplier float64) WaitOption {
+	return func(o *WaitOptions) {
+		if initialInterval > 0 {
+			o.InitialInterval = initialInterval
+		}
+
+		if maxInterval > 0 {
+			o.MaxInterval = maxInterval
+		}
+
+		if multiplier >= 1 {
+			o.Multiplier = multiplier
 		}
 	}
 }
+
+// WithWaitAttemptHandler is used to specify a function called after each [Checker.Wait] attempt.
+func WithWaitAttemptHandler(handler func(attempt WaitAttempt)) WaitOption {
+	return func(o *WaitOptions) {
+		o.AttemptHandler = handler
+	}
+}
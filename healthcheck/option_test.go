@@ -2,8 +2,10 @@ package healthcheck_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/healthcheck/testdata/observer"
 	"github.com/ankorstore/yokai/healthcheck/testdata/probes"
 	"github.com/stretchr/testify/assert"
 )
@@ -59,3 +61,111 @@ func TestWithAlreadyRegisteredProbe(t *testing.T) {
 		opt.Registrations[probe.Name()].Kinds(),
 	)
 }
+
+func TestWithProbeAndOptions(t *testing.T) {
+	t.Parallel()
+
+	probe := probes.NewSuccessProbe()
+
+	opt := healthcheck.DefaultCheckerOptions()
+	healthcheck.WithProbeWithOptions(probe, healthcheck.WithProbeTimeout(time.Second), healthcheck.WithProbeCritical(false))(&opt)
+
+	registration := opt.Registrations[probe.Name()]
+	assert.Equal(t, probe, registration.Probe())
+	assert.Equal(
+		t,
+		[]healthcheck.ProbeKind{
+			healthcheck.Startup,
+			healthcheck.Liveness,
+			healthcheck.Readiness,
+		},
+		registration.Kinds(),
+	)
+	assert.Equal(t, time.Second, registration.Timeout())
+	assert.False(t, registration.Critical())
+}
+
+func TestWithDefaultProbeTimeout(t *testing.T) {
+	t.Parallel()
+
+	opt := healthcheck.DefaultCheckerOptions()
+	healthcheck.WithDefaultProbeTimeout(time.Second)(&opt)
+
+	assert.Equal(t, time.Second, opt.DefaultProbeTimeout)
+}
+
+func TestResolveCheckerProbeOptions(t *testing.T) {
+	t.Parallel()
+
+	opt := healthcheck.ResolveCheckerProbeOptions()
+
+	assert.Equal(t, healthcheck.DefaultCheckerProbeOptions(), opt)
+	assert.Empty(t, opt.Kinds)
+	assert.Equal(t, time.Duration(0), opt.Timeout)
+	assert.True(t, opt.Critical)
+	assert.True(t, opt.Cached)
+
+	opt = healthcheck.ResolveCheckerProbeOptions(
+		healthcheck.Liveness,
+		healthcheck.Readiness,
+		healthcheck.WithProbeTimeout(time.Second),
+		healthcheck.WithProbeCritical(false),
+		healthcheck.WithProbeCached(false),
+	)
+
+	assert.Equal(t, []healthcheck.ProbeKind{healthcheck.Liveness, healthcheck.Readiness}, opt.Kinds)
+	assert.Equal(t, time.Second, opt.Timeout)
+	assert.False(t, opt.Critical)
+	assert.False(t, opt.Cached)
+}
+
+func TestWithObserver(t *testing.T) {
+	t.Parallel()
+
+	testObserver := observer.NewTestCheckerObserver()
+
+	opt := healthcheck.DefaultCheckerOptions()
+	healthcheck.WithObserver(testObserver)(&opt)
+
+	assert.Equal(t, []healthcheck.CheckerObserver{testObserver}, opt.Observers)
+}
+
+func TestWithBackgroundExecution(t *testing.T) {
+	t.Parallel()
+
+	opt := healthcheck.DefaultCheckerOptions()
+	healthcheck.WithBackgroundExecution(time.Second, time.Minute)(&opt)
+
+	assert.Equal(t, time.Second, opt.BackgroundInterval)
+	assert.Equal(t, time.Minute, opt.BackgroundMaxAge)
+}
+
+func TestWithWaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	opt := healthcheck.DefaultWaitOptions()
+	assert.Equal(t, healthcheck.DefaultWaitTimeout, opt.Timeout)
+
+	healthcheck.WithWaitTimeout(time.Minute)(&opt)
+
+	assert.Equal(t, time.Minute, opt.Timeout)
+}
+
+func TestWithWaitBackoff(t *testing.T) {
+	t.Parallel()
+
+	opt := healthcheck.DefaultWaitOptions()
+	healthcheck.WithWaitBackoff(time.Second, time.Minute, 3)(&opt)
+
+	assert.Equal(t, time.Second, opt.InitialInterval)
+	assert.Equal(t, time.Minute, opt.MaxInterval)
+	assert.Equal(t, 3.0, opt.Multiplier)
+
+	// invalid values are ignored
+	opt = healthcheck.DefaultWaitOptions()
+	healthcheck.WithWaitBackoff(0, -time.Second, 0.5)(&opt)
+
+	assert.Equal(t, healthcheck.DefaultWaitInitialInterval, opt.InitialInterval)
+	assert.Equal(t, healthcheck.DefaultWaitMaxInterval, opt.MaxInterval)
+	assert.Equal(t, healthcheck.DefaultWaitMultiplier, opt.Multiplier)
+}
@@ -2,6 +2,8 @@ package healthcheck
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 )
 
 // CheckerProbe is the interface for the probes executed by the [Checker].
@@ -11,9 +13,23 @@ type CheckerProbe interface {
 }
 
 // CheckerProbeResult is the result of a [CheckerProbe] execution.
+//
+// The Critical, Duration and Error fields are filled by the [Checker] when executing the probe.
+// The Duration is marshalled in JSON as milliseconds, in the duration_ms field.
 type CheckerProbeResult struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success  bool          `json:"success"`
+	Message  string        `json:"message"`
+	Critical bool          `json:"critical"`
+	Duration time.Duration `json:"-"`
+	Error    string        `json:"error,omitempty"`
+}
+
+type checkerProbeResultJSON struct {
+	Success    bool    `json:"success"`
+	Message    string  `json:"message"`
+	Critical   bool    `json:"critical"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
 }
 
 // NewCheckerProbeResult returns a [CheckerProbeResult], with a probe execution status and feedback message.
@@ -23,3 +39,30 @@ func NewCheckerProbeResult(success bool, message string) *CheckerProbeResult {
 		Message: message,
 	}
 }
+
+// MarshalJSON marshals the [CheckerProbeResult] as JSON, with its duration in milliseconds.
+func (r CheckerProbeResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(checkerProbeResultJSON{
+		Success:    r.Success,
+		Message:    r.Message,
+		Critical:   r.Critical,
+		DurationMs: float64(r.Duration) / float64(time.Millisecond),
+		Error:      r.Error,
+	})
+}
+
+// UnmarshalJSON unmarshals the [CheckerProbeResult] from JSON, with its duration in milliseconds.
+func (r *CheckerProbeResult) UnmarshalJSON(data []byte) error {
+	var res checkerProbeResultJSON
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+
+	r.Success = res.Success
+	r.Message = res.Message
+	r.Critical = res.Critical
+	r.Duration = time.Duration(res.DurationMs * float64(time.Millisecond))
+	r.Error = res.Error
+
+	return nil
+}
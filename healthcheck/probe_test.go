@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/stretchr/testify/assert"
@@ -49,5 +50,21 @@ func TestCheckerProbeResultAsJson(t *testing.T) {
 	data, err := json.Marshal(result)
 
 	assert.Nil(t, err)
-	assert.Equal(t, `{"success":true,"message":"success"}`, string(data))
+	assert.Equal(t, `{"success":true,"message":"success","critical":false,"duration_ms":0}`, string(data))
+
+	result.Critical = true
+	result.Duration = 1500 * time.Microsecond
+	result.Error = "some error"
+
+	data, err = json.Marshal(result)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"success":true,"message":"success","critical":true,"duration_ms":1.5,"error":"some error"}`, string(data))
+
+	var unmarshalled healthcheck.CheckerProbeResult
+
+	err = json.Unmarshal(data, &unmarshalled)
+
+	assert.Nil(t, err)
+	assert.Equal(t, *result, unmarshalled)
 }
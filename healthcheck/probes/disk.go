@@ -0,0 +1,78 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+// DefaultDiskProbeName is the name of the disk probe.
+const DefaultDiskProbeName = "disk"
+
+// DiskProbe is a probe checking the free space of the filesystem containing a path.
+type DiskProbe struct {
+	name           string
+	path           string
+	minFreeBytes   uint64
+	minFreePercent float64
+}
+
+// NewDiskProbe returns a new [DiskProbe], for a given path.
+func NewDiskProbe(path string) *DiskProbe {
+	return &DiskProbe{
+		name: DefaultDiskProbeName,
+		path: path,
+	}
+}
+
+// Name returns the name of the [DiskProbe].
+func (p *DiskProbe) Name() string {
+	return p.name
+}
+
+// SetName sets the name of the [DiskProbe].
+func (p *DiskProbe) SetName(name string) *DiskProbe {
+	p.name = name
+
+	return p
+}
+
+// SetMinFreeBytes sets the minimum free space in bytes of the [DiskProbe] (0 to disable).
+func (p *DiskProbe) SetMinFreeBytes(minFreeBytes uint64) *DiskProbe {
+	p.minFreeBytes = minFreeBytes
+
+	return p
+}
+
+// SetMinFreePercent sets the minimum free space in percent of the [DiskProbe] (0 to disable).
+func (p *DiskProbe) SetMinFreePercent(minFreePercent float64) *DiskProbe {
+	p.minFreePercent = minFreePercent
+
+	return p
+}
+
+// Check returns a successful [healthcheck.CheckerProbeResult] if the free space is above the thresholds.
+func (p *DiskProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	free, total, err := diskSpace(p.path)
+	if err != nil {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("disk space error: %v", err))
+	}
+
+	freePercent := 0.0
+	if total > 0 {
+		freePercent = float64(free) / float64(total) * 100
+	}
+
+	message := fmt.Sprintf("disk free space on %s: %d bytes (%.2f%%)", p.path, free, freePercent)
+
+	if p.minFreeBytes > 0 && free < p.minFreeBytes {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("%s, below %d bytes", message, p.minFreeBytes))
+	}
+
+	if p.minFreePercent > 0 && freePercent < p.minFreePercent {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("%s, below %.2f%%", message, p.minFreePercent))
+	}
+
+	return healthcheck.NewCheckerProbeResult(true, message)
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package probes
+
+import "errors"
+
+func diskSpace(path string) (uint64, uint64, error) {
+	return 0, 0, errors.New("disk space check is not supported on this platform")
+}
@@ -0,0 +1,46 @@
+package probes_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ankorstore/yokai/healthcheck/probes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskProbe(t *testing.T) {
+	t.Parallel()
+
+	probe := probes.NewDiskProbe(t.TempDir())
+	assert.Equal(t, probes.DefaultDiskProbeName, probe.Name())
+
+	probe.SetName("custom")
+	assert.Equal(t, "custom", probe.Name())
+}
+
+func TestDiskProbeCheck(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	// success
+	result := probes.NewDiskProbe(path).SetMinFreeBytes(1).Check(context.Background())
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "disk free space on "+path)
+
+	// bytes threshold failure
+	result = probes.NewDiskProbe(path).SetMinFreeBytes(math.MaxUint64).Check(context.Background())
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "below 18446744073709551615 bytes")
+
+	// percent threshold failure
+	result = probes.NewDiskProbe(path).SetMinFreePercent(100.1).Check(context.Background())
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "below 100.10%")
+
+	// invalid path failure
+	result = probes.NewDiskProbe(path + "/invalid").Check(context.Background())
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "disk space error:")
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package probes
+
+import "syscall"
+
+func diskSpace(path string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	//nolint:unconvert,gosec
+	return uint64(stat.Bavail) * uint64(stat.Bsize), uint64(stat.Blocks) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,65 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+// DefaultDNSProbeName is the name of the DNS probe.
+const DefaultDNSProbeName = "dns"
+
+// DNSProbe is a probe checking that a host can be resolved.
+type DNSProbe struct {
+	name     string
+	host     string
+	resolver *net.Resolver
+}
+
+// NewDNSProbe returns a new [DNSProbe], for a given host.
+func NewDNSProbe(host string) *DNSProbe {
+	return &DNSProbe{
+		name:     DefaultDNSProbeName,
+		host:     host,
+		resolver: net.DefaultResolver,
+	}
+}
+
+// Name returns the name of the [DNSProbe].
+func (p *DNSProbe) Name() string {
+	return p.name
+}
+
+// SetName sets the name of the [DNSProbe].
+func (p *DNSProbe) SetName(name string) *DNSProbe {
+	p.name = name
+
+	return p
+}
+
+// SetResolver sets the [net.Resolver] of the [DNSProbe].
+func (p *DNSProbe) SetResolver(resolver *net.Resolver) *DNSProbe {
+	p.resolver = resolver
+
+	return p
+}
+
+// Check returns a successful [healthcheck.CheckerProbeResult] if the host resolves to at least one address.
+func (p *DNSProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	addresses, err := p.resolver.LookupHost(ctx, p.host)
+	if err != nil {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("dns resolution error: %v", err))
+	}
+
+	if len(addresses) == 0 {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("dns resolution error: no address for %s", p.host))
+	}
+
+	return healthcheck.NewCheckerProbeResult(
+		true,
+		fmt.Sprintf("dns resolution success for %s: %s", p.host, strings.Join(addresses, ", ")),
+	)
+}
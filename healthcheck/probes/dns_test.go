@@ -0,0 +1,42 @@
+package probes_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/ankorstore/yokai/healthcheck/probes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSProbe(t *testing.T) {
+	t.Parallel()
+
+	probe := probes.NewDNSProbe("localhost")
+	assert.Equal(t, probes.DefaultDNSProbeName, probe.Name())
+
+	probe.SetName("custom")
+	assert.Equal(t, "custom", probe.Name())
+}
+
+func TestDNSProbeCheck(t *testing.T) {
+	t.Parallel()
+
+	// success
+	result := probes.NewDNSProbe("localhost").Check(context.Background())
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "dns resolution success for localhost:")
+
+	// failure
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return nil, errors.New("custom dial error")
+		},
+	}
+
+	result = probes.NewDNSProbe("invalid.example.com").SetResolver(resolver).Check(context.Background())
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "dns resolution error:")
+}
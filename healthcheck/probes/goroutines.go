@@ -0,0 +1,51 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+// DefaultGoroutinesProbeName is the name of the goroutines probe.
+const DefaultGoroutinesProbeName = "goroutines"
+
+// GoroutinesProbe is a probe checking the number of goroutines of the application.
+type GoroutinesProbe struct {
+	name          string
+	maxGoroutines int
+}
+
+// NewGoroutinesProbe returns a new [GoroutinesProbe], for a given max number of goroutines (0 to disable).
+func NewGoroutinesProbe(maxGoroutines int) *GoroutinesProbe {
+	return &GoroutinesProbe{
+		name:          DefaultGoroutinesProbeName,
+		maxGoroutines: maxGoroutines,
+	}
+}
+
+// Name returns the name of the [GoroutinesProbe].
+func (p *GoroutinesProbe) Name() string {
+	return p.name
+}
+
+// SetName sets the name of the [GoroutinesProbe].
+func (p *GoroutinesProbe) SetName(name string) *GoroutinesProbe {
+	p.name = name
+
+	return p
+}
+
+// Check returns a successful [healthcheck.CheckerProbeResult] if the number of goroutines is below the threshold.
+func (p *GoroutinesProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	goroutines := runtime.NumGoroutine()
+
+	message := fmt.Sprintf("goroutines: %d", goroutines)
+
+	if p.maxGoroutines > 0 && goroutines > p.maxGoroutines {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("%s, above %d", message, p.maxGoroutines))
+	}
+
+	return healthcheck.NewCheckerProbeResult(true, message)
+}
@@ -0,0 +1,33 @@
+package probes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankorstore/yokai/healthcheck/probes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutinesProbe(t *testing.T) {
+	t.Parallel()
+
+	probe := probes.NewGoroutinesProbe(0)
+	assert.Equal(t, probes.DefaultGoroutinesProbeName, probe.Name())
+
+	probe.SetName("custom")
+	assert.Equal(t, "custom", probe.Name())
+}
+
+func TestGoroutinesProbeCheck(t *testing.T) {
+	t.Parallel()
+
+	// success
+	result := probes.NewGoroutinesProbe(1000000).Check(context.Background())
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "goroutines:")
+
+	// failure
+	result = probes.NewGoroutinesProbe(1).Check(context.Background())
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "above 1")
+}
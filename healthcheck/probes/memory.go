@@ -0,0 +1,52 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+// DefaultMemoryProbeName is the name of the memory probe.
+const DefaultMemoryProbeName = "memory"
+
+// MemoryProbe is a probe checking the heap allocated bytes of the application.
+type MemoryProbe struct {
+	name         string
+	maxHeapBytes uint64
+}
+
+// NewMemoryProbe returns a new [MemoryProbe], for a given max heap allocated bytes (0 to disable).
+func NewMemoryProbe(maxHeapBytes uint64) *MemoryProbe {
+	return &MemoryProbe{
+		name:         DefaultMemoryProbeName,
+		maxHeapBytes: maxHeapBytes,
+	}
+}
+
+// Name returns the name of the [MemoryProbe].
+func (p *MemoryProbe) Name() string {
+	return p.name
+}
+
+// SetName sets the name of the [MemoryProbe].
+func (p *MemoryProbe) SetName(name string) *MemoryProbe {
+	p.name = name
+
+	return p
+}
+
+// Check returns a successful [healthcheck.CheckerProbeResult] if the heap allocated bytes are below the threshold.
+func (p *MemoryProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	message := fmt.Sprintf("heap allocated: %d bytes", stats.HeapAlloc)
+
+	if p.maxHeapBytes > 0 && stats.HeapAlloc > p.maxHeapBytes {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("%s, above %d bytes", message, p.maxHeapBytes))
+	}
+
+	return healthcheck.NewCheckerProbeResult(true, message)
+}
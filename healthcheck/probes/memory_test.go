@@ -0,0 +1,33 @@
+package probes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankorstore/yokai/healthcheck/probes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryProbe(t *testing.T) {
+	t.Parallel()
+
+	probe := probes.NewMemoryProbe(0)
+	assert.Equal(t, probes.DefaultMemoryProbeName, probe.Name())
+
+	probe.SetName("custom")
+	assert.Equal(t, "custom", probe.Name())
+}
+
+func TestMemoryProbeCheck(t *testing.T) {
+	t.Parallel()
+
+	// success
+	result := probes.NewMemoryProbe(0).Check(context.Background())
+	assert.True(t, result.Success)
+	assert.Contains(t, result.Message, "heap allocated:")
+
+	// failure
+	result = probes.NewMemoryProbe(1).Check(context.Background())
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "above 1 bytes")
+}
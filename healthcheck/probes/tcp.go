@@ -0,0 +1,53 @@
+package probes
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+// DefaultTCPProbeName is the name of the TCP probe.
+const DefaultTCPProbeName = "tcp"
+
+// TCPProbe is a probe checking that a TCP connection can be established to an address.
+type TCPProbe struct {
+	name    string
+	address string
+	dialer  *net.Dialer
+}
+
+// NewTCPProbe returns a new [TCPProbe], for a given address (host:port).
+func NewTCPProbe(address string) *TCPProbe {
+	return &TCPProbe{
+		name:    DefaultTCPProbeName,
+		address: address,
+		dialer:  &net.Dialer{},
+	}
+}
+
+// Name returns the name of the [TCPProbe].
+func (p *TCPProbe) Name() string {
+	return p.name
+}
+
+// SetName sets the name of the [TCPProbe].
+func (p *TCPProbe) SetName(name string) *TCPProbe {
+	p.name = name
+
+	return p
+}
+
+// Check returns a successful [healthcheck.CheckerProbeResult] if a TCP connection can be established to the address.
+func (p *TCPProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	conn, err := p.dialer.DialContext(ctx, "tcp", p.address)
+	if err != nil {
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("tcp dial error: %v", err))
+	}
+
+	//nolint:errcheck
+	defer conn.Close()
+
+	return healthcheck.NewCheckerProbeResult(true, fmt.Sprintf("tcp dial success on %s", p.address))
+}
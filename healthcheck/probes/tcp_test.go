@@ -0,0 +1,42 @@
+package probes_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/ankorstore/yokai/healthcheck/probes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCPProbe(t *testing.T) {
+	t.Parallel()
+
+	probe := probes.NewTCPProbe("localhost:0")
+	assert.Equal(t, probes.DefaultTCPProbeName, probe.Name())
+
+	probe.SetName("custom")
+	assert.Equal(t, "custom", probe.Name())
+}
+
+func TestTCPProbeCheck(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	address := listener.Addr().String()
+
+	// success
+	result := probes.NewTCPProbe(address).Check(context.Background())
+	assert.True(t, result.Success)
+	assert.Equal(t, "tcp dial success on "+address, result.Message)
+
+	// failure
+	err = listener.Close()
+	assert.NoError(t, err)
+
+	result = probes.NewTCPProbe(address).Check(context.Background())
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "tcp dial error:")
+}
@@ -0,0 +1,32 @@
+package observer
+
+import (
+	"sync"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+type TestCheckerObserver struct {
+	mutex        sync.Mutex
+	observations map[string][]*healthcheck.CheckerProbeResult
+}
+
+func NewTestCheckerObserver() *TestCheckerObserver {
+	return &TestCheckerObserver{
+		observations: map[string][]*healthcheck.CheckerProbeResult{},
+	}
+}
+
+func (o *TestCheckerObserver) ObserveProbeResult(probeName string, result *healthcheck.CheckerProbeResult) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.observations[probeName] = append(o.observations[probeName], result)
+}
+
+func (o *TestCheckerObserver) Observations(probeName string) []*healthcheck.CheckerProbeResult {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.observations[probeName]
+}
@@ -0,0 +1,30 @@
+package probes
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+type CountingProbe struct {
+	count atomic.Int64
+}
+
+func NewCountingProbe() *CountingProbe {
+	return &CountingProbe{}
+}
+
+func (p *CountingProbe) Name() string {
+	return "countingProbe"
+}
+
+func (p *CountingProbe) Count() int64 {
+	return p.count.Load()
+}
+
+func (p *CountingProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	p.count.Add(1)
+
+	return healthcheck.NewCheckerProbeResult(true, "some counted success")
+}
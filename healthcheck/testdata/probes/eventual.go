@@ -0,0 +1,35 @@
+package probes
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+type EventualProbe struct {
+	failures int64
+	count    atomic.Int64
+}
+
+func NewEventualProbe(failures int64) *EventualProbe {
+	return &EventualProbe{
+		failures: failures,
+	}
+}
+
+func (p *EventualProbe) Name() string {
+	return "eventualProbe"
+}
+
+func (p *EventualProbe) Count() int64 {
+	return p.count.Load()
+}
+
+func (p *EventualProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	if p.count.Add(1) <= p.failures {
+		return healthcheck.NewCheckerProbeResult(false, "some eventual failure")
+	}
+
+	return healthcheck.NewCheckerProbeResult(true, "some eventual success")
+}
@@ -0,0 +1,21 @@
+package probes
+
+import (
+	"context"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+type PanicProbe struct{}
+
+func NewPanicProbe() *PanicProbe {
+	return &PanicProbe{}
+}
+
+func (p *PanicProbe) Name() string {
+	return "panicProbe"
+}
+
+func (p *PanicProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	panic("some panic")
+}
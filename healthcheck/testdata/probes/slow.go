@@ -0,0 +1,30 @@
+package probes
+
+import (
+	"context"
+	"time"
+
+	"github.com/ankorstore/yokai/healthcheck"
+)
+
+type SlowProbe struct {
+	name     string
+	duration time.Duration
+}
+
+func NewSlowProbe(name string, duration time.Duration) *SlowProbe {
+	return &SlowProbe{
+		name:     name,
+		duration: duration,
+	}
+}
+
+func (p *SlowProbe) Name() string {
+	return p.name
+}
+
+func (p *SlowProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	time.Sleep(p.duration)
+
+	return healthcheck.NewCheckerProbeResult(true, "some slow success")
+}
@@ -0,0 +1,74 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultWaitTimeout is lower than the Fx default start timeout (15s), for a startup wait to fail by itself.
+	DefaultWaitTimeout         = 10 * time.Second
+	DefaultWaitInitialInterval = 500 * time.Millisecond
+	DefaultWaitMaxInterval     = 5 * time.Second
+	DefaultWaitMultiplier      = 2.0
+)
+
+// WaitAttempt is an attempt of a [Checker.Wait] execution.
+type WaitAttempt struct {
+	Kind     ProbeKind
+	Number   int
+	Result   *CheckerResult
+	NextWait time.Duration
+}
+
+// Wait executes the checks for a [ProbeKind] until they succeed, waiting with an exponential backoff between the attempts,
+// and returns the last [CheckerResult]. An error is returned if the checks did not succeed before the timeout, or before the context ends.
+func (c *Checker) Wait(ctx context.Context, kind ProbeKind, options ...WaitOption) (*CheckerResult, error) {
+	appliedOptions := DefaultWaitOptions()
+	for _, opt := range options {
+		opt(&appliedOptions)
+	}
+
+	if appliedOptions.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, appliedOptions.Timeout)
+		defer cancel()
+	}
+
+	interval := appliedOptions.InitialInterval
+
+	for number := 1; ; number++ {
+		result := c.Check(ctx, kind)
+
+		attempt := WaitAttempt{
+			Kind:   kind,
+			Number: number,
+			Result: result,
+		}
+
+		if result.Success {
+			appliedOptions.AttemptHandler(attempt)
+
+			return result, nil
+		}
+
+		attempt.NextWait = interval
+		appliedOptions.AttemptHandler(attempt)
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return result, fmt.Errorf("%s checks did not succeed after %d attempts: %w", kind, number, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * appliedOptions.Multiplier)
+		if interval > appliedOptions.MaxInterval {
+			interval = appliedOptions.MaxInterval
+		}
+	}
+}
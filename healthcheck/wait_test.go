@@ -0,0 +1,74 @@
+package healthcheck_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/healthcheck/testdata/probes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckerWaitWithSuccess(t *testing.T) {
+	t.Parallel()
+
+	probe := probes.NewEventualProbe(3)
+
+	checker := healthcheck.NewChecker().RegisterProbe(probe, healthcheck.Startup)
+
+	var attempts []healthcheck.WaitAttempt
+
+	result, err := checker.Wait(
+		context.Background(),
+		healthcheck.Startup,
+		healthcheck.WithWaitBackoff(10*time.Millisecond, 25*time.Millisecond, 2),
+		healthcheck.WithWaitAttemptHandler(func(attempt healthcheck.WaitAttempt) {
+			attempts = append(attempts, attempt)
+		}),
+	)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, int64(4), probe.Count())
+
+	assert.Len(t, attempts, 4)
+
+	expectedWaits := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond, 0}
+	for i, attempt := range attempts {
+		assert.Equal(t, healthcheck.Startup, attempt.Kind)
+		assert.Equal(t, i+1, attempt.Number)
+		assert.Equal(t, i == 3, attempt.Result.Success)
+		assert.Equal(t, expectedWaits[i], attempt.NextWait)
+	}
+}
+
+func TestCheckerWaitWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	checker := healthcheck.NewChecker().RegisterProbe(probes.NewFailureProbe(), healthcheck.Startup)
+
+	result, err := checker.Wait(
+		context.Background(),
+		healthcheck.Startup,
+		healthcheck.WithWaitTimeout(50*time.Millisecond),
+		healthcheck.WithWaitBackoff(10*time.Millisecond, 10*time.Millisecond, 1),
+	)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "startup checks did not succeed after")
+	assert.False(t, result.Success)
+}
+
+func TestCheckerWaitWithCanceledContext(t *testing.T) {
+	t.Parallel()
+
+	checker := healthcheck.NewChecker().RegisterProbe(probes.NewFailureProbe(), healthcheck.Readiness)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := checker.Wait(ctx, healthcheck.Readiness, healthcheck.WithWaitTimeout(0))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "readiness checks did not succeed after 1 attempts: context canceled", err.Error())
+	assert.False(t, result.Success)
+}
@@ -0,0 +1,128 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ankorstore/yokai/healthcheck"
+	"github.com/ankorstore/yokai/log"
+)
+
+const (
+	// DefaultProbeName is the name of the HTTP probe.
+	DefaultProbeName = "http"
+	// DefaultMaxBodySize is the maximum size of the response body read by the HTTP probe.
+	DefaultMaxBodySize = 1024 * 1024
+)
+
+// HttpProbe is a probe compatible with the [healthcheck] module, checking an HTTP upstream.
+//
+// [healthcheck]: https://github.com/ankorstore/yokai/tree/main/healthcheck
+type HttpProbe struct {
+	name           string
+	client         *http.Client
+	method         string
+	url            string
+	expectedStatus int
+	expectedBody   string
+}
+
+// NewHttpProbe returns a new [HttpProbe], for a given [http.Client] and url.
+func NewHttpProbe(client *http.Client, url string) *HttpProbe {
+	return &HttpProbe{
+		name:   DefaultProbeName,
+		client: client,
+		method: http.MethodGet,
+		url:    url,
+	}
+}
+
+// Name returns the name of the [HttpProbe].
+func (p *HttpProbe) Name() string {
+	return p.name
+}
+
+// SetName sets the name of the [HttpProbe].
+func (p *HttpProbe) SetName(name string) *HttpProbe {
+	p.name = name
+
+	return p
+}
+
+// SetMethod sets the HTTP method of the [HttpProbe] requests (GET by default).
+func (p *HttpProbe) SetMethod(method string) *HttpProbe {
+	p.method = strings.ToUpper(method)
+
+	return p
+}
+
+// SetExpectedStatus sets the expected response status code of the [HttpProbe] (any 2xx by default).
+func (p *HttpProbe) SetExpectedStatus(expectedStatus int) *HttpProbe {
+	p.expectedStatus = expectedStatus
+
+	return p
+}
+
+// SetExpectedBody sets a string that the [HttpProbe] response body is expected to contain (not checked by default).
+func (p *HttpProbe) SetExpectedBody(expectedBody string) *HttpProbe {
+	p.expectedBody = expectedBody
+
+	return p
+}
+
+// Check returns a successful [healthcheck.CheckerProbeResult] if the upstream responds with the expected status and body.
+func (p *HttpProbe) Check(ctx context.Context) *healthcheck.CheckerProbeResult {
+	logger := log.CtxLogger(ctx)
+
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, nil)
+	if err != nil {
+		logger.Error().Err(err).Msg("http probe request error")
+
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("http request error: %v", err))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		logger.Error().Err(err).Msg("http probe request error")
+
+		return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("http request error: %v", err))
+	}
+
+	//nolint:errcheck
+	defer resp.Body.Close()
+
+	if p.expectedStatus != 0 && resp.StatusCode != p.expectedStatus {
+		return healthcheck.NewCheckerProbeResult(
+			false,
+			fmt.Sprintf("http response status %d, expected %d", resp.StatusCode, p.expectedStatus),
+		)
+	}
+
+	if p.expectedStatus == 0 && (resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices) {
+		return healthcheck.NewCheckerProbeResult(
+			false,
+			fmt.Sprintf("http response status %d, expected 2xx", resp.StatusCode),
+		)
+	}
+
+	if p.expectedBody != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, DefaultMaxBodySize))
+		if err != nil {
+			logger.Error().Err(err).Msg("http probe response body read error")
+
+			return healthcheck.NewCheckerProbeResult(false, fmt.Sprintf("http response body read error: %v", err))
+		}
+
+		if !strings.Contains(string(body), p.expectedBody) {
+			return healthcheck.NewCheckerProbeResult(
+				false,
+				fmt.Sprintf("http response body does not contain %q", p.expectedBody),
+			)
+		}
+	}
+
+	return healthcheck.NewCheckerProbeResult(true, fmt.Sprintf("http response status %d", resp.StatusCode))
+}
@@ -0,0 +1,137 @@
+package healthcheck_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpclient"
+	"github.com/ankorstore/yokai/httpclient/healthcheck"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaults(t *testing.T) {
+	t.Parallel()
+
+	probe := healthcheck.NewHttpProbe(http.DefaultClient, "http://localhost")
+
+	assert.Equal(t, "http", probe.Name())
+}
+
+func TestSetName(t *testing.T) {
+	t.Parallel()
+
+	probe := healthcheck.NewHttpProbe(http.DefaultClient, "http://localhost")
+
+	probe.SetName("custom")
+
+	assert.Equal(t, "custom", probe.Name())
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+			//nolint:errcheck
+			w.Write([]byte(`{"status":"ok"}`))
+		case "/accepted":
+			if r.Method != http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := httpclient.NewDefaultHttpClientFactory().Create()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		probe           *healthcheck.HttpProbe
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name:            "default success",
+			probe:           healthcheck.NewHttpProbe(client, server.URL+"/ok"),
+			expectedSuccess: true,
+			expectedMessage: "http response status 200",
+		},
+		{
+			name:            "default failure",
+			probe:           healthcheck.NewHttpProbe(client, server.URL+"/error"),
+			expectedSuccess: false,
+			expectedMessage: "http response status 500, expected 2xx",
+		},
+		{
+			name:            "expected status and method success",
+			probe:           healthcheck.NewHttpProbe(client, server.URL+"/accepted").SetMethod("head").SetExpectedStatus(http.StatusAccepted),
+			expectedSuccess: true,
+			expectedMessage: "http response status 202",
+		},
+		{
+			name:            "expected status failure",
+			probe:           healthcheck.NewHttpProbe(client, server.URL+"/ok").SetExpectedStatus(http.StatusNoContent),
+			expectedSuccess: false,
+			expectedMessage: "http response status 200, expected 204",
+		},
+		{
+			name:            "expected body success",
+			probe:           healthcheck.NewHttpProbe(client, server.URL+"/ok").SetExpectedBody(`"status":"ok"`),
+			expectedSuccess: true,
+			expectedMessage: "http response status 200",
+		},
+		{
+			name:            "expected body failure",
+			probe:           healthcheck.NewHttpProbe(client, server.URL+"/ok").SetExpectedBody("invalid"),
+			expectedSuccess: false,
+			expectedMessage: `http response body does not contain "invalid"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := tt.probe.Check(context.Background())
+			assert.Equal(t, tt.expectedSuccess, result.Success)
+			assert.Equal(t, tt.expectedMessage, result.Message)
+		})
+	}
+}
+
+func TestCheckRequestError(t *testing.T) {
+	t.Parallel()
+
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	probe := healthcheck.NewHttpProbe(http.DefaultClient, server.URL)
+
+	result := probe.Check(logger.WithContext(context.Background()))
+	assert.False(t, result.Success)
+	assert.Contains(t, result.Message, "http request error:")
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "error",
+		"message": "http probe request error",
+	})
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DefaultAPIKeyAuthenticatorName = "api_key"
+	DefaultAPIKeyHeader            = "X-Api-Key"
+)
+
+// APIKeyStore is the interface for the API keys stores.
+//
+// Lookup returns the [Principal] owning the provided key, or nil if the key is unknown.
+type APIKeyStore interface {
+	Lookup(ctx context.Context, key string) (*Principal, error)
+}
+
+// HashAPIKey returns the hex encoded sha256 hash of an API key, as kept by the stores.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// StaticAPIKeyStore is an [APIKeyStore] for a fixed set of API keys.
+type StaticAPIKeyStore struct {
+	principals map[string]*Principal
+}
+
+// NewStaticAPIKeyStore returns a new [StaticAPIKeyStore], for the provided principals by API key.
+func NewStaticAPIKeyStore(principals map[string]*Principal) *StaticAPIKeyStore {
+	store := &StaticAPIKeyStore{
+		principals: make(map[string]*Principal, len(principals)),
+	}
+
+	// keys are kept hashed, to avoid timing attacks on the lookups
+	for key, principal := range principals {
+		store.principals[HashAPIKey(key)] = principal
+	}
+
+	return store
+}
+
+// Lookup returns the [Principal] owning the provided key, or nil if the key is unknown.
+func (s *StaticAPIKeyStore) Lookup(_ context.Context, key string) (*Principal, error) {
+	principal, ok := s.principals[HashAPIKey(key)]
+	if !ok {
+		return nil, nil
+	}
+
+	return principal, nil
+}
+
+// APIKeyAuthenticator is an [Authenticator] validating API keys against an [APIKeyStore].
+type APIKeyAuthenticator struct {
+	store      APIKeyStore
+	name       string
+	header     string
+	queryParam string
+}
+
+// APIKeyAuthenticatorOption are functional options for the [APIKeyAuthenticator].
+type APIKeyAuthenticatorOption func(a *APIKeyAuthenticator)
+
+// WithAPIKeyName is used to specify the authenticator name (default api_key).
+func WithAPIKeyName(name string) APIKeyAuthenticatorOption {
+	return func(a *APIKeyAuthenticator) {
+		a.name = name
+	}
+}
+
+// WithAPIKeyHeader is used to specify the header carrying the API key (default X-Api-Key).
+func WithAPIKeyHeader(header string) APIKeyAuthenticatorOption {
+	return func(a *APIKeyAuthenticator) {
+		a.header = header
+	}
+}
+
+// WithAPIKeyQueryParam is used to specify a query parameter carrying the API key, checked if the header is missing.
+func WithAPIKeyQueryParam(queryParam string) APIKeyAuthenticatorOption {
+	return func(a *APIKeyAuthenticator) {
+		a.queryParam = queryParam
+	}
+}
+
+// NewAPIKeyAuthenticator returns a new [APIKeyAuthenticator], for a provided [APIKeyStore].
+func NewAPIKeyAuthenticator(store APIKeyStore, options ...APIKeyAuthenticatorOption) *APIKeyAuthenticator {
+	authenticator := &APIKeyAuthenticator{
+		store:  store,
+		name:   DefaultAPIKeyAuthenticatorName,
+		header: DefaultAPIKeyHeader,
+	}
+
+	for _, opt := range options {
+		opt(authenticator)
+	}
+
+	return authenticator
+}
+
+// Name returns the authenticator name.
+func (a *APIKeyAuthenticator) Name() string {
+	return a.name
+}
+
+// Authenticate validates the request API key, and returns the [Principal] owning it.
+func (a *APIKeyAuthenticator) Authenticate(c echo.Context) (*Principal, error) {
+	key := c.Request().Header.Get(a.header)
+	if key == "" && a.queryParam != "" {
+		key = c.QueryParam(a.queryParam)
+	}
+
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	principal, err := a.store.Lookup(c.Request().Context(), key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot lookup api key: %w", err)
+	}
+
+	if principal == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	authenticated := *principal
+	if authenticated.Authenticator == "" {
+		authenticated.Authenticator = a.name
+	}
+
+	return &authenticated, nil
+}
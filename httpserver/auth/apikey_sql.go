@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+)
+
+// DefaultSQLAPIKeyStoreTable is the default table name of the [SQLAPIKeyStore].
+const DefaultSQLAPIKeyStoreTable = "http_server_api_keys"
+
+// SQLAPIKeyStore is an [APIKeyStore] keeping hashed API keys in a SQL database table.
+// Scopes and roles are stored space separated, and keys with a zero expiration never expire.
+type SQLAPIKeyStore struct {
+	db      *sql.DB
+	dialect sqlstore.Dialect
+	table   string
+	now     func() time.Time
+}
+
+// SQLAPIKeyStoreOption are functional options for the [SQLAPIKeyStore].
+type SQLAPIKeyStoreOption func(s *SQLAPIKeyStore)
+
+// WithSQLAPIKeyStoreTable is used to specify the table of the [SQLAPIKeyStore] (default http_server_api_keys).
+func WithSQLAPIKeyStoreTable(table string) SQLAPIKeyStoreOption {
+	return func(s *SQLAPIKeyStore) {
+		s.table = table
+	}
+}
+
+// WithSQLAPIKeyStoreClock is used to specify the time source of the [SQLAPIKeyStore] (time.Now by default).
+func WithSQLAPIKeyStoreClock(now func() time.Time) SQLAPIKeyStoreOption {
+	return func(s *SQLAPIKeyStore) {
+		s.now = now
+	}
+}
+
+// NewSQLAPIKeyStore returns a new [SQLAPIKeyStore], for a [sql.DB] of the provided [sqlstore.Dialect].
+func NewSQLAPIKeyStore(db *sql.DB, dialect sqlstore.Dialect, options ...SQLAPIKeyStoreOption) (*SQLAPIKeyStore, error) {
+	if dialect == sqlstore.UnknownDialect {
+		return nil, fmt.Errorf("unsupported sql dialect for api key store")
+	}
+
+	store := &SQLAPIKeyStore{
+		db:      db,
+		dialect: dialect,
+		table:   DefaultSQLAPIKeyStoreTable,
+		now:     time.Now,
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	return store, nil
+}
+
+// CreateTable creates the [SQLAPIKeyStore] table, if it does not exist.
+func (s *SQLAPIKeyStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+				key_hash CHAR(64) NOT NULL PRIMARY KEY,
+				principal VARCHAR(191) NOT NULL,
+				scopes VARCHAR(1024) NOT NULL,
+				roles VARCHAR(1024) NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+			s.table,
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create api key table: %w", err)
+	}
+
+	return nil
+}
+
+// Save stores an API key for the provided [Principal], expiring at the provided time (never if zero).
+func (s *SQLAPIKeyStore) Save(ctx context.Context, key string, principal *Principal, expiresAt time.Time) error {
+	var expiration int64
+	if !expiresAt.IsZero() {
+		expiration = expiresAt.Unix()
+	}
+
+	_, err := s.db.ExecContext(
+		ctx,
+		s.dialect.Rebind(fmt.Sprintf("INSERT INTO %s (key_hash, principal, scopes, roles, expires_at) VALUES (?, ?, ?, ?, ?)", s.table)),
+		HashAPIKey(key),
+		principal.ID,
+		strings.Join(principal.Scopes, " "),
+		strings.Join(principal.Roles, " "),
+		expiration,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot save api key: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke deletes an API key.
+func (s *SQLAPIKeyStore) Revoke(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		s.dialect.Rebind(fmt.Sprintf("DELETE FROM %s WHERE key_hash = ?", s.table)),
+		HashAPIKey(key),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot revoke api key: %w", err)
+	}
+
+	return nil
+}
+
+// Lookup returns the [Principal] owning the provided key, or nil if the key is unknown or expired.
+func (s *SQLAPIKeyStore) Lookup(ctx context.Context, key string) (*Principal, error) {
+	var id, scopes, roles string
+	var expiresAt int64
+
+	err := s.db.QueryRowContext(
+		ctx,
+		s.dialect.Rebind(fmt.Sprintf("SELECT principal, scopes, roles, expires_at FROM %s WHERE key_hash = ?", s.table)),
+		HashAPIKey(key),
+	).Scan(&id, &scopes, &roles, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("cannot select api key: %w", err)
+	}
+
+	if expiresAt != 0 && expiresAt <= s.now().Unix() {
+		return nil, nil
+	}
+
+	return &Principal{
+		ID:     id,
+		Scopes: strings.Fields(scopes),
+		Roles:  strings.Fields(roles),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package auth_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLAPIKeyStoreWithUnknownDialect(t *testing.T) {
+	t.Parallel()
+
+	_, err := auth.NewSQLAPIKeyStore(nil, sqlstore.UnknownDialect)
+	assert.Error(t, err)
+	assert.Equal(t, "unsupported sql dialect for api key store", err.Error())
+}
+
+func TestSQLAPIKeyStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+
+	store, err := auth.NewSQLAPIKeyStore(
+		db,
+		sqlstore.SQLiteDialect,
+		auth.WithSQLAPIKeyStoreTable("api_keys"),
+		auth.WithSQLAPIKeyStoreClock(clock.Now),
+	)
+	assert.NoError(t, err)
+
+	// missing table
+	_, err = store.Lookup(ctx, "key1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot select api key")
+
+	assert.NoError(t, store.CreateTable(ctx))
+	assert.NoError(t, store.CreateTable(ctx))
+
+	err = store.Save(ctx, "key1", &auth.Principal{ID: "service1", Scopes: []string{"read", "write"}, Roles: []string{"admin"}}, time.Time{})
+	assert.NoError(t, err)
+
+	err = store.Save(ctx, "key2", &auth.Principal{ID: "service2"}, time.Unix(1010, 0))
+	assert.NoError(t, err)
+
+	// duplicated key
+	err = store.Save(ctx, "key1", &auth.Principal{ID: "service3"}, time.Time{})
+	assert.Error(t, err)
+
+	principal, err := store.Lookup(ctx, "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, "service1", principal.ID)
+	assert.Equal(t, []string{"read", "write"}, principal.Scopes)
+	assert.Equal(t, []string{"admin"}, principal.Roles)
+
+	principal, err = store.Lookup(ctx, "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, "service2", principal.ID)
+	assert.Empty(t, principal.Scopes)
+
+	principal, err = store.Lookup(ctx, "invalid")
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	// expiration
+	clock.Advance(10 * time.Second)
+
+	principal, err = store.Lookup(ctx, "key2")
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	// revocation
+	assert.NoError(t, store.Revoke(ctx, "key1"))
+
+	principal, err = store.Lookup(ctx, "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+
+	// keys are stored hashed
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM api_keys WHERE key_hash = ?", auth.HashAPIKey("key2")).Scan(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,115 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingAPIKeyStore struct{}
+
+func (s failingAPIKeyStore) Lookup(context.Context, string) (*auth.Principal, error) {
+	return nil, fmt.Errorf("store error")
+}
+
+func TestHashAPIKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", auth.HashAPIKey("test"))
+}
+
+func TestStaticAPIKeyStore(t *testing.T) {
+	t.Parallel()
+
+	store := auth.NewStaticAPIKeyStore(map[string]*auth.Principal{
+		"key1": {ID: "service1", Scopes: []string{"read"}},
+	})
+
+	principal, err := store.Lookup(context.Background(), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, "service1", principal.ID)
+	assert.Equal(t, []string{"read"}, principal.Scopes)
+
+	principal, err = store.Lookup(context.Background(), "invalid")
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	authenticator := auth.NewAPIKeyAuthenticator(auth.NewStaticAPIKeyStore(map[string]*auth.Principal{
+		"key1": {ID: "service1", Roles: []string{"admin"}},
+	}))
+
+	assert.Equal(t, auth.DefaultAPIKeyAuthenticatorName, authenticator.Name())
+
+	principal, err := authenticator.Authenticate(createAPIKeyContext("/", auth.DefaultAPIKeyHeader, "key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "service1", principal.ID)
+	assert.Equal(t, "api_key", principal.Authenticator)
+	assert.Equal(t, []string{"admin"}, principal.Roles)
+
+	_, err = authenticator.Authenticate(createAPIKeyContext("/", auth.DefaultAPIKeyHeader, "invalid"))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(createAPIKeyContext("/", "", ""))
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+
+	// query param not enabled
+	_, err = authenticator.Authenticate(createAPIKeyContext("/?api_key=key1", "", ""))
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+}
+
+func TestAPIKeyAuthenticatorWithOptions(t *testing.T) {
+	t.Parallel()
+
+	authenticator := auth.NewAPIKeyAuthenticator(
+		auth.NewStaticAPIKeyStore(map[string]*auth.Principal{
+			"key1": {ID: "service1"},
+		}),
+		auth.WithAPIKeyName("custom"),
+		auth.WithAPIKeyHeader("X-Custom-Key"),
+		auth.WithAPIKeyQueryParam("api_key"),
+	)
+
+	assert.Equal(t, "custom", authenticator.Name())
+
+	principal, err := authenticator.Authenticate(createAPIKeyContext("/", "X-Custom-Key", "key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "service1", principal.ID)
+	assert.Equal(t, "custom", principal.Authenticator)
+
+	principal, err = authenticator.Authenticate(createAPIKeyContext("/?api_key=key1", "", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, "service1", principal.ID)
+
+	_, err = authenticator.Authenticate(createAPIKeyContext("/", auth.DefaultAPIKeyHeader, "key1"))
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+}
+
+func TestAPIKeyAuthenticatorWithStoreError(t *testing.T) {
+	t.Parallel()
+
+	authenticator := auth.NewAPIKeyAuthenticator(failingAPIKeyStore{})
+
+	_, err := authenticator.Authenticate(createAPIKeyContext("/", auth.DefaultAPIKeyHeader, "key1"))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.Equal(t, "cannot lookup api key: store error", err.Error())
+}
+
+func createAPIKeyContext(target string, header string, key string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if header != "" {
+		req.Header.Set(header, key)
+	}
+
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PrincipalContextKey is the echo context key under which the authenticated [Principal] is stored,
+// usable for example with ratelimit.KeyByContextValue.
+const PrincipalContextKey = "principal"
+
+var (
+	// ErrNoCredentials is returned by an [Authenticator] when the request does not carry its kind of credentials.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is returned by an [Authenticator] when the request credentials are not valid.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is an authenticated identity.
+type Principal struct {
+	ID            string
+	Authenticator string
+	Scopes        []string
+	Roles         []string
+	Claims        map[string]any
+}
+
+// String returns the principal id.
+func (p *Principal) String() string {
+	return p.ID
+}
+
+// HasScopes returns true if the principal has all the provided scopes.
+func (p *Principal) HasScopes(scopes ...string) bool {
+	return containsAll(p.Scopes, scopes)
+}
+
+// HasRoles returns true if the principal has all the provided roles.
+func (p *Principal) HasRoles(roles ...string) bool {
+	return containsAll(p.Roles, roles)
+}
+
+// Authenticator is the interface for the request authenticators.
+//
+// Authenticate returns [ErrNoCredentials] if the request does not carry credentials for the authenticator, so the
+// next authenticator can be tried, or [ErrInvalidCredentials] if the credentials are not valid.
+type Authenticator interface {
+	Name() string
+	Authenticate(c echo.Context) (*Principal, error)
+}
+
+// Challenger is the interface for the authenticators providing a WWW-Authenticate challenge on 401 responses.
+type Challenger interface {
+	Challenge() string
+}
+
+// CtxPrincipalKey is a contextual struct key.
+type CtxPrincipalKey struct{}
+
+// WithPrincipal returns a copy of the provided context, carrying the provided [Principal].
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, CtxPrincipalKey{}, principal)
+}
+
+// CtxPrincipal returns the contextual [Principal], or nil if the request is not authenticated.
+func CtxPrincipal(ctx context.Context) *Principal {
+	if principal, ok := ctx.Value(CtxPrincipalKey{}).(*Principal); ok {
+		return principal
+	}
+
+	return nil
+}
+
+func containsAll(values []string, expected []string) bool {
+	for _, e := range expected {
+		found := false
+
+		for _, v := range values {
+			if v == e {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,41 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal(t *testing.T) {
+	t.Parallel()
+
+	principal := &auth.Principal{
+		ID:     "alice",
+		Scopes: []string{"read", "write"},
+		Roles:  []string{"admin"},
+	}
+
+	assert.Equal(t, "alice", principal.String())
+
+	assert.True(t, principal.HasScopes())
+	assert.True(t, principal.HasScopes("read"))
+	assert.True(t, principal.HasScopes("read", "write"))
+	assert.False(t, principal.HasScopes("read", "delete"))
+
+	assert.True(t, principal.HasRoles())
+	assert.True(t, principal.HasRoles("admin"))
+	assert.False(t, principal.HasRoles("admin", "owner"))
+}
+
+func TestCtxPrincipal(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, auth.CtxPrincipal(context.Background()))
+
+	principal := &auth.Principal{ID: "alice"}
+
+	ctx := auth.WithPrincipal(context.Background(), principal)
+	assert.Equal(t, principal, auth.CtxPrincipal(ctx))
+}
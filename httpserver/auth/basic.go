@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	DefaultBasicAuthenticatorName = "basic"
+	DefaultBasicRealm             = "Restricted"
+)
+
+// BasicUser is a user of the [BasicAuthenticator], with either a plain Password or a bcrypt PasswordHash.
+type BasicUser struct {
+	Password     string
+	PasswordHash string
+	Scopes       []string
+	Roles        []string
+}
+
+// BasicAuthenticator is an [Authenticator] validating basic auth credentials against a fixed set of users.
+type BasicAuthenticator struct {
+	users map[string]BasicUser
+	name  string
+	realm string
+}
+
+// BasicAuthenticatorOption are functional options for the [BasicAuthenticator].
+type BasicAuthenticatorOption func(a *BasicAuthenticator)
+
+// WithBasicName is used to specify the authenticator name (default basic).
+func WithBasicName(name string) BasicAuthenticatorOption {
+	return func(a *BasicAuthenticator) {
+		a.name = name
+	}
+}
+
+// WithBasicRealm is used to specify the realm of the WWW-Authenticate challenge (default Restricted).
+func WithBasicRealm(realm string) BasicAuthenticatorOption {
+	return func(a *BasicAuthenticator) {
+		a.realm = realm
+	}
+}
+
+// NewBasicAuthenticator returns a new [BasicAuthenticator], for the provided users by username.
+func NewBasicAuthenticator(users map[string]BasicUser, options ...BasicAuthenticatorOption) *BasicAuthenticator {
+	authenticator := &BasicAuthenticator{
+		users: users,
+		name:  DefaultBasicAuthenticatorName,
+		realm: DefaultBasicRealm,
+	}
+
+	for _, opt := range options {
+		opt(authenticator)
+	}
+
+	return authenticator
+}
+
+// Name returns the authenticator name.
+func (a *BasicAuthenticator) Name() string {
+	return a.name
+}
+
+// Challenge returns the WWW-Authenticate challenge.
+func (a *BasicAuthenticator) Challenge() string {
+	return fmt.Sprintf("Basic realm=%q", a.realm)
+}
+
+// Authenticate validates the request basic auth credentials, and returns the [Principal] of the user.
+func (a *BasicAuthenticator) Authenticate(c echo.Context) (*Principal, error) {
+	username, password, ok := c.Request().BasicAuth()
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	user, ok := a.users[username]
+	if !ok || !checkPassword(user, password) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Principal{
+		ID:            username,
+		Authenticator: a.name,
+		Scopes:        user.Scopes,
+		Roles:         user.Roles,
+	}, nil
+}
+
+func checkPassword(user BasicUser, password string) bool {
+	if user.PasswordHash != "" {
+		return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+	}
+
+	if user.Password == "" {
+		return false
+	}
+
+	// hashed before comparison, to compare in constant time whatever the lengths
+	expected := sha256.Sum256([]byte(user.Password))
+	actual := sha256.Sum256([]byte(password))
+
+	return subtle.ConstantTimeCompare(expected[:], actual[:]) == 1
+}
@@ -0,0 +1,75 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBasicAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("bob-password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	authenticator := auth.NewBasicAuthenticator(map[string]auth.BasicUser{
+		"alice": {Password: "alice-password", Scopes: []string{"read"}, Roles: []string{"admin"}},
+		"bob":   {PasswordHash: string(hash)},
+		"carol": {},
+	})
+
+	assert.Equal(t, auth.DefaultBasicAuthenticatorName, authenticator.Name())
+	assert.Equal(t, `Basic realm="Restricted"`, authenticator.Challenge())
+
+	principal, err := authenticator.Authenticate(createBasicContext("alice", "alice-password"))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", principal.ID)
+	assert.Equal(t, "basic", principal.Authenticator)
+	assert.Equal(t, []string{"read"}, principal.Scopes)
+	assert.Equal(t, []string{"admin"}, principal.Roles)
+
+	principal, err = authenticator.Authenticate(createBasicContext("bob", "bob-password"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", principal.ID)
+
+	_, err = authenticator.Authenticate(createBasicContext("alice", "invalid"))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(createBasicContext("bob", "invalid"))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	// user without password
+	_, err = authenticator.Authenticate(createBasicContext("carol", ""))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(createBasicContext("invalid", "alice-password"))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder()))
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+}
+
+func TestBasicAuthenticatorWithOptions(t *testing.T) {
+	t.Parallel()
+
+	authenticator := auth.NewBasicAuthenticator(
+		map[string]auth.BasicUser{},
+		auth.WithBasicName("custom"),
+		auth.WithBasicRealm("app"),
+	)
+
+	assert.Equal(t, "custom", authenticator.Name())
+	assert.Equal(t, `Basic realm="app"`, authenticator.Challenge())
+}
+
+func createBasicContext(username string, password string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth(username, password)
+
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
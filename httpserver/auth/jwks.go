@@ -0,0 +1,383 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ankorstore/yokai/log"
+)
+
+const (
+	DefaultJWKSRefreshInterval = time.Hour
+	DefaultJWKSFetchTimeout    = 10 * time.Second
+	// jwksMinRefreshInterval is the minimum interval between refresh attempts, once the set was fetched.
+	jwksMinRefreshInterval = 10 * time.Second
+	oidcDiscoveryPath      = "/.well-known/openid-configuration"
+)
+
+// KeySet is the interface for the JWT verification keys sets.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (any, error)
+}
+
+// JWKS is a static [KeySet], parsed from a JSON Web Key Set.
+type JWKS struct {
+	keys map[string]any
+}
+
+// jsonWebKey is a JSON Web Key, as defined in RFC 7517.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// ParseJWKS parses a JSON Web Key Set into a [JWKS]. Encryption keys are ignored.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("cannot decode jwks: %w", err)
+	}
+
+	jwks := &JWKS{
+		keys: make(map[string]any),
+	}
+
+	for _, jwk := range set.Keys {
+		if jwk.Use == "enc" {
+			continue
+		}
+
+		key, err := parseJsonWebKey(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse jwks key %s: %w", jwk.Kid, err)
+		}
+
+		jwks.keys[jwk.Kid] = key
+	}
+
+	return jwks, nil
+}
+
+// NewJWKSFromFile returns a [JWKS] parsed from the provided file.
+func NewJWKSFromFile(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read jwks file: %w", err)
+	}
+
+	return ParseJWKS(data)
+}
+
+// Key returns the key for the provided key id. If the key id is empty and the set contains a single key, it is returned.
+func (j *JWKS) Key(_ context.Context, kid string) (any, error) {
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot find jwks key %s", kid)
+}
+
+// Len returns the number of keys of the set.
+func (j *JWKS) Len() int {
+	return len(j.keys)
+}
+
+// RemoteJWKS is a [KeySet] fetched from a JWKS url, or discovered from an OpenID Connect issuer.
+//
+// The set is refreshed after the refresh interval, or when a token references an unknown key id.
+type RemoteJWKS struct {
+	mutex           sync.Mutex
+	url             string
+	issuer          string
+	client          *http.Client
+	refreshInterval time.Duration
+	now             func() time.Time
+	jwks            *JWKS
+	fetchedAt       time.Time
+	attemptedAt     time.Time
+	refreshing      chan struct{}
+	refreshErr      error
+}
+
+// RemoteJWKSOption are functional options for the [RemoteJWKS].
+type RemoteJWKSOption func(r *RemoteJWKS)
+
+// WithRemoteJWKSClient is used to specify the http client used to fetch the set (a client with a 10 seconds
+// timeout by default).
+func WithRemoteJWKSClient(client *http.Client) RemoteJWKSOption {
+	return func(r *RemoteJWKS) {
+		r.client = client
+	}
+}
+
+// WithRemoteJWKSRefreshInterval is used to specify the set refresh interval (1 hour by default).
+func WithRemoteJWKSRefreshInterval(interval time.Duration) RemoteJWKSOption {
+	return func(r *RemoteJWKS) {
+		r.refreshInterval = interval
+	}
+}
+
+// WithRemoteJWKSClock is used to specify the time source of the [RemoteJWKS] (time.Now by default).
+func WithRemoteJWKSClock(now func() time.Time) RemoteJWKSOption {
+	return func(r *RemoteJWKS) {
+		r.now = now
+	}
+}
+
+// NewRemoteJWKS returns a new [RemoteJWKS], fetched from the provided url.
+func NewRemoteJWKS(url string, options ...RemoteJWKSOption) *RemoteJWKS {
+	return newRemoteJWKS(url, "", options...)
+}
+
+// NewOIDCJWKS returns a new [RemoteJWKS], fetched from the jwks_uri of the OpenID Connect discovery document of
+// the provided issuer.
+func NewOIDCJWKS(issuer string, options ...RemoteJWKSOption) *RemoteJWKS {
+	return newRemoteJWKS("", issuer, options...)
+}
+
+func newRemoteJWKS(url string, issuer string, options ...RemoteJWKSOption) *RemoteJWKS {
+	remote := &RemoteJWKS{
+		url:             url,
+		issuer:          issuer,
+		client:          &http.Client{Timeout: DefaultJWKSFetchTimeout},
+		refreshInterval: DefaultJWKSRefreshInterval,
+		now:             time.Now,
+	}
+
+	for _, opt := range options {
+		opt(remote)
+	}
+
+	return remote
+}
+
+// Key returns the key for the provided key id, fetching the set if needed.
+func (r *RemoteJWKS) Key(ctx context.Context, kid string) (any, error) {
+	r.mutex.Lock()
+	jwks := r.jwks
+	expired := jwks != nil && r.now().Sub(r.fetchedAt) >= r.refreshInterval
+	r.mutex.Unlock()
+
+	if jwks == nil || expired {
+		// on refresh failure, the previously fetched set is kept until the next attempt
+		refreshed, err := r.refresh(ctx)
+		if refreshed == nil {
+			return nil, err
+		}
+
+		jwks = refreshed
+	}
+
+	key, err := jwks.Key(ctx, kid)
+	if err != nil {
+		refreshed, refreshErr := r.refresh(ctx)
+		if refreshErr != nil {
+			return nil, fmt.Errorf("%w: %w", err, refreshErr)
+		}
+
+		return refreshed.Key(ctx, kid)
+	}
+
+	return key, nil
+}
+
+// refresh fetches the set, once at a time and at most once per jwksMinRefreshInterval after a first fetch,
+// without holding the mutex during the fetch. It returns the current set, and the last refresh error.
+func (r *RemoteJWKS) refresh(ctx context.Context) (*JWKS, error) {
+	r.mutex.Lock()
+
+	if done := r.refreshing; done != nil {
+		r.mutex.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		return r.jwks, r.refreshErr
+	}
+
+	if r.jwks != nil && r.now().Sub(r.attemptedAt) < jwksMinRefreshInterval {
+		defer r.mutex.Unlock()
+
+		return r.jwks, r.refreshErr
+	}
+
+	done := make(chan struct{})
+	r.refreshing = done
+	r.attemptedAt = r.now()
+	url := r.url
+	r.mutex.Unlock()
+
+	jwks, url, err := r.fetchJWKS(ctx, url)
+	if err != nil {
+		log.CtxLogger(ctx).Warn().Err(err).Msg("cannot refresh jwks")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.refreshing = nil
+	r.refreshErr = err
+	close(done)
+
+	if err == nil {
+		r.url = url
+		r.jwks = jwks
+		r.fetchedAt = r.now()
+	}
+
+	return r.jwks, err
+}
+
+func (r *RemoteJWKS) fetchJWKS(ctx context.Context, url string) (*JWKS, string, error) {
+	if url == "" {
+		var discovery struct {
+			JwksUri string `json:"jwks_uri"`
+		}
+
+		if err := r.fetch(ctx, strings.TrimSuffix(r.issuer, "/")+oidcDiscoveryPath, &discovery); err != nil {
+			return nil, "", fmt.Errorf("cannot discover jwks url: %w", err)
+		}
+
+		if discovery.JwksUri == "" {
+			return nil, "", fmt.Errorf("cannot discover jwks url: missing jwks_uri")
+		}
+
+		url = discovery.JwksUri
+	}
+
+	var data json.RawMessage
+	if err := r.fetch(ctx, url, &data); err != nil {
+		return nil, "", fmt.Errorf("cannot fetch jwks: %w", err)
+	}
+
+	jwks, err := ParseJWKS(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return jwks, url, nil
+}
+
+func (r *RemoteJWKS) fetch(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	//nolint:errcheck
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func parseJsonWebKey(jwk jsonWebKey) (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key size")
+		}
+
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(jwk.K)
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,342 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJWKS(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	jwks, err := auth.ParseJWKS(marshalJWKS(
+		t,
+		rsaJWK("rsa", &rsaKey.PublicKey),
+		map[string]string{
+			"kty": "EC",
+			"kid": "ec",
+			"crv": "P-256",
+			"x":   encode(ecKey.X.Bytes()),
+			"y":   encode(ecKey.Y.Bytes()),
+		},
+		map[string]string{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": encode(edPublicKey)},
+		map[string]string{"kty": "oct", "kid": "hmac", "k": encode([]byte("secret"))},
+		map[string]string{"kty": "RSA", "kid": "enc", "use": "enc"},
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, jwks.Len())
+
+	key, err := jwks.Key(context.Background(), "rsa")
+	assert.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	key, err = jwks.Key(context.Background(), "ec")
+	assert.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+
+	key, err = jwks.Key(context.Background(), "ed")
+	assert.NoError(t, err)
+	assert.Equal(t, edPublicKey, key)
+
+	key, err = jwks.Key(context.Background(), "hmac")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), key)
+
+	_, err = jwks.Key(context.Background(), "enc")
+	assert.Error(t, err)
+	assert.Equal(t, "cannot find jwks key enc", err.Error())
+
+	// empty key id with several keys
+	_, err = jwks.Key(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestParseJWKSWithSingleKey(t *testing.T) {
+	t.Parallel()
+
+	jwks, err := auth.ParseJWKS(marshalJWKS(t, map[string]string{"kty": "oct", "kid": "hmac", "k": encode([]byte("secret"))}))
+	assert.NoError(t, err)
+
+	key, err := jwks.Key(context.Background(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), key)
+}
+
+func TestParseJWKSWithInvalidData(t *testing.T) {
+	t.Parallel()
+
+	_, err := auth.ParseJWKS([]byte("invalid"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot decode jwks")
+
+	_, err = auth.ParseJWKS(marshalJWKS(t, map[string]string{"kty": "invalid", "kid": "test"}))
+	assert.Error(t, err)
+	assert.Equal(t, "cannot parse jwks key test: unsupported key type invalid", err.Error())
+
+	_, err = auth.ParseJWKS(marshalJWKS(t, map[string]string{"kty": "EC", "kid": "test", "crv": "invalid"}))
+	assert.Error(t, err)
+	assert.Equal(t, "cannot parse jwks key test: unsupported curve invalid", err.Error())
+}
+
+func TestNewJWKSFromFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+
+	err := os.WriteFile(path, marshalJWKS(t, map[string]string{"kty": "oct", "kid": "hmac", "k": encode([]byte("secret"))}), 0o600)
+	assert.NoError(t, err)
+
+	jwks, err := auth.NewJWKSFromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, jwks.Len())
+
+	_, err = auth.NewJWKSFromFile(filepath.Join(t.TempDir(), "invalid.json"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read jwks file")
+}
+
+func TestRemoteJWKS(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	keys := []map[string]string{{"kty": "oct", "kid": "key1", "k": encode([]byte("secret1"))}}
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		//nolint:errcheck
+		w.Write(marshalJWKS(t, keys...))
+	}))
+	defer server.Close()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	jwks := auth.NewRemoteJWKS(
+		server.URL,
+		auth.WithRemoteJWKSClient(server.Client()),
+		auth.WithRemoteJWKSRefreshInterval(time.Minute),
+		auth.WithRemoteJWKSClock(clock.Now),
+	)
+
+	// lazy fetch
+	key, err := jwks.Key(context.Background(), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret1"), key)
+	assert.Equal(t, int32(1), hits.Load())
+
+	// cached
+	_, err = jwks.Key(context.Background(), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+
+	// key rotation
+	mutex.Lock()
+	keys = append(keys, map[string]string{"kty": "oct", "kid": "key2", "k": encode([]byte("secret2"))})
+	mutex.Unlock()
+
+	// unknown key id within the minimum refresh interval
+	_, err = jwks.Key(context.Background(), "key2")
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+
+	// unknown key id after the minimum refresh interval
+	clock.Advance(10 * time.Second)
+
+	key, err = jwks.Key(context.Background(), "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret2"), key)
+	assert.Equal(t, int32(2), hits.Load())
+
+	// refresh interval
+	clock.Advance(time.Minute)
+
+	_, err = jwks.Key(context.Background(), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestRemoteJWKSWithFailure(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		//nolint:errcheck
+		w.Write(marshalJWKS(t, map[string]string{"kty": "oct", "kid": "key1", "k": encode([]byte("secret1"))}))
+	}))
+	defer server.Close()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	jwks := auth.NewRemoteJWKS(
+		server.URL,
+		auth.WithRemoteJWKSRefreshInterval(time.Minute),
+		auth.WithRemoteJWKSClock(clock.Now),
+	)
+
+	failing.Store(true)
+
+	_, err := jwks.Key(context.Background(), "key1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot fetch jwks: unexpected status 500")
+
+	failing.Store(false)
+
+	_, err = jwks.Key(context.Background(), "key1")
+	assert.NoError(t, err)
+
+	// previously fetched set kept on refresh failure
+	failing.Store(true)
+	clock.Advance(time.Minute)
+
+	key, err := jwks.Key(context.Background(), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret1"), key)
+
+	// refresh failure reported for unknown key ids
+	_, err = jwks.Key(context.Background(), "key2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot find jwks key key2")
+	assert.Contains(t, err.Error(), "cannot fetch jwks: unexpected status 500")
+}
+
+func TestRemoteJWKSWithConcurrentFetch(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		<-release
+
+		//nolint:errcheck
+		w.Write(marshalJWKS(t, map[string]string{"kty": "oct", "kid": "key1", "k": encode([]byte("secret1"))}))
+	}))
+	defer server.Close()
+
+	jwks := auth.NewRemoteJWKS(server.URL, auth.WithRemoteJWKSClient(server.Client()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			key, err := jwks.Key(context.Background(), "key1")
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("secret1"), key)
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		return hits.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestOIDCJWKS(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		//nolint:errcheck
+		w.Write([]byte(`{"issuer":"` + server.URL + `","jwks_uri":"` + server.URL + `/keys"}`))
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		//nolint:errcheck
+		w.Write(marshalJWKS(t, map[string]string{"kty": "oct", "kid": "key1", "k": encode([]byte("secret1"))}))
+	})
+
+	key, err := auth.NewOIDCJWKS(server.URL+"/").Key(context.Background(), "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret1"), key)
+
+	_, err = auth.NewOIDCJWKS(server.URL+"/invalid").Key(context.Background(), "key1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot discover jwks url")
+}
+
+type testClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"n":   encode(key.N.Bytes()),
+		"e":   encode(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func marshalJWKS(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	assert.NoError(t, err)
+
+	return data
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DefaultJWTAuthenticatorName = "jwt"
+	DefaultJWTSubjectClaim      = "sub"
+	DefaultJWTScopesClaim       = "scope"
+	DefaultJWTRolesClaim        = "roles"
+	bearerScheme                = "Bearer"
+)
+
+// DefaultJWTAlgorithms are the JWT signing algorithms accepted by default by the [JWTAuthenticator].
+var DefaultJWTAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// JWTAuthenticator is an [Authenticator] validating JWT bearer tokens, signed by a key of a [KeySet].
+type JWTAuthenticator struct {
+	keys    KeySet
+	options jwtAuthenticatorOptions
+	parser  *jwt.Parser
+}
+
+type jwtAuthenticatorOptions struct {
+	Name          string
+	Issuer        string
+	Audience      []string
+	Algorithms    []string
+	Leeway        time.Duration
+	SubjectClaim  string
+	ScopesClaim   string
+	RolesClaim    string
+	TokenLookupFn func(c echo.Context) string
+}
+
+// JWTAuthenticatorOption are functional options for the [JWTAuthenticator].
+type JWTAuthenticatorOption func(o *jwtAuthenticatorOptions)
+
+// WithJWTName is used to specify the authenticator name (default jwt).
+func WithJWTName(name string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.Name = name
+	}
+}
+
+// WithJWTIssuer is used to specify the expected token issuer (iss claim).
+func WithJWTIssuer(issuer string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.Issuer = issuer
+	}
+}
+
+// WithJWTAudience is used to specify the accepted token audiences (aud claim): the token must contain one of them.
+func WithJWTAudience(audience ...string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.Audience = audience
+	}
+}
+
+// WithJWTAlgorithms is used to specify the accepted signing algorithms (default [DefaultJWTAlgorithms]).
+func WithJWTAlgorithms(algorithms ...string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.Algorithms = algorithms
+	}
+}
+
+// WithJWTLeeway is used to specify the leeway applied on the time based claims validations.
+func WithJWTLeeway(leeway time.Duration) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.Leeway = leeway
+	}
+}
+
+// WithJWTSubjectClaim is used to specify the claim holding the principal id (default sub).
+func WithJWTSubjectClaim(claim string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.SubjectClaim = claim
+	}
+}
+
+// WithJWTScopesClaim is used to specify the claim holding the principal scopes (default scope).
+// The claim can be a space separated string or a list, and nested claims can be addressed with dots.
+func WithJWTScopesClaim(claim string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.ScopesClaim = claim
+	}
+}
+
+// WithJWTRolesClaim is used to specify the claim holding the principal roles (default roles).
+// The claim can be a space separated string or a list, and nested claims can be addressed with dots,
+// for example realm_access.roles.
+func WithJWTRolesClaim(claim string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.RolesClaim = claim
+	}
+}
+
+// WithJWTTokenLookup is used to specify how the token is extracted from the request
+// (from the Authorization bearer header by default).
+func WithJWTTokenLookup(lookup func(c echo.Context) string) JWTAuthenticatorOption {
+	return func(o *jwtAuthenticatorOptions) {
+		o.TokenLookupFn = lookup
+	}
+}
+
+// NewJWTAuthenticator returns a new [JWTAuthenticator], for a provided [KeySet].
+func NewJWTAuthenticator(keys KeySet, options ...JWTAuthenticatorOption) *JWTAuthenticator {
+	appliedOptions := jwtAuthenticatorOptions{
+		Name:          DefaultJWTAuthenticatorName,
+		Algorithms:    DefaultJWTAlgorithms,
+		SubjectClaim:  DefaultJWTSubjectClaim,
+		ScopesClaim:   DefaultJWTScopesClaim,
+		RolesClaim:    DefaultJWTRolesClaim,
+		TokenLookupFn: BearerToken,
+	}
+
+	for _, opt := range options {
+		opt(&appliedOptions)
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(appliedOptions.Algorithms),
+		jwt.WithLeeway(appliedOptions.Leeway),
+	}
+
+	if appliedOptions.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(appliedOptions.Issuer))
+	}
+
+	return &JWTAuthenticator{
+		keys:    keys,
+		options: appliedOptions,
+		parser:  jwt.NewParser(parserOptions...),
+	}
+}
+
+// Name returns the authenticator name.
+func (a *JWTAuthenticator) Name() string {
+	return a.options.Name
+}
+
+// Challenge returns the WWW-Authenticate challenge.
+func (a *JWTAuthenticator) Challenge() string {
+	return bearerScheme
+}
+
+// Authenticate validates the request JWT, and returns the [Principal] built from its claims.
+func (a *JWTAuthenticator) Authenticate(c echo.Context) (*Principal, error) {
+	tokenString := a.options.TokenLookupFn(c)
+	if tokenString == "" {
+		return nil, ErrNoCredentials
+	}
+
+	ctx := c.Request().Context()
+
+	claims := jwt.MapClaims{}
+
+	_, err := a.parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		return a.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	if len(a.options.Audience) > 0 {
+		audience, err := claims.GetAudience()
+		if err != nil || !containsAny(audience, a.options.Audience) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, jwt.ErrTokenInvalidAudience)
+		}
+	}
+
+	subject, _ := lookupClaim(claims, a.options.SubjectClaim).(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, errors.New("missing subject"))
+	}
+
+	return &Principal{
+		ID:            subject,
+		Authenticator: a.options.Name,
+		Scopes:        claimValues(lookupClaim(claims, a.options.ScopesClaim)),
+		Roles:         claimValues(lookupClaim(claims, a.options.RolesClaim)),
+		Claims:        claims,
+	}, nil
+}
+
+// BearerToken extracts the bearer token from the request Authorization header.
+func BearerToken(c echo.Context) string {
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+
+	if len(header) > len(bearerScheme) && strings.EqualFold(header[:len(bearerScheme)+1], bearerScheme+" ") {
+		return strings.TrimSpace(header[len(bearerScheme)+1:])
+	}
+
+	return ""
+}
+
+// lookupClaim returns a claim value, nested claims being addressed with dots.
+func lookupClaim(claims map[string]any, name string) any {
+	if value, ok := claims[name]; ok {
+		return value
+	}
+
+	current := claims
+	parts := strings.Split(name, ".")
+
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil
+		}
+
+		if i == len(parts)-1 {
+			return value
+		}
+
+		if current, ok = value.(map[string]any); !ok {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func claimValues(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []any:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+func containsAny(values []string, expected []string) bool {
+	for _, e := range expected {
+		for _, v := range values {
+			if v == e {
+				return true
+			}
+		}
+	}
+
+	return false
+}
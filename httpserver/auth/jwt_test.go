@@ -0,0 +1,187 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTAuthenticator(t *testing.T) {
+	t.Parallel()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	jwks, err := auth.ParseJWKS(marshalJWKS(t, rsaJWK("test", &privateKey.PublicKey)))
+	assert.NoError(t, err)
+
+	authenticator := auth.NewJWTAuthenticator(
+		jwks,
+		auth.WithJWTIssuer("https://issuer.example.com"),
+		auth.WithJWTAudience("other", "api"),
+		auth.WithJWTRolesClaim("realm_access.roles"),
+	)
+
+	assert.Equal(t, auth.DefaultJWTAuthenticatorName, authenticator.Name())
+	assert.Equal(t, "Bearer", authenticator.Challenge())
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":          "alice",
+			"iss":          "https://issuer.example.com",
+			"aud":          []string{"api"},
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"scope":        "read write",
+			"realm_access": map[string]any{"roles": []string{"admin"}},
+		}
+	}
+
+	// valid token
+	principal, err := authenticator.Authenticate(createJWTContext(signJWT(t, jwt.SigningMethodRS256, "test", privateKey, validClaims())))
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", principal.ID)
+	assert.Equal(t, "jwt", principal.Authenticator)
+	assert.Equal(t, []string{"read", "write"}, principal.Scopes)
+	assert.Equal(t, []string{"admin"}, principal.Roles)
+	assert.Equal(t, "https://issuer.example.com", principal.Claims["iss"])
+
+	// missing token
+	_, err = authenticator.Authenticate(createJWTContext(""))
+	assert.ErrorIs(t, err, auth.ErrNoCredentials)
+
+	// malformed token
+	_, err = authenticator.Authenticate(createJWTContext("invalid"))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	// expired token
+	claims := validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	_, err = authenticator.Authenticate(createJWTContext(signJWT(t, jwt.SigningMethodRS256, "test", privateKey, claims)))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+
+	// invalid issuer
+	claims = validClaims()
+	claims["iss"] = "https://other.example.com"
+
+	_, err = authenticator.Authenticate(createJWTContext(signJWT(t, jwt.SigningMethodRS256, "test", privateKey, claims)))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+
+	// invalid audience
+	claims = validClaims()
+	claims["aud"] = "invalid"
+
+	_, err = authenticator.Authenticate(createJWTContext(signJWT(t, jwt.SigningMethodRS256, "test", privateKey, claims)))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	// missing subject
+	claims = validClaims()
+	delete(claims, "sub")
+
+	_, err = authenticator.Authenticate(createJWTContext(signJWT(t, jwt.SigningMethodRS256, "test", privateKey, claims)))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	// unknown key
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	_, err = authenticator.Authenticate(createJWTContext(signJWT(t, jwt.SigningMethodRS256, "other", otherKey, validClaims())))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	// invalid signature
+	_, err = authenticator.Authenticate(createJWTContext(signJWT(t, jwt.SigningMethodRS256, "test", otherKey, validClaims())))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
+
+func TestJWTAuthenticatorWithOptions(t *testing.T) {
+	t.Parallel()
+
+	jwks, err := auth.ParseJWKS(marshalJWKS(t, map[string]string{"kty": "oct", "kid": "hmac", "k": encode([]byte("secret"))}))
+	assert.NoError(t, err)
+
+	authenticator := auth.NewJWTAuthenticator(
+		jwks,
+		auth.WithJWTName("custom"),
+		auth.WithJWTAlgorithms("HS256"),
+		auth.WithJWTLeeway(time.Minute),
+		auth.WithJWTSubjectClaim("client_id"),
+		auth.WithJWTScopesClaim("scp"),
+		auth.WithJWTRolesClaim("groups"),
+		auth.WithJWTTokenLookup(func(c echo.Context) string {
+			return c.QueryParam("token")
+		}),
+	)
+
+	assert.Equal(t, "custom", authenticator.Name())
+
+	token := signJWT(t, jwt.SigningMethodHS256, "hmac", []byte("secret"), jwt.MapClaims{
+		"client_id": "service",
+		"exp":       time.Now().Add(-30 * time.Second).Unix(),
+		"scp":       []string{"read"},
+		"groups":    "ops dev",
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/?token="+token, nil)
+
+	principal, err := authenticator.Authenticate(echo.New().NewContext(req, httptest.NewRecorder()))
+	assert.NoError(t, err)
+	assert.Equal(t, "service", principal.ID)
+	assert.Equal(t, "custom", principal.Authenticator)
+	assert.Equal(t, []string{"read"}, principal.Scopes)
+	assert.Equal(t, []string{"ops", "dev"}, principal.Roles)
+
+	// not accepted algorithm
+	token = signJWT(t, jwt.SigningMethodHS512, "hmac", []byte("secret"), jwt.MapClaims{"client_id": "service"})
+	req = httptest.NewRequest(http.MethodGet, "/?token="+token, nil)
+
+	_, err = authenticator.Authenticate(echo.New().NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "token", auth.BearerToken(createJWTContext("token")))
+	assert.Equal(t, "", auth.BearerToken(createJWTContext("")))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, "bearer token")
+	assert.Equal(t, "token", auth.BearerToken(echo.New().NewContext(req, httptest.NewRecorder())))
+
+	req.Header.Set(echo.HeaderAuthorization, "Basic dGVzdDp0ZXN0")
+	assert.Equal(t, "", auth.BearerToken(echo.New().NewContext(req, httptest.NewRecorder())))
+}
+
+func createJWTContext(token string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func signJWT(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+
+	return signed
+}
@@ -0,0 +1,33 @@
+package httpserver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ankorstore/yokai/generate/sqids"
+	"github.com/labstack/echo/v4"
+)
+
+// BindSqidsPathParam decodes the path param of a given name, encoded by a given [sqids.SqidsEncoder], into an int64.
+//
+// It returns an [echo.BindingError] (400 Bad Request) if the path param is missing, invalid, or does not encode a single int64.
+func BindSqidsPathParam(c echo.Context, encoder sqids.SqidsEncoder, name string) (int64, error) {
+	var id int64
+
+	err := echo.PathParamsBinder(c).MustCustomFunc(name, func(values []string) []error {
+		numbers, err := encoder.Decode(values[0])
+		if err != nil {
+			return []error{echo.NewBindingError(name, values, "failed to decode sqids id", err)}
+		}
+
+		if len(numbers) != 1 || numbers[0] > math.MaxInt64 {
+			return []error{echo.NewBindingError(name, values, "failed to decode sqids id", fmt.Errorf("sqids id %q does not encode a single int64", values[0]))}
+		}
+
+		id = int64(numbers[0])
+
+		return nil
+	}).BindError()
+
+	return id, err
+}
@@ -0,0 +1,95 @@
+package httpserver_test
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/generate/sqids"
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindSqidsPathParam(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := sqids.NewDefaultSqidsEncoderFactory().Create(sqids.WithMinLength(8))
+	assert.NoError(t, err)
+
+	validId, err := encoder.Encode(42)
+	assert.NoError(t, err)
+
+	multipleId, err := encoder.Encode(1, 2)
+	assert.NoError(t, err)
+
+	overflowId, err := encoder.Encode(math.MaxInt64 + 1)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		paramNames  []string
+		paramValue  string
+		expectedId  int64
+		expectedErr string
+	}{
+		{
+			name:       "valid id",
+			paramNames: []string{"id"},
+			paramValue: validId,
+			expectedId: 42,
+		},
+		{
+			name:        "missing id",
+			paramNames:  []string{"other"},
+			paramValue:  validId,
+			expectedErr: "code=400, message=required field value is empty, field=id",
+		},
+		{
+			name:        "invalid id",
+			paramNames:  []string{"id"},
+			paramValue:  "invalid-id",
+			expectedErr: `code=400, message=failed to decode sqids id, internal=invalid sqids id "invalid-id", field=id`,
+		},
+		{
+			name:        "multiple numbers id",
+			paramNames:  []string{"id"},
+			paramValue:  multipleId,
+			expectedErr: "does not encode a single int64",
+		},
+		{
+			name:        "overflowing id",
+			paramNames:  []string{"id"},
+			paramValue:  overflowId,
+			expectedErr: "does not encode a single int64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames(tt.paramNames...)
+			c.SetParamValues(tt.paramValue)
+
+			id, err := httpserver.BindSqidsPathParam(c, encoder, "id")
+
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedId, id)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+
+				var bindingErr *echo.BindingError
+				assert.ErrorAs(t, err, &bindingErr)
+				assert.Equal(t, http.StatusBadRequest, bindingErr.Code)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 // JsonErrorHandler provides a [echo.HTTPErrorHandler] that outputs errors in JSON format.
 // It can also be configured to obfuscate error message (to avoid to leak sensitive details), and to add the error stack to the response.
+// The invalid parameters of a [ValidationError] are added to the response, under errors.
 type JsonErrorHandler struct {
 	obfuscate bool
 	stack     bool
@@ -90,6 +91,12 @@ func (h *JsonErrorHandler) Handle() echo.HTTPErrorHandler {
 			httpRespFields["message"] = http.StatusText(httpError.Code)
 		}
 
+		// validation details are intended for the clients, and are not obfuscated
+		var validationError *ValidationError
+		if errors.As(err, &validationError) && len(validationError.InvalidParams) > 0 {
+			httpRespFields["errors"] = validationError.InvalidParams
+		}
+
 		var httpRespErr error
 		if c.Request().Method == http.MethodHead {
 			httpRespErr = c.NoContent(httpError.Code)
@@ -530,3 +530,43 @@ func TestErrorHandlingWithInternalHttpErrorWithObfuscateAndStack(t *testing.T) {
 		"message": "error handler",
 	})
 }
+
+func TestErrorHandlingWithValidationError(t *testing.T) {
+	t.Parallel()
+
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.Logger = httpserver.NewEchoLogger(logger)
+	httpServer.HTTPErrorHandler = httpserver.NewJsonErrorHandler(true, false).Handle()
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		validationErr := httpserver.NewValidationError(
+			"invalid request",
+			httpserver.InvalidParam{Name: "page", In: "query", Reason: "must be positive"},
+		)
+
+		return echo.NewHTTPError(http.StatusBadRequest, validationErr.Error()).SetInternal(validationErr)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req = req.WithContext(logger.WithContext(context.Background()))
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(
+		t,
+		`{"message":"Bad Request","errors":[{"name":"page","in":"query","reason":"must be positive"}]}`,
+		rec.Body.String(),
+	)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "error",
+		"message": "error handler",
+	})
+}
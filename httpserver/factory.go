@@ -1,6 +1,8 @@
 package httpserver
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 )
 
@@ -29,8 +31,14 @@ func NewDefaultHttpServerFactory() HttpServerFactory {
 //		httpserver.WithBanner(false),                                 // banner disabled by default
 //		httpserver.WithLogger(log.New("default")),                    // echo default logger
 //		httpserver.WithBinder(&echo.DefaultBinder{}),                 // echo default binder
+//		httpserver.WithValidator(nil),                                // no validator
 //		httpserver.WithJsonSerializer(&echo.DefaultJSONSerializer{}), // echo default json serializer
 //		httpserver.WithHttpErrorHandler(nil),                         // echo default error handler
+//		httpserver.WithReadTimeout(0),                                // no read timeout
+//		httpserver.WithReadHeaderTimeout(0),                          // no read header timeout (read timeout applied)
+//		httpserver.WithWriteTimeout(0),                               // no write timeout
+//		httpserver.WithIdleTimeout(0),                                // no idle timeout (read timeout applied)
+//		httpserver.WithMaxHeaderBytes(0),                             // net/http default max header bytes
 //	)
 func (f *DefaultHttpServerFactory) Create(options ...HttpServerOption) (*echo.Echo, error) {
 	appliedOpts := DefaultHttpServerOptions()
@@ -45,6 +53,7 @@ func (f *DefaultHttpServerFactory) Create(options ...HttpServerOption) (*echo.Ec
 
 	httpServer.Logger = appliedOpts.Logger
 	httpServer.Binder = appliedOpts.Binder
+	httpServer.Validator = appliedOpts.Validator
 	httpServer.JSONSerializer = appliedOpts.JsonSerializer
 
 	if appliedOpts.HttpErrorHandler != nil {
@@ -55,5 +64,13 @@ func (f *DefaultHttpServerFactory) Create(options ...HttpServerOption) (*echo.Ec
 		httpServer.Renderer = appliedOpts.Renderer
 	}
 
+	for _, server := range []*http.Server{httpServer.Server, httpServer.TLSServer} {
+		server.ReadTimeout = appliedOpts.ReadTimeout
+		server.ReadHeaderTimeout = appliedOpts.ReadHeaderTimeout
+		server.WriteTimeout = appliedOpts.WriteTimeout
+		server.IdleTimeout = appliedOpts.IdleTimeout
+		server.MaxHeaderBytes = appliedOpts.MaxHeaderBytes
+	}
+
 	return httpServer, nil
 }
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/httpserver"
 	"github.com/ankorstore/yokai/httpserver/middleware"
@@ -45,6 +46,7 @@ func TestCreate(t *testing.T) {
 
 	echoLogger := httpserver.NewEchoLogger(logger)
 	binder := &echo.DefaultBinder{}
+	validator := &testValidator{}
 	jsonSerializer := &echo.DefaultJSONSerializer{}
 	httpErrorHandler := func(err error, c echo.Context) {}
 	render := httpserver.NewHtmlTemplateRenderer("testdata/templates/*.html")
@@ -54,6 +56,7 @@ func TestCreate(t *testing.T) {
 		httpserver.WithBanner(true),
 		httpserver.WithLogger(echoLogger),
 		httpserver.WithBinder(binder),
+		httpserver.WithValidator(validator),
 		httpserver.WithJsonSerializer(jsonSerializer),
 		httpserver.WithHttpErrorHandler(httpErrorHandler),
 		httpserver.WithRenderer(render),
@@ -66,11 +69,33 @@ func TestCreate(t *testing.T) {
 	assert.False(t, httpServer.HideBanner)
 	assert.Equal(t, echoLogger, httpServer.Logger)
 	assert.Equal(t, binder, httpServer.Binder)
+	assert.Equal(t, validator, httpServer.Validator)
 	assert.Equal(t, jsonSerializer, httpServer.JSONSerializer)
 	assert.NotNil(t, httpServer.HTTPErrorHandler)
 	assert.NotNil(t, httpServer.Renderer)
 }
 
+func TestCreateWithServerTimeouts(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := httpserver.NewDefaultHttpServerFactory().Create(
+		httpserver.WithReadTimeout(1*time.Second),
+		httpserver.WithReadHeaderTimeout(2*time.Second),
+		httpserver.WithWriteTimeout(3*time.Second),
+		httpserver.WithIdleTimeout(4*time.Second),
+		httpserver.WithMaxHeaderBytes(1024),
+	)
+	assert.NoError(t, err)
+
+	for _, server := range []*http.Server{httpServer.Server, httpServer.TLSServer} {
+		assert.Equal(t, 1*time.Second, server.ReadTimeout)
+		assert.Equal(t, 2*time.Second, server.ReadHeaderTimeout)
+		assert.Equal(t, 3*time.Second, server.WriteTimeout)
+		assert.Equal(t, 4*time.Second, server.IdleTimeout)
+		assert.Equal(t, 1024, server.MaxHeaderBytes)
+	}
+}
+
 func TestCreateWithRequestLoggerAndTracerAndErrorHandlerOn2xx(t *testing.T) {
 	t.Parallel()
 
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 
+	"github.com/ankorstore/yokai/httpserver"
 	"github.com/labstack/echo/v4"
 )
 
@@ -12,3 +13,11 @@ func DebugRoutesHandler(httpServer *echo.Echo) echo.HandlerFunc {
 		return c.JSON(http.StatusOK, httpServer.Routes())
 	}
 }
+
+// DebugRoutesWithInfoHandler is an [echo.HandlerFunc] that returns routing information, with the routes metadata
+// collected in a [httpserver.RoutesInfo].
+func DebugRoutesWithInfoHandler(httpServer *echo.Echo, routesInfo *httpserver.RoutesInfo) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, routesInfo.Routes(httpServer))
+	}
+}
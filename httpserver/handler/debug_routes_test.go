@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/ankorstore/yokai/httpserver"
 	"github.com/ankorstore/yokai/httpserver/handler"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -23,3 +24,30 @@ func TestDebugRoutesHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Contains(t, rec.Body.String(), `"method":"GET","path":"/debug/routes"`)
 }
+
+func TestDebugRoutesWithInfoHandler(t *testing.T) {
+	t.Parallel()
+
+	routesInfo := httpserver.NewRoutesInfo()
+	routesInfo.Add(httpserver.RouteInfo{
+		Method:      http.MethodGet,
+		Path:        "/debug/routes",
+		Tags:        []string{"debug"},
+		Description: "debug routes",
+		Metadata:    map[string]any{"internal": true},
+	})
+
+	httpServer := echo.New()
+	httpServer.GET("/debug/routes", handler.DebugRoutesWithInfoHandler(httpServer, routesInfo)).Name = "debug-routes"
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/routes", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(
+		t,
+		`[{"method":"GET","path":"/debug/routes","name":"debug-routes","tags":["debug"],"description":"debug routes","metadata":{"internal":true}}]`,
+		rec.Body.String(),
+	)
+}
@@ -2,15 +2,196 @@ package handler
 
 import (
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
 	"github.com/labstack/echo/v4"
 )
 
+// MIMEApplicationHealthJSON is the media type of the [health check response format for HTTP APIs].
+//
+// [health check response format for HTTP APIs]: https://datatracker.ietf.org/doc/html/draft-inadarei-api-health-check
+const MIMEApplicationHealthJSON = "application/health+json"
+
+// HealthCheckVerbosity is an enum for the supported verbosity levels of the [HealthCheckHandler] responses.
+type HealthCheckVerbosity int
+
+const (
+	HealthCheckFullVerbosity    HealthCheckVerbosity = iota // status and probes details
+	HealthCheckMinimalVerbosity                             // status only
+)
+
+// String returns a string representation of the [HealthCheckVerbosity].
+func (v HealthCheckVerbosity) String() string {
+	switch v {
+	case HealthCheckMinimalVerbosity:
+		return "minimal"
+	default:
+		return "full"
+	}
+}
+
+// FetchHealthCheckVerbosity returns a [HealthCheckVerbosity] for a given value.
+func FetchHealthCheckVerbosity(v string) HealthCheckVerbosity {
+	switch strings.ToLower(v) {
+	case "minimal":
+		return HealthCheckMinimalVerbosity
+	default:
+		return HealthCheckFullVerbosity
+	}
+}
+
+// HealthCheckVerbosityResolver resolves the [HealthCheckVerbosity] to use for a request.
+type HealthCheckVerbosityResolver func(c echo.Context) HealthCheckVerbosity
+
+// NewNetworksHealthCheckVerbosityResolver returns a [HealthCheckVerbosityResolver] resolving the full verbosity
+// for requests coming from the provided networks (CIDR notation), and the minimal verbosity otherwise.
+// The request remote address is used, to not rely on headers that can be forged by the callers.
+func NewNetworksHealthCheckVerbosityResolver(networks ...string) (HealthCheckVerbosityResolver, error) {
+	ipNets := make([]*net.IPNet, 0, len(networks))
+
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid healthcheck verbosity network %q: %w", network, err)
+		}
+
+		ipNets = append(ipNets, ipNet)
+	}
+
+	return func(c echo.Context) HealthCheckVerbosity {
+		host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+		if err != nil {
+			host = c.Request().RemoteAddr
+		}
+
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return HealthCheckMinimalVerbosity
+		}
+
+		for _, ipNet := range ipNets {
+			if ipNet.Contains(ip) {
+				return HealthCheckFullVerbosity
+			}
+		}
+
+		return HealthCheckMinimalVerbosity
+	}, nil
+}
+
+// NewAuthenticatedHealthCheckVerbosityResolver returns a [HealthCheckVerbosityResolver] resolving the full verbosity
+// for authenticated requests, and the minimal verbosity otherwise. A request is authenticated if it carries an
+// [auth.Principal] resolved by the authentication middleware, or valid credentials for one of the provided authenticators.
+func NewAuthenticatedHealthCheckVerbosityResolver(authenticators ...auth.Authenticator) HealthCheckVerbosityResolver {
+	return func(c echo.Context) HealthCheckVerbosity {
+		if auth.CtxPrincipal(c.Request().Context()) != nil {
+			return HealthCheckFullVerbosity
+		}
+
+		for _, authenticator := range authenticators {
+			principal, err := authenticator.Authenticate(c)
+			if err == nil && principal != nil {
+				return HealthCheckFullVerbosity
+			}
+		}
+
+		return HealthCheckMinimalVerbosity
+	}
+}
+
+// NewAnyHealthCheckVerbosityResolver returns a [HealthCheckVerbosityResolver] resolving the full verbosity if one
+// of the provided resolvers does, and the minimal verbosity otherwise.
+func NewAnyHealthCheckVerbosityResolver(resolvers ...HealthCheckVerbosityResolver) HealthCheckVerbosityResolver {
+	return func(c echo.Context) HealthCheckVerbosity {
+		for _, resolver := range resolvers {
+			if resolver(c) == HealthCheckFullVerbosity {
+				return HealthCheckFullVerbosity
+			}
+		}
+
+		return HealthCheckMinimalVerbosity
+	}
+}
+
+// HealthCheckHandlerOptions are options for the [HealthCheckHandler].
+type HealthCheckHandlerOptions struct {
+	VerbosityResolver HealthCheckVerbosityResolver
+	ServiceId         string
+	Version           string
+	Description       string
+}
+
+// DefaultHealthCheckHandlerOptions are the default options used in the [HealthCheckHandler].
+func DefaultHealthCheckHandlerOptions() HealthCheckHandlerOptions {
+	return HealthCheckHandlerOptions{
+		VerbosityResolver: func(echo.Context) HealthCheckVerbosity {
+			return HealthCheckFullVerbosity
+		},
+	}
+}
+
+// HealthCheckHandlerOption are functional options for the [HealthCheckHandler].
+type HealthCheckHandlerOption func(o *HealthCheckHandlerOptions)
+
+// WithHealthCheckVerbosity is used to specify a fixed [HealthCheckVerbosity] for all requests.
+func WithHealthCheckVerbosity(v HealthCheckVerbosity) HealthCheckHandlerOption {
+	return func(o *HealthCheckHandlerOptions) {
+		o.VerbosityResolver = func(echo.Context) HealthCheckVerbosity {
+			return v
+		}
+	}
+}
+
+// WithHealthCheckVerbosityResolver is used to specify a [HealthCheckVerbosityResolver], to resolve the verbosity per request.
+func WithHealthCheckVerbosityResolver(r HealthCheckVerbosityResolver) HealthCheckHandlerOption {
+	return func(o *HealthCheckHandlerOptions) {
+		o.VerbosityResolver = r
+	}
+}
+
+// WithHealthCheckService is used to specify the service id, version and description of the application/health+json responses.
+func WithHealthCheckService(serviceId string, version string, description string) HealthCheckHandlerOption {
+	return func(o *HealthCheckHandlerOptions) {
+		o.ServiceId = serviceId
+		o.Version = version
+		o.Description = description
+	}
+}
+
+// HealthCheckResponse is the application/health+json response of the [HealthCheckHandler].
+type HealthCheckResponse struct {
+	Status      string                                `json:"status"`
+	Version     string                                `json:"version,omitempty"`
+	ServiceId   string                                `json:"serviceId,omitempty"`
+	Description string                                `json:"description,omitempty"`
+	Checks      map[string][]HealthCheckResponseCheck `json:"checks,omitempty"`
+}
+
+// HealthCheckResponseCheck is a probe check of the [HealthCheckResponse].
+type HealthCheckResponseCheck struct {
+	ComponentId   string  `json:"componentId"`
+	Status        string  `json:"status"`
+	Time          string  `json:"time"`
+	ObservedValue float64 `json:"observedValue"`
+	ObservedUnit  string  `json:"observedUnit"`
+	Output        string  `json:"output,omitempty"`
+}
+
 // HealthCheckHandler is an [echo.HandlerFunc] returns the execution result of a [healthcheck.Checker] for a [healthcheck.ProbeKind].
-func HealthCheckHandler(checker *healthcheck.Checker, kind healthcheck.ProbeKind) echo.HandlerFunc {
+// The response is rendered as application/health+json if requested by content negotiation, as JSON otherwise.
+func HealthCheckHandler(checker *healthcheck.Checker, kind healthcheck.ProbeKind, options ...HealthCheckHandlerOption) echo.HandlerFunc {
+	appliedOptions := DefaultHealthCheckHandlerOptions()
+	for _, opt := range options {
+		opt(&appliedOptions)
+	}
+
 	return func(c echo.Context) error {
 		result := checker.Check(c.Request().Context(), kind)
 
@@ -24,8 +205,122 @@ func HealthCheckHandler(checker *healthcheck.Checker, kind healthcheck.ProbeKind
 			}
 
 			evt.Msg("healthcheck failure")
+		} else if result.Status == healthcheck.Degraded {
+			evt := httpserver.CtxLogger(c).Warn()
+			for probeName, probeResult := range result.ProbesResults {
+				evt.Str(probeName, fmt.Sprintf("success: %v, message: %s", probeResult.Success, probeResult.Message))
+			}
+
+			evt.Msg("healthcheck degraded")
+		}
+
+		verbosity := appliedOptions.VerbosityResolver(c)
+
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+		if acceptsHealthJSON(c.Request().Header.Get(echo.HeaderAccept)) {
+			// preset content type is kept by c.JSON
+			c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationHealthJSON)
+
+			return c.JSON(status, createHealthCheckResponse(result, verbosity, appliedOptions))
+		}
+
+		if verbosity == HealthCheckMinimalVerbosity {
+			return c.JSON(status, struct {
+				Success bool                      `json:"success"`
+				Status  healthcheck.CheckerStatus `json:"status"`
+			}{
+				Success: result.Success,
+				Status:  result.Status,
+			})
 		}
 
 		return c.JSON(status, result)
 	}
 }
+
+func createHealthCheckResponse(
+	result *healthcheck.CheckerResult,
+	verbosity HealthCheckVerbosity,
+	options HealthCheckHandlerOptions,
+) *HealthCheckResponse {
+	response := &HealthCheckResponse{
+		Status: healthCheckResponseStatus(result.Status),
+	}
+
+	if verbosity == HealthCheckMinimalVerbosity {
+		return response
+	}
+
+	response.Version = options.Version
+	response.ServiceId = options.ServiceId
+	response.Description = options.Description
+	response.Checks = make(map[string][]HealthCheckResponseCheck, len(result.ProbesResults))
+
+	checkTime := time.Now().UTC().Format(time.RFC3339)
+
+	for probeName, probeResult := range result.ProbesResults {
+		check := HealthCheckResponseCheck{
+			ComponentId:   probeName,
+			Status:        "pass",
+			Time:          checkTime,
+			ObservedValue: float64(probeResult.Duration.Microseconds()) / 1000,
+			ObservedUnit:  "ms",
+		}
+
+		if !probeResult.Success {
+			check.Status = "fail"
+			if !probeResult.Critical {
+				check.Status = "warn"
+			}
+
+			check.Output = probeResult.Message
+			if probeResult.Error != "" {
+				check.Output = fmt.Sprintf("%s: %s", probeResult.Message, probeResult.Error)
+			}
+		}
+
+		response.Checks[probeName+":responseTime"] = []HealthCheckResponseCheck{check}
+	}
+
+	return response
+}
+
+func healthCheckResponseStatus(status healthcheck.CheckerStatus) string {
+	switch status {
+	case healthcheck.Degraded:
+		return "warn"
+	case healthcheck.Unhealthy:
+		return "fail"
+	default:
+		return "pass"
+	}
+}
+
+// acceptsHealthJSON returns true if application/health+json is preferred over application/json in an Accept header.
+func acceptsHealthJSON(accept string) bool {
+	healthJSONQuality := -1.0
+	jsonQuality := -1.0
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		parts := strings.Split(mediaRange, ";")
+
+		quality := 1.0
+		for _, param := range parts[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(parts[0])) {
+		case MIMEApplicationHealthJSON:
+			healthJSONQuality = quality
+		case echo.MIMEApplicationJSON:
+			jsonQuality = quality
+		}
+	}
+
+	return healthJSONQuality > 0 && healthJSONQuality >= jsonQuality
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/ankorstore/yokai/healthcheck"
 	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
 	"github.com/ankorstore/yokai/httpserver/handler"
 	"github.com/ankorstore/yokai/httpserver/testdata/probes"
 	"github.com/ankorstore/yokai/log"
@@ -43,10 +45,10 @@ func TestHealthCheckHandler(t *testing.T) {
 	httpServer.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
-	assert.Contains(
+	assert.Regexp(
 		t,
+		`\{"success":true,"status":"healthy","probes":\{"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}`,
 		rec.Body.String(),
-		`{"success":true,"probes":{"successProbe":{"success":true,"message":"some success"}}}`,
 	)
 
 	logBufferRecords, err := logBuffer.Records()
@@ -64,10 +66,10 @@ func TestHealthCheckHandler(t *testing.T) {
 	httpServer.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Contains(
+	assert.Regexp(
 		t,
+		`\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}`,
 		rec.Body.String(),
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"some failure"},"successProbe":{"success":true,"message":"some success"}}}`,
 	)
 
 	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
@@ -88,10 +90,10 @@ func TestHealthCheckHandler(t *testing.T) {
 	httpServer.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	assert.Contains(
+	assert.Regexp(
 		t,
+		`\{"success":false,"status":"unhealthy","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":true,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}`,
 		rec.Body.String(),
-		`{"success":false,"probes":{"failureProbe":{"success":false,"message":"some failure"},"successProbe":{"success":true,"message":"some success"}}}`,
 	)
 
 	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
@@ -101,3 +103,259 @@ func TestHealthCheckHandler(t *testing.T) {
 		"message":      "healthcheck failure",
 	})
 }
+
+func TestHealthCheckHandlerWithNonCriticalProbe(t *testing.T) {
+	t.Parallel()
+
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+		healthcheck.WithProbeWithOptions(probes.NewFailureProbe(), healthcheck.Readiness, healthcheck.WithProbeCritical(false)),
+	)
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.Logger = httpserver.NewEchoLogger(logger)
+
+	// [GET] /readyz => readiness probes (should log degradation)
+	httpServer.GET("/readyz", handler.HealthCheckHandler(checker, healthcheck.Readiness))
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	req = req.WithContext(logger.WithContext(context.Background()))
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Regexp(
+		t,
+		`\{"success":true,"status":"degraded","probes":\{"failureProbe":\{"success":false,"message":"some failure","critical":false,"duration_ms":[\d.]+\},"successProbe":\{"success":true,"message":"some success","critical":true,"duration_ms":[\d.]+\}\}\}`,
+		rec.Body.String(),
+	)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":        "warn",
+		"successProbe": "success: true, message: some success",
+		"failureProbe": "success: false, message: some failure",
+		"message":      "healthcheck degraded",
+	})
+}
+
+func TestHealthCheckHandlerWithHealthJSON(t *testing.T) {
+	t.Parallel()
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+		healthcheck.WithProbe(probes.NewFailureProbe(), healthcheck.Liveness),
+	)
+	assert.NoError(t, err)
+
+	degradedChecker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+		healthcheck.WithProbeWithOptions(probes.NewFailureProbe(), healthcheck.WithProbeCritical(false)),
+	)
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.GET("/healthz", handler.HealthCheckHandler(
+		checker,
+		healthcheck.Startup,
+		handler.WithHealthCheckService("test-service", "1.0.0", "test description"),
+	))
+	httpServer.GET("/livez", handler.HealthCheckHandler(checker, healthcheck.Liveness))
+	httpServer.GET("/readyz", handler.HealthCheckHandler(degradedChecker, healthcheck.Readiness))
+
+	// [GET] /healthz => pass
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set(echo.HeaderAccept, handler.MIMEApplicationHealthJSON)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, handler.MIMEApplicationHealthJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+	assert.Regexp(
+		t,
+		`^\{"status":"pass","version":"1.0.0","serviceId":"test-service","description":"test description","checks":\{"successProbe:responseTime":\[\{"componentId":"successProbe","status":"pass","time":"[^"]+","observedValue":[\d.e-]+,"observedUnit":"ms"\}\]\}\}\n$`,
+		rec.Body.String(),
+	)
+
+	// [GET] /livez => fail
+	req = httptest.NewRequest(http.MethodGet, "/livez", nil)
+	req.Header.Set(echo.HeaderAccept, "application/json;q=0.5, application/health+json")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, handler.MIMEApplicationHealthJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Regexp(t, `^\{"status":"fail","checks":\{`, rec.Body.String())
+	assert.Regexp(t, `"failureProbe:responseTime":\[\{"componentId":"failureProbe","status":"fail","time":"[^"]+","observedValue":[\d.e-]+,"observedUnit":"ms","output":"some failure"\}\]`, rec.Body.String())
+
+	// [GET] /readyz => warn
+	req = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	req.Header.Set(echo.HeaderAccept, "application/health+json")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Regexp(t, `^\{"status":"warn","checks":\{`, rec.Body.String())
+	assert.Regexp(t, `"failureProbe:responseTime":\[\{"componentId":"failureProbe","status":"warn",`, rec.Body.String())
+
+	// [GET] /readyz => json preferred
+	req = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	req.Header.Set(echo.HeaderAccept, "application/health+json;q=0.5, application/json")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON))
+	assert.Regexp(t, `^\{"success":true,"status":"degraded","probes":\{`, rec.Body.String())
+}
+
+func TestHealthCheckHandlerWithVerbosity(t *testing.T) {
+	t.Parallel()
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+	)
+	assert.NoError(t, err)
+
+	resolver, err := handler.NewNetworksHealthCheckVerbosityResolver("10.0.0.0/8")
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.GET("/minimal", handler.HealthCheckHandler(
+		checker,
+		healthcheck.Startup,
+		handler.WithHealthCheckVerbosity(handler.HealthCheckMinimalVerbosity),
+	))
+	httpServer.GET("/resolved", handler.HealthCheckHandler(
+		checker,
+		healthcheck.Startup,
+		handler.WithHealthCheckVerbosityResolver(resolver),
+	))
+
+	tests := []struct {
+		path       string
+		accept     string
+		remoteAddr string
+		expected   string
+	}{
+		{"/minimal", "", "10.0.0.1:1234", `^\{"success":true,"status":"healthy"\}\n$`},
+		{"/minimal", handler.MIMEApplicationHealthJSON, "10.0.0.1:1234", `^\{"status":"pass"\}\n$`},
+		{"/resolved", "", "192.168.0.1:1234", `^\{"success":true,"status":"healthy"\}\n$`},
+		{"/resolved", handler.MIMEApplicationHealthJSON, "192.168.0.1:1234", `^\{"status":"pass"\}\n$`},
+		{"/resolved", "", "10.0.0.1:1234", `^\{"success":true,"status":"healthy","probes":\{"successProbe":`},
+		{"/resolved", handler.MIMEApplicationHealthJSON, "10.0.0.1:1234", `^\{"status":"pass","checks":\{"successProbe:responseTime":`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = tt.remoteAddr
+		req.Header.Set(echo.HeaderAccept, tt.accept)
+		// forged header must not be trusted
+		req.Header.Set(echo.HeaderXForwardedFor, "10.0.0.2")
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Regexp(t, tt.expected, rec.Body.String(), "path %s, accept %s, remote %s", tt.path, tt.accept, tt.remoteAddr)
+	}
+}
+
+func TestHealthCheckHandlerWithAuthenticatedVerbosity(t *testing.T) {
+	t.Parallel()
+
+	checker, err := healthcheck.NewDefaultCheckerFactory().Create(
+		healthcheck.WithProbe(probes.NewSuccessProbe()),
+	)
+	assert.NoError(t, err)
+
+	networksResolver, err := handler.NewNetworksHealthCheckVerbosityResolver("10.0.0.0/8")
+	assert.NoError(t, err)
+
+	authenticator := auth.NewBasicAuthenticator(map[string]auth.BasicUser{
+		"admin": {Password: "secret"},
+	})
+
+	httpServer := echo.New()
+	httpServer.GET("/resolved", handler.HealthCheckHandler(
+		checker,
+		healthcheck.Startup,
+		handler.WithHealthCheckVerbosityResolver(
+			handler.NewAnyHealthCheckVerbosityResolver(
+				networksResolver,
+				handler.NewAuthenticatedHealthCheckVerbosityResolver(authenticator),
+			),
+		),
+	))
+	httpServer.GET("/principal", handler.HealthCheckHandler(
+		checker,
+		healthcheck.Startup,
+		handler.WithHealthCheckVerbosityResolver(handler.NewAuthenticatedHealthCheckVerbosityResolver()),
+	), func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("X-Principal") != "" {
+				ctx := auth.WithPrincipal(c.Request().Context(), &auth.Principal{ID: "principal"})
+				c.SetRequest(c.Request().WithContext(ctx))
+			}
+
+			return next(c)
+		}
+	})
+
+	tests := []struct {
+		path       string
+		remoteAddr string
+		username   string
+		password   string
+		principal  bool
+		expected   string
+	}{
+		{"/resolved", "192.168.0.1:1234", "", "", false, `^\{"success":true,"status":"healthy"\}\n$`},
+		{"/resolved", "192.168.0.1:1234", "admin", "invalid", false, `^\{"success":true,"status":"healthy"\}\n$`},
+		{"/resolved", "192.168.0.1:1234", "admin", "secret", false, `^\{"success":true,"status":"healthy","probes":\{"successProbe":`},
+		{"/resolved", "10.0.0.1:1234", "", "", false, `^\{"success":true,"status":"healthy","probes":\{"successProbe":`},
+		{"/principal", "192.168.0.1:1234", "", "", false, `^\{"success":true,"status":"healthy"\}\n$`},
+		{"/principal", "192.168.0.1:1234", "", "", true, `^\{"success":true,"status":"healthy","probes":\{"successProbe":`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.username != "" {
+			req.SetBasicAuth(tt.username, tt.password)
+		}
+		if tt.principal {
+			req.Header.Set("X-Principal", "true")
+		}
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Regexp(t, tt.expected, rec.Body.String(), "path %s, remote %s, user %s", tt.path, tt.remoteAddr, tt.username)
+	}
+}
+
+func TestNewNetworksHealthCheckVerbosityResolverWithInvalidNetwork(t *testing.T) {
+	t.Parallel()
+
+	_, err := handler.NewNetworksHealthCheckVerbosityResolver("invalid")
+	assert.Error(t, err)
+	assert.Equal(t, `invalid healthcheck verbosity network "invalid": invalid CIDR address: invalid`, err.Error())
+}
+
+func TestHealthCheckVerbosity(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "full", handler.HealthCheckFullVerbosity.String())
+	assert.Equal(t, "minimal", handler.HealthCheckMinimalVerbosity.String())
+
+	assert.Equal(t, handler.HealthCheckFullVerbosity, handler.FetchHealthCheckVerbosity("full"))
+	assert.Equal(t, handler.HealthCheckMinimalVerbosity, handler.FetchHealthCheckVerbosity("Minimal"))
+	assert.Equal(t, handler.HealthCheckFullVerbosity, handler.FetchHealthCheckVerbosity("invalid"))
+}
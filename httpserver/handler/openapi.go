@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// DefaultSwaggerUIAssetsURL is the default url of the Swagger UI assets, with a pinned version.
+	DefaultSwaggerUIAssetsURL = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14"
+	// DefaultRedocAssetsURL is the default url of the Redoc assets, with a pinned version.
+	DefaultRedocAssetsURL = "https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles"
+)
+
+const swaggerUITemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Title }}</title>
+	<link rel="stylesheet" href="{{ .AssetsURL }}/swagger-ui.css"{{ with .StylesheetIntegrity }} integrity="{{ . }}"{{ end }} crossorigin="anonymous">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="{{ .AssetsURL }}/swagger-ui-bundle.js"{{ with .ScriptIntegrity }} integrity="{{ . }}"{{ end }} crossorigin="anonymous"></script>
+	<script nonce="{{ .Nonce }}">
+		window.onload = () => {
+			window.ui = SwaggerUIBundle({
+				url: {{ .SpecURL }},
+				dom_id: '#swagger-ui',
+			});
+		};
+	</script>
+</body>
+</html>
+`
+
+const redocTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{ .Title }}</title>
+</head>
+<body>
+	<redoc spec-url="{{ .SpecURL }}"></redoc>
+	<script src="{{ .AssetsURL }}/redoc.standalone.js"{{ with .ScriptIntegrity }} integrity="{{ . }}"{{ end }} crossorigin="anonymous"></script>
+</body>
+</html>
+`
+
+// OpenAPIPageOptions are options for the [SwaggerUIHandler] and [RedocHandler].
+type OpenAPIPageOptions struct {
+	AssetsURL           string
+	ScriptIntegrity     string
+	StylesheetIntegrity string
+}
+
+// OpenAPIPageOption are functional options for the [SwaggerUIHandler] and [RedocHandler].
+type OpenAPIPageOption func(o *OpenAPIPageOptions)
+
+// WithOpenAPIPageAssetsURL is used to specify the url of the page assets, for example to serve them yourself.
+func WithOpenAPIPageAssetsURL(assetsURL string) OpenAPIPageOption {
+	return func(o *OpenAPIPageOptions) {
+		o.AssetsURL = strings.TrimSuffix(assetsURL, "/")
+	}
+}
+
+// WithOpenAPIPageIntegrity is used to specify the subresource integrity hashes of the page script and stylesheet.
+func WithOpenAPIPageIntegrity(script string, stylesheet string) OpenAPIPageOption {
+	return func(o *OpenAPIPageOptions) {
+		o.ScriptIntegrity = script
+		o.StylesheetIntegrity = stylesheet
+	}
+}
+
+// OpenAPIHandler is an [echo.HandlerFunc] that returns an OpenAPI document.
+func OpenAPIHandler(document *openapi.Document) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, document)
+	}
+}
+
+// SwaggerUIHandler is an [echo.HandlerFunc] that returns a Swagger UI page, for the OpenAPI document at specURL.
+func SwaggerUIHandler(specURL string, title string, options ...OpenAPIPageOption) echo.HandlerFunc {
+	return openAPIPageHandler(
+		template.Must(template.New("swagger-ui").Parse(swaggerUITemplate)),
+		specURL,
+		title,
+		append([]OpenAPIPageOption{WithOpenAPIPageAssetsURL(DefaultSwaggerUIAssetsURL)}, options...),
+	)
+}
+
+// RedocHandler is an [echo.HandlerFunc] that returns a Redoc page, for the OpenAPI document at specURL.
+func RedocHandler(specURL string, title string, options ...OpenAPIPageOption) echo.HandlerFunc {
+	return openAPIPageHandler(
+		template.Must(template.New("redoc").Parse(redocTemplate)),
+		specURL,
+		title,
+		append([]OpenAPIPageOption{WithOpenAPIPageAssetsURL(DefaultRedocAssetsURL)}, options...),
+	)
+}
+
+// openAPIPageHandler renders the page with a per request nonce, and replaces the Content-Security-Policy
+// header with a policy allowing the page assets, its inline script, styles and workers.
+func openAPIPageHandler(tmpl *template.Template, specURL string, title string, options []OpenAPIPageOption) echo.HandlerFunc {
+	appliedOptions := OpenAPIPageOptions{}
+	for _, opt := range options {
+		opt(&appliedOptions)
+	}
+
+	assetsSource := "'self'"
+	if assetsURL, err := url.Parse(appliedOptions.AssetsURL); err == nil && assetsURL.Host != "" {
+		assetsSource = fmt.Sprintf("%s://%s", assetsURL.Scheme, assetsURL.Host)
+	}
+
+	return func(c echo.Context) error {
+		nonce, err := openAPIPageNonce()
+		if err != nil {
+			return err
+		}
+
+		var page bytes.Buffer
+		err = tmpl.Execute(&page, map[string]string{
+			"SpecURL":             specURL,
+			"Title":               title,
+			"AssetsURL":           appliedOptions.AssetsURL,
+			"ScriptIntegrity":     appliedOptions.ScriptIntegrity,
+			"StylesheetIntegrity": appliedOptions.StylesheetIntegrity,
+			"Nonce":               nonce,
+		})
+		if err != nil {
+			return err
+		}
+
+		c.Response().Header().Del(echo.HeaderContentSecurityPolicyReportOnly)
+		c.Response().Header().Set(
+			echo.HeaderContentSecurityPolicy,
+			fmt.Sprintf(
+				"default-src 'self'; script-src 'self' 'nonce-%s' %s; style-src 'self' 'unsafe-inline' %s; "+
+					"img-src 'self' data: %s; worker-src 'self' blob:",
+				nonce,
+				assetsSource,
+				assetsSource,
+				assetsSource,
+			),
+		)
+
+		return c.HTMLBlob(http.StatusOK, page.Bytes())
+	}
+}
+
+func openAPIPageNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(nonce), nil
+}
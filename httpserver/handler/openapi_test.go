@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/handler"
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIHandler(t *testing.T) {
+	t.Parallel()
+
+	generator := openapi.NewGenerator(openapi.WithInfo(openapi.Info{Title: "test", Version: "1.0.0"}))
+
+	err := generator.AddOperation(http.MethodGet, "/test", openapi.OperationSpec{Summary: "test"})
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.GET("/openapi.json", handler.OpenAPIHandler(generator.Document()))
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(
+		t,
+		`{"openapi":"3.1.0","info":{"title":"test","version":"1.0.0"},"paths":{"/test":{"get":{"summary":"test"}}}}`,
+		rec.Body.String(),
+	)
+}
+
+func TestSwaggerUIHandler(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.GET("/swagger", handler.SwaggerUIHandler("/openapi.json", "test <api>"))
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "<title>test &lt;api&gt;</title>")
+	assert.Contains(t, rec.Body.String(), `url: "/openapi.json"`)
+	assert.Contains(t, rec.Body.String(), handler.DefaultSwaggerUIAssetsURL+"/swagger-ui-bundle.js")
+	assert.Contains(t, rec.Body.String(), handler.DefaultSwaggerUIAssetsURL+"/swagger-ui.css")
+	assert.NotContains(t, rec.Body.String(), "integrity=")
+
+	csp := rec.Header().Get(echo.HeaderContentSecurityPolicy)
+	assert.Contains(t, csp, "script-src 'self' 'nonce-")
+	assert.Contains(t, csp, "https://cdn.jsdelivr.net")
+
+	nonce := strings.SplitN(strings.SplitN(csp, "'nonce-", 2)[1], "'", 2)[0]
+	assert.Contains(t, rec.Body.String(), `<script nonce="`+nonce+`">`)
+}
+
+func TestSwaggerUIHandlerWithOptions(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.SecureWithConfig(middleware.SecureConfig{
+		ContentSecurityPolicy: "default-src 'self'",
+		CSPReportOnly:         true,
+	}))
+	httpServer.GET("/swagger", handler.SwaggerUIHandler(
+		"/openapi.json",
+		"test",
+		handler.WithOpenAPIPageAssetsURL("/assets/swagger-ui/"),
+		handler.WithOpenAPIPageIntegrity("sha384-script", "sha384-stylesheet"),
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<script src="/assets/swagger-ui/swagger-ui-bundle.js" integrity="sha384-script" crossorigin="anonymous">`)
+	assert.Contains(t, rec.Body.String(), `<link rel="stylesheet" href="/assets/swagger-ui/swagger-ui.css" integrity="sha384-stylesheet" crossorigin="anonymous">`)
+
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentSecurityPolicyReportOnly))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentSecurityPolicy), "style-src 'self' 'unsafe-inline' 'self';")
+}
+
+func TestRedocHandler(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.GET("/redoc", handler.RedocHandler("/openapi.json", "test"))
+
+	req := httptest.NewRequest(http.MethodGet, "/redoc", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<redoc spec-url="/openapi.json"></redoc>`)
+	assert.Contains(t, rec.Body.String(), handler.DefaultRedocAssetsURL+"/redoc.standalone.js")
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentSecurityPolicy), "worker-src 'self' blob:")
+}
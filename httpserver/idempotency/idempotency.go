@@ -0,0 +1,56 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// Response is a response stored for an idempotency key, to be replayed on the request retries.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Record is the state of an idempotency key: the fingerprint of its request, and its response once completed.
+type Record struct {
+	Fingerprint string
+	Response    *Response
+	ExpiresAt   time.Time
+}
+
+// Completed returns true if the response of the [Record] request is stored.
+func (r *Record) Completed() bool {
+	return r.Response != nil
+}
+
+// Store is the interface for the idempotency keys stores.
+type Store interface {
+	// Acquire reserves a key for a request fingerprint, until the ttl expiration. It returns nil if the key was
+	// acquired, or the existing [Record] of the key otherwise.
+	Acquire(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*Record, error)
+	// Complete stores the [Response] of the request of an acquired key.
+	Complete(ctx context.Context, key string, response *Response) error
+	// Release releases an acquired key, for its request to be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// Fingerprint returns the fingerprint of a request, from its method, uri and body.
+func Fingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.URL.RequestURI()))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
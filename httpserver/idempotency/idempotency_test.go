@@ -0,0 +1,110 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/idempotency"
+	"github.com/stretchr/testify/assert"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(duration time.Duration) {
+	c.now = c.now.Add(duration)
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	fingerprint := idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/test?foo=bar", nil), []byte("body"))
+
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(
+		t,
+		fingerprint,
+		idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/test?foo=bar", nil), []byte("body")),
+	)
+	assert.NotEqual(
+		t,
+		fingerprint,
+		idempotency.Fingerprint(httptest.NewRequest(http.MethodPut, "/test?foo=bar", nil), []byte("body")),
+	)
+	assert.NotEqual(
+		t,
+		fingerprint,
+		idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/test?foo=baz", nil), []byte("body")),
+	)
+	assert.NotEqual(
+		t,
+		fingerprint,
+		idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/test?foo=bar", nil), []byte("other")),
+	)
+}
+
+func assertStore(t *testing.T, store idempotency.Store, clock *testClock) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	// acquisition
+	record, err := store.Acquire(ctx, "key", "fingerprint", time.Minute)
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	// in progress
+	record, err = store.Acquire(ctx, "key", "fingerprint", time.Minute)
+	assert.NoError(t, err)
+	assert.NotNil(t, record)
+	assert.Equal(t, "fingerprint", record.Fingerprint)
+	assert.False(t, record.Completed())
+
+	// release and new acquisition
+	assert.NoError(t, store.Release(ctx, "key"))
+
+	record, err = store.Acquire(ctx, "key", "other", time.Minute)
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	// completion
+	assert.NoError(t, store.Complete(ctx, "key", &idempotency.Response{
+		Status: http.StatusCreated,
+		Header: http.Header{"Content-Type": []string{"application/json"}},
+		Body:   []byte(`{"id":1}`),
+	}))
+
+	record, err = store.Acquire(ctx, "key", "fingerprint", time.Minute)
+	assert.NoError(t, err)
+	assert.NotNil(t, record)
+	assert.Equal(t, "other", record.Fingerprint)
+	assert.True(t, record.Completed())
+	assert.Equal(t, http.StatusCreated, record.Response.Status)
+	assert.Equal(t, "application/json", record.Response.Header.Get("Content-Type"))
+	assert.Equal(t, `{"id":1}`, string(record.Response.Body))
+
+	// completed keys are not released
+	assert.NoError(t, store.Release(ctx, "key"))
+
+	clock.Advance(30 * time.Second)
+
+	record, err = store.Acquire(ctx, "key", "fingerprint", time.Minute)
+	assert.NoError(t, err)
+	assert.NotNil(t, record)
+	assert.True(t, record.Completed())
+
+	// expiration
+	clock.Advance(30 * time.Second)
+
+	record, err = store.Acquire(ctx, "key", "fingerprint", time.Minute)
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+}
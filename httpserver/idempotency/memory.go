@@ -0,0 +1,111 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a [Store] keeping the idempotency keys records in memory, suitable for single instance setups.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	records   map[string]*Record
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// MemoryStoreOption are functional options for the [MemoryStore].
+type MemoryStoreOption func(s *MemoryStore)
+
+// WithMemoryStoreClock is used to specify the time source of the [MemoryStore] (time.Now by default).
+func WithMemoryStoreClock(now func() time.Time) MemoryStoreOption {
+	return func(s *MemoryStore) {
+		s.now = now
+	}
+}
+
+// NewMemoryStore returns a new [MemoryStore].
+func NewMemoryStore(options ...MemoryStoreOption) *MemoryStore {
+	store := &MemoryStore{
+		records: map[string]*Record{},
+		now:     time.Now,
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	store.lastSweep = store.now()
+
+	return store
+}
+
+// Acquire reserves a key for a request fingerprint, or returns the existing [Record] of the key.
+func (s *MemoryStore) Acquire(_ context.Context, key string, fingerprint string, ttl time.Duration) (*Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+
+	s.sweep(now)
+
+	if record, ok := s.records[key]; ok && record.ExpiresAt.After(now) {
+		recordCopy := *record
+
+		return &recordCopy, nil
+	}
+
+	s.records[key] = &Record{
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+	}
+
+	return nil, nil
+}
+
+// Complete stores the [Response] of the request of an acquired key.
+func (s *MemoryStore) Complete(_ context.Context, key string, response *Response) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, ok := s.records[key]; ok {
+		record.Response = response
+	}
+
+	return nil
+}
+
+// Release releases an acquired key, if its request is still in progress.
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if record, ok := s.records[key]; ok && !record.Completed() {
+		delete(s.records, key)
+	}
+
+	return nil
+}
+
+// Len returns the number of keys tracked by the [MemoryStore].
+func (s *MemoryStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.records)
+}
+
+// sweep removes the expired records, at most once per minute.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+		}
+	}
+
+	s.lastSweep = now
+}
@@ -0,0 +1,73 @@
+package idempotency_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/idempotency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+
+	assertStore(t, idempotency.NewMemoryStore(idempotency.WithMemoryStoreClock(clock.Now)), clock)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := idempotency.NewMemoryStore(idempotency.WithMemoryStoreClock(clock.Now))
+
+	_, err := store.Acquire(context.Background(), "foo", "fingerprint", time.Second)
+	assert.NoError(t, err)
+
+	_, err = store.Acquire(context.Background(), "bar", "fingerprint", time.Second)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, store.Len())
+
+	clock.Advance(2 * time.Minute)
+
+	_, err = store.Acquire(context.Background(), "baz", "fingerprint", time.Second)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestMemoryStoreConcurrentAcquisitions(t *testing.T) {
+	t.Parallel()
+
+	store := idempotency.NewMemoryStore()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+
+	acquired := 0
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			record, err := store.Acquire(context.Background(), "key", "fingerprint", time.Minute)
+			assert.NoError(t, err)
+
+			if record == nil {
+				mutex.Lock()
+				acquired++
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, acquired)
+}
@@ -0,0 +1,246 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+)
+
+// DefaultSQLStoreTable is the default table name of the [SQLStore].
+const DefaultSQLStoreTable = "http_server_idempotency_keys"
+
+// maxSQLKeyLength is the maximum key length stored as is, longer keys are hashed.
+const maxSQLKeyLength = 191
+
+// SQLStore is a [Store] keeping the idempotency keys records in a SQL database table, suitable for multi instances
+// setups. The records of requests still in progress have a zero response status.
+type SQLStore struct {
+	db      *sql.DB
+	dialect sqlstore.Dialect
+	table   string
+	now     func() time.Time
+}
+
+// SQLStoreOption are functional options for the [SQLStore].
+type SQLStoreOption func(s *SQLStore)
+
+// WithSQLStoreTable is used to specify the table of the [SQLStore] (default http_server_idempotency_keys).
+func WithSQLStoreTable(table string) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.table = table
+	}
+}
+
+// WithSQLStoreClock is used to specify the time source of the [SQLStore] (time.Now by default).
+func WithSQLStoreClock(now func() time.Time) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.now = now
+	}
+}
+
+// NewSQLStore returns a new [SQLStore], for a [sql.DB] of the provided [sqlstore.Dialect].
+func NewSQLStore(db *sql.DB, dialect sqlstore.Dialect, options ...SQLStoreOption) (*SQLStore, error) {
+	if dialect == sqlstore.UnknownDialect {
+		return nil, fmt.Errorf("unsupported sql dialect for idempotency store")
+	}
+
+	store := &SQLStore{
+		db:      db,
+		dialect: dialect,
+		table:   DefaultSQLStoreTable,
+		now:     time.Now,
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	return store, nil
+}
+
+// CreateTable creates the [SQLStore] table, if it does not exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, CreateTableQuery(s.dialect, s.table))
+	if err != nil {
+		return fmt.Errorf("cannot create idempotency table: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTableQuery returns the query creating the [SQLStore] table for a [sqlstore.Dialect], for example to be
+// added to the application database migrations.
+func CreateTableQuery(dialect sqlstore.Dialect, table string) string {
+	bodyType := "BLOB"
+
+	switch dialect {
+	case sqlstore.MySQLDialect:
+		bodyType = "LONGBLOB"
+	case sqlstore.PostgresDialect:
+		bodyType = "BYTEA"
+	}
+
+	return fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s (
+			idempotency_key VARCHAR(191) NOT NULL PRIMARY KEY,
+			fingerprint CHAR(64) NOT NULL,
+			response_status INT NOT NULL,
+			response_header TEXT,
+			response_body %s,
+			expires_at BIGINT NOT NULL
+		)`,
+		table,
+		bodyType,
+	)
+}
+
+// Acquire reserves a key for a request fingerprint, or returns the existing [Record] of the key.
+func (s *SQLStore) Acquire(ctx context.Context, key string, fingerprint string, ttl time.Duration) (*Record, error) {
+	key = s.key(key)
+	now := s.now()
+
+	_, err := s.db.ExecContext(
+		ctx,
+		s.query(fmt.Sprintf("DELETE FROM %s WHERE idempotency_key = ? AND expires_at <= ?", s.table)),
+		key,
+		now.UnixNano(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot delete expired idempotency key: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, s.query(s.insertQuery()), key, fingerprint, now.Add(ttl).UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("cannot insert idempotency key: %w", err)
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("cannot insert idempotency key: %w", err)
+	}
+
+	if inserted > 0 {
+		return nil, nil
+	}
+
+	var record Record
+	var status int
+	var header sql.NullString
+	var body []byte
+	var expiresAt int64
+
+	err = s.db.QueryRowContext(
+		ctx,
+		s.query(fmt.Sprintf(
+			"SELECT fingerprint, response_status, response_header, response_body, expires_at FROM %s WHERE idempotency_key = ?",
+			s.table,
+		)),
+		key,
+	).Scan(&record.Fingerprint, &status, &header, &body, &expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select idempotency key: %w", err)
+	}
+
+	record.ExpiresAt = time.Unix(0, expiresAt)
+
+	if status != 0 {
+		record.Response = &Response{
+			Status: status,
+			Header: http.Header{},
+			Body:   body,
+		}
+
+		if header.Valid && header.String != "" {
+			err = json.Unmarshal([]byte(header.String), &record.Response.Header)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode idempotency key response header: %w", err)
+			}
+		}
+	}
+
+	return &record, nil
+}
+
+// Complete stores the [Response] of the request of an acquired key.
+func (s *SQLStore) Complete(ctx context.Context, key string, response *Response) error {
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return fmt.Errorf("cannot encode idempotency key response header: %w", err)
+	}
+
+	_, err = s.db.ExecContext(
+		ctx,
+		s.query(fmt.Sprintf(
+			"UPDATE %s SET response_status = ?, response_header = ?, response_body = ? WHERE idempotency_key = ?",
+			s.table,
+		)),
+		response.Status,
+		string(header),
+		response.Body,
+		s.key(key),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Release releases an acquired key, if its request is still in progress.
+func (s *SQLStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		s.query(fmt.Sprintf("DELETE FROM %s WHERE idempotency_key = ? AND response_status = 0", s.table)),
+		s.key(key),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Purge deletes the expired idempotency keys, and returns the number of deleted rows.
+func (s *SQLStore) Purge(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(
+		ctx,
+		s.query(fmt.Sprintf("DELETE FROM %s WHERE expires_at <= ?", s.table)),
+		s.now().UnixNano(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("cannot purge idempotency keys: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+func (s *SQLStore) insertQuery() string {
+	if s.dialect == sqlstore.MySQLDialect {
+		return fmt.Sprintf("INSERT IGNORE INTO %s (idempotency_key, fingerprint, response_status, expires_at) VALUES (?, ?, 0, ?)", s.table)
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (idempotency_key, fingerprint, response_status, expires_at) VALUES (?, ?, 0, ?) ON CONFLICT DO NOTHING", s.table)
+}
+
+// query converts the ? placeholders for the dialect.
+func (s *SQLStore) query(query string) string {
+	return s.dialect.Rebind(query)
+}
+
+// key hashes the keys too long to be stored as is.
+func (s *SQLStore) key(key string) string {
+	if len(key) <= maxSQLKeyLength {
+		return key
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,103 @@
+package idempotency_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/idempotency"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLStoreWithUnknownDialect(t *testing.T) {
+	t.Parallel()
+
+	_, err := idempotency.NewSQLStore(nil, sqlstore.UnknownDialect)
+	assert.Error(t, err)
+	assert.Equal(t, "unsupported sql dialect for idempotency store", err.Error())
+}
+
+func TestSQLStore(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+
+	assertStore(t, createTestSQLStore(t, clock), clock)
+}
+
+func TestSQLStoreWithLongKeyAndPurge(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := createTestSQLStore(t, clock)
+
+	key := strings.Repeat("k", 500)
+
+	record, err := store.Acquire(context.Background(), key, "fingerprint", time.Second)
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	assert.NoError(t, store.Complete(context.Background(), key, &idempotency.Response{Status: http.StatusNoContent}))
+
+	record, err = store.Acquire(context.Background(), key, "fingerprint", time.Second)
+	assert.NoError(t, err)
+	assert.NotNil(t, record)
+	assert.Equal(t, http.StatusNoContent, record.Response.Status)
+
+	count, err := store.Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	clock.Advance(time.Minute)
+
+	count, err = store.Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestSQLStoreWithMissingTable(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	store, err := idempotency.NewSQLStore(db, sqlstore.SQLiteDialect, idempotency.WithSQLStoreTable("missing"))
+	assert.NoError(t, err)
+
+	_, err = store.Acquire(context.Background(), "key", "fingerprint", time.Second)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot delete expired idempotency key")
+}
+
+func TestCreateTableQuery(t *testing.T) {
+	t.Parallel()
+
+	assert.Contains(t, idempotency.CreateTableQuery(sqlstore.MySQLDialect, "keys"), "response_body LONGBLOB")
+	assert.Contains(t, idempotency.CreateTableQuery(sqlstore.PostgresDialect, "keys"), "response_body BYTEA")
+	assert.Contains(t, idempotency.CreateTableQuery(sqlstore.SQLiteDialect, "keys"), "response_body BLOB")
+	assert.Contains(t, idempotency.CreateTableQuery(sqlstore.SQLiteDialect, "keys"), "CREATE TABLE IF NOT EXISTS keys")
+}
+
+func createTestSQLStore(t *testing.T, clock *testClock) *idempotency.SQLStore {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+
+	store, err := idempotency.NewSQLStore(db, sqlstore.SQLiteDialect, idempotency.WithSQLStoreClock(clock.Now))
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.CreateTable(context.Background()))
+
+	return store
+}
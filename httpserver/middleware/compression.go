@@ -0,0 +1,371 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	EncodingGzip                = "gzip"
+	EncodingZstd                = "zstd"
+	EncodingBrotli              = "br"
+	DefaultCompressionMinLength = 1024
+)
+
+// CompressionMiddlewareConfig is the configuration for the [CompressionMiddleware].
+//
+// The responses are compressed with the preferred encoding of the request Accept-Encoding header among Encodings
+// (listed by server preference in case of equal client preference), if their content type matches one of the
+// ContentTypes (exact media types, or type/* wildcards), and if their body reaches MinLength bytes.
+// Flushed responses are compressed (and flushed) as they go, whatever their length, and WebSocket upgrades are skipped.
+type CompressionMiddlewareConfig struct {
+	Skipper      middleware.Skipper
+	Encodings    []string
+	MinLength    int
+	ContentTypes []string
+}
+
+// DefaultCompressionMiddlewareConfig is the default configuration for the [CompressionMiddleware].
+var DefaultCompressionMiddlewareConfig = CompressionMiddlewareConfig{
+	Skipper:   middleware.DefaultSkipper,
+	Encodings: []string{EncodingZstd, EncodingBrotli, EncodingGzip},
+	MinLength: DefaultCompressionMinLength,
+	ContentTypes: []string{
+		"text/*",
+		echo.MIMEApplicationJSON,
+		"application/problem+json",
+		echo.MIMEApplicationXML,
+		echo.MIMEApplicationJavaScript,
+		"image/svg+xml",
+	},
+}
+
+// CompressionMiddleware returns a [CompressionMiddleware] with the [DefaultCompressionMiddlewareConfig].
+func CompressionMiddleware() echo.MiddlewareFunc {
+	return CompressionMiddlewareWithConfig(DefaultCompressionMiddlewareConfig)
+}
+
+// CompressionMiddlewareWithConfig returns a [CompressionMiddleware] for a provided [CompressionMiddlewareConfig].
+func CompressionMiddlewareWithConfig(config CompressionMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultCompressionMiddlewareConfig.Skipper
+	}
+
+	if len(config.Encodings) == 0 {
+		config.Encodings = DefaultCompressionMiddlewareConfig.Encodings
+	}
+
+	if config.MinLength < 0 {
+		config.MinLength = DefaultCompressionMiddlewareConfig.MinLength
+	}
+
+	if len(config.ContentTypes) == 0 {
+		config.ContentTypes = DefaultCompressionMiddlewareConfig.ContentTypes
+	}
+
+	pools := make(map[string]*sync.Pool)
+	for _, encoding := range config.Encodings {
+		if pool := newCompressorPool(encoding); pool != nil {
+			pools[encoding] = pool
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// skipper
+			if config.Skipper(c) || c.IsWebSocket() {
+				return next(c)
+			}
+
+			resp := c.Response()
+			resp.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding), config.Encodings, pools)
+			if encoding == "" || c.Request().Method == http.MethodHead {
+				return next(c)
+			}
+
+			writer := &compressionWriter{
+				ResponseWriter: resp.Writer,
+				encoding:       encoding,
+				pool:           pools[encoding],
+				minLength:      config.MinLength,
+				contentTypes:   config.ContentTypes,
+			}
+
+			resp.Writer = writer
+
+			err := next(c)
+
+			resp.Writer = writer.ResponseWriter
+
+			if closeErr := writer.close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+
+			return err
+		}
+	}
+}
+
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+func newCompressorPool(encoding string) *sync.Pool {
+	switch encoding {
+	case EncodingGzip:
+		return &sync.Pool{
+			New: func() any {
+				return gzip.NewWriter(io.Discard)
+			},
+		}
+	case EncodingZstd:
+		return &sync.Pool{
+			New: func() any {
+				//nolint:errcheck // the default options are valid
+				encoder, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+
+				return encoder
+			},
+		}
+	case EncodingBrotli:
+		return &sync.Pool{
+			New: func() any {
+				return brotli.NewWriter(io.Discard)
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// negotiateEncoding returns the supported encoding preferred by an Accept-Encoding header, or an empty string if none.
+func negotiateEncoding(acceptEncoding string, encodings []string, pools map[string]*sync.Pool) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		value, params, _ := strings.Cut(part, ";")
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if q, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		qualities[strings.ToLower(strings.TrimSpace(value))] = quality
+	}
+
+	bestEncoding := ""
+	bestQuality := 0.0
+
+	for _, encoding := range encodings {
+		if pools[encoding] == nil {
+			continue
+		}
+
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+
+		if quality > bestQuality {
+			bestEncoding, bestQuality = encoding, quality
+		}
+	}
+
+	return bestEncoding
+}
+
+// compressionWriter buffers the response until it reaches the min length, to decide if it must be compressed.
+type compressionWriter struct {
+	http.ResponseWriter
+	encoding     string
+	pool         *sync.Pool
+	minLength    int
+	contentTypes []string
+	status       int
+	buffer       bytes.Buffer
+	decided      bool
+	compressor   compressor
+}
+
+func (w *compressionWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+
+		return
+	}
+
+	w.status = status
+}
+
+func (w *compressionWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		return w.write(b)
+	}
+
+	w.buffer.Write(b)
+
+	if w.buffer.Len() < w.minLength {
+		return len(b), nil
+	}
+
+	err := w.decide()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (w *compressionWriter) Flush() {
+	if !w.decided {
+		//nolint:errcheck // the error will surface on the next write
+		w.decide()
+	}
+
+	if w.compressor != nil {
+		//nolint:errcheck // the error will surface on the next write
+		w.compressor.Flush()
+	}
+
+	//nolint:errcheck // not all writers support flushing
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *compressionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide compresses the response if eligible, and writes the response status and the buffered body.
+func (w *compressionWriter) decide() error {
+	w.decided = true
+
+	header := w.Header()
+
+	if header.Get(echo.HeaderContentType) == "" && w.buffer.Len() > 0 {
+		header.Set(echo.HeaderContentType, http.DetectContentType(w.buffer.Bytes()))
+	}
+
+	if w.eligible() {
+		header.Set(echo.HeaderContentEncoding, w.encoding)
+		header.Del(echo.HeaderContentLength)
+
+		// the compressed representation is not byte for byte identical
+		if etag := header.Get(HeaderETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set(HeaderETag, "W/"+etag)
+		}
+
+		//nolint:forcetypeassert
+		w.compressor = w.pool.Get().(compressor)
+		w.compressor.Reset(w.ResponseWriter)
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.write(w.buffer.Bytes())
+	w.buffer.Reset()
+
+	return err
+}
+
+func (w *compressionWriter) eligible() bool {
+	switch w.status {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+
+	header := w.Header()
+	if header.Get(echo.HeaderContentEncoding) != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+
+	return matchContentType(header.Get(echo.HeaderContentType), w.contentTypes)
+}
+
+func (w *compressionWriter) write(b []byte) (int, error) {
+	if w.compressor != nil {
+		return w.compressor.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// close writes the responses that did not reach the min length as is, and releases the compressor.
+func (w *compressionWriter) close() error {
+	if !w.decided {
+		if w.status == 0 && w.buffer.Len() == 0 {
+			return nil
+		}
+
+		w.decided = true
+
+		if w.status != 0 {
+			w.ResponseWriter.WriteHeader(w.status)
+		}
+
+		_, err := w.ResponseWriter.Write(w.buffer.Bytes())
+
+		return err
+	}
+
+	if w.compressor == nil {
+		return nil
+	}
+
+	err := w.compressor.Close()
+
+	w.compressor.Reset(io.Discard)
+	w.pool.Put(w.compressor)
+	w.compressor = nil
+
+	return err
+}
+
+// matchContentType returns true if the media type of a content type matches one of the provided media types,
+// exactly or with a type/* wildcard.
+func matchContentType(contentType string, mediaTypes []string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	if mediaType == "" {
+		return false
+	}
+
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	for _, candidate := range mediaTypes {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+
+		if candidate == mediaType || candidate == mainType+"/*" || candidate == "*/*" {
+			return true
+		}
+	}
+
+	return false
+}
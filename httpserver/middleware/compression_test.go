@@ -0,0 +1,238 @@
+package middleware_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCompressionBody = strings.Repeat("compressible content ", 100)
+
+func decompress(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+
+	var reader io.Reader
+
+	switch encoding {
+	case middleware.EncodingGzip:
+		gzipReader, err := gzip.NewReader(body)
+		assert.NoError(t, err)
+
+		reader = gzipReader
+	case middleware.EncodingZstd:
+		zstdReader, err := zstd.NewReader(body)
+		assert.NoError(t, err)
+		defer zstdReader.Close()
+
+		reader = zstdReader
+	case middleware.EncodingBrotli:
+		reader = brotli.NewReader(body)
+	default:
+		reader = body
+	}
+
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	return string(data)
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.CompressionMiddleware())
+
+	httpServer.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusOK, testCompressionBody)
+	})
+	httpServer.GET("/small", func(c echo.Context) error {
+		return c.String(http.StatusOK, "small")
+	})
+	httpServer.GET("/image", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "image/png", []byte(testCompressionBody))
+	})
+	httpServer.GET("/empty", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		path           string
+		acceptEncoding string
+		encoding       string
+	}{
+		{"/text", "gzip", middleware.EncodingGzip},
+		{"/text", "br", middleware.EncodingBrotli},
+		{"/text", "zstd", middleware.EncodingZstd},
+		{"/text", "gzip, deflate, br, zstd", middleware.EncodingZstd},
+		{"/text", "gzip;q=1, br;q=0.5", middleware.EncodingGzip},
+		{"/text", "*", middleware.EncodingZstd},
+		{"/text", "*, zstd;q=0", middleware.EncodingBrotli},
+		{"/text", "deflate", ""},
+		{"/text", "", ""},
+		{"/small", "gzip", ""},
+		{"/image", "gzip", ""},
+		{"/empty", "gzip", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.path, tt.acceptEncoding), func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.encoding, rec.Header().Get(echo.HeaderContentEncoding))
+			assert.Equal(t, echo.HeaderAcceptEncoding, rec.Header().Get(echo.HeaderVary))
+
+			switch tt.path {
+			case "/text", "/image":
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, testCompressionBody, decompress(t, tt.encoding, rec.Body))
+			case "/small":
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "small", rec.Body.String())
+			case "/empty":
+				assert.Equal(t, http.StatusNoContent, rec.Code)
+				assert.Empty(t, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompressionMiddlewareWithConfig(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.CompressionMiddlewareWithConfig(middleware.CompressionMiddlewareConfig{
+		Encodings:    []string{middleware.EncodingGzip},
+		MinLength:    1,
+		ContentTypes: []string{"image/*"},
+		Skipper: func(c echo.Context) bool {
+			return c.Request().URL.Path == "/skipped"
+		},
+	}))
+
+	httpServer.GET("/image", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "image/png", []byte("image"))
+	})
+	httpServer.GET("/text", func(c echo.Context) error {
+		return c.String(http.StatusOK, "text")
+	})
+	httpServer.GET("/skipped", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "image/png", []byte("image"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/image", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "br, gzip")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, middleware.EncodingGzip, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, "image", decompress(t, middleware.EncodingGzip, rec.Body))
+
+	req = httptest.NewRequest(http.MethodGet, "/text", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, "text", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/skipped", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Empty(t, rec.Header().Get(echo.HeaderVary))
+	assert.Equal(t, "image", rec.Body.String())
+}
+
+func TestCompressionMiddlewareWithFlushedResponse(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.CompressionMiddleware())
+
+	httpServer.GET("/stream", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlain)
+		c.Response().WriteHeader(http.StatusOK)
+
+		for i := 0; i < 3; i++ {
+			_, err := c.Response().Write([]byte(fmt.Sprintf("chunk %d\n", i)))
+			assert.NoError(t, err)
+
+			c.Response().Flush()
+		}
+
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, middleware.EncodingGzip, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, "chunk 0\nchunk 1\nchunk 2\n", decompress(t, middleware.EncodingGzip, rec.Body))
+}
+
+func TestCompressionMiddlewareWithStrongETag(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.CompressionMiddleware())
+
+	httpServer.GET("/text", func(c echo.Context) error {
+		c.Response().Header().Set(middleware.HeaderETag, `"v1"`)
+
+		return c.String(http.StatusOK, testCompressionBody)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/text", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, `W/"v1"`, rec.Header().Get(middleware.HeaderETag))
+}
+
+func TestCompressionMiddlewareWithWebSocketUpgrade(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.CompressionMiddleware(), middleware.ETagMiddleware())
+
+	httpServer.GET("/ws", func(c echo.Context) error {
+		// the response writer must not be wrapped, to be hijacked
+		assert.IsType(t, &httptest.ResponseRecorder{}, c.Response().Writer)
+
+		return c.String(http.StatusOK, testCompressionBody)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	req.Header.Set(echo.HeaderConnection, "Upgrade")
+	req.Header.Set(echo.HeaderUpgrade, "websocket")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Empty(t, rec.Header().Get(middleware.HeaderETag))
+	assert.Equal(t, testCompressionBody, rec.Body.String())
+}
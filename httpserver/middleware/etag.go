@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	HeaderETag         = "ETag"
+	HeaderIfNoneMatch  = "If-None-Match"
+	HeaderAcceptRanges = "Accept-Ranges"
+	DefaultETagMaxSize = 1 << 20
+)
+
+// ETagMiddlewareConfig is the configuration for the [ETagMiddleware].
+//
+// The successful GET and HEAD responses get a weak ETag generated from their body (unless already provided by the
+// handler), and a 304 response is sent instead if the request If-None-Match header matches it, or, without
+// If-None-Match, if the request If-Modified-Since header is not older than the response Last-Modified header.
+// The responses are buffered to be hashed, up to MaxSize bytes: flushed responses, text/event-stream ones, files
+// (responses with an Accept-Ranges header, like the ones of [echo.Context.File]) and responses bigger than MaxSize
+// (from their Content-Length header or their body) are streamed as is, and WebSocket upgrades are skipped.
+type ETagMiddlewareConfig struct {
+	Skipper middleware.Skipper
+	MaxSize int
+}
+
+// DefaultETagMiddlewareConfig is the default configuration for the [ETagMiddleware].
+var DefaultETagMiddlewareConfig = ETagMiddlewareConfig{
+	Skipper: middleware.DefaultSkipper,
+	MaxSize: DefaultETagMaxSize,
+}
+
+// ETagMiddleware returns an [ETagMiddleware] with the [DefaultETagMiddlewareConfig].
+func ETagMiddleware() echo.MiddlewareFunc {
+	return ETagMiddlewareWithConfig(DefaultETagMiddlewareConfig)
+}
+
+// ETagMiddlewareWithConfig returns an [ETagMiddleware] for a provided [ETagMiddlewareConfig].
+func ETagMiddlewareWithConfig(config ETagMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultETagMiddlewareConfig.Skipper
+	}
+
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultETagMiddlewareConfig.MaxSize
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			// skipper
+			if config.Skipper(c) || c.IsWebSocket() || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+				return next(c)
+			}
+
+			resp := c.Response()
+
+			writer := &etagWriter{
+				ResponseWriter: resp.Writer,
+				maxSize:        config.MaxSize,
+			}
+
+			resp.Writer = writer
+
+			err := next(c)
+
+			resp.Writer = writer.ResponseWriter
+
+			if writer.streaming || (writer.status == 0 && writer.buffer.Len() == 0) {
+				return err
+			}
+
+			status := writer.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			header := resp.Header()
+
+			if err == nil && status == http.StatusOK {
+				if header.Get(HeaderETag) == "" {
+					header.Set(HeaderETag, generateETag(writer.buffer.Bytes()))
+				}
+
+				if notModified(req, header) {
+					header.Del(echo.HeaderContentType)
+					header.Del(echo.HeaderContentLength)
+
+					resp.Status = http.StatusNotModified
+					resp.Size = 0
+					writer.ResponseWriter.WriteHeader(http.StatusNotModified)
+
+					return nil
+				}
+			}
+
+			writer.ResponseWriter.WriteHeader(status)
+
+			_, writeErr := writer.ResponseWriter.Write(writer.buffer.Bytes())
+			if err == nil {
+				err = writeErr
+			}
+
+			return err
+		}
+	}
+}
+
+// generateETag generates a weak ETag from a response body.
+func generateETag(body []byte) string {
+	hash := fnv.New64a()
+	hash.Write(body)
+
+	return fmt.Sprintf(`W/"%x-%x"`, len(body), hash.Sum64())
+}
+
+// notModified returns true if the request conditional headers match the response validators.
+func notModified(req *http.Request, header http.Header) bool {
+	if ifNoneMatch := req.Header.Get(HeaderIfNoneMatch); ifNoneMatch != "" {
+		etag := strings.TrimPrefix(header.Get(HeaderETag), "W/")
+
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+
+			// weak comparison
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(req.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(header.Get(echo.HeaderLastModified))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+// etagWriter buffers the response to hash it, until flushed or detected as not to be buffered.
+type etagWriter struct {
+	http.ResponseWriter
+	maxSize   int
+	status    int
+	buffer    bytes.Buffer
+	streaming bool
+}
+
+func (w *etagWriter) WriteHeader(status int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(status)
+
+		return
+	}
+
+	w.status = status
+
+	if !w.bufferable(0) {
+		w.stream()
+	}
+}
+
+func (w *etagWriter) Write(b []byte) (int, error) {
+	if !w.streaming && !w.bufferable(len(b)) {
+		w.stream()
+	}
+
+	if w.streaming {
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.buffer.Write(b)
+}
+
+// bufferable returns false for event streams, files and responses bigger than the max size.
+func (w *etagWriter) bufferable(size int) bool {
+	header := w.Header()
+
+	if strings.HasPrefix(header.Get(echo.HeaderContentType), "text/event-stream") || header.Get(HeaderAcceptRanges) != "" {
+		return false
+	}
+
+	if contentLength, err := strconv.Atoi(header.Get(echo.HeaderContentLength)); err == nil && contentLength > w.maxSize {
+		return false
+	}
+
+	return w.buffer.Len()+size <= w.maxSize
+}
+
+func (w *etagWriter) Flush() {
+	if !w.streaming {
+		w.stream()
+	}
+
+	//nolint:errcheck // not all writers support flushing
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *etagWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// stream writes the response status and the buffered body, and then lets the response go through.
+func (w *etagWriter) stream() {
+	w.streaming = true
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if w.buffer.Len() > 0 {
+		//nolint:errcheck // the error will surface on the next write
+		w.ResponseWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+	}
+}
@@ -0,0 +1,287 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETagMiddleware(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.ETagMiddleware())
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"name": "john"})
+	})
+
+	// first request, to get the etag
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"john"}`, rec.Body.String())
+
+	etag := rec.Header().Get(middleware.HeaderETag)
+	assert.Regexp(t, `^W/"[0-9a-f]+-[0-9a-f]+"$`, etag)
+
+	tests := []struct {
+		ifNoneMatch string
+		code        int
+	}{
+		{etag, http.StatusNotModified},
+		{etag[2:], http.StatusNotModified},
+		{fmt.Sprintf(`"other", %s`, etag), http.StatusNotModified},
+		{"*", http.StatusNotModified},
+		{`W/"other"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ifNoneMatch, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(middleware.HeaderIfNoneMatch, tt.ifNoneMatch)
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, etag, rec.Header().Get(middleware.HeaderETag))
+
+			if tt.code == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+				assert.Empty(t, rec.Header().Get(echo.HeaderContentType))
+			} else {
+				assert.JSONEq(t, `{"name":"john"}`, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestETagMiddlewareWithIfModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	lastModified := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.ETagMiddleware())
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderLastModified, lastModified.Format(http.TimeFormat))
+
+		return c.String(http.StatusOK, "test")
+	})
+
+	tests := []struct {
+		ifModifiedSince time.Time
+		code            int
+	}{
+		{lastModified, http.StatusNotModified},
+		{lastModified.Add(time.Hour), http.StatusNotModified},
+		{lastModified.Add(-time.Hour), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ifModifiedSince.String(), func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(echo.HeaderIfModifiedSince, tt.ifModifiedSince.Format(http.TimeFormat))
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+		})
+	}
+}
+
+func TestETagMiddlewareWithHandlerETag(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.ETagMiddleware())
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		c.Response().Header().Set(middleware.HeaderETag, `"v1"`)
+
+		return c.String(http.StatusOK, "test")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(middleware.HeaderIfNoneMatch, `"v1"`)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, `"v1"`, rec.Header().Get(middleware.HeaderETag))
+}
+
+func TestETagMiddlewareWithIgnoredResponses(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.ETagMiddlewareWithConfig(middleware.ETagMiddlewareConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Request().URL.Path == "/skipped"
+		},
+	}))
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	}
+
+	httpServer.GET("/skipped", handler)
+	httpServer.POST("/post", handler)
+	httpServer.GET("/error", func(c echo.Context) error {
+		return c.String(http.StatusNotFound, "not found")
+	})
+	httpServer.GET("/stream", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().WriteHeader(http.StatusOK)
+
+		_, err := c.Response().Write([]byte("data: test\n\n"))
+
+		return err
+	})
+	httpServer.GET("/flush", func(c echo.Context) error {
+		c.Response().WriteHeader(http.StatusOK)
+		c.Response().Flush()
+
+		_, err := c.Response().Write([]byte("test"))
+
+		return err
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+	}{
+		{http.MethodGet, "/skipped", http.StatusOK},
+		{http.MethodPost, "/post", http.StatusOK},
+		{http.MethodGet, "/error", http.StatusNotFound},
+		{http.MethodGet, "/stream", http.StatusOK},
+		{http.MethodGet, "/flush", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(middleware.HeaderIfNoneMatch, "*")
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.NotEmpty(t, rec.Body.String())
+			assert.Empty(t, rec.Header().Get(middleware.HeaderETag))
+		})
+	}
+}
+
+func TestETagMiddlewareWithMaxSize(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.ETagMiddlewareWithConfig(middleware.ETagMiddlewareConfig{
+		MaxSize: 10,
+	}))
+
+	httpServer.GET("/small", func(c echo.Context) error {
+		return c.String(http.StatusOK, "small")
+	})
+	httpServer.GET("/large", func(c echo.Context) error {
+		c.Response().WriteHeader(http.StatusOK)
+
+		for range 3 {
+			if _, err := c.Response().Write([]byte("large")); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	httpServer.GET("/content-length", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentLength, "15")
+
+		return c.String(http.StatusOK, "content-length-")
+	})
+	httpServer.Match([]string{http.MethodGet, http.MethodHead}, "/file", func(c echo.Context) error {
+		return c.File("etag_test.go")
+	})
+
+	file, err := os.ReadFile("etag_test.go")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		etag   bool
+	}{
+		{http.MethodGet, "/small", "small", true},
+		{http.MethodGet, "/large", "largelargelarge", false},
+		{http.MethodGet, "/content-length", "content-length-", false},
+		{http.MethodGet, "/file", string(file), false},
+		{http.MethodHead, "/file", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+
+			if tt.etag {
+				assert.NotEmpty(t, rec.Header().Get(middleware.HeaderETag))
+			} else {
+				assert.Empty(t, rec.Header().Get(middleware.HeaderETag))
+			}
+		})
+	}
+}
+
+func TestETagMiddlewareWithCompressionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.CompressionMiddleware(), middleware.ETagMiddleware())
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		return c.String(http.StatusOK, testCompressionBody)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, middleware.EncodingGzip, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, testCompressionBody, decompress(t, middleware.EncodingGzip, rec.Body))
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	req.Header.Set(middleware.HeaderIfNoneMatch, rec.Header().Get(middleware.HeaderETag))
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Empty(t, rec.Body.String())
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/log"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	LogFieldPrincipal     = "principal"
+	LogFieldAuthenticator = "authenticator"
+)
+
+// RequestAuthenticationMiddlewareConfig is the configuration for the [RequestAuthenticationMiddleware].
+//
+// The Authenticators are tried in order, until one finds credentials in the request. The resolved [auth.Principal]
+// is stored in the request context (see [auth.CtxPrincipal]) and in the echo context under [auth.PrincipalContextKey],
+// and added to the request log fields and span attributes.
+// Invalid credentials get an [echo.HTTPError] with a 401 status code, as requests without credentials if Required is true.
+type RequestAuthenticationMiddlewareConfig struct {
+	Skipper        middleware.Skipper
+	Authenticators []auth.Authenticator
+	Required       bool
+}
+
+// DefaultRequestAuthenticationMiddlewareConfig is the default configuration for the [RequestAuthenticationMiddleware].
+var DefaultRequestAuthenticationMiddlewareConfig = RequestAuthenticationMiddlewareConfig{
+	Skipper:        middleware.DefaultSkipper,
+	Authenticators: []auth.Authenticator{},
+	Required:       true,
+}
+
+// RequestAuthenticationMiddleware returns a [RequestAuthenticationMiddleware] with the [DefaultRequestAuthenticationMiddlewareConfig],
+// for the provided authenticators.
+func RequestAuthenticationMiddleware(authenticators ...auth.Authenticator) echo.MiddlewareFunc {
+	config := DefaultRequestAuthenticationMiddlewareConfig
+	config.Authenticators = authenticators
+
+	return RequestAuthenticationMiddlewareWithConfig(config)
+}
+
+// RequestAuthenticationMiddlewareWithConfig returns a [RequestAuthenticationMiddleware] for a provided [RequestAuthenticationMiddlewareConfig].
+func RequestAuthenticationMiddlewareWithConfig(config RequestAuthenticationMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestAuthenticationMiddlewareConfig.Skipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// skipper
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			for _, authenticator := range config.Authenticators {
+				principal, err := authenticator.Authenticate(c)
+				if err != nil {
+					if errors.Is(err, auth.ErrNoCredentials) {
+						continue
+					}
+
+					if errors.Is(err, auth.ErrInvalidCredentials) {
+						log.CtxLogger(c.Request().Context()).
+							Debug().
+							Err(err).
+							Str(LogFieldAuthenticator, authenticator.Name()).
+							Msg("authentication failure")
+
+						return unauthorized(c, config.Authenticators).SetInternal(err)
+					}
+
+					log.CtxLogger(c.Request().Context()).
+						Error().
+						Err(err).
+						Str(LogFieldAuthenticator, authenticator.Name()).
+						Msg("authentication error")
+
+					return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+				}
+
+				if principal == nil {
+					continue
+				}
+
+				if principal.Authenticator == "" {
+					principal.Authenticator = authenticator.Name()
+				}
+
+				storePrincipal(c, principal)
+
+				return next(c)
+			}
+
+			if config.Required {
+				return unauthorized(c, config.Authenticators)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// storePrincipal stores the principal in the request and echo contexts, and adds it to the logger and span.
+func storePrincipal(c echo.Context, principal *auth.Principal) {
+	req := c.Request()
+
+	ctx := auth.WithPrincipal(req.Context(), principal)
+
+	// logger context propagation
+	var logger zerolog.Logger
+	if echoLogger, ok := c.Logger().(*httpserver.EchoLogger); ok {
+		logger = echoLogger.ToZerolog().With().Str(LogFieldPrincipal, principal.ID).Logger()
+	} else {
+		logger = log.CtxLogger(ctx).With().Str(LogFieldPrincipal, principal.ID).Logger()
+	}
+
+	c.SetRequest(req.WithContext(logger.WithContext(ctx)))
+	c.SetLogger(httpserver.NewEchoLogger(log.FromZerolog(logger)))
+	c.Set(auth.PrincipalContextKey, principal)
+
+	// span attributes
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(semconv.EnduserID(principal.ID))
+
+	if len(principal.Roles) > 0 {
+		span.SetAttributes(semconv.EnduserRole(strings.Join(principal.Roles, ",")))
+	}
+
+	if len(principal.Scopes) > 0 {
+		span.SetAttributes(semconv.EnduserScope(strings.Join(principal.Scopes, " ")))
+	}
+}
+
+// unauthorized returns a 401 error, with the challenges of the authenticators.
+func unauthorized(c echo.Context, authenticators []auth.Authenticator) *echo.HTTPError {
+	for _, authenticator := range authenticators {
+		if challenger, ok := authenticator.(auth.Challenger); ok {
+			c.Response().Header().Add(echo.HeaderWWWAuthenticate, challenger.Challenge())
+		}
+	}
+
+	return echo.NewHTTPError(http.StatusUnauthorized)
+}
@@ -0,0 +1,212 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/ankorstore/yokai/trace"
+	"github.com/ankorstore/yokai/trace/tracetest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+type failingAuthenticator struct{}
+
+func (a failingAuthenticator) Name() string {
+	return "failing"
+}
+
+func (a failingAuthenticator) Authenticate(echo.Context) (*auth.Principal, error) {
+	return nil, fmt.Errorf("authenticator error")
+}
+
+func TestRequestAuthenticationMiddleware(t *testing.T) {
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	exporter := tracetest.NewDefaultTestTraceExporter()
+	tracerProvider, err := trace.NewDefaultTracerProviderFactory().Create(
+		trace.Global(false),
+		trace.WithSpanProcessor(trace.NewTestSpanProcessor(exporter)),
+	)
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.Logger = httpserver.NewEchoLogger(logger)
+
+	httpServer.Use(middleware.RequestTracerMiddlewareWithConfig("test", middleware.RequestTracerMiddlewareConfig{
+		TracerProvider: tracerProvider,
+	}))
+	httpServer.Use(middleware.RequestLoggerMiddleware())
+	httpServer.Use(middleware.RequestAuthenticationMiddleware(
+		auth.NewAPIKeyAuthenticator(auth.NewStaticAPIKeyStore(map[string]*auth.Principal{
+			"key1": {ID: "service1", Scopes: []string{"read", "write"}, Roles: []string{"admin"}},
+		})),
+		auth.NewBasicAuthenticator(map[string]auth.BasicUser{
+			"alice": {Password: "password"},
+		}),
+	))
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		principal := auth.CtxPrincipal(c.Request().Context())
+
+		httpserver.CtxLogger(c).Info().Msg("test log")
+
+		return c.String(http.StatusOK, fmt.Sprintf("%s:%s:%v", principal.ID, principal.Authenticator, c.Get(auth.PrincipalContextKey)))
+	})
+
+	// api key authentication
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "key1")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "service1:api_key:service1", rec.Body.String())
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":     "info",
+		"message":   "test log",
+		"principal": "service1",
+	})
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":     "info",
+		"message":   "request logger",
+		"status":    http.StatusOK,
+		"principal": "service1",
+	})
+
+	tracetest.AssertHasTraceSpan(
+		t,
+		exporter,
+		"GET /test",
+		semconv.EnduserID("service1"),
+		semconv.EnduserRole("admin"),
+		semconv.EnduserScope("read write"),
+	)
+
+	// basic authentication
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.SetBasicAuth("alice", "password")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice:basic:alice", rec.Body.String())
+
+	// invalid credentials
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.SetBasicAuth("alice", "invalid")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Basic realm="Restricted"`, rec.Header().Get(echo.HeaderWWWAuthenticate))
+
+	// missing credentials
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequestAuthenticationMiddlewareWithOptionalAuthentication(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestAuthenticationMiddlewareWithConfig(middleware.RequestAuthenticationMiddlewareConfig{
+		Authenticators: []auth.Authenticator{
+			auth.NewAPIKeyAuthenticator(auth.NewStaticAPIKeyStore(map[string]*auth.Principal{
+				"key1": {ID: "service1"},
+			})),
+		},
+		Required: false,
+	}))
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		if principal := auth.CtxPrincipal(c.Request().Context()); principal != nil {
+			return c.String(http.StatusOK, principal.ID)
+		}
+
+		return c.String(http.StatusOK, "anonymous")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "anonymous", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "key1")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "service1", rec.Body.String())
+
+	// invalid credentials are still rejected
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(auth.DefaultAPIKeyHeader, "invalid")
+	rec = httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestRequestAuthenticationMiddlewareWithAuthenticatorError(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestAuthenticationMiddleware(failingAuthenticator{}))
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestRequestAuthenticationMiddlewareWithSkipper(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestAuthenticationMiddlewareWithConfig(middleware.RequestAuthenticationMiddlewareConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Request().URL.Path == "/public"
+		},
+		Authenticators: []auth.Authenticator{failingAuthenticator{}},
+		Required:       true,
+	}))
+
+	httpServer.GET("/public", func(c echo.Context) error {
+		assert.Nil(t, auth.CtxPrincipal(c.Request().Context()))
+
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/public", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestAuthorizationMiddlewareConfig is the configuration for the [RequestAuthorizationMiddleware].
+//
+// Requests must carry an [auth.Principal], resolved by the [RequestAuthenticationMiddleware], otherwise an
+// [echo.HTTPError] with a 401 status code is returned. If Authenticators are provided, the principal must have been
+// resolved by one of them, also with a 401 status code otherwise.
+// The principal must have all the Scopes and Roles, otherwise an [echo.HTTPError] with a 403 status code is returned.
+type RequestAuthorizationMiddlewareConfig struct {
+	Skipper        middleware.Skipper
+	Authenticators []string
+	Scopes         []string
+	Roles          []string
+}
+
+// DefaultRequestAuthorizationMiddlewareConfig is the default configuration for the [RequestAuthorizationMiddleware].
+var DefaultRequestAuthorizationMiddlewareConfig = RequestAuthorizationMiddlewareConfig{
+	Skipper:        middleware.DefaultSkipper,
+	Authenticators: []string{},
+	Scopes:         []string{},
+	Roles:          []string{},
+}
+
+// RequestAuthorizationMiddleware returns a [RequestAuthorizationMiddleware] with the [DefaultRequestAuthorizationMiddlewareConfig],
+// only requiring an authenticated principal.
+func RequestAuthorizationMiddleware() echo.MiddlewareFunc {
+	return RequestAuthorizationMiddlewareWithConfig(DefaultRequestAuthorizationMiddlewareConfig)
+}
+
+// RequestAuthorizationMiddlewareWithConfig returns a [RequestAuthorizationMiddleware] for a provided [RequestAuthorizationMiddlewareConfig].
+func RequestAuthorizationMiddlewareWithConfig(config RequestAuthorizationMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestAuthorizationMiddlewareConfig.Skipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// skipper
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			principal := auth.CtxPrincipal(c.Request().Context())
+			if principal == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized)
+			}
+
+			if len(config.Authenticators) > 0 {
+				accepted := false
+				for _, authenticator := range config.Authenticators {
+					if principal.Authenticator == authenticator {
+						accepted = true
+
+						break
+					}
+				}
+
+				if !accepted {
+					return echo.NewHTTPError(http.StatusUnauthorized)
+				}
+			}
+
+			if !principal.HasScopes(config.Scopes...) || !principal.HasRoles(config.Roles...) {
+				return echo.NewHTTPError(http.StatusForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
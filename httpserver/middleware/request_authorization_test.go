@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/auth"
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestAuthorizationMiddleware(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestAuthenticationMiddlewareWithConfig(middleware.RequestAuthenticationMiddlewareConfig{
+		Authenticators: []auth.Authenticator{
+			auth.NewAPIKeyAuthenticator(auth.NewStaticAPIKeyStore(map[string]*auth.Principal{
+				"reader": {ID: "reader", Scopes: []string{"read"}},
+				"admin":  {ID: "admin", Scopes: []string{"read", "write"}, Roles: []string{"admin"}},
+			})),
+			auth.NewBasicAuthenticator(map[string]auth.BasicUser{
+				"alice": {Password: "password", Scopes: []string{"read", "write"}, Roles: []string{"admin"}},
+			}),
+		},
+		Required: false,
+	}))
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+
+	httpServer.GET("/authenticated", handler, middleware.RequestAuthorizationMiddleware())
+	httpServer.GET("/scoped", handler, middleware.RequestAuthorizationMiddlewareWithConfig(middleware.RequestAuthorizationMiddlewareConfig{
+		Scopes: []string{"read", "write"},
+	}))
+	httpServer.GET("/admin", handler, middleware.RequestAuthorizationMiddlewareWithConfig(middleware.RequestAuthorizationMiddlewareConfig{
+		Authenticators: []string{auth.DefaultAPIKeyAuthenticatorName},
+		Roles:          []string{"admin"},
+	}))
+
+	send := func(path string, apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if apiKey != "" {
+			req.Header.Set(auth.DefaultAPIKeyHeader, apiKey)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send("/authenticated", ""))
+	assert.Equal(t, http.StatusOK, send("/authenticated", "reader"))
+
+	assert.Equal(t, http.StatusUnauthorized, send("/scoped", ""))
+	assert.Equal(t, http.StatusForbidden, send("/scoped", "reader"))
+	assert.Equal(t, http.StatusOK, send("/scoped", "admin"))
+
+	assert.Equal(t, http.StatusForbidden, send("/admin", "reader"))
+	assert.Equal(t, http.StatusOK, send("/admin", "admin"))
+
+	// not accepted authenticator
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.SetBasicAuth("alice", "password")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRequestAuthorizationMiddlewareWithSkipper(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.GET("/test", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, middleware.RequestAuthorizationMiddlewareWithConfig(middleware.RequestAuthorizationMiddlewareConfig{
+		Skipper: func(echo.Context) bool {
+			return true
+		},
+		Roles: []string{"admin"},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
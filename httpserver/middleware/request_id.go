@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/generate/uuid"
 	"github.com/ankorstore/yokai/httpserver"
 	"github.com/labstack/echo/v4"
@@ -10,9 +11,12 @@ import (
 )
 
 // RequestIdMiddlewareConfig is the configuration for the [RequestIdMiddleware].
+//
+// If a Validator is provided, incoming request ids failing validation are replaced by generated ones.
 type RequestIdMiddlewareConfig struct {
 	Skipper         middleware.Skipper
 	Generator       uuid.UuidGenerator
+	Validator       correlation.CorrelationIdValidator
 	RequestIdHeader string
 }
 
@@ -54,6 +58,10 @@ func RequestIdMiddlewareWithConfig(config RequestIdMiddlewareConfig) echo.Middle
 			// request_id req / resp header propagation
 			rid := req.Header.Get(config.RequestIdHeader)
 
+			if rid != "" && config.Validator != nil && config.Validator.Validate(rid) != nil {
+				rid = ""
+			}
+
 			if rid == "" {
 				rid = config.Generator.Generate()
 				req.Header.Set(config.RequestIdHeader, rid)
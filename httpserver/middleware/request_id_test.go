@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/ankorstore/yokai/generate/correlation"
 	"github.com/ankorstore/yokai/generate/generatetest/uuid"
 	"github.com/ankorstore/yokai/httpserver/middleware"
 	"github.com/labstack/echo/v4"
@@ -115,6 +116,58 @@ func TestRequestIdMiddlewareWithCustomGenerator(t *testing.T) {
 	assert.Equal(t, "generated-id", rec.Header().Get(echo.HeaderXRequestID))
 }
 
+func TestRequestIdMiddlewareWithValidator(t *testing.T) {
+	t.Parallel()
+
+	validator, err := correlation.NewDefaultCorrelationIdValidator(10, "^[a-z-]+$")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		incomingId string
+		expectedId string
+	}{
+		{"valid incoming id", "valid-id", "valid-id"},
+		{"too long incoming id", "too-long-valid-id", "generated-id"},
+		{"invalid format incoming id", "invalid id", "generated-id"},
+		{"missing incoming id", "", "generated-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			httpServer := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incomingId != "" {
+				req.Header.Add(echo.HeaderXRequestID, tt.incomingId)
+			}
+			rec := httptest.NewRecorder()
+
+			ctx := httpServer.NewContext(req, rec)
+			handler := func(c echo.Context) error {
+				return c.String(
+					http.StatusOK,
+					c.Request().Header.Get(echo.HeaderXRequestID),
+				)
+			}
+
+			m := middleware.RequestIdMiddlewareWithConfig(middleware.RequestIdMiddlewareConfig{
+				Generator: uuid.NewTestUuidGenerator("generated-id"),
+				Validator: validator,
+			})
+			h := m(handler)
+
+			err := h(ctx)
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expectedId, rec.Body.String())
+			assert.Equal(t, tt.expectedId, rec.Header().Get(echo.HeaderXRequestID))
+		})
+	}
+}
+
 func TestRequestIdMiddlewareWithCustomIdHeader(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,303 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/idempotency"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/ankorstore/yokai/log"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	DefaultIdempotencyTTL          = 24 * time.Hour
+	DefaultIdempotencyMaxSize      = 1 << 20
+	MaxIdempotencyKeyLength        = 255
+	MissingIdempotencyKeyMessage   = "missing idempotency key"
+	InvalidIdempotencyKeyMessage   = "invalid idempotency key"
+	ConcurrentIdempotencyMessage   = "a request with the same idempotency key is in progress"
+	MismatchingIdempotencyMessage  = "the idempotency key was used for a different request"
+	UnavailableIdempotencyMessage  = "cannot check idempotency key"
+	idempotencyConcurrentRetryTime = "1"
+)
+
+// RequestIdempotencyMiddlewareConfig is the configuration for the [RequestIdempotencyMiddleware].
+//
+// The mutating requests (all but GET, HEAD and OPTIONS) with an Idempotency-Key header are executed once per key and
+// TTL: their response is stored and replayed on retries, with an Idempotent-Replayed header. Retries while the first
+// request is in progress get a 409 error, and requests reusing a key with a different method, uri or body get a 422
+// error. The requests without key are executed as is, or get a 400 error if Required is true.
+//
+// The responses with a 5xx status, or returned as errors to the error handler, are not stored: the key is released
+// for the request to be retried. If Scope is provided, the keys are scoped by its value (for example by principal).
+//
+// Only the [DefaultIdempotencyHeaders] and the provided Headers of the responses are stored and replayed, to avoid
+// to replay per request headers (like Set-Cookie).
+//
+// The requests and responses bodies are buffered up to MaxSize bytes: the requests with a bigger body are executed
+// without idempotency, and the bigger responses are not stored (the key is released).
+type RequestIdempotencyMiddlewareConfig struct {
+	Skipper  middleware.Skipper
+	Store    idempotency.Store
+	TTL      time.Duration
+	Required bool
+	Scope    ratelimit.KeyExtractor
+	Headers  []string
+	MaxSize  int
+}
+
+// DefaultIdempotencyHeaders are the response headers always stored and replayed by the [RequestIdempotencyMiddleware].
+var DefaultIdempotencyHeaders = []string{
+	echo.HeaderContentType,
+	echo.HeaderContentEncoding,
+	echo.HeaderLocation,
+	HeaderETag,
+}
+
+// DefaultRequestIdempotencyMiddlewareConfig is the default configuration for the [RequestIdempotencyMiddleware].
+var DefaultRequestIdempotencyMiddlewareConfig = RequestIdempotencyMiddlewareConfig{
+	Skipper:  middleware.DefaultSkipper,
+	TTL:      DefaultIdempotencyTTL,
+	Required: false,
+	MaxSize:  DefaultIdempotencyMaxSize,
+}
+
+// RequestIdempotencyMiddleware returns a [RequestIdempotencyMiddleware] with the
+// [DefaultRequestIdempotencyMiddlewareConfig], for the provided [idempotency.Store].
+func RequestIdempotencyMiddleware(store idempotency.Store) echo.MiddlewareFunc {
+	config := DefaultRequestIdempotencyMiddlewareConfig
+	config.Store = store
+
+	return RequestIdempotencyMiddlewareWithConfig(config)
+}
+
+// RequestIdempotencyMiddlewareWithConfig returns a [RequestIdempotencyMiddleware] for a provided
+// [RequestIdempotencyMiddlewareConfig].
+//
+//nolint:cyclop
+func RequestIdempotencyMiddlewareWithConfig(config RequestIdempotencyMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestIdempotencyMiddlewareConfig.Skipper
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = DefaultRequestIdempotencyMiddlewareConfig.TTL
+	}
+
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultRequestIdempotencyMiddlewareConfig.MaxSize
+	}
+
+	headers := make([]string, 0, len(DefaultIdempotencyHeaders)+len(config.Headers))
+	for _, name := range append(slices.Clone(DefaultIdempotencyHeaders), config.Headers...) {
+		if name = http.CanonicalHeaderKey(name); !slices.Contains(headers, name) {
+			headers = append(headers, name)
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			// skipper
+			if config.Skipper(c) || config.Store == nil {
+				return next(c)
+			}
+
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			key := req.Header.Get(idempotency.HeaderIdempotencyKey)
+			if key == "" {
+				if config.Required {
+					return echo.NewHTTPError(http.StatusBadRequest, MissingIdempotencyKeyMessage)
+				}
+
+				return next(c)
+			}
+
+			if len(key) > MaxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, InvalidIdempotencyKeyMessage)
+			}
+
+			if config.Scope != nil {
+				if scope := config.Scope(c); scope != "" {
+					key = fmt.Sprintf("%s:%s", scope, key)
+				}
+			}
+
+			// fingerprint, the requests bodies bigger than MaxSize are not buffered
+			if req.ContentLength > int64(config.MaxSize) {
+				return next(c)
+			}
+
+			var body []byte
+			if req.Body != nil {
+				var err error
+
+				body, err = io.ReadAll(io.LimitReader(req.Body, int64(config.MaxSize)+1))
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+				}
+
+				if len(body) > config.MaxSize {
+					req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+
+					return next(c)
+				}
+
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			fingerprint := idempotency.Fingerprint(req, body)
+
+			record, err := config.Store.Acquire(req.Context(), key, fingerprint, config.TTL)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, UnavailableIdempotencyMessage).SetInternal(err)
+			}
+
+			if record != nil {
+				switch {
+				case record.Fingerprint != fingerprint:
+					return echo.NewHTTPError(http.StatusUnprocessableEntity, MismatchingIdempotencyMessage)
+				case !record.Completed():
+					c.Response().Header().Set(echo.HeaderRetryAfter, idempotencyConcurrentRetryTime)
+
+					return echo.NewHTTPError(http.StatusConflict, ConcurrentIdempotencyMessage)
+				default:
+					return replayIdempotentResponse(c, record.Response, headers)
+				}
+			}
+
+			return executeIdempotentRequest(c, next, config.Store, key, headers, config.MaxSize)
+		}
+	}
+}
+
+func executeIdempotentRequest(
+	c echo.Context,
+	next echo.HandlerFunc,
+	store idempotency.Store,
+	key string,
+	headers []string,
+	maxSize int,
+) error {
+	resp := c.Response()
+	writer := resp.Writer
+
+	idempotentWriter := &idempotencyWriter{
+		ResponseWriter: writer,
+		maxSize:        maxSize,
+	}
+
+	resp.Writer = idempotentWriter
+
+	// the key is released if the handler panics, for the request to be retried
+	defer func() {
+		resp.Writer = writer
+
+		if r := recover(); r != nil {
+			releaseIdempotencyKey(c, store, key)
+
+			panic(r)
+		}
+	}()
+
+	err := next(c)
+
+	if err != nil || !resp.Committed || resp.Status >= http.StatusInternalServerError || idempotentWriter.overflow {
+		releaseIdempotencyKey(c, store, key)
+
+		return err
+	}
+
+	// the store is updated even if the request was canceled
+	ctx := context.WithoutCancel(c.Request().Context())
+
+	completeErr := store.Complete(ctx, key, &idempotency.Response{
+		Status: resp.Status,
+		Header: filterIdempotentHeader(resp.Header(), headers),
+		Body:   idempotentWriter.body.Bytes(),
+	})
+	if completeErr != nil {
+		log.CtxLogger(ctx).Error().Err(completeErr).Msg("cannot complete idempotency key")
+	}
+
+	return nil
+}
+
+func releaseIdempotencyKey(c echo.Context, store idempotency.Store, key string) {
+	ctx := context.WithoutCancel(c.Request().Context())
+
+	if err := store.Release(ctx, key); err != nil {
+		log.CtxLogger(ctx).Error().Err(err).Msg("cannot release idempotency key")
+	}
+}
+
+func replayIdempotentResponse(c echo.Context, response *idempotency.Response, headers []string) error {
+	resp := c.Response()
+
+	for name, values := range filterIdempotentHeader(response.Header, headers) {
+		resp.Header()[name] = values
+	}
+
+	resp.Header().Set(idempotency.HeaderIdempotentReplayed, "true")
+	resp.WriteHeader(response.Status)
+
+	_, err := resp.Write(response.Body)
+
+	return err
+}
+
+// filterIdempotentHeader returns a copy of the header, with only the provided canonical header names.
+func filterIdempotentHeader(header http.Header, names []string) http.Header {
+	filtered := http.Header{}
+
+	for _, name := range names {
+		if values := header.Values(name); len(values) > 0 {
+			filtered[name] = slices.Clone(values)
+		}
+	}
+
+	return filtered
+}
+
+// idempotencyWriter records the response body up to maxSize bytes, while writing it.
+type idempotencyWriter struct {
+	http.ResponseWriter
+	maxSize  int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(b) > w.maxSize {
+			// too big to be stored
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) Flush() {
+	//nolint:errcheck // not all writers support flushing
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *idempotencyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
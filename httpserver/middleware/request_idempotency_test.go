@@ -0,0 +1,386 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/idempotency"
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingIdempotencyStore struct{}
+
+func (s failingIdempotencyStore) Acquire(context.Context, string, string, time.Duration) (*idempotency.Record, error) {
+	return nil, fmt.Errorf("store error")
+}
+
+func (s failingIdempotencyStore) Complete(context.Context, string, *idempotency.Response) error {
+	return fmt.Errorf("store error")
+}
+
+func (s failingIdempotencyStore) Release(context.Context, string) error {
+	return fmt.Errorf("store error")
+}
+
+func sendIdempotentRequest(httpServer *echo.Echo, method string, path string, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	if key != "" {
+		req.Header.Set(idempotency.HeaderIdempotencyKey, key)
+	}
+
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRequestIdempotencyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestIdempotencyMiddlewareWithConfig(middleware.RequestIdempotencyMiddlewareConfig{
+		Store:   idempotency.NewMemoryStore(),
+		Headers: []string{"x-order"},
+	}))
+
+	httpServer.POST("/orders", func(c echo.Context) error {
+		count := calls.Add(1)
+
+		var order map[string]any
+		if err := c.Bind(&order); err != nil {
+			return err
+		}
+
+		c.Response().Header().Set("X-Order", fmt.Sprintf("%d", count))
+		c.Response().Header().Set(echo.HeaderXRequestID, fmt.Sprintf("request-%d", count))
+		c.Response().Header().Set(echo.HeaderSetCookie, fmt.Sprintf("session=%d", count))
+		c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("/orders/%d", count))
+
+		return c.JSON(http.StatusCreated, map[string]any{"id": count, "name": order["name"]})
+	})
+
+	// first request
+	rec := sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", `{"name":"foo"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":1,"name":"foo"}`, rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get("X-Order"))
+	assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	// retry
+	rec = sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", `{"name":"foo"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":1,"name":"foo"}`, rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get("X-Order"))
+	assert.Equal(t, "/orders/1", rec.Header().Get(echo.HeaderLocation))
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON))
+	assert.Empty(t, rec.Header().Get(echo.HeaderXRequestID))
+	assert.Empty(t, rec.Header().Get(echo.HeaderSetCookie))
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	// mismatching retry
+	rec = sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", `{"name":"bar"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), middleware.MismatchingIdempotencyMessage)
+
+	// other key
+	rec = sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "other", `{"name":"bar"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":2,"name":"bar"}`, rec.Body.String())
+
+	// without key
+	rec = sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "", `{"name":"baz"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id":3,"name":"baz"}`, rec.Body.String())
+
+	// invalid key
+	rec = sendIdempotentRequest(httpServer, http.MethodPost, "/orders", strings.Repeat("k", 256), `{"name":"baz"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), middleware.InvalidIdempotencyKeyMessage)
+
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRequestIdempotencyMiddlewareWithConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestIdempotencyMiddleware(idempotency.NewMemoryStore()))
+
+	httpServer.POST("/orders", func(c echo.Context) error {
+		close(started)
+		<-release
+
+		return c.String(http.StatusCreated, "created")
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		done <- sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", "body")
+	}()
+
+	<-started
+
+	rec := sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", "body")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Contains(t, rec.Body.String(), middleware.ConcurrentIdempotencyMessage)
+
+	close(release)
+
+	rec = <-done
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "created", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+}
+
+func TestRequestIdempotencyMiddlewareWithFailures(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestIdempotencyMiddleware(idempotency.NewMemoryStore()))
+
+	httpServer.POST("/error", func(c echo.Context) error {
+		calls.Add(1)
+
+		return echo.NewHTTPError(http.StatusBadRequest, "error")
+	})
+	httpServer.POST("/unavailable", func(c echo.Context) error {
+		calls.Add(1)
+
+		return c.String(http.StatusServiceUnavailable, "unavailable")
+	})
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/error", http.StatusBadRequest},
+		{"/unavailable", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		// the key is released, the request is executed again
+		rec := sendIdempotentRequest(httpServer, http.MethodPost, tt.path, "key"+tt.path, "body")
+		assert.Equal(t, tt.code, rec.Code)
+		assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+		rec = sendIdempotentRequest(httpServer, http.MethodPost, tt.path, "key"+tt.path, "body")
+		assert.Equal(t, tt.code, rec.Code)
+		assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+	}
+
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestRequestIdempotencyMiddlewareWithPanic(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestIdempotencyMiddleware(idempotency.NewMemoryStore()))
+
+	httpServer.POST("/orders", func(c echo.Context) error {
+		if calls.Add(1) == 1 {
+			panic("handler panic")
+		}
+
+		return c.String(http.StatusCreated, "created")
+	})
+
+	assert.PanicsWithValue(t, "handler panic", func() {
+		sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", "body")
+	})
+
+	// the key is released, the request is executed again
+	rec := sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", "body")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "created", rec.Body.String())
+	assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	rec = sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", "body")
+	assert.Equal(t, "created", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRequestIdempotencyMiddlewareWithMaxSize(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestIdempotencyMiddlewareWithConfig(middleware.RequestIdempotencyMiddlewareConfig{
+		Store:   idempotency.NewMemoryStore(),
+		MaxSize: 10,
+	}))
+
+	httpServer.POST("/echo", func(c echo.Context) error {
+		calls.Add(1)
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+
+		return c.String(http.StatusOK, string(body))
+	})
+
+	tests := []struct {
+		name  string
+		body  string
+		calls int32
+	}{
+		{"small", "small", 1},
+		{"big", "too big to be stored", 2},
+	}
+
+	for _, tt := range tests {
+		calls.Store(0)
+
+		for range 2 {
+			rec := sendIdempotentRequest(httpServer, http.MethodPost, "/echo", "key-"+tt.name, tt.body)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		}
+
+		assert.Equal(t, tt.calls, calls.Load(), tt.name)
+	}
+
+	// chunked request bodies, without content length
+	calls.Store(0)
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/echo", io.MultiReader(strings.NewReader("too big "), strings.NewReader("to be stored")))
+		req.Header.Set(idempotency.HeaderIdempotencyKey, "key-chunked")
+
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "too big to be stored", rec.Body.String())
+	}
+
+	assert.Equal(t, int32(2), calls.Load())
+
+	// responses bigger than the max size are not stored
+	httpServer.POST("/big", func(c echo.Context) error {
+		calls.Add(1)
+
+		return c.String(http.StatusOK, "too big to be stored")
+	})
+
+	calls.Store(0)
+
+	for range 2 {
+		rec := sendIdempotentRequest(httpServer, http.MethodPost, "/big", "key-big-response", "small")
+		assert.Equal(t, "too big to be stored", rec.Body.String())
+		assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+	}
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRequestIdempotencyMiddlewareWithConfig(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestIdempotencyMiddlewareWithConfig(middleware.RequestIdempotencyMiddlewareConfig{
+		Skipper: func(c echo.Context) bool {
+			return c.Request().URL.Path == "/skipped"
+		},
+		Store:    idempotency.NewMemoryStore(),
+		TTL:      time.Hour,
+		Required: true,
+		Scope:    ratelimit.KeyByHeader("X-Tenant"),
+	}))
+
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, fmt.Sprintf("%d", calls.Add(1)))
+	}
+
+	httpServer.GET("/orders", handler)
+	httpServer.POST("/orders", handler)
+	httpServer.POST("/skipped", handler)
+
+	send := func(method string, path string, key string, tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-Tenant", tenant)
+
+		if key != "" {
+			req.Header.Set(idempotency.HeaderIdempotencyKey, key)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// required key
+	rec := send(http.MethodPost, "/orders", "", "foo")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), middleware.MissingIdempotencyKeyMessage)
+
+	// safe methods and skipped paths
+	rec = send(http.MethodGet, "/orders", "", "foo")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Body.String())
+
+	rec = send(http.MethodPost, "/skipped", "", "foo")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Body.String())
+
+	// scoped keys
+	rec = send(http.MethodPost, "/orders", "key", "foo")
+	assert.Equal(t, "3", rec.Body.String())
+
+	rec = send(http.MethodPost, "/orders", "key", "foo")
+	assert.Equal(t, "3", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+
+	rec = send(http.MethodPost, "/orders", "key", "bar")
+	assert.Equal(t, "4", rec.Body.String())
+	assert.Empty(t, rec.Header().Get(idempotency.HeaderIdempotentReplayed))
+}
+
+func TestRequestIdempotencyMiddlewareWithFailingStore(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(middleware.RequestIdempotencyMiddleware(failingIdempotencyStore{}))
+
+	httpServer.POST("/orders", func(c echo.Context) error {
+		return c.String(http.StatusCreated, "created")
+	})
+
+	rec := sendIdempotentRequest(httpServer, http.MethodPost, "/orders", "key", "body")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), middleware.UnavailableIdempotencyMessage)
+}
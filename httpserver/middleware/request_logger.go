@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/auth"
 	"github.com/ankorstore/yokai/log"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -21,11 +22,15 @@ const (
 )
 
 // RequestLoggerMiddlewareConfig is the configuration for the [RequestLoggerMiddleware].
+//
+// The requests matching RequestUriPrefixesToExclude, or for which RequestExcluder returns true, are not logged unless
+// they fail with an error or a 5xx status. Unlike the Skipper, the logger is still propagated in their context.
 type RequestLoggerMiddlewareConfig struct {
 	Skipper                         middleware.Skipper
 	LogLevelFromResponseOrErrorCode bool
 	RequestHeadersToLog             map[string]string
 	RequestUriPrefixesToExclude     []string
+	RequestExcluder                 middleware.Skipper
 }
 
 // DefaultRequestLoggerMiddlewareConfig is the default configuration for the [RequestLoggerMiddleware].
@@ -121,7 +126,10 @@ func RequestLoggerMiddlewareWithConfig(config RequestLoggerMiddlewareConfig) ech
 			}
 
 			// skip if matching exclusions and not error or code > 500
-			if httpserver.MatchPrefix(config.RequestUriPrefixesToExclude, req.RequestURI) &&
+			excluded := httpserver.MatchPrefix(config.RequestUriPrefixesToExclude, req.RequestURI) ||
+				(config.RequestExcluder != nil && config.RequestExcluder(c))
+
+			if excluded &&
 				err == nil &&
 				status < http.StatusInternalServerError {
 				return nil
@@ -175,6 +183,11 @@ func RequestLoggerMiddlewareWithConfig(config RequestLoggerMiddlewareConfig) ech
 				evt.Str("spanID", spanContext.SpanID().String())
 			}
 
+			// log event principal
+			if principal := auth.CtxPrincipal(c.Request().Context()); principal != nil {
+				evt.Str(LogFieldPrincipal, principal.ID)
+			}
+
 			// log event propagation
 			evt.
 				Str("method", req.Method).
@@ -211,6 +211,53 @@ func TestRequestLoggerMiddlewareWithCustomRequestUriToExclude(t *testing.T) {
 	assert.False(t, hasRecord)
 }
 
+func TestRequestLoggerMiddlewareWithRequestExcluder(t *testing.T) {
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.Logger = httpserver.NewEchoLogger(logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/test/123", nil)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	ctx.SetPath("/test/:id")
+
+	handler := func(c echo.Context) error {
+		c.Logger().Info("test")
+
+		return c.String(http.StatusOK, "ok")
+	}
+
+	m := middleware.RequestLoggerMiddlewareWithConfig(middleware.RequestLoggerMiddlewareConfig{
+		RequestExcluder: func(c echo.Context) bool {
+			return c.Path() == "/test/:id"
+		},
+	})
+	h := m(handler)
+
+	err = h(ctx)
+	assert.NoError(t, err)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "info",
+		"message": "test",
+	})
+
+	hasRecord, err := logBuffer.HasRecord(map[string]interface{}{
+		"level":   "info",
+		"method":  "GET",
+		"uri":     "/test/123",
+		"message": "request logger",
+	})
+	assert.NoError(t, err)
+	assert.False(t, hasRecord)
+}
+
 func TestRequestLoggerMiddlewareWithCustomRequestUriToExcludeWithResponseError(t *testing.T) {
 	logBuffer := logtest.NewDefaultTestLogBuffer()
 	logger, err := log.NewDefaultLoggerFactory().Create(
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/ankorstore/yokai/log"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	HttpServerMetricsRequestsRateLimited = "http_server_requests_rate_limited_total"
+	HeaderRateLimitLimit                 = "RateLimit-Limit"
+	HeaderRateLimitRemaining             = "RateLimit-Remaining"
+	HeaderRateLimitReset                 = "RateLimit-Reset"
+	DefaultRateLimitName                 = "default"
+)
+
+// RequestRateLimitMiddlewareConfig is the configuration for the [RequestRateLimitMiddleware].
+//
+// Requests are keyed by Name and by the KeyExtractor result (client IP by default), and checked against the Limit in
+// the Store. Limited requests get an [echo.HTTPError] with a 429 status code, to be rendered by the error handler.
+// If the Store fails, requests are allowed, unless DenyOnStoreError is true.
+type RequestRateLimitMiddlewareConfig struct {
+	Skipper              middleware.Skipper
+	Name                 string
+	Store                ratelimit.Store
+	Limit                ratelimit.Limit
+	KeyExtractor         ratelimit.KeyExtractor
+	DenyOnStoreError     bool
+	Registry             prometheus.Registerer
+	Namespace            string
+	Subsystem            string
+	NormalizeRequestPath bool
+}
+
+// DefaultRequestRateLimitMiddlewareConfig is the default configuration for the [RequestRateLimitMiddleware].
+var DefaultRequestRateLimitMiddlewareConfig = RequestRateLimitMiddlewareConfig{
+	Skipper: middleware.DefaultSkipper,
+	Name:    DefaultRateLimitName,
+	Limit: ratelimit.Limit{
+		Algorithm: ratelimit.TokenBucket,
+		Requests:  100,
+		Period:    time.Minute,
+	},
+	KeyExtractor:         ratelimit.KeyByIP(),
+	DenyOnStoreError:     false,
+	Registry:             prometheus.DefaultRegisterer,
+	Namespace:            "",
+	Subsystem:            "",
+	NormalizeRequestPath: true,
+}
+
+// RequestRateLimitMiddleware returns a [RequestRateLimitMiddleware] with the [DefaultRequestRateLimitMiddlewareConfig],
+// and an in memory store.
+func RequestRateLimitMiddleware() echo.MiddlewareFunc {
+	return RequestRateLimitMiddlewareWithConfig(DefaultRequestRateLimitMiddlewareConfig)
+}
+
+// RequestRateLimitMiddlewareWithConfig returns a [RequestRateLimitMiddleware] for a provided [RequestRateLimitMiddlewareConfig].
+func RequestRateLimitMiddlewareWithConfig(config RequestRateLimitMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestRateLimitMiddlewareConfig.Skipper
+	}
+
+	if config.Name == "" {
+		config.Name = DefaultRequestRateLimitMiddlewareConfig.Name
+	}
+
+	if config.Store == nil {
+		config.Store = ratelimit.NewMemoryStore()
+	}
+
+	if config.Limit.Requests <= 0 || config.Limit.Period <= 0 {
+		config.Limit = DefaultRequestRateLimitMiddlewareConfig.Limit
+	}
+
+	if config.KeyExtractor == nil {
+		config.KeyExtractor = DefaultRequestRateLimitMiddlewareConfig.KeyExtractor
+	}
+
+	if config.Registry == nil {
+		config.Registry = DefaultRequestRateLimitMiddlewareConfig.Registry
+	}
+
+	httpRequestsRateLimitedCounter := registerRateLimitedCounter(config)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// skipper
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+
+			key := config.KeyExtractor(c)
+			if key == "" {
+				key = c.RealIP()
+			}
+
+			result, err := config.Store.Allow(req.Context(), fmt.Sprintf("%s:%s", config.Name, key), config.Limit)
+			if err != nil {
+				log.CtxLogger(req.Context()).Error().Err(err).Str("limit", config.Name).Msg("rate limit store error")
+
+				if config.DenyOnStoreError {
+					return echo.NewHTTPError(http.StatusServiceUnavailable).SetInternal(err)
+				}
+
+				return next(c)
+			}
+
+			headers := c.Response().Header()
+			headers.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+			headers.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+			headers.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				headers.Set(echo.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+
+				path := req.URL.Path
+				if config.NormalizeRequestPath && c.Path() != "" {
+					path = c.Path()
+				}
+
+				httpRequestsRateLimitedCounter.WithLabelValues(config.Name, req.Method, path).Inc()
+
+				return echo.NewHTTPError(http.StatusTooManyRequests)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// registerRateLimitedCounter registers the rate limited requests counter, or reuses the already registered one,
+// since several rate limit middlewares can share the same registry. If the counter cannot be registered, the error
+// is logged and the requests are still rate limited, without metrics.
+func registerRateLimitedCounter(config RequestRateLimitMiddlewareConfig) *prometheus.CounterVec {
+	counter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Subsystem: config.Subsystem,
+			Name:      HttpServerMetricsRequestsRateLimited,
+			Help:      "Number of rate limited HTTP requests",
+		},
+		[]string{
+			"limit",
+			"method",
+			"path",
+		},
+	)
+
+	if err := config.Registry.Register(counter); err != nil {
+		var alreadyRegisteredError prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegisteredError) {
+			if existingCounter, ok := alreadyRegisteredError.ExistingCollector.(*prometheus.CounterVec); ok {
+				return existingCounter
+			}
+		}
+
+		log.CtxLogger(context.Background()).Error().Err(err).Msg("cannot register rate limited requests counter")
+	}
+
+	return counter
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
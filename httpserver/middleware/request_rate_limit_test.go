@@ -0,0 +1,213 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingRateLimitStore struct{}
+
+func (s failingRateLimitStore) Allow(context.Context, string, ratelimit.Limit) (*ratelimit.Result, error) {
+	return nil, fmt.Errorf("store error")
+}
+
+func TestRequestRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewPedanticRegistry()
+
+	httpServer := echo.New()
+	httpServer.GET("/limited/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{
+		Name:                 "test",
+		Limit:                ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Requests: 2, Period: time.Hour},
+		KeyExtractor:         ratelimit.KeyByHeader("X-Api-Key"),
+		Registry:             registry,
+		Namespace:            "foo",
+		Subsystem:            "bar",
+		NormalizeRequestPath: true,
+	}))
+
+	send := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/limited/1", nil)
+		req.Header.Set("X-Api-Key", apiKey)
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := send("key1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(middleware.HeaderRateLimitLimit))
+	assert.Equal(t, "1", rec.Header().Get(middleware.HeaderRateLimitRemaining))
+	assert.NotEmpty(t, rec.Header().Get(middleware.HeaderRateLimitReset))
+	assert.Empty(t, rec.Header().Get(echo.HeaderRetryAfter))
+
+	rec = send("key1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get(middleware.HeaderRateLimitRemaining))
+
+	rec = send("key1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get(middleware.HeaderRateLimitRemaining))
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderRetryAfter))
+
+	// other key
+	rec = send("key2")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	expectedMetric := `
+		# HELP foo_bar_http_server_requests_rate_limited_total Number of rate limited HTTP requests
+		# TYPE foo_bar_http_server_requests_rate_limited_total counter
+		foo_bar_http_server_requests_rate_limited_total{limit="test",method="GET",path="/limited/:id"} 1
+	`
+
+	err := testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expectedMetric),
+		"foo_bar_http_server_requests_rate_limited_total",
+	)
+	assert.NoError(t, err)
+}
+
+func TestRequestRateLimitMiddlewareWithSharedRegistry(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewPedanticRegistry()
+
+	assert.NotPanics(t, func() {
+		middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{Registry: registry})
+		middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{Registry: registry})
+	})
+}
+
+func TestRequestRateLimitMiddlewareWithRegistryError(t *testing.T) {
+	t.Parallel()
+
+	// conflicting metric, with different labels
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: middleware.HttpServerMetricsRequestsRateLimited, Help: "conflicting"},
+		[]string{"other"},
+	))
+
+	httpServer := echo.New()
+
+	assert.NotPanics(t, func() {
+		httpServer.Use(middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{
+			Limit:    ratelimit.Limit{Algorithm: ratelimit.TokenBucket, Requests: 1, Period: time.Minute},
+			Registry: registry,
+		}))
+	})
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	// still rate limited, without metrics
+	codes := make([]int, 2)
+	for i := range codes {
+		rec := httptest.NewRecorder()
+		httpServer.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+		codes[i] = rec.Code
+	}
+
+	assert.Equal(t, []int{http.StatusNoContent, http.StatusTooManyRequests}, codes)
+}
+
+func TestRequestRateLimitMiddlewareWithDefaults(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := httpServer.NewContext(req, rec)
+
+	m := middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{
+		Registry: prometheus.NewPedanticRegistry(),
+	})
+
+	err := m(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get(middleware.HeaderRateLimitLimit))
+	assert.Equal(t, "99", rec.Header().Get(middleware.HeaderRateLimitRemaining))
+}
+
+func TestRequestRateLimitMiddlewareWithSkipper(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	ctx := httpServer.NewContext(req, rec)
+
+	m := middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{
+		Skipper: func(echo.Context) bool {
+			return true
+		},
+		Registry: prometheus.NewPedanticRegistry(),
+	})
+
+	err := m(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})(ctx)
+	assert.NoError(t, err)
+
+	assert.Empty(t, rec.Header().Get(middleware.HeaderRateLimitLimit))
+}
+
+func TestRequestRateLimitMiddlewareWithStoreError(t *testing.T) {
+	t.Parallel()
+
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	// allowed by default
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	m := middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{
+		Store:    failingRateLimitStore{},
+		Registry: prometheus.NewPedanticRegistry(),
+	})
+
+	err := m(handler)(httpServer.NewContext(req, rec))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// denied if configured
+	rec = httptest.NewRecorder()
+
+	m = middleware.RequestRateLimitMiddlewareWithConfig(middleware.RequestRateLimitMiddlewareConfig{
+		Store:            failingRateLimitStore{},
+		DenyOnStoreError: true,
+		Registry:         prometheus.NewPedanticRegistry(),
+	})
+
+	err = m(handler)(httpServer.NewContext(req, rec))
+	assert.Error(t, err)
+
+	var httpError *echo.HTTPError
+	assert.ErrorAs(t, err, &httpError)
+	assert.Equal(t, http.StatusServiceUnavailable, httpError.Code)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// DefaultRequestTimeout is the default request timeout.
+const DefaultRequestTimeout = 30 * time.Second
+
+// RequestTimeoutMiddlewareConfig is the configuration for the [RequestTimeoutMiddleware].
+//
+// The request context is canceled after the Timeout: if the handler did not write the response by then, an
+// [echo.HTTPError] with the StatusCode (503 by default) is returned, to be rendered by the error handler.
+type RequestTimeoutMiddlewareConfig struct {
+	Skipper    middleware.Skipper
+	Timeout    time.Duration
+	StatusCode int
+}
+
+// DefaultRequestTimeoutMiddlewareConfig is the default configuration for the [RequestTimeoutMiddleware].
+var DefaultRequestTimeoutMiddlewareConfig = RequestTimeoutMiddlewareConfig{
+	Skipper:    middleware.DefaultSkipper,
+	Timeout:    DefaultRequestTimeout,
+	StatusCode: http.StatusServiceUnavailable,
+}
+
+// RequestTimeoutMiddleware returns a [RequestTimeoutMiddleware] with the [DefaultRequestTimeoutMiddlewareConfig].
+func RequestTimeoutMiddleware() echo.MiddlewareFunc {
+	return RequestTimeoutMiddlewareWithConfig(DefaultRequestTimeoutMiddlewareConfig)
+}
+
+// RequestTimeoutMiddlewareWithConfig returns a [RequestTimeoutMiddleware] for a provided [RequestTimeoutMiddlewareConfig].
+func RequestTimeoutMiddlewareWithConfig(config RequestTimeoutMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestTimeoutMiddlewareConfig.Skipper
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultRequestTimeoutMiddlewareConfig.Timeout
+	}
+
+	if config.StatusCode == 0 {
+		config.StatusCode = DefaultRequestTimeoutMiddlewareConfig.StatusCode
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+
+			ctx, cancel := context.WithTimeout(req.Context(), config.Timeout)
+			defer cancel()
+
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Response().Committed {
+				internal := err
+				if internal == nil {
+					internal = ctx.Err()
+				}
+
+				return echo.NewHTTPError(config.StatusCode).SetInternal(internal)
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTimeoutMiddlewareWithDefaults(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		deadline, ok := c.Request().Context().Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(middleware.DefaultRequestTimeout), deadline, time.Second)
+
+		return c.String(http.StatusOK, "test")
+	}
+
+	m := middleware.RequestTimeoutMiddleware()
+	h := m(handler)
+
+	err := h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "test", rec.Body.String())
+}
+
+func TestRequestTimeoutMiddlewareWithSkipper(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		_, ok := c.Request().Context().Deadline()
+		assert.False(t, ok)
+
+		return c.String(http.StatusOK, "test")
+	}
+
+	m := middleware.RequestTimeoutMiddlewareWithConfig(middleware.RequestTimeoutMiddlewareConfig{
+		Skipper: func(echo.Context) bool {
+			return true
+		},
+	})
+	h := m(handler)
+
+	err := h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRequestTimeoutMiddlewareWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		statusCode     int
+		handlerErr     error
+		expectedStatus int
+	}{
+		{"default status without handler error", 0, nil, http.StatusServiceUnavailable},
+		{"default status with handler error", 0, fmt.Errorf("custom error"), http.StatusServiceUnavailable},
+		{"custom status", http.StatusGatewayTimeout, nil, http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			httpServer := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			ctx := httpServer.NewContext(req, rec)
+			handler := func(c echo.Context) error {
+				<-c.Request().Context().Done()
+
+				return tt.handlerErr
+			}
+
+			m := middleware.RequestTimeoutMiddlewareWithConfig(middleware.RequestTimeoutMiddlewareConfig{
+				Timeout:    10 * time.Millisecond,
+				StatusCode: tt.statusCode,
+			})
+			h := m(handler)
+
+			err := h(ctx)
+			assert.Error(t, err)
+
+			var httpErr *echo.HTTPError
+			assert.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, tt.expectedStatus, httpErr.Code)
+			assert.Equal(t, http.StatusText(tt.expectedStatus), httpErr.Message)
+
+			if tt.handlerErr != nil {
+				assert.Equal(t, tt.handlerErr, httpErr.Internal)
+			} else {
+				assert.ErrorIs(t, httpErr.Internal, context.DeadlineExceeded)
+			}
+		})
+	}
+}
+
+func TestRequestTimeoutMiddlewareWithCommittedResponse(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		err := c.String(http.StatusOK, "test")
+
+		<-c.Request().Context().Done()
+
+		return err
+	}
+
+	m := middleware.RequestTimeoutMiddlewareWithConfig(middleware.RequestTimeoutMiddlewareConfig{
+		Timeout: 10 * time.Millisecond,
+	})
+	h := m(handler)
+
+	err := h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "test", rec.Body.String())
+}
@@ -0,0 +1,292 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/log"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	InvalidRequestMessage  = "request does not match the contract"
+	InvalidResponseMessage = "response does not match the contract"
+)
+
+// RequestValidationMiddlewareConfig is the configuration for the [RequestValidationMiddleware].
+//
+// Requests matching an operation of the Router contract get their path, query, headers and body validated: invalid
+// requests get an [echo.HTTPError] with a 400 status code, with an [httpserver.ValidationError] as internal error for
+// the error handler to render the invalid parameters. Requests not matching any operation are not validated.
+// The contract security requirements are not validated, this is done by the authentication middleware.
+//
+// If ValidateResponses is true, the handlers responses are validated as well: invalid responses are logged, or
+// replaced by an [echo.HTTPError] with a 500 status code if FailOnInvalidResponse is true (the responses are then
+// buffered, which makes this mode unfit for streamed responses).
+type RequestValidationMiddlewareConfig struct {
+	Skipper               middleware.Skipper
+	Router                routers.Router
+	ValidateResponses     bool
+	FailOnInvalidResponse bool
+}
+
+// DefaultRequestValidationMiddlewareConfig is the default configuration for the [RequestValidationMiddleware].
+var DefaultRequestValidationMiddlewareConfig = RequestValidationMiddlewareConfig{
+	Skipper:               middleware.DefaultSkipper,
+	ValidateResponses:     false,
+	FailOnInvalidResponse: false,
+}
+
+// RequestValidationMiddleware returns a [RequestValidationMiddleware] with the [DefaultRequestValidationMiddlewareConfig],
+// for the provided contract router (see [openapi.NewContractRouter]).
+func RequestValidationMiddleware(router routers.Router) echo.MiddlewareFunc {
+	config := DefaultRequestValidationMiddlewareConfig
+	config.Router = router
+
+	return RequestValidationMiddlewareWithConfig(config)
+}
+
+// RequestValidationMiddlewareWithConfig returns a [RequestValidationMiddleware] for a provided [RequestValidationMiddlewareConfig].
+func RequestValidationMiddlewareWithConfig(config RequestValidationMiddlewareConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestValidationMiddlewareConfig.Skipper
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// skipper
+			if config.Skipper(c) || config.Router == nil {
+				return next(c)
+			}
+
+			req := c.Request()
+
+			// requests not described by the contract are left to the router
+			route, pathParams, err := config.Router.FindRoute(req)
+			if err != nil {
+				return next(c)
+			}
+
+			requestValidationInput := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			err = openapi3filter.ValidateRequest(req.Context(), requestValidationInput)
+			if err != nil {
+				validationErr := httpserver.NewValidationError(InvalidRequestMessage, invalidParams(err)...)
+
+				return echo.NewHTTPError(http.StatusBadRequest, InvalidRequestMessage).SetInternal(validationErr)
+			}
+
+			if !config.ValidateResponses {
+				return next(c)
+			}
+
+			return validateResponse(c, next, config, requestValidationInput)
+		}
+	}
+}
+
+func validateResponse(
+	c echo.Context,
+	next echo.HandlerFunc,
+	config RequestValidationMiddlewareConfig,
+	requestValidationInput *openapi3filter.RequestValidationInput,
+) error {
+	resp := c.Response()
+	writer := resp.Writer
+
+	recorder := &validationWriter{
+		ResponseWriter: writer,
+		buffered:       config.FailOnInvalidResponse,
+	}
+
+	resp.Writer = recorder
+
+	err := next(c)
+
+	resp.Writer = writer
+
+	if err != nil || !resp.Committed {
+		return errors.Join(err, recorder.flush())
+	}
+
+	responseValidationInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestValidationInput,
+		Status:                 resp.Status,
+		Header:                 resp.Header(),
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+		},
+	}
+	responseValidationInput.SetBodyBytes(recorder.body.Bytes())
+
+	validationErr := openapi3filter.ValidateResponse(c.Request().Context(), responseValidationInput)
+	if validationErr == nil {
+		return recorder.flush()
+	}
+
+	if !config.FailOnInvalidResponse {
+		log.CtxLogger(c.Request().Context()).
+			Warn().
+			Err(validationErr).
+			Int("status", resp.Status).
+			Msg(InvalidResponseMessage)
+
+		return nil
+	}
+
+	// the buffered response is discarded, for the error handler to write the error response
+	resp.Committed = false
+	resp.Status = 0
+	resp.Size = 0
+	resp.Header().Del(echo.HeaderContentLength)
+
+	return echo.NewHTTPError(http.StatusInternalServerError, InvalidResponseMessage).SetInternal(validationErr)
+}
+
+// invalidParams converts contract validation errors into [httpserver.InvalidParam].
+func invalidParams(err error) []httpserver.InvalidParam {
+	var params []httpserver.InvalidParam
+
+	// type assertion, since request errors unwrap to the multiple errors of their schema validation
+	if multiErr, ok := err.(openapi3.MultiError); ok { //nolint:errorlint
+		for _, e := range multiErr {
+			params = append(params, invalidParams(e)...)
+		}
+
+		return params
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return []httpserver.InvalidParam{{Reason: err.Error()}}
+	}
+
+	param := httpserver.InvalidParam{}
+
+	switch {
+	case requestErr.Parameter != nil:
+		param.In = requestErr.Parameter.In
+		param.Name = requestErr.Parameter.Name
+	case requestErr.RequestBody != nil:
+		param.In = "body"
+	}
+
+	var schemaErrs []*openapi3.SchemaError
+
+	var nestedMultiErr openapi3.MultiError
+	if errors.As(requestErr.Err, &nestedMultiErr) {
+		for _, e := range nestedMultiErr {
+			var schemaErr *openapi3.SchemaError
+			if errors.As(e, &schemaErr) {
+				schemaErrs = append(schemaErrs, schemaErr)
+			}
+		}
+	} else {
+		var schemaErr *openapi3.SchemaError
+		if errors.As(requestErr.Err, &schemaErr) {
+			schemaErrs = append(schemaErrs, schemaErr)
+		}
+	}
+
+	if len(schemaErrs) == 0 {
+		param.Reason = requestErr.Reason
+		if requestErr.Err != nil && (param.Reason == "" || param.In != "body") {
+			param.Reason = requestErr.Err.Error()
+		}
+
+		return []httpserver.InvalidParam{param}
+	}
+
+	for _, schemaErr := range schemaErrs {
+		params = append(params, httpserver.InvalidParam{
+			Name:   schemaErrorName(param.Name, schemaErr),
+			In:     param.In,
+			Reason: schemaErr.Reason,
+		})
+	}
+
+	return params
+}
+
+// schemaErrorName returns the dotted path of the value failing a schema validation.
+func schemaErrorName(name string, schemaErr *openapi3.SchemaError) string {
+	var parts []string
+	if name != "" {
+		parts = append(parts, name)
+	}
+
+	return strings.Join(append(parts, schemaErr.JSONPointer()...), ".")
+}
+
+// validationWriter records the response body for its validation. If buffered, the response is only written on flush,
+// otherwise it is written as it goes.
+type validationWriter struct {
+	http.ResponseWriter
+	buffered bool
+	status   int
+	body     bytes.Buffer
+}
+
+func (r *validationWriter) WriteHeader(status int) {
+	r.status = status
+
+	if !r.buffered {
+		r.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (r *validationWriter) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	if r.buffered {
+		return len(b), nil
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *validationWriter) Flush() {
+	if !r.buffered {
+		//nolint:errcheck // not all writers support flushing
+		http.NewResponseController(r.ResponseWriter).Flush()
+	}
+}
+
+func (r *validationWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *validationWriter) flush() error {
+	if !r.buffered {
+		return nil
+	}
+
+	r.buffered = false
+
+	if r.status != 0 {
+		r.ResponseWriter.WriteHeader(r.status)
+	}
+
+	_, err := io.Copy(r.ResponseWriter, &r.body)
+
+	return err
+}
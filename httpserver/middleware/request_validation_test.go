@@ -0,0 +1,267 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/httpserver/middleware"
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func contractRouter(t *testing.T) routers.Router {
+	t.Helper()
+
+	doc, err := openapi.LoadContract("../testdata/openapi/contract.yaml")
+	assert.NoError(t, err)
+
+	router, err := openapi.NewContractRouter(doc)
+	assert.NoError(t, err)
+
+	return router
+}
+
+func TestRequestValidationMiddlewareWithValidRequest(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"john","address":{"street":"main"}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		var body map[string]any
+		err := c.Bind(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, "john", body["name"])
+
+		return c.JSON(http.StatusCreated, body)
+	}
+
+	m := middleware.RequestValidationMiddleware(contractRouter(t))
+	h := m(handler)
+
+	err := h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestRequestValidationMiddlewareWithInvalidParameters(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users/abc?fields=password", nil)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		t.Error("handler should not be called")
+
+		return nil
+	}
+
+	m := middleware.RequestValidationMiddleware(contractRouter(t))
+	h := m(handler)
+
+	err := h(ctx)
+	assert.Error(t, err)
+
+	var httpErr *echo.HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, middleware.InvalidRequestMessage, httpErr.Message)
+
+	var validationErr *httpserver.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.InvalidParams, 3)
+
+	names := make(map[string]string)
+	for _, param := range validationErr.InvalidParams {
+		names[param.In+"."+param.Name] = param.Reason
+	}
+
+	assert.Contains(t, names, "path.id")
+	assert.Contains(t, names, "query.fields")
+	assert.Equal(t, "value is required but missing", names["header.X-Tenant"])
+}
+
+func TestRequestValidationMiddlewareWithInvalidBody(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"id":"1","name":"","address":{}}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		t.Error("handler should not be called")
+
+		return nil
+	}
+
+	m := middleware.RequestValidationMiddleware(contractRouter(t))
+	h := m(handler)
+
+	err := h(ctx)
+
+	var validationErr *httpserver.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	names := make([]string, len(validationErr.InvalidParams))
+	for i, param := range validationErr.InvalidParams {
+		assert.Equal(t, "body", param.In)
+		names[i] = param.Name
+	}
+
+	assert.ElementsMatch(t, []string{"id", "name", "address.street"}, names)
+}
+
+func TestRequestValidationMiddlewareWithUnknownRoute(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	}
+
+	m := middleware.RequestValidationMiddleware(contractRouter(t))
+	h := m(handler)
+
+	err := h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRequestValidationMiddlewareWithSkipper(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "test")
+	}
+
+	m := middleware.RequestValidationMiddlewareWithConfig(middleware.RequestValidationMiddlewareConfig{
+		Skipper: func(echo.Context) bool {
+			return true
+		},
+		Router: contractRouter(t),
+	})
+	h := m(handler)
+
+	err := h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRequestValidationMiddlewareWithValidResponse(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Tenant", "test")
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]any{"id": 1, "name": "john", "address": map[string]any{"street": "main"}})
+	}
+
+	m := middleware.RequestValidationMiddlewareWithConfig(middleware.RequestValidationMiddlewareConfig{
+		Router:                contractRouter(t),
+		ValidateResponses:     true,
+		FailOnInvalidResponse: true,
+	})
+	h := m(handler)
+
+	err := h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":1,"name":"john","address":{"street":"main"}}`, rec.Body.String())
+}
+
+func TestRequestValidationMiddlewareWithInvalidResponseFailure(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.HTTPErrorHandler = httpserver.NewJsonErrorHandler(false, false).Handle()
+
+	httpServer.Use(middleware.RequestValidationMiddlewareWithConfig(middleware.RequestValidationMiddlewareConfig{
+		Router:                contractRouter(t),
+		ValidateResponses:     true,
+		FailOnInvalidResponse: true,
+	}))
+
+	httpServer.GET("/users/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]any{"id": "1"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Tenant", "test")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), middleware.InvalidResponseMessage)
+	assert.NotContains(t, rec.Body.String(), `"id":"1"`)
+}
+
+func TestRequestValidationMiddlewareWithInvalidResponseLog(t *testing.T) {
+	t.Parallel()
+
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("X-Tenant", "test")
+	req = req.WithContext(logger.WithContext(req.Context()))
+	rec := httptest.NewRecorder()
+
+	ctx := httpServer.NewContext(req, rec)
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]any{"id": "1"})
+	}
+
+	m := middleware.RequestValidationMiddlewareWithConfig(middleware.RequestValidationMiddlewareConfig{
+		Router:            contractRouter(t),
+		ValidateResponses: true,
+	})
+	h := m(handler)
+
+	err = h(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"id":"1"}`, rec.Body.String())
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "warn",
+		"status":  http.StatusOK,
+		"message": middleware.InvalidResponseMessage,
+	})
+}
@@ -0,0 +1,194 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"gopkg.in/yaml.v3"
+)
+
+// contractVersion is the OpenAPI version the contracts are validated with.
+const contractVersion = "3.0.3"
+
+// LoadContract loads an OpenAPI 3.0 or 3.1 contract, in json or yaml, from a file.
+// The external references of the contract are resolved relatively to this file.
+// OpenAPI 3.1 contracts (like the generated documents) are converted to OpenAPI 3.0.
+func LoadContract(filePath string) (*openapi3.T, error) {
+	return loadContract(os.DirFS(filepath.Dir(filePath)), filepath.Base(filePath), filePath)
+}
+
+// LoadContractFS loads an OpenAPI 3.0 or 3.1 contract, in json or yaml, from a [fs.FS] (for example an [embed.FS]).
+// The external references of the contract are resolved from the same [fs.FS].
+// OpenAPI 3.1 contracts (like the generated documents) are converted to OpenAPI 3.0.
+func LoadContractFS(fsys fs.FS, filePath string) (*openapi3.T, error) {
+	return loadContract(fsys, filePath, filePath)
+}
+
+func loadContract(fsys fs.FS, filePath string, name string) (*openapi3.T, error) {
+	data, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read contract %s: %w", name, err)
+	}
+
+	// the external references of an OpenAPI 3.1 contract are converted as well
+	downgrade := strings.HasPrefix(readContractVersion(data), "3.1")
+	if downgrade {
+		data, err = downgradeContract(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load contract %s: %w", name, err)
+		}
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+		refData, err := fs.ReadFile(fsys, strings.TrimPrefix(path.Clean(location.Path), "/"))
+		if err != nil || !downgrade {
+			return refData, err
+		}
+
+		return downgradeContract(refData)
+	}
+
+	doc, err := loader.LoadFromDataWithPath(data, &url.URL{Path: filePath})
+	if err != nil {
+		return nil, fmt.Errorf("cannot load contract %s: %w", name, err)
+	}
+
+	err = doc.Validate(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract %s: %w", name, err)
+	}
+
+	return doc, nil
+}
+
+// NewContractRouter returns a [routers.Router] matching requests against the operations of an OpenAPI 3.0 contract.
+// The contract servers are ignored: the operations paths are matched against the requests paths.
+func NewContractRouter(doc *openapi3.T) (routers.Router, error) {
+	routerDoc := *doc
+	routerDoc.Servers = nil
+
+	router, err := legacy.NewRouter(&routerDoc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create contract router: %w", err)
+	}
+
+	return router, nil
+}
+
+// downgradeContract converts an OpenAPI 3.1 contract (or a file referenced by it), in json or yaml, to an OpenAPI 3.0
+// json contract, since the contracts are validated with OpenAPI 3.0. The schemas type arrays with null, numeric
+// exclusive bounds, examples and base64 content encoding are converted to their OpenAPI 3.0 equivalent.
+func downgradeContract(data []byte) ([]byte, error) {
+	var content any
+
+	err := yaml.Unmarshal(data, &content)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode contract: %w", err)
+	}
+
+	content = downgradeValue(content)
+
+	if root, ok := content.(map[string]any); ok && root["openapi"] != nil {
+		root["openapi"] = contractVersion
+	}
+
+	return json.Marshal(content)
+}
+
+// readContractVersion returns the OpenAPI version of a contract, or an empty string if it cannot be decoded.
+func readContractVersion(data []byte) string {
+	var header struct {
+		OpenAPI string `yaml:"openapi"`
+	}
+
+	//nolint:errcheck // undecodable contracts are reported by their loading
+	yaml.Unmarshal(data, &header)
+
+	return header.OpenAPI
+}
+
+func downgradeValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = downgradeValue(item)
+		}
+
+		downgradeSchema(v)
+
+		return v
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = item
+		}
+
+		return downgradeValue(converted)
+	case []any:
+		for i, item := range v {
+			v[i] = downgradeValue(item)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
+
+func downgradeSchema(schema map[string]any) {
+	if types, ok := schema["type"].([]any); ok {
+		var nonNullTypes []any
+		for _, t := range types {
+			if t == "null" {
+				schema["nullable"] = true
+			} else {
+				nonNullTypes = append(nonNullTypes, t)
+			}
+		}
+
+		switch len(nonNullTypes) {
+		case 0:
+			delete(schema, "type")
+		case 1:
+			schema["type"] = nonNullTypes[0]
+		default:
+			schema["type"] = nonNullTypes
+		}
+	}
+
+	for exclusive, bound := range map[string]string{"exclusiveMinimum": "minimum", "exclusiveMaximum": "maximum"} {
+		switch value := schema[exclusive].(type) {
+		case int, int64, uint64, float64:
+			schema[bound] = value
+			schema[exclusive] = true
+		}
+	}
+
+	if examples, ok := schema["examples"].([]any); ok {
+		if len(examples) > 0 {
+			schema["example"] = examples[0]
+		}
+
+		delete(schema, "examples")
+	}
+
+	if encoding, ok := schema["contentEncoding"].(string); ok {
+		if encoding == "base64" && schema["format"] == nil {
+			schema["format"] = "byte"
+		}
+
+		delete(schema, "contentEncoding")
+	}
+}
@@ -0,0 +1,97 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadContract(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.LoadContract("../testdata/openapi/contract.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test", doc.Info.Title)
+	assert.NotNil(t, doc.Paths.Value("/users/{id}"))
+
+	schema := doc.Paths.Value("/users").Post.RequestBody.Value.Content.Get("application/json").Schema
+	assert.Equal(t, []string{"name", "address"}, schema.Value.Required)
+}
+
+func TestLoadContractFS(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.LoadContractFS(os.DirFS("../testdata"), "openapi/contract.yaml")
+	assert.NoError(t, err)
+
+	schema := doc.Paths.Value("/users").Post.RequestBody.Value.Content.Get("application/json").Schema
+	assert.Contains(t, schema.Value.Properties, "address")
+}
+
+func TestLoadContractWithOpenAPI31(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.LoadContract("../testdata/openapi/contract31.yaml")
+	assert.NoError(t, err)
+
+	// converted to openapi 3.0, including the referenced files
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	schema := doc.Paths.Value("/products").Post.RequestBody.Value.Content.Get("application/json").Schema
+	assert.Equal(t, []string{"name", "price"}, schema.Value.Required)
+
+	assert.Equal(t, "book", schema.Value.Properties["name"].Value.Example)
+
+	price := schema.Value.Properties["price"].Value
+	assert.Equal(t, float64(0), *price.Min)
+	assert.True(t, price.ExclusiveMin)
+	assert.Equal(t, float64(1000), *price.Max)
+	assert.True(t, price.ExclusiveMax)
+
+	description := schema.Value.Properties["description"].Value
+	assert.True(t, description.Type.Is("string"))
+	assert.True(t, description.Nullable)
+
+	assert.Equal(t, "byte", schema.Value.Properties["picture"].Value.Format)
+}
+
+func TestLoadContractFailures(t *testing.T) {
+	t.Parallel()
+
+	_, err := openapi.LoadContract("../testdata/openapi/missing.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read contract ../testdata/openapi/missing.yaml")
+
+	_, err = openapi.LoadContract("../testdata/openapi/invalid.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid contract")
+}
+
+func TestNewContractRouter(t *testing.T) {
+	t.Parallel()
+
+	doc, err := openapi.LoadContract("../testdata/openapi/contract.yaml")
+	assert.NoError(t, err)
+
+	router, err := openapi.NewContractRouter(doc)
+	assert.NoError(t, err)
+
+	route, pathParams, err := router.FindRoute(httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/{id}", route.Path)
+	assert.Equal(t, map[string]string{"id": "1"}, pathParams)
+
+	_, _, err = router.FindRoute(httptest.NewRequest(http.MethodDelete, "/users/1", nil))
+	assert.Error(t, err)
+
+	_, _, err = router.FindRoute(httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	assert.Error(t, err)
+
+	// the contract servers are left untouched
+	assert.Len(t, doc.Servers, 1)
+}
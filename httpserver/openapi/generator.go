@@ -0,0 +1,389 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	MIMEApplicationJSON = "application/json"
+	MIMETextPlain       = "text/plain"
+)
+
+// OperationSpec is the specification of an operation, from which the [Generator] generates its documentation.
+//
+// Request is a value of the type bound from the requests: its struct fields tagged with param, query or header
+// are documented as parameters, and its other fields as the json request body.
+type OperationSpec struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	Request     any
+	Responses   []ResponseSpec
+	Security    []SecurityRequirement
+}
+
+// ResponseSpec is the specification of an operation response.
+//
+// Body is a value of the type of the response body, or nil if the response has no body. The content type is
+// text/plain for strings, and application/json otherwise, if not provided.
+type ResponseSpec struct {
+	Status      int
+	Description string
+	ContentType string
+	Body        any
+}
+
+// Generator generates an OpenAPI [Document] from operations specifications.
+type Generator struct {
+	document *Document
+	schemas  *SchemaGenerator
+}
+
+// GeneratorOption are functional options for the [Generator].
+type GeneratorOption func(g *Generator)
+
+// WithInfo is used to specify the document metadata.
+func WithInfo(info Info) GeneratorOption {
+	return func(g *Generator) {
+		g.document.Info = info
+	}
+}
+
+// WithServers is used to specify the document servers.
+func WithServers(servers ...Server) GeneratorOption {
+	return func(g *Generator) {
+		g.document.Servers = append(g.document.Servers, servers...)
+	}
+}
+
+// WithSecurityScheme is used to declare a security scheme, to be referenced by name in security requirements.
+func WithSecurityScheme(name string, scheme *SecurityScheme) GeneratorOption {
+	return func(g *Generator) {
+		g.document.Components.SecuritySchemes[name] = scheme
+	}
+}
+
+// WithSecurity is used to specify the security requirements applying to all operations (any of them).
+func WithSecurity(requirements ...SecurityRequirement) GeneratorOption {
+	return func(g *Generator) {
+		g.document.Security = append(g.document.Security, requirements...)
+	}
+}
+
+// WithSchemaGenerator is used to specify the [SchemaGenerator] generating the requests and responses schemas.
+func WithSchemaGenerator(schemas *SchemaGenerator) GeneratorOption {
+	return func(g *Generator) {
+		g.schemas = schemas
+	}
+}
+
+// NewGenerator returns a new [Generator].
+func NewGenerator(options ...GeneratorOption) *Generator {
+	generator := &Generator{
+		document: &Document{
+			OpenAPI: Version,
+			Info: Info{
+				Title:   "API",
+				Version: "1.0.0",
+			},
+			Paths: make(map[string]*PathItem),
+			Components: &Components{
+				Schemas:         make(map[string]*Schema),
+				SecuritySchemes: make(map[string]*SecurityScheme),
+			},
+		},
+		schemas: NewSchemaGenerator(),
+	}
+
+	for _, opt := range options {
+		opt(generator)
+	}
+
+	return generator
+}
+
+// AddOperation documents an operation, for a http method and an echo path (for example /users/:id).
+func (g *Generator) AddOperation(method string, path string, spec OperationSpec) error {
+	openAPIPath, pathParams := ConvertPath(path)
+
+	pathItem, ok := g.document.Paths[openAPIPath]
+	if !ok {
+		pathItem = &PathItem{}
+	}
+
+	slot, err := pathItem.operation(method)
+	if err != nil {
+		return err
+	}
+
+	if *slot != nil {
+		return fmt.Errorf("duplicate operation %s %s", strings.ToUpper(method), path)
+	}
+
+	operation := &Operation{
+		OperationID: spec.OperationID,
+		Summary:     spec.Summary,
+		Description: spec.Description,
+		Tags:        spec.Tags,
+		Deprecated:  spec.Deprecated,
+		Security:    spec.Security,
+	}
+
+	if spec.Request != nil {
+		g.addRequest(operation, strings.ToUpper(method), reflect.TypeOf(spec.Request))
+	}
+
+	// path params not bound from the request
+	for _, pathParam := range pathParams {
+		if !hasParameter(operation, pathParam, "path") {
+			operation.Parameters = append(operation.Parameters, &Parameter{
+				Name:     pathParam,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+
+	for _, responseSpec := range spec.Responses {
+		g.addResponse(operation, responseSpec)
+	}
+
+	*slot = operation
+	g.document.Paths[openAPIPath] = pathItem
+
+	return nil
+}
+
+// Document returns the generated OpenAPI document.
+func (g *Generator) Document() *Document {
+	document := *g.document
+
+	components := &Components{
+		Schemas:         g.schemas.Schemas(),
+		SecuritySchemes: g.document.Components.SecuritySchemes,
+	}
+
+	if len(components.Schemas) > 0 || len(components.SecuritySchemes) > 0 {
+		document.Components = components
+	} else {
+		document.Components = nil
+	}
+
+	return &document
+}
+
+func (g *Generator) addRequest(operation *Operation, method string, requestType reflect.Type) {
+	for requestType.Kind() == reflect.Pointer {
+		requestType = requestType.Elem()
+	}
+
+	hasBody := method != http.MethodGet && method != http.MethodHead && method != http.MethodDelete
+
+	if requestType.Kind() != reflect.Struct || requestType == timeType {
+		if hasBody {
+			operation.RequestBody = jsonRequestBody(g.schemas.Generate(requestType))
+		}
+
+		return
+	}
+
+	var bodyFields bool
+	var paramFields bool
+
+	g.requestFields(requestType, func(field reflect.StructField) {
+		in, name := parameterLocation(field)
+		if in == "" {
+			bodyFields = true
+
+			return
+		}
+
+		paramFields = true
+
+		schema, required := g.schemas.fieldSchema(field)
+
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name:        name,
+			In:          in,
+			Description: schema.Description,
+			Required:    required || in == "path",
+			Schema:      schema,
+		})
+	})
+
+	if !hasBody || !bodyFields {
+		return
+	}
+
+	// requests without parameters fields are documented with their reusable schema
+	if !paramFields {
+		operation.RequestBody = jsonRequestBody(g.schemas.Generate(requestType))
+
+		return
+	}
+
+	operation.RequestBody = jsonRequestBody(g.schemas.structSchema(requestType, func(field reflect.StructField) bool {
+		in, _ := parameterLocation(field)
+
+		return in == ""
+	}))
+}
+
+func (g *Generator) requestFields(t reflect.Type, fn func(field reflect.StructField)) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		if _, ok := jsonFieldName(field); !ok {
+			if in, _ := parameterLocation(field); in == "" {
+				continue
+			}
+		}
+
+		if field.Anonymous {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			if fieldType.Kind() == reflect.Struct {
+				g.requestFields(fieldType, fn)
+
+				continue
+			}
+		}
+
+		if field.IsExported() {
+			fn(field)
+		}
+	}
+}
+
+func (g *Generator) addResponse(operation *Operation, spec ResponseSpec) {
+	status := "default"
+	if spec.Status > 0 {
+		status = strconv.Itoa(spec.Status)
+	}
+
+	response := &Response{
+		Description: spec.Description,
+	}
+
+	if response.Description == "" {
+		response.Description = http.StatusText(spec.Status)
+		if response.Description == "" {
+			response.Description = "Default response"
+		}
+	}
+
+	if spec.Body != nil {
+		bodyType := reflect.TypeOf(spec.Body)
+
+		contentType := spec.ContentType
+		if contentType == "" {
+			contentType = MIMEApplicationJSON
+			if bodyType.Kind() == reflect.String {
+				contentType = MIMETextPlain
+			}
+		}
+
+		response.Content = map[string]*MediaType{
+			contentType: {
+				Schema: g.schemas.Generate(bodyType),
+			},
+		}
+	}
+
+	if operation.Responses == nil {
+		operation.Responses = make(map[string]*Response)
+	}
+
+	operation.Responses[status] = response
+}
+
+func (p *PathItem) operation(method string) (**Operation, error) {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return &p.Get, nil
+	case http.MethodPut:
+		return &p.Put, nil
+	case http.MethodPost:
+		return &p.Post, nil
+	case http.MethodDelete:
+		return &p.Delete, nil
+	case http.MethodOptions:
+		return &p.Options, nil
+	case http.MethodHead:
+		return &p.Head, nil
+	case http.MethodPatch:
+		return &p.Patch, nil
+	case http.MethodTrace:
+		return &p.Trace, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation method %s", method)
+	}
+}
+
+// ConvertPath converts an echo path into an OpenAPI path, and returns its path parameters names.
+// For example, /users/:id/* is converted into /users/{id}/{*}.
+func ConvertPath(path string) (string, []string) {
+	var params []string
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		case segment == "*":
+			params = append(params, segment)
+			segments[i] = "{*}"
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+func parameterLocation(field reflect.StructField) (string, string) {
+	for _, location := range []struct {
+		tag string
+		in  string
+	}{
+		{tag: "param", in: "path"},
+		{tag: "query", in: "query"},
+		{tag: "header", in: "header"},
+	} {
+		if name, _, _ := strings.Cut(field.Tag.Get(location.tag), ","); name != "" && name != "-" {
+			return location.in, name
+		}
+	}
+
+	return "", ""
+}
+
+func hasParameter(operation *Operation, name string, in string) bool {
+	for _, parameter := range operation.Parameters {
+		if parameter.Name == name && parameter.In == in {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonRequestBody(schema *Schema) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content: map[string]*MediaType{
+			MIMEApplicationJSON: {
+				Schema: schema,
+			},
+		},
+	}
+}
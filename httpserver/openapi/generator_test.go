@@ -0,0 +1,150 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type testUpdateUserRequest struct {
+	ID      int    `param:"id" validate:"required"`
+	DryRun  bool   `query:"dry_run"`
+	TraceID string `header:"X-Trace-Id" description:"trace id"`
+	Name    string `json:"name" validate:"required"`
+}
+
+type testListUsersRequest struct {
+	Page  int    `query:"page" validate:"min=1"`
+	Query string `query:"q"`
+}
+
+func TestGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := openapi.NewGenerator(
+		openapi.WithInfo(openapi.Info{Title: "test", Version: "1.2.3"}),
+		openapi.WithServers(openapi.Server{URL: "https://api.example.com"}),
+		openapi.WithSecurityScheme("jwt", &openapi.SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}),
+		openapi.WithSecurity(openapi.SecurityRequirement{"jwt": {}}),
+	)
+
+	err := generator.AddOperation(http.MethodPost, "/users", openapi.OperationSpec{
+		OperationID: "createUser",
+		Summary:     "create user",
+		Tags:        []string{"users"},
+		Request:     testAddress{},
+		Responses: []openapi.ResponseSpec{
+			{Status: http.StatusCreated, Body: testUser{}},
+			{Status: http.StatusBadRequest, Description: "invalid user", Body: "error"},
+		},
+	})
+	assert.NoError(t, err)
+
+	err = generator.AddOperation(http.MethodPatch, "/users/:id", openapi.OperationSpec{
+		Deprecated: true,
+		Request:    &testUpdateUserRequest{},
+		Responses:  []openapi.ResponseSpec{{Status: http.StatusNoContent}, {Body: map[string]string{}}},
+		Security:   []openapi.SecurityRequirement{{"jwt": {"write"}}},
+	})
+	assert.NoError(t, err)
+
+	err = generator.AddOperation(http.MethodGet, "/users", openapi.OperationSpec{
+		Request: testListUsersRequest{},
+	})
+	assert.NoError(t, err)
+
+	err = generator.AddOperation(http.MethodGet, "/users/:id/files/*", openapi.OperationSpec{})
+	assert.NoError(t, err)
+
+	document := generator.Document()
+	assert.Equal(t, "3.1.0", document.OpenAPI)
+	assert.Equal(t, openapi.Info{Title: "test", Version: "1.2.3"}, document.Info)
+	assert.Equal(t, []openapi.Server{{URL: "https://api.example.com"}}, document.Servers)
+	assert.Equal(t, []openapi.SecurityRequirement{{"jwt": {}}}, document.Security)
+	assert.Equal(t, "bearer", document.Components.SecuritySchemes["jwt"].Scheme)
+	assert.Contains(t, document.Components.Schemas, "testUser")
+	assert.Contains(t, document.Components.Schemas, "testAddress")
+
+	// body from a reusable schema
+	create := document.Paths["/users"].Post
+	assert.Equal(t, "createUser", create.OperationID)
+	assert.Equal(t, "create user", create.Summary)
+	assert.Equal(t, []string{"users"}, create.Tags)
+	assert.True(t, create.RequestBody.Required)
+	assert.Equal(t, "#/components/schemas/testAddress", create.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "Created", create.Responses["201"].Description)
+	assert.Equal(t, "#/components/schemas/testUser", create.Responses["201"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "invalid user", create.Responses["400"].Description)
+	assert.Equal(t, "string", create.Responses["400"].Content["text/plain"].Schema.Type)
+
+	// parameters and inline body
+	update := document.Paths["/users/{id}"].Patch
+	assert.True(t, update.Deprecated)
+	assert.Equal(t, []openapi.SecurityRequirement{{"jwt": {"write"}}}, update.Security)
+	assert.Len(t, update.Parameters, 3)
+	assert.Equal(t, &openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}}, update.Parameters[0])
+	assert.Equal(t, &openapi.Parameter{Name: "dry_run", In: "query", Schema: &openapi.Schema{Type: "boolean"}}, update.Parameters[1])
+	assert.Equal(t, "header", update.Parameters[2].In)
+	assert.Equal(t, "X-Trace-Id", update.Parameters[2].Name)
+	assert.Equal(t, "trace id", update.Parameters[2].Description)
+
+	body := update.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "object", body.Type)
+	assert.Equal(t, []string{"name"}, body.Required)
+	assert.Len(t, body.Properties, 1)
+	assert.Equal(t, "No Content", update.Responses["204"].Description)
+	assert.Nil(t, update.Responses["204"].Content)
+	assert.Equal(t, "Default response", update.Responses["default"].Description)
+
+	// query parameters without body
+	list := document.Paths["/users"].Get
+	assert.Nil(t, list.RequestBody)
+	assert.Len(t, list.Parameters, 2)
+	assert.Equal(t, float64(1), *list.Parameters[0].Schema.Minimum)
+
+	// undeclared path parameters
+	files := document.Paths["/users/{id}/files/{*}"].Get
+	assert.Len(t, files.Parameters, 2)
+	assert.Equal(t, &openapi.Parameter{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}, files.Parameters[0])
+	assert.Equal(t, "*", files.Parameters[1].Name)
+
+	_, err = json.Marshal(document)
+	assert.NoError(t, err)
+}
+
+func TestGeneratorWithInvalidOperations(t *testing.T) {
+	t.Parallel()
+
+	generator := openapi.NewGenerator()
+
+	err := generator.AddOperation("PROPFIND", "/test", openapi.OperationSpec{})
+	assert.Error(t, err)
+	assert.Equal(t, "unsupported operation method PROPFIND", err.Error())
+
+	err = generator.AddOperation(http.MethodGet, "/test", openapi.OperationSpec{})
+	assert.NoError(t, err)
+
+	err = generator.AddOperation("get", "/test", openapi.OperationSpec{})
+	assert.Error(t, err)
+	assert.Equal(t, "duplicate operation GET /test", err.Error())
+
+	document := generator.Document()
+	assert.Equal(t, openapi.Info{Title: "API", Version: "1.0.0"}, document.Info)
+	assert.Nil(t, document.Components)
+	assert.Len(t, document.Paths, 1)
+}
+
+func TestConvertPath(t *testing.T) {
+	t.Parallel()
+
+	path, params := openapi.ConvertPath("/users/:id/posts/:post_id")
+	assert.Equal(t, "/users/{id}/posts/{post_id}", path)
+	assert.Equal(t, []string{"id", "post_id"}, params)
+
+	path, params = openapi.ConvertPath("/")
+	assert.Equal(t, "/", path)
+	assert.Empty(t, params)
+}
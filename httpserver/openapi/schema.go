@@ -0,0 +1,432 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultValidateTagName = "validate"
+	SchemaRefPrefix        = "#/components/schemas/"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	schemaNameRegexp  = regexp.MustCompile(`[^\[\],]*\.`)
+	schemaCharsRegexp = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+// validate tags formats and patterns, see https://pkg.go.dev/github.com/go-playground/validator/v10
+var (
+	validateFormats = map[string]string{
+		"email":            "email",
+		"url":              "uri",
+		"uri":              "uri",
+		"http_url":         "uri",
+		"uuid":             "uuid",
+		"uuid3":            "uuid",
+		"uuid4":            "uuid",
+		"uuid5":            "uuid",
+		"ipv4":             "ipv4",
+		"ipv6":             "ipv6",
+		"hostname":         "hostname",
+		"hostname_rfc1123": "hostname",
+	}
+	validatePatterns = map[string]string{
+		"alpha":    "^[a-zA-Z]+$",
+		"alphanum": "^[a-zA-Z0-9]+$",
+		"numeric":  `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+		"number":   "^[0-9]+$",
+	}
+)
+
+// Schema is a JSON schema (draft 2020-12), as used by OpenAPI 3.1.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinLength            *uint64            `json:"minLength,omitempty"`
+	MaxLength            *uint64            `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinItems             *uint64            `json:"minItems,omitempty"`
+	MaxItems             *uint64            `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	MinProperties        *uint64            `json:"minProperties,omitempty"`
+	MaxProperties        *uint64            `json:"maxProperties,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+}
+
+// SchemaGenerator generates schemas from Go types, following their json encoding.
+//
+// Named structs are generated as reusable schemas (see [SchemaGenerator.Schemas]) and referenced, and the struct
+// fields validate tags are converted into schema constraints (required, min, max, len, oneof, email, uuid, ...).
+type SchemaGenerator struct {
+	tagName string
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	types   map[string]reflect.Type
+}
+
+// SchemaGeneratorOption are functional options for the [SchemaGenerator].
+type SchemaGeneratorOption func(g *SchemaGenerator)
+
+// WithValidateTagName is used to specify the struct fields validation tag name (default validate).
+func WithValidateTagName(tagName string) SchemaGeneratorOption {
+	return func(g *SchemaGenerator) {
+		g.tagName = tagName
+	}
+}
+
+// NewSchemaGenerator returns a new [SchemaGenerator].
+func NewSchemaGenerator(options ...SchemaGeneratorOption) *SchemaGenerator {
+	generator := &SchemaGenerator{
+		tagName: DefaultValidateTagName,
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+		types:   make(map[string]reflect.Type),
+	}
+
+	for _, opt := range options {
+		opt(generator)
+	}
+
+	return generator
+}
+
+// Generate returns the schema of a Go type, as a reference for named structs.
+func (g *SchemaGenerator) Generate(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	//nolint:exhaustive
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: float(0)}
+	case reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+
+		return &Schema{Type: "array", Items: g.Generate(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.Generate(t.Elem()), MinItems: size(t.Len()), MaxItems: size(t.Len())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.Generate(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, nil)
+		}
+
+		return g.ref(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// Schemas returns the reusable schemas generated so far, by name.
+func (g *SchemaGenerator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+func (g *SchemaGenerator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.name(t)
+
+		// registered before generation, for recursive types
+		schema := &Schema{}
+		g.names[t] = name
+		g.types[name] = t
+		g.schemas[name] = schema
+
+		*schema = *g.structSchema(t, nil)
+	}
+
+	return &Schema{Ref: SchemaRefPrefix + name}
+}
+
+func (g *SchemaGenerator) name(t reflect.Type) string {
+	// generic types names contain their type arguments packages paths
+	name := strings.Trim(schemaCharsRegexp.ReplaceAllString(schemaNameRegexp.ReplaceAllString(t.Name(), ""), "_"), "_")
+
+	if _, ok := g.types[name]; ok {
+		pkgPath := strings.Split(t.PkgPath(), "/")
+		pkgName := pkgPath[len(pkgPath)-1]
+
+		name = strings.ToUpper(pkgName[:1]) + pkgName[1:] + name
+	}
+
+	candidate := name
+	for i := 2; ; i++ {
+		if _, ok := g.types[candidate]; !ok {
+			return candidate
+		}
+
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+func (g *SchemaGenerator) structSchema(t reflect.Type, filter func(field reflect.StructField) bool) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	g.structFields(t, schema, filter)
+
+	return schema
+}
+
+func (g *SchemaGenerator) structFields(t reflect.Type, schema *Schema, filter func(field reflect.StructField) bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		// embedded structs fields are promoted, as done by the json encoding
+		if tagName, _, _ := strings.Cut(field.Tag.Get("json"), ","); field.Anonymous && tagName == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			if fieldType.Kind() == reflect.Struct {
+				g.structFields(fieldType, schema, filter)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() || (filter != nil && !filter(field)) {
+			continue
+		}
+
+		fieldSchema, required := g.fieldSchema(field)
+
+		schema.Properties[name] = fieldSchema
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func (g *SchemaGenerator) fieldSchema(field reflect.StructField) (*Schema, bool) {
+	schema := g.Generate(field.Type)
+
+	required := g.applyRules(schema, strings.Split(field.Tag.Get(g.tagName), ","))
+
+	if description := field.Tag.Get("description"); description != "" {
+		schema.Description = description
+	}
+
+	if example := field.Tag.Get("example"); example != "" {
+		schema.Examples = []any{parseValue(schema, example)}
+	}
+
+	return schema, required
+}
+
+//nolint:cyclop
+func (g *SchemaGenerator) applyRules(schema *Schema, rules []string) bool {
+	required := false
+
+	for i, rule := range rules {
+		// alternatives cannot be represented as constraints
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		name, value, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "dive":
+			switch {
+			case schema.Items != nil:
+				g.applyRules(schema.Items, rules[i+1:])
+			case schema.AdditionalProperties != nil && (i+1 == len(rules) || rules[i+1] != "keys"):
+				g.applyRules(schema.AdditionalProperties, rules[i+1:])
+			}
+
+			return required
+		case "required":
+			required = true
+		case "min", "gte":
+			applyBound(schema, value, true, false)
+		case "max", "lte":
+			applyBound(schema, value, false, false)
+		case "gt":
+			applyBound(schema, value, true, true)
+		case "lt":
+			applyBound(schema, value, false, true)
+		case "len":
+			applyBound(schema, value, true, false)
+			applyBound(schema, value, false, false)
+		case "oneof":
+			for _, item := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, parseValue(schema, item))
+			}
+		case "unique":
+			if schema.Type == "array" {
+				schema.UniqueItems = true
+			}
+		default:
+			if format, ok := validateFormats[name]; ok && schema.Type == "string" {
+				schema.Format = format
+			}
+
+			if pattern, ok := validatePatterns[name]; ok && schema.Type == "string" {
+				schema.Pattern = pattern
+			}
+		}
+	}
+
+	return required
+}
+
+func applyBound(schema *Schema, value string, lower bool, exclusive bool) {
+	switch schema.Type {
+	case "integer", "number":
+		bound, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case lower && exclusive:
+			schema.ExclusiveMinimum = &bound
+		case lower:
+			schema.Minimum = &bound
+		case exclusive:
+			schema.ExclusiveMaximum = &bound
+		default:
+			schema.Maximum = &bound
+		}
+	case "string", "array", "object":
+		bound, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return
+		}
+
+		if exclusive {
+			if lower {
+				bound++
+			} else if bound > 0 {
+				bound--
+			}
+		}
+
+		var target **uint64
+
+		switch {
+		case schema.Type == "string" && schema.Format != "date-time":
+			target = &schema.MaxLength
+			if lower {
+				target = &schema.MinLength
+			}
+		case schema.Type == "array":
+			target = &schema.MaxItems
+			if lower {
+				target = &schema.MinItems
+			}
+		case schema.Type == "object" && schema.AdditionalProperties != nil:
+			target = &schema.MaxProperties
+			if lower {
+				target = &schema.MinProperties
+			}
+		default:
+			return
+		}
+
+		*target = &bound
+	}
+}
+
+func parseValue(schema *Schema, value string) any {
+	switch schema.Type {
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+
+	return value
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func size(value int) *uint64 {
+	//nolint:gosec
+	converted := uint64(value)
+
+	return &converted
+}
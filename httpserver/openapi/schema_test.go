@@ -0,0 +1,139 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	Street string `json:"street" validate:"required,max=100"`
+	City   string `json:"city,omitempty"`
+}
+
+type testBase struct {
+	ID        int       `json:"id" description:"user id" example:"123"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testUser struct {
+	testBase
+	Name      string            `json:"name" validate:"required,min=2,max=50"`
+	Email     string            `json:"email" validate:"required,email,max=255"`
+	Age       uint8             `json:"age" validate:"gte=18,lt=130"`
+	Score     float64           `json:"score" validate:"gt=0"`
+	Role      string            `json:"role" validate:"oneof=admin user"`
+	Level     int32             `json:"level" validate:"oneof=1 2 3"`
+	Tags      []string          `json:"tags" validate:"max=5,unique,dive,alphanum,min=1"`
+	Labels    map[string]string `json:"labels" validate:"min=1"`
+	Address   *testAddress      `json:"address"`
+	Addresses []testAddress     `json:"addresses"`
+	Avatar    []byte            `json:"avatar"`
+	IP        net.IP            `json:"ip"`
+	Extra     json.RawMessage   `json:"extra"`
+	Any       any               `json:"any"`
+	Manager   *testUser         `json:"manager"`
+	Internal  string            `json:"-"`
+	private   string
+}
+
+type testPage[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+func TestSchemaGenerator(t *testing.T) {
+	t.Parallel()
+
+	generator := openapi.NewSchemaGenerator()
+
+	schema := generator.Generate(reflect.TypeOf(&testUser{}))
+	assert.Equal(t, "#/components/schemas/testUser", schema.Ref)
+
+	schemas := generator.Schemas()
+	assert.Len(t, schemas, 2)
+
+	user := schemas["testUser"]
+	assert.Equal(t, "object", user.Type)
+	assert.Equal(t, []string{"name", "email"}, user.Required)
+	assert.NotContains(t, user.Properties, "Internal")
+	assert.NotContains(t, user.Properties, "private")
+
+	// promoted fields
+	assert.Equal(t, &openapi.Schema{Type: "integer", Format: "int64", Description: "user id", Examples: []any{int64(123)}}, user.Properties["id"])
+	assert.Equal(t, &openapi.Schema{Type: "string", Format: "date-time"}, user.Properties["created_at"])
+
+	// constraints
+	assert.Equal(t, uint64(2), *user.Properties["name"].MinLength)
+	assert.Equal(t, uint64(50), *user.Properties["name"].MaxLength)
+	assert.Equal(t, "email", user.Properties["email"].Format)
+	assert.Equal(t, uint64(255), *user.Properties["email"].MaxLength)
+	assert.Equal(t, float64(18), *user.Properties["age"].Minimum)
+	assert.Equal(t, float64(130), *user.Properties["age"].ExclusiveMaximum)
+	assert.Equal(t, float64(0), *user.Properties["score"].ExclusiveMinimum)
+	assert.Equal(t, []any{"admin", "user"}, user.Properties["role"].Enum)
+	assert.Equal(t, []any{int64(1), int64(2), int64(3)}, user.Properties["level"].Enum)
+
+	tags := user.Properties["tags"]
+	assert.Equal(t, "array", tags.Type)
+	assert.Equal(t, uint64(5), *tags.MaxItems)
+	assert.True(t, tags.UniqueItems)
+	assert.Equal(t, "^[a-zA-Z0-9]+$", tags.Items.Pattern)
+	assert.Equal(t, uint64(1), *tags.Items.MinLength)
+
+	labels := user.Properties["labels"]
+	assert.Equal(t, "object", labels.Type)
+	assert.Equal(t, &openapi.Schema{Type: "string"}, labels.AdditionalProperties)
+	assert.Equal(t, uint64(1), *labels.MinProperties)
+
+	// types
+	assert.Equal(t, "#/components/schemas/testAddress", user.Properties["address"].Ref)
+	assert.Equal(t, "#/components/schemas/testAddress", user.Properties["addresses"].Items.Ref)
+	assert.Equal(t, &openapi.Schema{Type: "string", ContentEncoding: "base64"}, user.Properties["avatar"])
+	assert.Equal(t, &openapi.Schema{Type: "string"}, user.Properties["ip"])
+	assert.Equal(t, &openapi.Schema{}, user.Properties["extra"])
+	assert.Equal(t, &openapi.Schema{}, user.Properties["any"])
+	assert.Equal(t, "#/components/schemas/testUser", user.Properties["manager"].Ref)
+
+	address := schemas["testAddress"]
+	assert.Equal(t, []string{"street"}, address.Required)
+	assert.Equal(t, uint64(100), *address.Properties["street"].MaxLength)
+}
+
+func TestSchemaGeneratorWithGenericTypes(t *testing.T) {
+	t.Parallel()
+
+	generator := openapi.NewSchemaGenerator()
+
+	schema := generator.Generate(reflect.TypeOf(testPage[testAddress]{}))
+	assert.Equal(t, "#/components/schemas/testPage_testAddress", schema.Ref)
+
+	page := generator.Schemas()["testPage_testAddress"]
+	assert.Equal(t, "#/components/schemas/testAddress", page.Properties["items"].Items.Ref)
+}
+
+func TestSchemaGeneratorWithInlineTypes(t *testing.T) {
+	t.Parallel()
+
+	generator := openapi.NewSchemaGenerator(openapi.WithValidateTagName("binding"))
+
+	schema := generator.Generate(reflect.TypeOf(struct {
+		Name  string   `json:"name" binding:"required"`
+		Codes [2]int16 `json:"codes"`
+	}{}))
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"name"}, schema.Required)
+	assert.Equal(t, &openapi.Schema{Type: "integer", Format: "int32"}, schema.Properties["codes"].Items)
+	assert.Equal(t, uint64(2), *schema.Properties["codes"].MinItems)
+	assert.Empty(t, generator.Schemas())
+
+	assert.Equal(t, &openapi.Schema{Type: "integer", Format: "int64", Minimum: new(float64)}, generator.Generate(reflect.TypeOf(uint(1))))
+	assert.Equal(t, &openapi.Schema{Type: "boolean"}, generator.Generate(reflect.TypeOf(true)))
+	assert.Equal(t, &openapi.Schema{Type: "number", Format: "float"}, generator.Generate(reflect.TypeOf(float32(1))))
+}
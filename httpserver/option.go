@@ -1,31 +1,45 @@
 package httpserver
 
 import (
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
 )
 
 // Options are options for the [HttpServerFactory] implementations.
 type Options struct {
-	Debug            bool
-	Banner           bool
-	Logger           echo.Logger
-	Binder           echo.Binder
-	JsonSerializer   echo.JSONSerializer
-	HttpErrorHandler echo.HTTPErrorHandler
-	Renderer         echo.Renderer
+	Debug             bool
+	Banner            bool
+	Logger            echo.Logger
+	Binder            echo.Binder
+	Validator         echo.Validator
+	JsonSerializer    echo.JSONSerializer
+	HttpErrorHandler  echo.HTTPErrorHandler
+	Renderer          echo.Renderer
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
 }
 
 // DefaultHttpServerOptions are the default options used in the [DefaultHttpServerFactory].
 func DefaultHttpServerOptions() Options {
 	return Options{
-		Debug:            false,
-		Banner:           false,
-		Logger:           log.New("default"),
-		Binder:           &echo.DefaultBinder{},
-		JsonSerializer:   &echo.DefaultJSONSerializer{},
-		HttpErrorHandler: nil,
-		Renderer:         nil,
+		Debug:             false,
+		Banner:            false,
+		Logger:            log.New("default"),
+		Binder:            &echo.DefaultBinder{},
+		Validator:         nil,
+		JsonSerializer:    &echo.DefaultJSONSerializer{},
+		HttpErrorHandler:  nil,
+		Renderer:          nil,
+		ReadTimeout:       0,
+		ReadHeaderTimeout: 0,
+		WriteTimeout:      0,
+		IdleTimeout:       0,
+		MaxHeaderBytes:    0,
 	}
 }
 
@@ -60,6 +74,13 @@ func WithBinder(b echo.Binder) HttpServerOption {
 	}
 }
 
+// WithValidator is used to specify a [echo.Validator] to be used by the server.
+func WithValidator(v echo.Validator) HttpServerOption {
+	return func(o *Options) {
+		o.Validator = v
+	}
+}
+
 // WithJsonSerializer is used to specify a [echo.JSONSerializer] to be used by the server.
 func WithJsonSerializer(s echo.JSONSerializer) HttpServerOption {
 	return func(o *Options) {
@@ -80,3 +101,38 @@ func WithRenderer(r echo.Renderer) HttpServerOption {
 		o.Renderer = r
 	}
 }
+
+// WithReadTimeout is used to specify the server maximum duration for reading the entire request, including the body.
+func WithReadTimeout(t time.Duration) HttpServerOption {
+	return func(o *Options) {
+		o.ReadTimeout = t
+	}
+}
+
+// WithReadHeaderTimeout is used to specify the server maximum duration for reading the request headers.
+func WithReadHeaderTimeout(t time.Duration) HttpServerOption {
+	return func(o *Options) {
+		o.ReadHeaderTimeout = t
+	}
+}
+
+// WithWriteTimeout is used to specify the server maximum duration before timing out writes of the response.
+func WithWriteTimeout(t time.Duration) HttpServerOption {
+	return func(o *Options) {
+		o.WriteTimeout = t
+	}
+}
+
+// WithIdleTimeout is used to specify the server maximum duration to wait for the next request on keep-alive connections.
+func WithIdleTimeout(t time.Duration) HttpServerOption {
+	return func(o *Options) {
+		o.IdleTimeout = t
+	}
+}
+
+// WithMaxHeaderBytes is used to specify the server maximum number of bytes read while parsing the request headers.
+func WithMaxHeaderBytes(b int) HttpServerOption {
+	return func(o *Options) {
+		o.MaxHeaderBytes = b
+	}
+}
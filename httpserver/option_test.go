@@ -2,6 +2,7 @@ package httpserver_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ankorstore/yokai/httpserver"
 	"github.com/labstack/echo/v4"
@@ -47,6 +48,16 @@ func TestWithBinder(t *testing.T) {
 	assert.Equal(t, binder, opt.Binder)
 }
 
+func TestWithValidator(t *testing.T) {
+	t.Parallel()
+
+	opt := httpserver.DefaultHttpServerOptions()
+	validator := &testValidator{}
+	httpserver.WithValidator(validator)(&opt)
+
+	assert.Equal(t, validator, opt.Validator)
+}
+
 func TestWithJsonSerializer(t *testing.T) {
 	t.Parallel()
 
@@ -75,3 +86,33 @@ func TestWithRenderer(t *testing.T) {
 
 	assert.NotNil(t, opt.Renderer)
 }
+
+func TestWithServerTimeouts(t *testing.T) {
+	t.Parallel()
+
+	opt := httpserver.DefaultHttpServerOptions()
+	httpserver.WithReadTimeout(1 * time.Second)(&opt)
+	httpserver.WithReadHeaderTimeout(2 * time.Second)(&opt)
+	httpserver.WithWriteTimeout(3 * time.Second)(&opt)
+	httpserver.WithIdleTimeout(4 * time.Second)(&opt)
+
+	assert.Equal(t, 1*time.Second, opt.ReadTimeout)
+	assert.Equal(t, 2*time.Second, opt.ReadHeaderTimeout)
+	assert.Equal(t, 3*time.Second, opt.WriteTimeout)
+	assert.Equal(t, 4*time.Second, opt.IdleTimeout)
+}
+
+func TestWithMaxHeaderBytes(t *testing.T) {
+	t.Parallel()
+
+	opt := httpserver.DefaultHttpServerOptions()
+	httpserver.WithMaxHeaderBytes(1024)(&opt)
+
+	assert.Equal(t, 1024, opt.MaxHeaderBytes)
+}
+
+type testValidator struct{}
+
+func (v *testValidator) Validate(i interface{}) error {
+	return nil
+}
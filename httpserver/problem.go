@@ -0,0 +1,253 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ankorstore/yokai/log"
+	goerrors "github.com/go-errors/errors"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+	DefaultProblemType         = "about:blank"
+	InvalidParamsExtension     = "invalid-params"
+)
+
+// ProblemDetails is an [RFC 9457] problem details object, with its extension members.
+//
+// [RFC 9457]: https://www.rfc-editor.org/rfc/rfc9457
+type ProblemDetails struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// MarshalJSON encodes the problem details members, and its extension members at the same level.
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+
+	return json.Marshal(members)
+}
+
+// ErrorMapping maps the errors matching Match to a problem type, title and status.
+// If Extensions is provided, it returns extension members to add to the problem details of the matched error.
+type ErrorMapping struct {
+	Match      func(err error) bool
+	Type       string
+	Title      string
+	Status     int
+	Extensions func(err error) map[string]any
+}
+
+// NewErrorMapping returns a new [ErrorMapping], for the errors matching a target error (with [errors.Is]), like
+// sentinel errors.
+func NewErrorMapping(target error, status int, problemType string, title string) *ErrorMapping {
+	return &ErrorMapping{
+		Match: func(err error) bool {
+			return errors.Is(err, target)
+		},
+		Type:   problemType,
+		Title:  title,
+		Status: status,
+	}
+}
+
+// NewErrorTypeMapping returns a new [ErrorMapping], for the errors of type E (with [errors.As]).
+func NewErrorTypeMapping[E error](status int, problemType string, title string) *ErrorMapping {
+	return &ErrorMapping{
+		Match: func(err error) bool {
+			var target E
+
+			return errors.As(err, &target)
+		},
+		Type:   problemType,
+		Title:  title,
+		Status: status,
+	}
+}
+
+// ProblemDetailsErrorHandler provides a [echo.HTTPErrorHandler] that outputs errors as [RFC 9457] problem details,
+// in application/problem+json format, with the request id as instance.
+//
+// The errors matching an [ErrorMapping] (checked in order) get its problem type, title and status, and their message as
+// detail. The invalid parameters of a [ValidationError] are added under the invalid-params extension member.
+// It can also be configured to obfuscate the detail of the not mapped errors (to avoid to leak sensitive details), and
+// to add the error stack to the response, under the stack extension member.
+//
+// [RFC 9457]: https://www.rfc-editor.org/rfc/rfc9457
+type ProblemDetailsErrorHandler struct {
+	obfuscate bool
+	stack     bool
+	mappings  []*ErrorMapping
+}
+
+// NewProblemDetailsErrorHandler returns a new ProblemDetailsErrorHandler instance.
+func NewProblemDetailsErrorHandler(obfuscate bool, stack bool, mappings ...*ErrorMapping) *ProblemDetailsErrorHandler {
+	return &ProblemDetailsErrorHandler{
+		obfuscate: obfuscate,
+		stack:     stack,
+		mappings:  mappings,
+	}
+}
+
+// Handle handles errors.
+func (h *ProblemDetailsErrorHandler) Handle() echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		logger := log.CtxLogger(c.Request().Context())
+
+		if c.Response().Committed {
+			return
+		}
+
+		problem := h.problemDetails(err, c)
+
+		logger.Error().Err(err).Int("status", problem.Status).Str("type", problem.Type).Msg("error handler")
+
+		var httpRespErr error
+		if c.Request().Method == http.MethodHead {
+			httpRespErr = c.NoContent(problem.Status)
+		} else {
+			c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+			httpRespErr = c.JSON(problem.Status, problem)
+		}
+
+		if httpRespErr != nil {
+			logger.Error().Err(httpRespErr).Msg("error handler failure")
+		}
+	}
+}
+
+func (h *ProblemDetailsErrorHandler) problemDetails(err error, c echo.Context) *ProblemDetails {
+	problem := &ProblemDetails{
+		Type:       DefaultProblemType,
+		Status:     http.StatusInternalServerError,
+		Instance:   c.Response().Header().Get(echo.HeaderXRequestID),
+		Extensions: map[string]any{},
+	}
+
+	// http errors provide the status and detail, and wrap the error to map
+	cause := err
+
+	var httpError *echo.HTTPError
+	if errors.As(err, &httpError) {
+		var internalHttpError *echo.HTTPError
+		if httpError.Internal != nil && errors.As(httpError.Internal, &internalHttpError) {
+			httpError = internalHttpError
+		}
+
+		problem.Status = httpError.Code
+		problem.Detail = httpErrorMessage(httpError)
+
+		if httpError.Internal != nil {
+			cause = httpError.Internal
+		}
+	} else if err != nil {
+		problem.Detail = err.Error()
+	}
+
+	obfuscate := h.obfuscate
+
+	// validation details are intended for the clients, and are not obfuscated
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		if httpError == nil {
+			problem.Status = http.StatusBadRequest
+		}
+
+		problem.Detail = validationError.Message
+		obfuscate = false
+
+		if len(validationError.InvalidParams) > 0 {
+			problem.Extensions[InvalidParamsExtension] = validationError.InvalidParams
+		}
+	}
+
+	// mapped errors are intended for the clients, and are not obfuscated
+	for _, mapping := range h.mappings {
+		if mapping.Match == nil || !mapping.Match(err) {
+			continue
+		}
+
+		if mapping.Type != "" {
+			problem.Type = mapping.Type
+		}
+
+		if mapping.Status != 0 {
+			problem.Status = mapping.Status
+		}
+
+		problem.Title = mapping.Title
+
+		if validationError == nil && cause != nil {
+			problem.Detail = cause.Error()
+		}
+
+		if mapping.Extensions != nil {
+			for name, value := range mapping.Extensions(err) {
+				problem.Extensions[name] = value
+			}
+		}
+
+		obfuscate = false
+
+		break
+	}
+
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	if obfuscate {
+		problem.Detail = ""
+	}
+
+	if h.stack {
+		errStack := "n/a"
+		if err != nil {
+			errStack = goerrors.New(err).ErrorStack()
+		}
+
+		problem.Extensions["stack"] = errStack
+	}
+
+	return problem
+}
+
+func httpErrorMessage(httpError *echo.HTTPError) string {
+	switch m := httpError.Message.(type) {
+	case nil:
+		return ""
+	case string:
+		return m
+	case error:
+		return m.Error()
+	default:
+		data, err := json.Marshal(m)
+		if err != nil {
+			return http.StatusText(httpError.Code)
+		}
+
+		return string(data)
+	}
+}
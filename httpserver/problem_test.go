@@ -0,0 +1,255 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/ankorstore/yokai/log"
+	"github.com/ankorstore/yokai/log/logtest"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestNotFound = errors.New("user not found")
+
+type testQuotaError struct {
+	limit int
+}
+
+func (e *testQuotaError) Error() string {
+	return fmt.Sprintf("quota of %d exceeded", e.limit)
+}
+
+func newTestProblemServer(t *testing.T, handler *httpserver.ProblemDetailsErrorHandler) (*echo.Echo, logtest.TestLogBuffer, context.Context) {
+	t.Helper()
+
+	logBuffer := logtest.NewDefaultTestLogBuffer()
+	logger, err := log.NewDefaultLoggerFactory().Create(
+		log.WithOutputWriter(logBuffer),
+	)
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.Logger = httpserver.NewEchoLogger(logger)
+	httpServer.HTTPErrorHandler = handler.Handle()
+
+	return httpServer, logBuffer, logger.WithContext(context.Background())
+}
+
+func TestProblemDetailsMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	problem := &httpserver.ProblemDetails{
+		Type:   "https://example.com/problems/quota",
+		Title:  "Quota exceeded",
+		Status: http.StatusTooManyRequests,
+		Extensions: map[string]any{
+			"limit":  10,
+			"status": "ignored",
+		},
+	}
+
+	data, err := json.Marshal(problem)
+	assert.NoError(t, err)
+
+	assert.JSONEq(
+		t,
+		`{"type":"https://example.com/problems/quota","title":"Quota exceeded","status":429,"limit":10}`,
+		string(data),
+	)
+}
+
+func TestProblemDetailsErrorHandling(t *testing.T) {
+	t.Parallel()
+
+	httpServer, logBuffer, ctx := newTestProblemServer(t, httpserver.NewProblemDetailsErrorHandler(false, false))
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderXRequestID, "test-request-id")
+
+		return fmt.Errorf("custom error")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, httpserver.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.JSONEq(
+		t,
+		`{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"custom error","instance":"test-request-id"}`,
+		rec.Body.String(),
+	)
+
+	logtest.AssertHasLogRecord(t, logBuffer, map[string]interface{}{
+		"level":   "error",
+		"error":   "custom error",
+		"status":  500,
+		"message": "error handler",
+	})
+}
+
+func TestProblemDetailsErrorHandlingWithHttpError(t *testing.T) {
+	t.Parallel()
+
+	httpServer, _, ctx := newTestProblemServer(t, httpserver.NewProblemDetailsErrorHandler(false, false))
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusConflict, "already exists").SetInternal(fmt.Errorf("internal"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{"type":"about:blank","title":"Conflict","status":409,"detail":"already exists"}`, rec.Body.String())
+}
+
+func TestProblemDetailsErrorHandlingWithObfuscateAndStack(t *testing.T) {
+	t.Parallel()
+
+	httpServer, _, ctx := newTestProblemServer(t, httpserver.NewProblemDetailsErrorHandler(true, true))
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		return fmt.Errorf("sensitive error")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	var body map[string]any
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	assert.NoError(t, err)
+
+	assert.NotContains(t, body, "detail")
+	assert.Equal(t, "Internal Server Error", body["title"])
+	assert.Contains(t, body["stack"], "sensitive error")
+}
+
+func TestProblemDetailsErrorHandlingWithMappings(t *testing.T) {
+	t.Parallel()
+
+	quotaMapping := httpserver.NewErrorTypeMapping[*testQuotaError](
+		http.StatusTooManyRequests,
+		"https://example.com/problems/quota",
+		"Quota exceeded",
+	)
+	quotaMapping.Extensions = func(err error) map[string]any {
+		var quotaErr *testQuotaError
+		errors.As(err, &quotaErr)
+
+		return map[string]any{"limit": quotaErr.limit}
+	}
+
+	handler := httpserver.NewProblemDetailsErrorHandler(
+		true,
+		false,
+		httpserver.NewErrorMapping(errTestNotFound, http.StatusNotFound, "https://example.com/problems/not-found", "Not found"),
+		quotaMapping,
+	)
+
+	httpServer, _, ctx := newTestProblemServer(t, handler)
+
+	httpServer.GET("/sentinel", func(c echo.Context) error {
+		return fmt.Errorf("cannot get user 1: %w", errTestNotFound)
+	})
+	httpServer.GET("/type", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(&testQuotaError{limit: 10})
+	})
+	httpServer.GET("/unmapped", func(c echo.Context) error {
+		return fmt.Errorf("unmapped")
+	})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{
+			"/sentinel",
+			http.StatusNotFound,
+			`{"type":"https://example.com/problems/not-found","title":"Not found","status":404,"detail":"cannot get user 1: user not found"}`,
+		},
+		{
+			"/type",
+			http.StatusTooManyRequests,
+			`{"type":"https://example.com/problems/quota","title":"Quota exceeded","status":429,"detail":"quota of 10 exceeded","limit":10}`,
+		},
+		{
+			"/unmapped",
+			http.StatusInternalServerError,
+			`{"type":"about:blank","title":"Internal Server Error","status":500}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			httpServer.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.JSONEq(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestProblemDetailsErrorHandlingWithValidationError(t *testing.T) {
+	t.Parallel()
+
+	httpServer, _, ctx := newTestProblemServer(t, httpserver.NewProblemDetailsErrorHandler(true, false))
+
+	httpServer.GET("/test", func(c echo.Context) error {
+		return httpserver.NewValidationError(
+			"invalid request",
+			httpserver.InvalidParam{Name: "page", In: "query", Reason: "must be positive"},
+		)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(
+		t,
+		`{
+			"type":"about:blank",
+			"title":"Bad Request",
+			"status":400,
+			"detail":"invalid request",
+			"invalid-params":[{"name":"page","in":"query","reason":"must be positive"}]
+		}`,
+		rec.Body.String(),
+	)
+}
+
+func TestProblemDetailsErrorHandlingWithHeadRequest(t *testing.T) {
+	t.Parallel()
+
+	httpServer, _, ctx := newTestProblemServer(t, httpserver.NewProblemDetailsErrorHandler(false, false))
+
+	httpServer.HEAD("/test", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusForbidden)
+	})
+
+	req := httptest.NewRequest(http.MethodHead, "/test", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DefaultKeyHeader           = "X-Api-Key"
+	DefaultPrincipalContextKey = "principal"
+)
+
+// KeyExtractor extracts the rate limit key of a request.
+// An empty key means the request cannot be keyed, and will fall back on the client IP.
+type KeyExtractor func(c echo.Context) string
+
+// KeyByIP returns a [KeyExtractor] keying requests by client IP.
+func KeyByIP() KeyExtractor {
+	return func(c echo.Context) string {
+		return c.RealIP()
+	}
+}
+
+// KeyByHeader returns a [KeyExtractor] keying requests by the value of the provided header.
+func KeyByHeader(header string) KeyExtractor {
+	return func(c echo.Context) string {
+		return c.Request().Header.Get(header)
+	}
+}
+
+// KeyByContextValue returns a [KeyExtractor] keying requests by the value stored in the echo context under the
+// provided key, for example the authenticated principal.
+func KeyByContextValue(key string) KeyExtractor {
+	return func(c echo.Context) string {
+		value := c.Get(key)
+		if value == nil {
+			return ""
+		}
+
+		if stringer, ok := value.(fmt.Stringer); ok {
+			return stringer.String()
+		}
+
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// KeyByRoute returns a [KeyExtractor] keying requests by route (method and path pattern), to limit a route globally.
+func KeyByRoute() KeyExtractor {
+	return func(c echo.Context) string {
+		path := c.Path()
+		if path == "" {
+			path = c.Request().URL.Path
+		}
+
+		return fmt.Sprintf("%s %s", c.Request().Method, path)
+	}
+}
+
+// FetchKeyExtractor returns a [KeyExtractor] for a given kind (ip, header, principal or route), defaults to [KeyByIP].
+// The value is the header name for the header kind, and the context key for the principal kind.
+func FetchKeyExtractor(kind string, value string) KeyExtractor {
+	switch kind {
+	case "header":
+		if value == "" {
+			value = DefaultKeyHeader
+		}
+
+		return KeyByHeader(value)
+	case "principal":
+		if value == "" {
+			value = DefaultPrincipalContextKey
+		}
+
+		return KeyByContextValue(value)
+	case "route":
+		return KeyByRoute()
+	default:
+		return KeyByIP()
+	}
+}
@@ -0,0 +1,50 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPrincipal struct {
+	id string
+}
+
+func (p testPrincipal) String() string {
+	return p.id
+}
+
+func TestKeyExtractors(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/123", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Api-Key", "api-key")
+	req.Header.Set("X-Tenant", "tenant")
+
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetPath("/users/:id")
+
+	assert.Equal(t, "10.0.0.1", ratelimit.KeyByIP()(c))
+	assert.Equal(t, "tenant", ratelimit.KeyByHeader("X-Tenant")(c))
+	assert.Equal(t, "GET /users/:id", ratelimit.KeyByRoute()(c))
+	assert.Equal(t, "", ratelimit.KeyByContextValue("principal")(c))
+
+	c.Set("principal", testPrincipal{id: "user-123"})
+	c.Set("user", 123)
+
+	assert.Equal(t, "user-123", ratelimit.KeyByContextValue("principal")(c))
+	assert.Equal(t, "123", ratelimit.KeyByContextValue("user")(c))
+
+	assert.Equal(t, "10.0.0.1", ratelimit.FetchKeyExtractor("ip", "")(c))
+	assert.Equal(t, "10.0.0.1", ratelimit.FetchKeyExtractor("invalid", "")(c))
+	assert.Equal(t, "api-key", ratelimit.FetchKeyExtractor("header", "")(c))
+	assert.Equal(t, "tenant", ratelimit.FetchKeyExtractor("header", "X-Tenant")(c))
+	assert.Equal(t, "user-123", ratelimit.FetchKeyExtractor("principal", "")(c))
+	assert.Equal(t, "123", ratelimit.FetchKeyExtractor("principal", "user")(c))
+	assert.Equal(t, "GET /users/:id", ratelimit.FetchKeyExtractor("route", "")(c))
+}
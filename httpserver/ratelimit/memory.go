@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a [Store] keeping the rate limits states in memory, suitable for single instance setups.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	entries   map[string]memoryEntry
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	state     state
+	expiresAt time.Time
+}
+
+// MemoryStoreOption are functional options for the [MemoryStore].
+type MemoryStoreOption func(s *MemoryStore)
+
+// WithMemoryStoreClock is used to specify the time source of the [MemoryStore] (time.Now by default).
+func WithMemoryStoreClock(now func() time.Time) MemoryStoreOption {
+	return func(s *MemoryStore) {
+		s.now = now
+	}
+}
+
+// NewMemoryStore returns a new [MemoryStore].
+func NewMemoryStore(options ...MemoryStoreOption) *MemoryStore {
+	store := &MemoryStore{
+		entries: map[string]memoryEntry{},
+		now:     time.Now,
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	store.lastSweep = store.now()
+
+	return store
+}
+
+// Allow applies a request for the key on the [Limit], and returns the [Result].
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (*Result, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+
+	s.sweep(now)
+
+	current := state{}
+	if entry, ok := s.entries[key]; ok && entry.expiresAt.After(now) {
+		current = entry.state
+	}
+
+	next, result, expiresAt := apply(limit, current, now)
+
+	s.entries[key] = memoryEntry{
+		state:     next,
+		expiresAt: expiresAt,
+	}
+
+	return result, nil
+}
+
+// Len returns the number of keys tracked by the [MemoryStore].
+func (s *MemoryStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.entries)
+}
+
+// sweep removes the expired entries, at most once per minute.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	for key, entry := range s.entries {
+		if !entry.expiresAt.After(now) {
+			delete(s.entries, key)
+		}
+	}
+
+	s.lastSweep = now
+}
@@ -0,0 +1,74 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreSweep(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := ratelimit.NewMemoryStore(ratelimit.WithMemoryStoreClock(clock.Now))
+	limit := ratelimit.Limit{Requests: 10, Period: time.Second}
+
+	_, err := store.Allow(context.Background(), "foo", limit)
+	assert.NoError(t, err)
+
+	_, err = store.Allow(context.Background(), "bar", limit)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, store.Len())
+
+	clock.Advance(2 * time.Minute)
+
+	_, err = store.Allow(context.Background(), "baz", limit)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestMemoryStoreConcurrency(t *testing.T) {
+	t.Parallel()
+
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 50, Period: time.Hour}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+
+	allowed := 0
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res, err := store.Allow(context.Background(), "key", limit)
+			assert.NoError(t, err)
+
+			if res.Allowed {
+				mutex.Lock()
+				allowed++
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 50, allowed)
+}
+
+func TestMemoryStoreWithInvalidLimit(t *testing.T) {
+	t.Parallel()
+
+	_, err := ratelimit.NewMemoryStore().Allow(context.Background(), "key", ratelimit.Limit{})
+	assert.Error(t, err)
+}
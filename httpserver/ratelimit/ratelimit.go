@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Algorithm is an enum for the rate limiting algorithms.
+type Algorithm int
+
+const (
+	TokenBucket Algorithm = iota
+	SlidingWindow
+)
+
+// String returns a string representation of the [Algorithm].
+func (a Algorithm) String() string {
+	switch a {
+	case SlidingWindow:
+		return "sliding_window"
+	default:
+		return "token_bucket"
+	}
+}
+
+// FetchAlgorithm returns an [Algorithm] for a given value (token_bucket or sliding_window), defaults to [TokenBucket].
+func FetchAlgorithm(algorithm string) Algorithm {
+	switch strings.ToLower(strings.ReplaceAll(algorithm, "-", "_")) {
+	case "sliding_window":
+		return SlidingWindow
+	default:
+		return TokenBucket
+	}
+}
+
+// Limit is a rate limit: Requests per Period.
+//
+// With the [TokenBucket] algorithm, Burst is the bucket capacity (Requests by default), refilled at Requests per Period.
+// With the [SlidingWindow] algorithm, Burst is ignored.
+type Limit struct {
+	Algorithm Algorithm
+	Requests  int
+	Period    time.Duration
+	Burst     int
+}
+
+// Capacity returns the maximum number of requests the [Limit] allows at once.
+func (l Limit) Capacity() int {
+	if l.Algorithm == TokenBucket && l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Requests
+}
+
+// Validate returns an error if the [Limit] is not usable.
+func (l Limit) Validate() error {
+	if l.Requests <= 0 || l.Period <= 0 {
+		return fmt.Errorf("invalid rate limit of %d requests per %s", l.Requests, l.Period)
+	}
+
+	return nil
+}
+
+// Result is the result of a rate limit check.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store is the interface for the rate limits stores.
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// state is the rate limit state of a key, shared by the stores.
+//
+// With the [TokenBucket] algorithm, Value is the number of available tokens, and Time the last refill time.
+// With the [SlidingWindow] algorithm, Value is the current window count, Previous the previous window count,
+// and Time the current window start.
+type state struct {
+	Value    float64
+	Previous float64
+	Time     int64
+}
+
+// apply applies a request to the state, and returns the updated state, the result and the state expiration time.
+func apply(limit Limit, current state, now time.Time) (state, *Result, time.Time) {
+	if limit.Algorithm == SlidingWindow {
+		return applySlidingWindow(limit, current, now)
+	}
+
+	return applyTokenBucket(limit, current, now)
+}
+
+func applyTokenBucket(limit Limit, current state, now time.Time) (state, *Result, time.Time) {
+	capacity := float64(limit.Capacity())
+	rate := float64(limit.Requests) / limit.Period.Seconds()
+
+	tokens := capacity
+	if current.Time != 0 {
+		elapsed := now.Sub(time.Unix(0, current.Time)).Seconds()
+		tokens = math.Min(capacity, current.Value+math.Max(0, elapsed)*rate)
+	}
+
+	result := &Result{
+		Limit: limit.Capacity(),
+	}
+
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / rate)
+	}
+
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((capacity - tokens) / rate)
+
+	return state{Value: tokens, Time: now.UnixNano()}, result, now.Add(result.Reset)
+}
+
+func applySlidingWindow(limit Limit, current state, now time.Time) (state, *Result, time.Time) {
+	period := limit.Period.Nanoseconds()
+	windowStart := now.UnixNano() - now.UnixNano()%period
+
+	next := state{Time: windowStart}
+
+	switch {
+	case current.Time == windowStart:
+		next.Value = current.Value
+		next.Previous = current.Previous
+	case current.Time == windowStart-period:
+		next.Previous = current.Value
+	}
+
+	elapsed := float64(now.UnixNano()-windowStart) / float64(period)
+	count := next.Previous*(1-elapsed) + next.Value
+
+	result := &Result{
+		Limit: limit.Requests,
+		Reset: time.Duration(windowStart + period - now.UnixNano()),
+	}
+
+	if count+1 <= float64(limit.Requests) {
+		next.Value++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = result.Reset
+		if next.Previous > 0 && next.Value+1 <= float64(limit.Requests) {
+			// previous window weight decreasing enough to allow a request in the current window
+			weight := (float64(limit.Requests) - next.Value - 1) / next.Previous
+			result.RetryAfter = time.Duration((1 - weight - elapsed) * float64(period))
+		}
+	}
+
+	result.Remaining = int(math.Max(0, math.Floor(float64(limit.Requests)-count)))
+
+	return next, result, time.Unix(0, windowStart+2*period)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,167 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(duration time.Duration) {
+	c.now = c.now.Add(duration)
+}
+
+func TestFetchAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ratelimit.TokenBucket, ratelimit.FetchAlgorithm("token_bucket"))
+	assert.Equal(t, ratelimit.SlidingWindow, ratelimit.FetchAlgorithm("sliding_window"))
+	assert.Equal(t, ratelimit.SlidingWindow, ratelimit.FetchAlgorithm("Sliding-Window"))
+	assert.Equal(t, ratelimit.TokenBucket, ratelimit.FetchAlgorithm("invalid"))
+
+	assert.Equal(t, "token_bucket", ratelimit.TokenBucket.String())
+	assert.Equal(t, "sliding_window", ratelimit.SlidingWindow.String())
+}
+
+func TestLimit(t *testing.T) {
+	t.Parallel()
+
+	limit := ratelimit.Limit{Algorithm: ratelimit.TokenBucket, Requests: 10, Period: time.Second}
+	assert.Equal(t, 10, limit.Capacity())
+	assert.NoError(t, limit.Validate())
+
+	limit.Burst = 20
+	assert.Equal(t, 20, limit.Capacity())
+
+	limit.Algorithm = ratelimit.SlidingWindow
+	assert.Equal(t, 10, limit.Capacity())
+
+	err := ratelimit.Limit{Requests: 0, Period: time.Second}.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, "invalid rate limit of 0 requests per 1s", err.Error())
+}
+
+func TestTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := ratelimit.NewMemoryStore(ratelimit.WithMemoryStoreClock(clock.Now))
+	limit := ratelimit.Limit{Algorithm: ratelimit.TokenBucket, Requests: 2, Period: time.Second}
+
+	assertTokenBucket(t, store, clock, limit)
+}
+
+func TestTokenBucketWithBurst(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := ratelimit.NewMemoryStore(ratelimit.WithMemoryStoreClock(clock.Now))
+	limit := ratelimit.Limit{Algorithm: ratelimit.TokenBucket, Requests: 1, Period: time.Second, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		res, err := store.Allow(context.Background(), "key", limit)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+
+	res, err := store.Allow(context.Background(), "key", limit)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.Reset)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := ratelimit.NewMemoryStore(ratelimit.WithMemoryStoreClock(clock.Now))
+	limit := ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Requests: 2, Period: time.Second}
+
+	assertSlidingWindow(t, store, clock, limit)
+}
+
+func assertTokenBucket(t *testing.T, store ratelimit.Store, clock *testClock, limit ratelimit.Limit) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	res, err := store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond}, res)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second}, res)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Second, RetryAfter: 500 * time.Millisecond}, res)
+
+	// other keys are not impacted
+	res, err = store.Allow(ctx, "other", limit)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// refill
+	clock.Advance(500 * time.Millisecond)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second}, res)
+
+	// full refill
+	clock.Advance(10 * time.Second)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond}, res)
+}
+
+func assertSlidingWindow(t *testing.T, store ratelimit.Store, clock *testClock, limit ratelimit.Limit) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	res, err := store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, res)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second}, res)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Second, RetryAfter: time.Second}, res)
+
+	// previous window counted at half weight
+	clock.Advance(1500 * time.Millisecond)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 500 * time.Millisecond}, res)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}, res)
+
+	// previous windows expired
+	clock.Advance(2 * time.Second)
+
+	res, err = store.Allow(ctx, "key", limit)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond}, res)
+}
@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+)
+
+// DefaultSQLStoreTable is the default table name of the [SQLStore].
+const DefaultSQLStoreTable = "http_server_rate_limits"
+
+// maxSQLKeyLength is the maximum key length stored as is, longer keys are hashed.
+const maxSQLKeyLength = 191
+
+// SQLStore is a [Store] keeping the rate limits states in a SQL database table, suitable for multi instances setups.
+type SQLStore struct {
+	db      *sql.DB
+	dialect sqlstore.Dialect
+	table   string
+	now     func() time.Time
+}
+
+// SQLStoreOption are functional options for the [SQLStore].
+type SQLStoreOption func(s *SQLStore)
+
+// WithSQLStoreTable is used to specify the table of the [SQLStore] (default http_server_rate_limits).
+func WithSQLStoreTable(table string) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.table = table
+	}
+}
+
+// WithSQLStoreClock is used to specify the time source of the [SQLStore] (time.Now by default).
+func WithSQLStoreClock(now func() time.Time) SQLStoreOption {
+	return func(s *SQLStore) {
+		s.now = now
+	}
+}
+
+// NewSQLStore returns a new [SQLStore], for a [sql.DB] of the provided [sqlstore.Dialect].
+func NewSQLStore(db *sql.DB, dialect sqlstore.Dialect, options ...SQLStoreOption) (*SQLStore, error) {
+	if dialect == sqlstore.UnknownDialect {
+		return nil, fmt.Errorf("unsupported sql dialect for rate limit store")
+	}
+
+	store := &SQLStore{
+		db:      db,
+		dialect: dialect,
+		table:   DefaultSQLStoreTable,
+		now:     time.Now,
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	return store, nil
+}
+
+// CreateTable creates the [SQLStore] table, if it does not exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+				rate_key VARCHAR(191) NOT NULL PRIMARY KEY,
+				rate_value DOUBLE PRECISION NOT NULL,
+				rate_previous DOUBLE PRECISION NOT NULL,
+				rate_time BIGINT NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+			s.table,
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create rate limit table: %w", err)
+	}
+
+	return nil
+}
+
+// Allow applies a request for the key on the [Limit], and returns the [Result].
+func (s *SQLStore) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+
+	key = s.key(key)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot begin rate limit transaction: %w", err)
+	}
+
+	//nolint:errcheck
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, s.query(s.insertQuery()), key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot insert rate limit: %w", err)
+	}
+
+	var current state
+	var expiresAt int64
+
+	err = tx.QueryRowContext(ctx, s.query(s.selectQuery()), key).Scan(
+		&current.Value,
+		&current.Previous,
+		&current.Time,
+		&expiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select rate limit: %w", err)
+	}
+
+	now := s.now()
+
+	if expiresAt <= now.UnixNano() {
+		current = state{}
+	}
+
+	next, result, nextExpiresAt := apply(limit, current, now)
+
+	_, err = tx.ExecContext(
+		ctx,
+		s.query(fmt.Sprintf("UPDATE %s SET rate_value = ?, rate_previous = ?, rate_time = ?, expires_at = ? WHERE rate_key = ?", s.table)),
+		next.Value,
+		next.Previous,
+		next.Time,
+		nextExpiresAt.UnixNano(),
+		key,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot update rate limit: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("cannot commit rate limit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// Purge deletes the expired rate limits, and returns the number of deleted rows.
+func (s *SQLStore) Purge(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(
+		ctx,
+		s.query(fmt.Sprintf("DELETE FROM %s WHERE expires_at <= ?", s.table)),
+		s.now().UnixNano(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("cannot purge rate limits: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+func (s *SQLStore) insertQuery() string {
+	if s.dialect == sqlstore.MySQLDialect {
+		return fmt.Sprintf("INSERT IGNORE INTO %s (rate_key, rate_value, rate_previous, rate_time, expires_at) VALUES (?, 0, 0, 0, 0)", s.table)
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (rate_key, rate_value, rate_previous, rate_time, expires_at) VALUES (?, 0, 0, 0, 0) ON CONFLICT DO NOTHING", s.table)
+}
+
+func (s *SQLStore) selectQuery() string {
+	query := fmt.Sprintf("SELECT rate_value, rate_previous, rate_time, expires_at FROM %s WHERE rate_key = ?", s.table)
+
+	// sqlite locks the whole database on write, the previous insert already holds the lock
+	if s.dialect != sqlstore.SQLiteDialect {
+		query = query + " FOR UPDATE"
+	}
+
+	return query
+}
+
+// query converts the ? placeholders for the dialect.
+func (s *SQLStore) query(query string) string {
+	return s.dialect.Rebind(query)
+}
+
+// key hashes the keys too long to be stored as is.
+func (s *SQLStore) key(key string) string {
+	if len(key) <= maxSQLKeyLength {
+		return key
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
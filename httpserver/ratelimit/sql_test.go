@@ -0,0 +1,104 @@
+package ratelimit_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/ratelimit"
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLStoreWithUnknownDialect(t *testing.T) {
+	t.Parallel()
+
+	_, err := ratelimit.NewSQLStore(nil, sqlstore.UnknownDialect)
+	assert.Error(t, err)
+	assert.Equal(t, "unsupported sql dialect for rate limit store", err.Error())
+}
+
+func TestSQLStoreTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := createTestSQLStore(t, clock)
+
+	assertTokenBucket(t, store, clock, ratelimit.Limit{Algorithm: ratelimit.TokenBucket, Requests: 2, Period: time.Second})
+}
+
+func TestSQLStoreSlidingWindow(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := createTestSQLStore(t, clock)
+
+	assertSlidingWindow(t, store, clock, ratelimit.Limit{Algorithm: ratelimit.SlidingWindow, Requests: 2, Period: time.Second})
+}
+
+func TestSQLStoreWithLongKeyAndPurge(t *testing.T) {
+	t.Parallel()
+
+	clock := &testClock{now: time.Unix(1000, 0)}
+	store := createTestSQLStore(t, clock)
+	limit := ratelimit.Limit{Requests: 1, Period: time.Second}
+
+	key := strings.Repeat("k", 500)
+
+	res, err := store.Allow(context.Background(), key, limit)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = store.Allow(context.Background(), key, limit)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	count, err := store.Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	clock.Advance(time.Minute)
+
+	count, err = store.Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestSQLStoreWithInvalidLimitOrMissingTable(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	store, err := ratelimit.NewSQLStore(db, sqlstore.SQLiteDialect, ratelimit.WithSQLStoreTable("missing"))
+	assert.NoError(t, err)
+
+	_, err = store.Allow(context.Background(), "key", ratelimit.Limit{})
+	assert.Error(t, err)
+
+	_, err = store.Allow(context.Background(), "key", ratelimit.Limit{Requests: 1, Period: time.Second})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot insert rate limit")
+}
+
+func createTestSQLStore(t *testing.T, clock *testClock) *ratelimit.SQLStore {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+
+	store, err := ratelimit.NewSQLStore(db, sqlstore.SQLiteDialect, ratelimit.WithSQLStoreClock(clock.Now))
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.CreateTable(context.Background()))
+
+	return store
+}
@@ -0,0 +1,78 @@
+package httpserver
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RouteInfo is the information of a http server route, with its metadata.
+type RouteInfo struct {
+	Method      string         `json:"method"`
+	Path        string         `json:"path"`
+	Name        string         `json:"name"`
+	Tags        []string       `json:"tags,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+// RoutesInfo collects the metadata of the http server routes, by method and path.
+type RoutesInfo struct {
+	mutex  sync.RWMutex
+	routes map[string]RouteInfo
+}
+
+// NewRoutesInfo returns a new [RoutesInfo].
+func NewRoutesInfo() *RoutesInfo {
+	return &RoutesInfo{
+		routes: map[string]RouteInfo{},
+	}
+}
+
+// Add adds the metadata of a route, identified by its method and path.
+func (i *RoutesInfo) Add(route RouteInfo) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.routes[route.Method+route.Path] = route
+}
+
+// Get returns the metadata of a route, identified by its method and path.
+func (i *RoutesInfo) Get(method string, path string) (RouteInfo, bool) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	route, ok := i.routes[method+path]
+
+	return route, ok
+}
+
+// Routes returns the routes of a http server, with their metadata if any, sorted by path and method.
+func (i *RoutesInfo) Routes(httpServer *echo.Echo) []RouteInfo {
+	routes := make([]RouteInfo, 0, len(httpServer.Routes()))
+
+	for _, echoRoute := range httpServer.Routes() {
+		route, ok := i.Get(echoRoute.Method, echoRoute.Path)
+		if !ok {
+			route = RouteInfo{
+				Method: echoRoute.Method,
+				Path:   echoRoute.Path,
+			}
+		}
+
+		route.Name = echoRoute.Name
+
+		routes = append(routes, route)
+	}
+
+	sort.Slice(routes, func(a, b int) bool {
+		if routes[a].Path == routes[b].Path {
+			return routes[a].Method < routes[b].Method
+		}
+
+		return routes[a].Path < routes[b].Path
+	})
+
+	return routes
+}
@@ -0,0 +1,64 @@
+package httpserver_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutesInfo(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	httpServer.GET("/users", handler).Name = "list-users"
+	httpServer.POST("/users", handler).Name = "create-user"
+	httpServer.GET("/health", handler)
+
+	routesInfo := httpserver.NewRoutesInfo()
+	routesInfo.Add(httpserver.RouteInfo{
+		Method:      http.MethodGet,
+		Path:        "/users",
+		Name:        "list-users",
+		Tags:        []string{"users"},
+		Description: "list the users",
+		Metadata:    map[string]any{"owner": "team-a"},
+	})
+
+	route, ok := routesInfo.Get(http.MethodGet, "/users")
+	assert.True(t, ok)
+	assert.Equal(t, "list-users", route.Name)
+
+	_, ok = routesInfo.Get(http.MethodGet, "/invalid")
+	assert.False(t, ok)
+
+	routes := routesInfo.Routes(httpServer)
+	assert.Len(t, routes, 3)
+
+	assert.Equal(t, http.MethodGet, routes[0].Method)
+	assert.Equal(t, "/health", routes[0].Path)
+	assert.NotEmpty(t, routes[0].Name)
+	assert.Empty(t, routes[0].Tags)
+
+	assert.Equal(
+		t,
+		httpserver.RouteInfo{
+			Method:      http.MethodGet,
+			Path:        "/users",
+			Name:        "list-users",
+			Tags:        []string{"users"},
+			Description: "list the users",
+			Metadata:    map[string]any{"owner": "team-a"},
+		},
+		routes[1],
+	)
+
+	assert.Equal(t, httpserver.RouteInfo{Method: http.MethodPost, Path: "/users", Name: "create-user"}, routes[2])
+}
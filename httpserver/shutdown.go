@@ -0,0 +1,90 @@
+package httpserver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ShutdownParticipant handles the graceful shutdown of an [echo.Echo] http server, and tracks its in-flight requests.
+type ShutdownParticipant struct {
+	name     string
+	mutex    sync.Mutex
+	server   *echo.Echo
+	inFlight atomic.Int64
+	once     sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// NewShutdownParticipant returns a new [ShutdownParticipant] instance.
+func NewShutdownParticipant(name string) *ShutdownParticipant {
+	return &ShutdownParticipant{
+		name: name,
+		done: make(chan struct{}),
+	}
+}
+
+// Name returns the name of the [ShutdownParticipant].
+func (p *ShutdownParticipant) Name() string {
+	return p.name
+}
+
+// Bind binds an [echo.Echo] http server to the [ShutdownParticipant].
+func (p *ShutdownParticipant) Bind(server *echo.Echo) *ShutdownParticipant {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.server = server
+
+	return p
+}
+
+// Middleware returns an [echo.MiddlewareFunc] tracking the in-flight requests.
+func (p *ShutdownParticipant) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p.inFlight.Add(1)
+			defer p.inFlight.Add(-1)
+
+			return next(c)
+		}
+	}
+}
+
+// InFlight returns the number of in-flight requests.
+func (p *ShutdownParticipant) InFlight() int64 {
+	return p.inFlight.Load()
+}
+
+// Done returns a channel closed when the shutdown starts, for the long-lived connections (like WebSocket or
+// Server-Sent Events streams), which are not closed by the http server shutdown, to be closed gracefully.
+func (p *ShutdownParticipant) Done() <-chan struct{} {
+	return p.done
+}
+
+// Shutdown gracefully shuts down the bound http server, and forces its close if the context ends before completion.
+// Only the first call performs the shutdown, next calls return its result.
+func (p *ShutdownParticipant) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		close(p.done)
+
+		p.mutex.Lock()
+		server := p.server
+		p.mutex.Unlock()
+
+		if server == nil {
+			return
+		}
+
+		p.err = server.Shutdown(ctx)
+		if p.err != nil {
+			//nolint:errcheck
+			server.Close()
+		}
+	})
+
+	return p.err
+}
@@ -0,0 +1,122 @@
+package httpserver_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownParticipantWithoutServer(t *testing.T) {
+	t.Parallel()
+
+	participant := httpserver.NewShutdownParticipant("test")
+
+	assert.Equal(t, "test", participant.Name())
+	assert.Equal(t, int64(0), participant.InFlight())
+
+	select {
+	case <-participant.Done():
+		t.Error("done channel should not be closed before shutdown")
+	default:
+	}
+
+	assert.NoError(t, participant.Shutdown(context.Background()))
+
+	_, open := <-participant.Done()
+	assert.False(t, open)
+}
+
+func TestShutdownParticipantWithServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("graceful shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		server, participant, release := startShutdownTestServer(t)
+
+		response := make(chan int, 1)
+		go func() {
+			//nolint:noctx
+			resp, err := http.Get("http://" + server.ListenerAddr().String())
+			if err == nil {
+				response <- resp.StatusCode
+				//nolint:errcheck
+				resp.Body.Close()
+			}
+		}()
+
+		assert.Eventually(t, func() bool {
+			return participant.InFlight() == 1
+		}, time.Second, 10*time.Millisecond)
+
+		shutdown := make(chan error, 1)
+		go func() {
+			shutdown <- participant.Shutdown(context.Background())
+		}()
+
+		close(release)
+
+		assert.NoError(t, <-shutdown)
+		assert.Equal(t, http.StatusNoContent, <-response)
+		assert.Equal(t, int64(0), participant.InFlight())
+
+		// next calls return the first shutdown result
+		assert.NoError(t, participant.Shutdown(context.Background()))
+	})
+
+	t.Run("forced shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		server, participant, release := startShutdownTestServer(t)
+		defer close(release)
+
+		go func() {
+			//nolint:noctx,bodyclose
+			http.Get("http://" + server.ListenerAddr().String())
+		}()
+
+		assert.Eventually(t, func() bool {
+			return participant.InFlight() == 1
+		}, time.Second, 10*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := participant.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, participant.Shutdown(context.Background()), context.DeadlineExceeded)
+	})
+}
+
+func startShutdownTestServer(t *testing.T) (*echo.Echo, *httpserver.ShutdownParticipant, chan struct{}) {
+	t.Helper()
+
+	release := make(chan struct{})
+
+	server := echo.New()
+	server.HideBanner = true
+	server.HidePort = true
+
+	participant := httpserver.NewShutdownParticipant("test").Bind(server)
+
+	server.Use(participant.Middleware())
+	server.GET("/", func(c echo.Context) error {
+		<-release
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	//nolint:errcheck
+	go server.Start("127.0.0.1:0")
+
+	assert.Eventually(t, func() bool {
+		return server.ListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	return server, participant, release
+}
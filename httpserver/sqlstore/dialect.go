@@ -0,0 +1,65 @@
+package sqlstore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is an enum for the SQL dialects supported by the http server SQL stores.
+type Dialect int
+
+const (
+	UnknownDialect Dialect = iota
+	MySQLDialect
+	PostgresDialect
+	SQLiteDialect
+)
+
+// String returns a string representation of the [Dialect].
+func (d Dialect) String() string {
+	switch d {
+	case MySQLDialect:
+		return "mysql"
+	case PostgresDialect:
+		return "postgres"
+	case SQLiteDialect:
+		return "sqlite"
+	default:
+		return "unknown"
+	}
+}
+
+// Rebind converts the ? placeholders of a query for the [Dialect].
+func (d Dialect) Rebind(query string) string {
+	if d != PostgresDialect {
+		return query
+	}
+
+	var builder strings.Builder
+
+	position := 0
+	for _, char := range query {
+		if char == '?' {
+			position++
+			builder.WriteString(fmt.Sprintf("$%d", position))
+		} else {
+			builder.WriteRune(char)
+		}
+	}
+
+	return builder.String()
+}
+
+// FetchDialect returns a [Dialect] for a given value, for example an SQL driver name.
+func FetchDialect(dialect string) Dialect {
+	switch strings.ToLower(dialect) {
+	case "mysql":
+		return MySQLDialect
+	case "postgres", "postgresql", "pgx":
+		return PostgresDialect
+	case "sqlite", "sqlite3":
+		return SQLiteDialect
+	default:
+		return UnknownDialect
+	}
+}
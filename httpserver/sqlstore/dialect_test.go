@@ -0,0 +1,30 @@
+package sqlstore_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver/sqlstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchDialect(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, sqlstore.MySQLDialect, sqlstore.FetchDialect("mysql"))
+	assert.Equal(t, sqlstore.PostgresDialect, sqlstore.FetchDialect("postgres"))
+	assert.Equal(t, sqlstore.PostgresDialect, sqlstore.FetchDialect("pgx"))
+	assert.Equal(t, sqlstore.SQLiteDialect, sqlstore.FetchDialect("sqlite3"))
+	assert.Equal(t, sqlstore.UnknownDialect, sqlstore.FetchDialect("invalid"))
+}
+
+func TestDialect(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "mysql", sqlstore.MySQLDialect.String())
+	assert.Equal(t, "postgres", sqlstore.PostgresDialect.String())
+	assert.Equal(t, "sqlite", sqlstore.SQLiteDialect.String())
+	assert.Equal(t, "unknown", sqlstore.UnknownDialect.String())
+
+	assert.Equal(t, "SELECT * FROM t WHERE a = ? AND b = ?", sqlstore.MySQLDialect.Rebind("SELECT * FROM t WHERE a = ? AND b = ?"))
+	assert.Equal(t, "SELECT * FROM t WHERE a = $1 AND b = $2", sqlstore.PostgresDialect.Rebind("SELECT * FROM t WHERE a = ? AND b = ?"))
+}
@@ -0,0 +1,135 @@
+package stream
+
+import (
+	"errors"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	HttpServerMetricsStreamConnectionsActive   = "http_server_stream_connections_active"
+	HttpServerMetricsStreamConnectionsDuration = "http_server_stream_connections_duration_seconds"
+	HttpServerMetricsStreamMessagesCount       = "http_server_stream_messages_total"
+	MessageSent                                = "sent"
+	MessageReceived                            = "received"
+)
+
+// Metrics are the streams metrics: active connections, connections durations, and sent and received messages,
+// labelled by stream kind and route path.
+type Metrics struct {
+	active   *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	messages *prometheus.CounterVec
+}
+
+// NewMetrics returns new [Metrics], registered in the provided [prometheus.Registerer], or reusing the already
+// registered ones, since several streams handlers can share the same registry.
+func NewMetrics(registry prometheus.Registerer, namespace string, subsystem string) (*Metrics, error) {
+	active := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      HttpServerMetricsStreamConnectionsActive,
+			Help:      "Number of active HTTP streams connections",
+		},
+		[]string{
+			"kind",
+			"path",
+		},
+	)
+
+	duration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      HttpServerMetricsStreamConnectionsDuration,
+			Help:      "Duration of HTTP streams connections",
+			Buckets:   []float64{1, 10, 60, 300, 900, 1800, 3600, 7200},
+		},
+		[]string{
+			"kind",
+			"path",
+		},
+	)
+
+	messages := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      HttpServerMetricsStreamMessagesCount,
+			Help:      "Number of HTTP streams messages",
+		},
+		[]string{
+			"kind",
+			"path",
+			"direction",
+		},
+	)
+
+	var err error
+
+	if active, err = register(registry, active); err != nil {
+		return nil, err
+	}
+
+	if duration, err = register(registry, duration); err != nil {
+		return nil, err
+	}
+
+	if messages, err = register(registry, messages); err != nil {
+		return nil, err
+	}
+
+	return &Metrics{
+		active:   active,
+		duration: duration,
+		messages: messages,
+	}, nil
+}
+
+// connect records a new connection, and returns the function to call on disconnection.
+func (m *Metrics) connect(c echo.Context, kind Kind) func() {
+	if m == nil {
+		return func() {}
+	}
+
+	path := c.Path()
+	start := time.Now()
+
+	m.active.WithLabelValues(string(kind), path).Inc()
+
+	return func() {
+		m.active.WithLabelValues(string(kind), path).Dec()
+		m.duration.WithLabelValues(string(kind), path).Observe(time.Since(start).Seconds())
+	}
+}
+
+// message records a sent or received message.
+func (m *Metrics) message(c echo.Context, kind Kind, direction string) {
+	if m == nil {
+		return
+	}
+
+	m.messages.WithLabelValues(string(kind), c.Path(), direction).Inc()
+}
+
+func register[C prometheus.Collector](registry prometheus.Registerer, collector C) (C, error) {
+	if registry == nil {
+		return collector, nil
+	}
+
+	if err := registry.Register(collector); err != nil {
+		var alreadyRegisteredError prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegisteredError) {
+			if existingCollector, ok := alreadyRegisteredError.ExistingCollector.(C); ok {
+				return existingCollector, nil
+			}
+		}
+
+		return collector, err
+	}
+
+	return collector, nil
+}
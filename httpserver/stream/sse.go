@@ -0,0 +1,217 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ankorstore/yokai/log"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	MIMETextEventStream         = "text/event-stream"
+	HeaderLastEventID           = "Last-Event-ID"
+	DefaultSSEKeepAliveInterval = 15 * time.Second
+)
+
+// Event is a Server-Sent Event. The Data can span several lines.
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// SSEHandlerFunc is a Server-Sent Events handler, sending events until it returns.
+//
+// The context carries the request id, logger and tracer of the request, and is canceled when the client
+// disconnects, or when the server shuts down.
+type SSEHandlerFunc func(ctx context.Context, stream *SSEStream) error
+
+// SSEConfig is the configuration for the [SSEHandlerWithConfig].
+//
+// A keepalive comment is sent every KeepAliveInterval, for the proxies to not close idle streams. Once Done is
+// closed, for example on server shutdown, the handlers context is canceled to end the streams.
+type SSEConfig struct {
+	KeepAliveInterval time.Duration
+	Metrics           *Metrics
+	Done              <-chan struct{}
+}
+
+// DefaultSSEConfig is the default configuration for the [SSEHandlerWithConfig].
+var DefaultSSEConfig = SSEConfig{
+	KeepAliveInterval: DefaultSSEKeepAliveInterval,
+}
+
+// SSEHandler returns an [echo.HandlerFunc] streaming the Server-Sent Events of a [SSEHandlerFunc], with the
+// [DefaultSSEConfig].
+func SSEHandler(handler SSEHandlerFunc) echo.HandlerFunc {
+	return SSEHandlerWithConfig(handler, DefaultSSEConfig)
+}
+
+// SSEHandlerWithConfig returns an [echo.HandlerFunc] streaming the Server-Sent Events of a [SSEHandlerFunc], for a
+// provided [SSEConfig].
+func SSEHandlerWithConfig(handler SSEHandlerFunc, config SSEConfig) echo.HandlerFunc {
+	if config.KeepAliveInterval <= 0 {
+		config.KeepAliveInterval = DefaultSSEConfig.KeepAliveInterval
+	}
+
+	return func(c echo.Context) error {
+		resp := c.Response()
+
+		// the streams outlive the server write timeout
+		err := http.NewResponseController(resp).SetWriteDeadline(time.Time{})
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return fmt.Errorf("cannot clear sse write deadline: %w", err)
+		}
+
+		resp.Header().Set(echo.HeaderContentType, MIMETextEventStream)
+		resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+		resp.Header().Set("X-Accel-Buffering", "no")
+		resp.WriteHeader(http.StatusOK)
+		resp.Flush()
+
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+
+		stream := &SSEStream{
+			context: c,
+			metrics: config.Metrics,
+		}
+
+		disconnect := config.Metrics.connect(c, SSE)
+		defer disconnect()
+
+		var wg sync.WaitGroup
+		var shutdown bool
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(config.KeepAliveInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-config.Done:
+					shutdown = true
+
+					cancel()
+
+					return
+				case <-ticker.C:
+					if err := stream.write(": keepalive\n\n"); err != nil {
+						cancel()
+
+						return
+					}
+				}
+			}
+		}()
+
+		err = handler(ctx, stream)
+
+		cancel()
+		wg.Wait()
+
+		if err != nil && !shutdown && !errors.Is(err, context.Canceled) {
+			log.CtxLogger(ctx).Error().Err(err).Msg("sse handler error")
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+// SSEStream is a Server-Sent Events stream. Its methods can be called concurrently.
+type SSEStream struct {
+	context echo.Context
+	metrics *Metrics
+	mutex   sync.Mutex
+}
+
+// Context returns the [echo.Context] of the stream request.
+func (s *SSEStream) Context() echo.Context {
+	return s.context
+}
+
+// LastEventID returns the Last-Event-ID header of the stream request, sent by the clients on reconnection.
+func (s *SSEStream) LastEventID() string {
+	return s.context.Request().Header.Get(HeaderLastEventID)
+}
+
+// Send sends an [Event].
+func (s *SSEStream) Send(event Event) error {
+	var builder strings.Builder
+
+	if event.ID != "" {
+		builder.WriteString(fmt.Sprintf("id: %s\n", sanitizeEventField(event.ID)))
+	}
+
+	if event.Event != "" {
+		builder.WriteString(fmt.Sprintf("event: %s\n", sanitizeEventField(event.Event)))
+	}
+
+	if event.Retry > 0 {
+		builder.WriteString(fmt.Sprintf("retry: %d\n", event.Retry.Milliseconds()))
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(event.Data, "\r\n", "\n"), "\n") {
+		builder.WriteString(fmt.Sprintf("data: %s\n", line))
+	}
+
+	builder.WriteString("\n")
+
+	if err := s.write(builder.String()); err != nil {
+		return err
+	}
+
+	s.metrics.message(s.context, SSE, MessageSent)
+
+	return nil
+}
+
+// SendData sends an unnamed event with the provided data.
+func (s *SSEStream) SendData(data string) error {
+	return s.Send(Event{Data: data})
+}
+
+// SendJSON sends an event with the provided name (optional), and the JSON encoding of the provided value as data.
+func (s *SSEStream) SendJSON(event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("cannot encode event data: %w", err)
+	}
+
+	return s.Send(Event{Event: event, Data: string(data)})
+}
+
+func (s *SSEStream) write(data string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	resp := s.context.Response()
+
+	if _, err := resp.Write([]byte(data)); err != nil {
+		return err
+	}
+
+	resp.Flush()
+
+	return nil
+}
+
+func sanitizeEventField(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
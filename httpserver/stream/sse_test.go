@@ -0,0 +1,189 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/stream"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSEHandler(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewPedanticRegistry()
+
+	metrics, err := stream.NewMetrics(registry, "", "")
+	assert.NoError(t, err)
+
+	httpServer := echo.New()
+	httpServer.GET("/events", stream.SSEHandlerWithConfig(
+		func(ctx context.Context, s *stream.SSEStream) error {
+			assert.Equal(t, "41", s.LastEventID())
+			assert.Equal(t, "/events", s.Context().Path())
+
+			err := s.Send(stream.Event{
+				ID:    "42",
+				Event: "update\nforged",
+				Data:  "line 1\nline 2",
+				Retry: 3 * time.Second,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = s.SendData("data")
+			if err != nil {
+				return err
+			}
+
+			return s.SendJSON("user", map[string]string{"name": "john"})
+		},
+		stream.SSEConfig{Metrics: metrics},
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set(stream.HeaderLastEventID, "41")
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, stream.MIMETextEventStream, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "no-cache", rec.Header().Get(echo.HeaderCacheControl))
+	assert.Empty(t, rec.Header().Get(echo.HeaderConnection))
+	assert.True(t, rec.Flushed)
+
+	expectedBody := "id: 42\nevent: updateforged\nretry: 3000\ndata: line 1\ndata: line 2\n\n" +
+		"data: data\n\n" +
+		"event: user\ndata: {\"name\":\"john\"}\n\n"
+
+	assert.Equal(t, expectedBody, rec.Body.String())
+
+	expectedMetrics := `
+		# HELP http_server_stream_connections_active Number of active HTTP streams connections
+		# TYPE http_server_stream_connections_active gauge
+		http_server_stream_connections_active{kind="sse",path="/events"} 0
+		# HELP http_server_stream_messages_total Number of HTTP streams messages
+		# TYPE http_server_stream_messages_total counter
+		http_server_stream_messages_total{direction="sent",kind="sse",path="/events"} 3
+	`
+
+	assert.NoError(t, testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expectedMetrics),
+		"http_server_stream_connections_active",
+		"http_server_stream_messages_total",
+	))
+}
+
+type deadlineFailingWriter struct {
+	http.ResponseWriter
+}
+
+func (w *deadlineFailingWriter) SetWriteDeadline(time.Time) error {
+	return fmt.Errorf("deadline error")
+}
+
+func TestSSEHandlerWithWriteDeadlineError(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Writer = &deadlineFailingWriter{c.Response().Writer}
+
+			return next(c)
+		}
+	})
+	httpServer.GET("/events", stream.SSEHandler(func(ctx context.Context, s *stream.SSEStream) error {
+		return s.SendData("data")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "data: data")
+}
+
+func TestSSEHandlerWithKeepAlive(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.GET("/events", stream.SSEHandlerWithConfig(
+		func(ctx context.Context, s *stream.SSEStream) error {
+			time.Sleep(50 * time.Millisecond)
+
+			return s.SendData("data")
+		},
+		stream.SSEConfig{KeepAliveInterval: 10 * time.Millisecond},
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Contains(t, rec.Body.String(), ": keepalive\n\n")
+	assert.Contains(t, rec.Body.String(), "data: data\n\n")
+}
+
+func TestSSEHandlerWithShutdown(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+
+	httpServer := echo.New()
+	httpServer.GET("/events", stream.SSEHandlerWithConfig(
+		func(ctx context.Context, s *stream.SSEStream) error {
+			if err := s.SendData("started"); err != nil {
+				return err
+			}
+
+			close(done)
+
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+		stream.SSEConfig{Done: done},
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "data: started\n\n", rec.Body.String())
+}
+
+func TestSSEHandlerWithHandlerError(t *testing.T) {
+	t.Parallel()
+
+	var handledErr error
+
+	httpServer := echo.New()
+	httpServer.HTTPErrorHandler = func(err error, c echo.Context) {
+		handledErr = err
+	}
+
+	httpServer.GET("/events", stream.SSEHandler(func(ctx context.Context, s *stream.SSEStream) error {
+		return fmt.Errorf("handler error")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Error(t, handledErr)
+	assert.Equal(t, "handler error", handledErr.Error())
+}
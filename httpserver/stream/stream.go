@@ -0,0 +1,9 @@
+package stream
+
+// Kind is the kind of a stream.
+type Kind string
+
+const (
+	WebSocket Kind = "websocket"
+	SSE       Kind = "sse"
+)
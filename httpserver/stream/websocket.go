@@ -0,0 +1,280 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ankorstore/yokai/log"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	TextMessage                   = websocket.TextMessage
+	BinaryMessage                 = websocket.BinaryMessage
+	DefaultWebSocketPingInterval  = 30 * time.Second
+	DefaultWebSocketPongTimeout   = 60 * time.Second
+	DefaultWebSocketWriteTimeout  = 10 * time.Second
+	WebSocketShutdownCloseMessage = "server shutdown"
+)
+
+// WebSocketHandlerFunc is a WebSocket handler, executed once the connection is upgraded.
+//
+// The context carries the request id, logger and tracer of the upgrade request, and is canceled when the
+// connection is closed, or when the server shuts down. The handler must keep reading messages for the ping /
+// pong keepalive to be handled.
+type WebSocketHandlerFunc func(ctx context.Context, conn *WebSocketConn) error
+
+// WebSocketConfig is the configuration for the [WebSocketHandlerWithConfig].
+//
+// The connections are pinged every PingInterval, and closed if no pong is received within PongTimeout. Messages
+// larger than ReadLimit bytes (if positive) close the connection. CheckOrigin validates the upgrade requests origins
+// (by default, the origin host must match the request host). Once Done is closed, for example on server shutdown,
+// the connections are closed with a going away close message.
+type WebSocketConfig struct {
+	CheckOrigin       func(r *http.Request) bool
+	EnableCompression bool
+	ReadLimit         int64
+	PingInterval      time.Duration
+	PongTimeout       time.Duration
+	WriteTimeout      time.Duration
+	Metrics           *Metrics
+	Done              <-chan struct{}
+}
+
+// DefaultWebSocketConfig is the default configuration for the [WebSocketHandlerWithConfig].
+var DefaultWebSocketConfig = WebSocketConfig{
+	PingInterval: DefaultWebSocketPingInterval,
+	PongTimeout:  DefaultWebSocketPongTimeout,
+	WriteTimeout: DefaultWebSocketWriteTimeout,
+}
+
+// WebSocketHandler returns an [echo.HandlerFunc] upgrading the requests to WebSocket connections handled by a
+// [WebSocketHandlerFunc], with the [DefaultWebSocketConfig].
+func WebSocketHandler(handler WebSocketHandlerFunc) echo.HandlerFunc {
+	return WebSocketHandlerWithConfig(handler, DefaultWebSocketConfig)
+}
+
+// WebSocketHandlerWithConfig returns an [echo.HandlerFunc] upgrading the requests to WebSocket connections handled by
+// a [WebSocketHandlerFunc], for a provided [WebSocketConfig].
+func WebSocketHandlerWithConfig(handler WebSocketHandlerFunc, config WebSocketConfig) echo.HandlerFunc {
+	if config.PingInterval <= 0 {
+		config.PingInterval = DefaultWebSocketConfig.PingInterval
+	}
+
+	if config.PongTimeout <= 0 {
+		config.PongTimeout = DefaultWebSocketConfig.PongTimeout
+	}
+
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = DefaultWebSocketConfig.WriteTimeout
+	}
+
+	return func(c echo.Context) error {
+		var upgradeErr error
+
+		upgrader := websocket.Upgrader{
+			CheckOrigin:       config.CheckOrigin,
+			EnableCompression: config.EnableCompression,
+			Error: func(_ http.ResponseWriter, _ *http.Request, status int, reason error) {
+				upgradeErr = echo.NewHTTPError(status, reason.Error())
+			},
+		}
+
+		ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			if upgradeErr != nil {
+				return upgradeErr
+			}
+
+			return err
+		}
+
+		// the connection is hijacked, the response must not be written anymore
+		c.Response().Status = http.StatusSwitchingProtocols
+		c.Response().Committed = true
+
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+
+		conn := &WebSocketConn{
+			conn:         ws,
+			context:      c,
+			metrics:      config.Metrics,
+			writeTimeout: config.WriteTimeout,
+		}
+
+		disconnect := config.Metrics.connect(c, WebSocket)
+		defer disconnect()
+
+		if config.ReadLimit > 0 {
+			ws.SetReadLimit(config.ReadLimit)
+		}
+
+		//nolint:errcheck
+		ws.SetReadDeadline(time.Now().Add(config.PongTimeout))
+		ws.SetPongHandler(func(string) error {
+			return ws.SetReadDeadline(time.Now().Add(config.PongTimeout))
+		})
+
+		var wg sync.WaitGroup
+		var shutdown bool
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(config.PingInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-config.Done:
+					shutdown = true
+
+					conn.close(websocket.CloseGoingAway, WebSocketShutdownCloseMessage)
+					cancel()
+
+					// unblocks the pending reads
+					//nolint:errcheck
+					ws.SetReadDeadline(time.Now())
+
+					return
+				case <-ticker.C:
+					err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(config.WriteTimeout))
+					if err != nil {
+						cancel()
+
+						return
+					}
+				}
+			}
+		}()
+
+		err = handler(ctx, conn)
+
+		cancel()
+		wg.Wait()
+
+		if !shutdown {
+			if err != nil && !isWebSocketClosure(err) {
+				conn.close(websocket.CloseInternalServerErr, http.StatusText(http.StatusInternalServerError))
+			} else {
+				conn.close(websocket.CloseNormalClosure, "")
+			}
+		}
+
+		//nolint:errcheck
+		ws.Close()
+
+		if err != nil && !shutdown && !isWebSocketClosure(err) {
+			log.CtxLogger(ctx).Error().Err(err).Msg("websocket handler error")
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+// WebSocketConn is an upgraded WebSocket connection. Its write methods can be called concurrently, but its read
+// methods must be called by a single goroutine.
+type WebSocketConn struct {
+	conn         *websocket.Conn
+	context      echo.Context
+	metrics      *Metrics
+	writeTimeout time.Duration
+	writeMutex   sync.Mutex
+}
+
+// Conn returns the underlying [websocket.Conn].
+func (c *WebSocketConn) Conn() *websocket.Conn {
+	return c.conn
+}
+
+// Context returns the [echo.Context] of the upgrade request.
+func (c *WebSocketConn) Context() echo.Context {
+	return c.context
+}
+
+// ReadMessage reads a message, and returns its type ([TextMessage] or [BinaryMessage]) and its data.
+func (c *WebSocketConn) ReadMessage() (int, []byte, error) {
+	messageType, data, err := c.conn.ReadMessage()
+	if err != nil {
+		return messageType, data, err
+	}
+
+	c.metrics.message(c.context, WebSocket, MessageReceived)
+
+	return messageType, data, nil
+}
+
+// ReadJSON reads a JSON message, and decodes it in the provided value.
+func (c *WebSocketConn) ReadJSON(v any) error {
+	err := c.conn.ReadJSON(v)
+	if err != nil {
+		return err
+	}
+
+	c.metrics.message(c.context, WebSocket, MessageReceived)
+
+	return nil
+}
+
+// WriteMessage writes a message of the provided type ([TextMessage] or [BinaryMessage]).
+func (c *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	//nolint:errcheck
+	c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+
+	err := c.conn.WriteMessage(messageType, data)
+	if err != nil {
+		return err
+	}
+
+	c.metrics.message(c.context, WebSocket, MessageSent)
+
+	return nil
+}
+
+// WriteJSON writes a value as JSON text message.
+func (c *WebSocketConn) WriteJSON(v any) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	//nolint:errcheck
+	c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+
+	err := c.conn.WriteJSON(v)
+	if err != nil {
+		return err
+	}
+
+	c.metrics.message(c.context, WebSocket, MessageSent)
+
+	return nil
+}
+
+func (c *WebSocketConn) close(code int, text string) {
+	//nolint:errcheck
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(c.writeTimeout))
+}
+
+// isWebSocketClosure returns true if the error is caused by the connection closure.
+func isWebSocketClosure(err error) bool {
+	return websocket.IsCloseError(
+		err,
+		websocket.CloseNormalClosure,
+		websocket.CloseGoingAway,
+		websocket.CloseNoStatusReceived,
+		websocket.CloseAbnormalClosure,
+	) || errors.Is(err, context.Canceled)
+}
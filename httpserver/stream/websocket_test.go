@@ -0,0 +1,249 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver/stream"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func startWebSocketTestServer(t *testing.T, handler echo.HandlerFunc) string {
+	t.Helper()
+
+	httpServer := echo.New()
+	httpServer.GET("/ws", handler)
+
+	server := httptest.NewServer(httpServer)
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+}
+
+func dialWebSocketTestServer(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	//nolint:bodyclose
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		//nolint:errcheck
+		conn.Close()
+	})
+
+	return conn
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewPedanticRegistry()
+
+	metrics, err := stream.NewMetrics(registry, "foo", "bar")
+	assert.NoError(t, err)
+
+	handlerErr := make(chan error, 1)
+
+	url := startWebSocketTestServer(t, stream.WebSocketHandlerWithConfig(
+		func(ctx context.Context, conn *stream.WebSocketConn) error {
+			assert.Equal(t, "/ws", conn.Context().Path())
+			assert.NotNil(t, conn.Conn())
+
+			for {
+				messageType, data, err := conn.ReadMessage()
+				if err != nil {
+					handlerErr <- err
+
+					return err
+				}
+
+				err = conn.WriteMessage(messageType, []byte(fmt.Sprintf("echo: %s", data)))
+				if err != nil {
+					return err
+				}
+
+				var payload map[string]string
+
+				err = conn.ReadJSON(&payload)
+				if err != nil {
+					return err
+				}
+
+				err = conn.WriteJSON(map[string]string{"echo": payload["name"]})
+				if err != nil {
+					return err
+				}
+			}
+		},
+		stream.WebSocketConfig{Metrics: metrics},
+	))
+
+	conn := dialWebSocketTestServer(t, url)
+
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+
+	messageType, data, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, messageType)
+	assert.Equal(t, "echo: hello", string(data))
+
+	assert.NoError(t, conn.WriteJSON(map[string]string{"name": "john"}))
+
+	var payload map[string]string
+	assert.NoError(t, conn.ReadJSON(&payload))
+	assert.Equal(t, map[string]string{"echo": "john"}, payload)
+
+	expectedMetrics := `
+		# HELP foo_bar_http_server_stream_connections_active Number of active HTTP streams connections
+		# TYPE foo_bar_http_server_stream_connections_active gauge
+		foo_bar_http_server_stream_connections_active{kind="websocket",path="/ws"} 1
+		# HELP foo_bar_http_server_stream_messages_total Number of HTTP streams messages
+		# TYPE foo_bar_http_server_stream_messages_total counter
+		foo_bar_http_server_stream_messages_total{direction="received",kind="websocket",path="/ws"} 2
+		foo_bar_http_server_stream_messages_total{direction="sent",kind="websocket",path="/ws"} 2
+	`
+
+	assert.NoError(t, testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expectedMetrics),
+		"foo_bar_http_server_stream_connections_active",
+		"foo_bar_http_server_stream_messages_total",
+	))
+
+	// client closure
+	assert.NoError(t, conn.WriteMessage(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+	))
+
+	assert.True(t, websocket.IsCloseError(<-handlerErr, websocket.CloseNormalClosure))
+
+	expectedMetrics = `
+		# HELP foo_bar_http_server_stream_connections_active Number of active HTTP streams connections
+		# TYPE foo_bar_http_server_stream_connections_active gauge
+		foo_bar_http_server_stream_connections_active{kind="websocket",path="/ws"} 0
+	`
+
+	assert.Eventually(t, func() bool {
+		return testutil.GatherAndCompare(
+			registry,
+			strings.NewReader(expectedMetrics),
+			"foo_bar_http_server_stream_connections_active",
+		) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "foo_bar_http_server_stream_connections_duration_seconds"))
+}
+
+func TestWebSocketHandlerWithShutdown(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+	handlerDone := make(chan error, 1)
+
+	url := startWebSocketTestServer(t, stream.WebSocketHandlerWithConfig(
+		func(ctx context.Context, conn *stream.WebSocketConn) error {
+			_, _, err := conn.ReadMessage()
+
+			assert.Error(t, ctx.Err())
+			handlerDone <- err
+
+			return err
+		},
+		stream.WebSocketConfig{Done: done},
+	))
+
+	conn := dialWebSocketTestServer(t, url)
+
+	close(done)
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+
+	var closeErr *websocket.CloseError
+	assert.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, stream.WebSocketShutdownCloseMessage, closeErr.Text)
+
+	assert.Error(t, <-handlerDone)
+}
+
+func TestWebSocketHandlerWithHandlerError(t *testing.T) {
+	t.Parallel()
+
+	url := startWebSocketTestServer(t, stream.WebSocketHandler(
+		func(ctx context.Context, conn *stream.WebSocketConn) error {
+			return fmt.Errorf("handler error")
+		},
+	))
+
+	conn := dialWebSocketTestServer(t, url)
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseInternalServerErr))
+}
+
+func TestWebSocketHandlerWithKeepAlive(t *testing.T) {
+	t.Parallel()
+
+	url := startWebSocketTestServer(t, stream.WebSocketHandlerWithConfig(
+		func(ctx context.Context, conn *stream.WebSocketConn) error {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return err
+				}
+			}
+		},
+		stream.WebSocketConfig{
+			PingInterval: 10 * time.Millisecond,
+			PongTimeout:  time.Second,
+		},
+	))
+
+	conn := dialWebSocketTestServer(t, url)
+
+	var pings atomic.Int32
+
+	conn.SetPingHandler(func(data string) error {
+		pings.Add(1)
+
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	assert.Eventually(t, func() bool {
+		return pings.Load() >= 3
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWebSocketHandlerWithInvalidUpgrade(t *testing.T) {
+	t.Parallel()
+
+	httpServer := echo.New()
+	httpServer.GET("/ws", stream.WebSocketHandler(func(ctx context.Context, conn *stream.WebSocketConn) error {
+		return nil
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	rec := httptest.NewRecorder()
+	httpServer.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
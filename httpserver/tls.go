@@ -0,0 +1,255 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CertificateReloadHandler is called by [CertificateReloader.Watch] when the certificate was reloaded (nil error), or failed to reload.
+type CertificateReloadHandler func(err error)
+
+// CertificateReloader provides a TLS certificate loaded from PEM files, reloadable when the files change.
+type CertificateReloader struct {
+	certFile    string
+	keyFile     string
+	mutex       sync.RWMutex
+	certPEM     []byte
+	keyPEM      []byte
+	certificate *tls.Certificate
+}
+
+// NewCertificateReloader returns a new [CertificateReloader], with the certificate loaded from the provided files.
+func NewCertificateReloader(certFile string, keyFile string) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if _, err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// Certificate returns the currently loaded certificate.
+func (r *CertificateReloader) Certificate() *tls.Certificate {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.certificate
+}
+
+// GetCertificate returns the currently loaded certificate, to be used as [tls.Config] GetCertificate.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// Reload reloads the certificate if the files content changed, and returns true if it was reloaded.
+// On failure, the previously loaded certificate is kept.
+func (r *CertificateReloader) Reload() (bool, error) {
+	certPEM, err := os.ReadFile(r.certFile)
+	if err != nil {
+		return false, fmt.Errorf("cannot read tls certificate file: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("cannot read tls key file: %w", err)
+	}
+
+	r.mutex.RLock()
+	unchanged := bytes.Equal(certPEM, r.certPEM) && bytes.Equal(keyPEM, r.keyPEM)
+	r.mutex.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("cannot load tls certificate: %w", err)
+	}
+
+	r.mutex.Lock()
+	r.certPEM = certPEM
+	r.keyPEM = keyPEM
+	r.certificate = &certificate
+	r.mutex.Unlock()
+
+	return true, nil
+}
+
+// Watch checks the files for changes at the provided interval, until the context is done.
+// The optional handler is called on each reload or reload failure.
+func (r *CertificateReloader) Watch(ctx context.Context, interval time.Duration, handler CertificateReloadHandler) {
+	watchReload(ctx, interval, r.Reload, handler)
+}
+
+// CertPoolReloader provides a [x509.CertPool] loaded from a PEM bundle file, reloadable when the file changes.
+type CertPoolReloader struct {
+	caFile string
+	mutex  sync.RWMutex
+	caPEM  []byte
+	pool   *x509.CertPool
+}
+
+// NewCertPoolReloader returns a new [CertPoolReloader], with the certificates loaded from the provided PEM bundle file.
+func NewCertPoolReloader(caFile string) (*CertPoolReloader, error) {
+	reloader := &CertPoolReloader{
+		caFile: caFile,
+	}
+
+	if _, err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+// CertPool returns the currently loaded [x509.CertPool].
+func (r *CertPoolReloader) CertPool() *x509.CertPool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.pool
+}
+
+// Reload reloads the certificates if the file content changed, and returns true if they were reloaded.
+// On failure, the previously loaded certificates are kept.
+func (r *CertPoolReloader) Reload() (bool, error) {
+	caPEM, err := os.ReadFile(r.caFile)
+	if err != nil {
+		return false, fmt.Errorf("cannot read tls ca file: %w", err)
+	}
+
+	r.mutex.RLock()
+	unchanged := bytes.Equal(caPEM, r.caPEM)
+	r.mutex.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return false, fmt.Errorf("cannot find any certificate in tls ca file %s", r.caFile)
+	}
+
+	r.mutex.Lock()
+	r.caPEM = caPEM
+	r.pool = pool
+	r.mutex.Unlock()
+
+	return true, nil
+}
+
+// Watch checks the file for changes at the provided interval, until the context is done.
+// The optional handler is called on each reload or reload failure.
+func (r *CertPoolReloader) Watch(ctx context.Context, interval time.Duration, handler CertificateReloadHandler) {
+	watchReload(ctx, interval, r.Reload, handler)
+}
+
+// GetConfigForClient returns a function to be used as [tls.Config] GetConfigForClient, returning a copy of the
+// provided config using the currently loaded certificates as ClientCAs.
+func (r *CertPoolReloader) GetConfigForClient(config *tls.Config) func(*tls.ClientHelloInfo) (*tls.Config, error) {
+	base := config.Clone()
+	base.GetConfigForClient = nil
+
+	return func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		clientConfig := base.Clone()
+		clientConfig.ClientCAs = r.CertPool()
+
+		return clientConfig, nil
+	}
+}
+
+func watchReload(ctx context.Context, interval time.Duration, reload func() (bool, error), handler CertificateReloadHandler) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := reload()
+			if handler != nil && (reloaded || err != nil) {
+				handler(err)
+			}
+		}
+	}
+}
+
+// LoadCertPool returns a [x509.CertPool] containing the certificates of the provided PEM bundle file.
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read tls ca file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("cannot find any certificate in tls ca file %s", caFile)
+	}
+
+	return pool, nil
+}
+
+// FetchTLSVersion returns a TLS version for a given value (1.0, 1.1, 1.2 or 1.3), defaults to TLS 1.2.
+func FetchTLSVersion(version string) uint16 {
+	switch strings.TrimPrefix(strings.ToLower(version), "tls") {
+	case "1.0", "10":
+		return tls.VersionTLS10
+	case "1.1", "11":
+		return tls.VersionTLS11
+	case "1.3", "13":
+		return tls.VersionTLS13
+	default:
+		return tls.VersionTLS12
+	}
+}
+
+// FetchTLSClientAuth returns a [tls.ClientAuthType] for a given value, defaults to [tls.NoClientCert].
+func FetchTLSClientAuth(clientAuth string) tls.ClientAuthType {
+	switch strings.ToLower(clientAuth) {
+	case "request":
+		return tls.RequestClientCert
+	case "require":
+		return tls.RequireAnyClientCert
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven
+	case "require_and_verify":
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
+
+// FetchTLSCipherSuites returns the TLS cipher suites ids for the given names, as listed by [tls.CipherSuites].
+func FetchTLSCipherSuites(names []string) ([]uint16, error) {
+	available := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		available[suite.Name] = suite.ID
+	}
+
+	ids := []uint16{}
+
+	for _, name := range names {
+		id, ok := available[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("invalid tls cipher suite %s", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
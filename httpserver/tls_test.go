@@ -0,0 +1,258 @@
+package httpserver_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ankorstore/yokai/httpserver"
+	testtls "github.com/ankorstore/yokai/httpserver/testdata/tls"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	authority := testtls.NewTestAuthority(t)
+	certFile, keyFile := authority.WriteIssued(t, dir, "server")
+
+	reloader, err := httpserver.NewCertificateReloader(certFile, keyFile)
+	assert.NoError(t, err)
+
+	certificate, err := reloader.GetCertificate(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.Same(t, reloader.Certificate(), certificate)
+	assert.Equal(t, "server", leafCommonName(t, certificate))
+
+	// no change
+	reloaded, err := reloader.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// rotation
+	authority.WriteIssued(t, dir, "server")
+
+	reloaded, err = reloader.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.NotSame(t, certificate, reloader.Certificate())
+
+	// invalid content keeps the previous certificate
+	previous := reloader.Certificate()
+
+	err = os.WriteFile(keyFile, []byte("invalid"), 0o600)
+	assert.NoError(t, err)
+
+	reloaded, err = reloader.Reload()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot load tls certificate")
+	assert.False(t, reloaded)
+	assert.Same(t, previous, reloader.Certificate())
+}
+
+func TestCertificateReloaderWithMissingFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := testtls.NewTestAuthority(t).WriteIssued(t, dir, "server")
+
+	_, err := httpserver.NewCertificateReloader(filepath.Join(dir, "missing.pem"), keyFile)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read tls certificate file")
+
+	_, err = httpserver.NewCertificateReloader(certFile, filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read tls key file")
+}
+
+func TestCertificateReloaderWatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	authority := testtls.NewTestAuthority(t)
+	certFile, keyFile := authority.WriteIssued(t, dir, "server")
+
+	reloader, err := httpserver.NewCertificateReloader(certFile, keyFile)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan error, 10)
+
+	go reloader.Watch(ctx, 10*time.Millisecond, func(err error) {
+		reloads <- err
+	})
+
+	certificate := reloader.Certificate()
+
+	authority.WriteIssued(t, dir, "server")
+
+	select {
+	case err = <-reloads:
+		assert.NoError(t, err)
+		assert.NotSame(t, certificate, reloader.Certificate())
+	case <-time.After(time.Second):
+		t.Error("certificate was not reloaded")
+	}
+}
+
+func TestLoadCertPool(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	caFile := testtls.NewTestAuthority(t).WriteCA(t, dir)
+
+	pool, err := httpserver.LoadCertPool(caFile)
+	assert.NoError(t, err)
+	assert.NotNil(t, pool)
+
+	_, err = httpserver.LoadCertPool(filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read tls ca file")
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	err = os.WriteFile(invalidFile, []byte("invalid"), 0o600)
+	assert.NoError(t, err)
+
+	_, err = httpserver.LoadCertPool(invalidFile)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot find any certificate in tls ca file")
+}
+
+func TestCertPoolReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	authority := testtls.NewTestAuthority(t)
+	caFile := authority.WriteCA(t, dir)
+
+	reloader, err := httpserver.NewCertPoolReloader(caFile)
+	assert.NoError(t, err)
+	assert.True(t, reloader.CertPool().Equal(authority.Pool()))
+
+	// unchanged
+	reloaded, err := reloader.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// changed
+	otherAuthority := testtls.NewTestAuthority(t)
+	otherAuthority.WriteCA(t, dir)
+
+	reloaded, err = reloader.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.True(t, reloader.CertPool().Equal(otherAuthority.Pool()))
+
+	// invalid: previous certificates kept
+	err = os.WriteFile(caFile, []byte("invalid"), 0o600)
+	assert.NoError(t, err)
+
+	reloaded, err = reloader.Reload()
+	assert.Error(t, err)
+	assert.False(t, reloaded)
+	assert.Contains(t, err.Error(), "cannot find any certificate in tls ca file")
+	assert.True(t, reloader.CertPool().Equal(otherAuthority.Pool()))
+
+	// missing
+	_, err = httpserver.NewCertPoolReloader(filepath.Join(dir, "missing.pem"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read tls ca file")
+}
+
+func TestCertPoolReloaderGetConfigForClient(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	authority := testtls.NewTestAuthority(t)
+
+	reloader, err := httpserver.NewCertPoolReloader(authority.WriteCA(t, dir))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan error, 10)
+
+	go reloader.Watch(ctx, 10*time.Millisecond, func(err error) {
+		reloads <- err
+	})
+
+	//nolint:gosec
+	config := &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}
+
+	getConfigForClient := reloader.GetConfigForClient(config)
+
+	clientConfig, err := getConfigForClient(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, clientConfig.ClientAuth)
+	assert.True(t, clientConfig.ClientCAs.Equal(authority.Pool()))
+
+	otherAuthority := testtls.NewTestAuthority(t)
+	otherAuthority.WriteCA(t, dir)
+
+	select {
+	case err = <-reloads:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("certificates were not reloaded")
+	}
+
+	clientConfig, err = getConfigForClient(&tls.ClientHelloInfo{})
+	assert.NoError(t, err)
+	assert.True(t, clientConfig.ClientCAs.Equal(otherAuthority.Pool()))
+}
+
+func TestFetchTLSVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, uint16(tls.VersionTLS10), httpserver.FetchTLSVersion("1.0"))
+	assert.Equal(t, uint16(tls.VersionTLS11), httpserver.FetchTLSVersion("1.1"))
+	assert.Equal(t, uint16(tls.VersionTLS12), httpserver.FetchTLSVersion("1.2"))
+	assert.Equal(t, uint16(tls.VersionTLS13), httpserver.FetchTLSVersion("1.3"))
+	assert.Equal(t, uint16(tls.VersionTLS13), httpserver.FetchTLSVersion("TLS13"))
+	assert.Equal(t, uint16(tls.VersionTLS12), httpserver.FetchTLSVersion("invalid"))
+}
+
+func TestFetchTLSClientAuth(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, tls.NoClientCert, httpserver.FetchTLSClientAuth("none"))
+	assert.Equal(t, tls.RequestClientCert, httpserver.FetchTLSClientAuth("request"))
+	assert.Equal(t, tls.RequireAnyClientCert, httpserver.FetchTLSClientAuth("require"))
+	assert.Equal(t, tls.VerifyClientCertIfGiven, httpserver.FetchTLSClientAuth("verify_if_given"))
+	assert.Equal(t, tls.RequireAndVerifyClientCert, httpserver.FetchTLSClientAuth("REQUIRE_AND_VERIFY"))
+	assert.Equal(t, tls.NoClientCert, httpserver.FetchTLSClientAuth("invalid"))
+}
+
+func TestFetchTLSCipherSuites(t *testing.T) {
+	t.Parallel()
+
+	ids, err := httpserver.FetchTLSCipherSuites([]string{
+		"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		"tls_ecdhe_rsa_with_aes_256_gcm_sha384",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, ids)
+
+	_, err = httpserver.FetchTLSCipherSuites([]string{"invalid"})
+	assert.Error(t, err)
+	assert.Equal(t, "invalid tls cipher suite invalid", err.Error())
+}
+
+func leafCommonName(t *testing.T, certificate *tls.Certificate) string {
+	t.Helper()
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	assert.NoError(t, err)
+
+	return leaf.Subject.CommonName
+}
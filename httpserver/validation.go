@@ -0,0 +1,60 @@
+package httpserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InvalidParam is the detail of a request validation failure, on a parameter or a body field.
+type InvalidParam struct {
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// String returns a string representation of the [InvalidParam].
+func (p InvalidParam) String() string {
+	var location []string
+	if p.In != "" {
+		location = append(location, p.In)
+	}
+
+	if p.Name != "" {
+		location = append(location, p.Name)
+	}
+
+	if len(location) == 0 {
+		return p.Reason
+	}
+
+	return fmt.Sprintf("%s: %s", strings.Join(location, "."), p.Reason)
+}
+
+// ValidationError is an error for invalid requests, with the details of their invalid parameters or body fields.
+// The [JsonErrorHandler] adds these details to the response, under errors.
+type ValidationError struct {
+	Message       string
+	InvalidParams []InvalidParam
+}
+
+// NewValidationError returns a new [ValidationError].
+func NewValidationError(message string, invalidParams ...InvalidParam) *ValidationError {
+	return &ValidationError{
+		Message:       message,
+		InvalidParams: invalidParams,
+	}
+}
+
+// Error returns the error message, with its invalid parameters.
+func (e *ValidationError) Error() string {
+	if len(e.InvalidParams) == 0 {
+		return e.Message
+	}
+
+	invalidParams := make([]string, len(e.InvalidParams))
+	for i, invalidParam := range e.InvalidParams {
+		invalidParams[i] = invalidParam.String()
+	}
+
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(invalidParams, ", "))
+}
@@ -0,0 +1,28 @@
+package httpserver_test
+
+import (
+	"testing"
+
+	"github.com/ankorstore/yokai/httpserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	err := httpserver.NewValidationError("invalid request")
+	assert.Equal(t, "invalid request", err.Error())
+
+	err = httpserver.NewValidationError(
+		"invalid request",
+		httpserver.InvalidParam{Name: "id", In: "path", Reason: "must be an integer"},
+		httpserver.InvalidParam{Name: "address.street", In: "body", Reason: "is required"},
+		httpserver.InvalidParam{In: "body", Reason: "must be an object"},
+		httpserver.InvalidParam{Reason: "invalid"},
+	)
+	assert.Equal(
+		t,
+		"invalid request: path.id: must be an integer, body.address.street: is required, body: must be an object, invalid",
+		err.Error(),
+	)
+}